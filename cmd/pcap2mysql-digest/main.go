@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime/debug"
+
+	"github.com/spf13/pflag"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/digest"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/transcript"
+)
+
+func main() {
+	var displayVersion bool
+	var errorReport, jsonOutput bool
+	pflag.BoolVar(&displayVersion, "version", false, "Display program version")
+	pflag.BoolVar(&errorReport, "errors", false,
+		"Report the errors, grouped by code, query and client, rather than the queries")
+	pflag.BoolVar(&jsonOutput, "json", false, "Output the report as json")
+	pflag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage %s [files]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nWith no files reads stdin.\n")
+		pflag.PrintDefaults()
+	}
+
+	pflag.Parse()
+
+	if displayVersion {
+		buildVersion := "unknown"
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			buildVersion = bi.Main.Version
+		}
+
+		fmt.Printf("Version: %s %s\n", Version, buildVersion)
+		return
+	}
+
+	files := pflag.Args()
+
+	if errorReport {
+		e := digest.NewErrors()
+		process(files, e.Add)
+		output(jsonOutput, e.Report(), digest.WriteErrorsText)
+		return
+	}
+	d := digest.New()
+	process(files, d.Add)
+	output(jsonOutput, d.Report(), digest.WriteText)
+}
+
+func process(files []string, add func(transcript.Connection)) {
+	if len(files) > 0 {
+		processFiles(add, files)
+	} else if err := processConnections(os.Stdin, add); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func output[E any](jsonOutput bool, report []E, writeText func(io.Writer, []E) error) {
+	if jsonOutput {
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+		if err := e.Encode(report); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := writeText(os.Stdout, report); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func processFiles(add func(transcript.Connection), files []string) {
+	for _, file := range files {
+		rdr, err := os.Open(file)
+		if err != nil {
+			log.Printf("Failed to read %s: %s", file, err)
+			continue
+		}
+		err = processConnections(rdr, add)
+		rdr.Close()
+		if err != nil {
+			log.Printf("Failed to process %s: %s", file, err)
+		}
+	}
+}
+
+func processConnections(rdr io.Reader, add func(transcript.Connection)) error {
+	return transcript.Read(rdr, func(c transcript.Connection) error {
+		add(c)
+		return nil
+	})
+}
@@ -1,21 +1,232 @@
 package main
 
 import (
+	"log"
+	"math"
+	"regexp"
+
+	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
 
 	"github.com/colinnewell/pcap-cli/cli"
 
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/audit"
 	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/decoding"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/filter"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/ndjson"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/redact"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/schema"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/slowlog"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/summary"
 )
 
 func main() {
-	var intermediateData, noSort, rawData, verbose bool
+	var intermediateData, noSort, rawData, splitSessions, summariesOnly, verbose bool
+	var memoryBudget, outputVersion int
+	var format, spillDir string
 
 	pflag.BoolVar(&intermediateData, "intermediate-data", false, "Emit the data before processing")
 	pflag.BoolVar(&rawData, "raw-data", false, "Include the raw packet data")
 	pflag.BoolVar(&noSort, "no-sort", false, "Don't sort packets by time")
+	// items are now in the order the protocol dictates rather than sorted
+	// by time so there's nothing to turn off.
+	_ = pflag.CommandLine.MarkDeprecated("no-sort", "items are always in protocol order")
 	pflag.BoolVar(&verbose, "verbose", false, "Verbose about things errors")
+	pflag.IntVar(&memoryBudget, "memory-budget", 0,
+		"Megabytes of packets and results to hold in memory before spilling to disk (0 for no limit)")
+	pflag.StringVar(&spillDir, "spill-dir", "", "Directory to spill to (defaults to the temp directory)")
+	pflag.StringVar(&format, "format", "json",
+		"Output format, json, ndjson (a connection per line), ndjson-items (a transmission per line) or slow-log. "+
+			"json and ndjson hold each connection in memory until it closes")
+	pflag.IntVar(&outputVersion, "output-version", 1,
+		"Version of the json output, 2 is described by docs/output-v2.schema.json")
+	pflag.BoolVar(&summariesOnly, "summaries-only", false,
+		"Only output a summary of each connection, with --format json or ndjson")
+	pflag.BoolVar(&splitSessions, "split-sessions", false,
+		"Output each session of a pooled connection as a connection of its own")
+
+	var filters filterFlags
+	pflag.StringSliceVar(&filters.clients, "client", nil, "Only connections from these client ips or CIDR ranges")
+	pflag.StringSliceVar(&filters.servers, "server", nil, "Only connections to these server ips or CIDR ranges")
+	pflag.StringSliceVar(&filters.options.Users, "user", nil, "Only exchanges by these users")
+	pflag.StringSliceVar(&filters.options.Databases, "database", nil, "Only exchanges using these databases")
+	pflag.StringSliceVar(&filters.options.Commands, "command", nil,
+		"Only these commands, like query or COM_STMT_EXECUTE")
+	pflag.StringVar(&filters.sql, "sql", "", "Only SQL matching this regular expression")
+	pflag.UintSliceVar(&filters.errorCodes, "error-code", nil, "Only exchanges that got these error codes")
+	pflag.BoolVar(&filters.options.Errors, "errors", false, "Only exchanges that got an error")
+	pflag.DurationVar(&filters.options.MinLatency, "min-latency", 0, "Only exchanges that took at least this long")
+	pflag.StringVar(&filters.since, "since", "", "Only exchanges from this time on (RFC 3339)")
+	pflag.StringVar(&filters.until, "until", "", "Only exchanges up to this time (RFC 3339)")
+	pflag.BoolVar(&filters.options.OnlyMatching, "only-matching", false,
+		"Only output the exchanges matching the filters rather than the whole connection")
+
+	var redactions redactFlags
+	pflag.BoolVar(&redactions.redact, "redact", false,
+		"Hash the literals and values and drop the raw and auth data, ready to share")
+	pflag.StringVar(&redactions.literals, "redact-literals", "",
+		"What to do with literals in SQL and quoted values in errors, keep, hash, mask or drop "+
+			"(default keep, hash with --redact)")
+	pflag.StringVar(&redactions.values, "redact-values", "",
+		"What to do with result values and parameters, keep, hash, mask or drop (default keep, hash with --redact)")
+	pflag.StringArrayVar(&redactions.rules, "redact-column", nil,
+		"Action for result columns matching schema.table.column=action, * wildcards allowed")
+	pflag.BoolVar(&redactions.options.Raw, "redact-raw", false, "Drop the raw packet data and auth data")
+	pflag.StringVar(&redactions.key, "redact-key", "", "Key for the hashes, so they can't be reversed by guessing")
+
+	audits := audit.DefaultOptions()
+	var auditing bool
+	pflag.BoolVar(&auditing, "audit", false,
+		"Report security findings rather than the connections, as json with --format json")
+	pflag.StringSliceVar(&audits.PrivilegedUsers, "audit-privileged-user", audits.PrivilegedUsers,
+		"Users whose logins the audit reports")
+	pflag.IntVar(&audits.BruteForceAttempts, "audit-brute-force", audits.BruteForceAttempts,
+		"Access denied errors from a client the audit reports as brute force")
+	pflag.DurationVar(&audits.BruteForceWindow, "audit-brute-force-window", audits.BruteForceWindow,
+		"Longest gap between the access denied errors of a brute force attempt")
+
+	r := decoding.New(&intermediateData, &rawData, &verbose, &memoryBudget, &spillDir)
+	defer r.Close()
+	cli.Main("", r, func(completed chan interface{}) {
+		options, err := filters.parse()
+		if err != nil {
+			log.Fatal(err)
+		}
+		redaction, err := redactions.parse()
+		if err != nil {
+			log.Fatal(err)
+		}
+		// the items can go straight out as they're decoded unless
+		// something needs the whole connection.  A connection is a
+		// single value in the json and ndjson, and the connections
+		// open at the same time overlap, so those need it too.
+		streams := format == "ndjson-items" || format == "slow-log"
+		if !streams || splitSessions || summariesOnly || auditing ||
+			options.Active() || redaction.Active() {
+			completed = decoding.Assemble(completed)
+		}
+		if summariesOnly {
+			// reading the captures again for the TCP flags is only worth
+			// it when the summaries are what's wanted.
+			endings, err := summary.ReadEndings(pflag.Args())
+			if err != nil {
+				log.Fatal(err)
+			}
+			completed = summary.Apply(endings, completed)
+		}
+		if splitSessions {
+			completed = filter.SplitSessions(completed)
+		}
+		if options.Active() {
+			completed = filter.Apply(options, completed)
+		}
+		if redaction.Active() {
+			completed = redact.Apply(redaction, completed)
+		}
+		if auditing {
+			audit.Output(audits, format == "json" && pflag.CommandLine.Changed("format"), completed)
+			return
+		}
+		switch outputVersion {
+		case 1:
+		case schema.Version:
+			if format != "slow-log" {
+				completed = schema.Convert(completed)
+			}
+		default:
+			log.Fatalf("Unknown output version: %d", outputVersion)
+		}
+		if summariesOnly {
+			if format != "json" && format != "ndjson" {
+				log.Fatalf("--summaries-only needs --format json or ndjson, not %s", format)
+			}
+			completed = summary.Only(completed)
+		}
+		switch format {
+		case "json":
+			cli.SimpleJSONOutput(completed)
+		case "ndjson":
+			ndjson.Connections(completed)
+		case "ndjson-items":
+			ndjson.Items(completed)
+		case "slow-log":
+			slowlog.Output(completed)
+		default:
+			log.Fatalf("Unknown output format: %s", format)
+		}
+	})
+}
+
+// filterFlags are the filter options as they come from the command line.
+type filterFlags struct {
+	options           filter.Options
+	clients, servers  []string
+	sql, since, until string
+	errorCodes        []uint
+}
+
+var errBadErrorCode = errors.New("error codes are 16 bit")
+
+func (f filterFlags) parse() (filter.Options, error) {
+	o := f.options
+	var err error
+	if o.Clients, err = filter.ParseNetworks(f.clients); err != nil {
+		return o, err
+	}
+	if o.Servers, err = filter.ParseNetworks(f.servers); err != nil {
+		return o, err
+	}
+	if f.sql != "" {
+		if o.SQL, err = regexp.Compile(f.sql); err != nil {
+			return o, errors.Wrap(err, "sql-filter")
+		}
+	}
+	for _, code := range f.errorCodes {
+		if code > math.MaxUint16 {
+			return o, errors.Wrapf(errBadErrorCode, "%d", code)
+		}
+		o.ErrorCodes = append(o.ErrorCodes, uint16(code))
+	}
+	if o.Since, err = filter.ParseTime(f.since); err != nil {
+		return o, err
+	}
+	o.Until, err = filter.ParseTime(f.until)
+	return o, err
+}
+
+// redactFlags are the redaction options as they come from the command
+// line.
+type redactFlags struct {
+	options          redact.Options
+	redact           bool
+	literals, values string
+	rules            []string
+	key              string
+}
 
-	r := decoding.New(&intermediateData, &rawData, &verbose, &noSort)
-	cli.Main("", r, cli.SimpleJSONOutput)
+func (f redactFlags) parse() (redact.Options, error) {
+	o := f.options
+	o.Key = []byte(f.key)
+	if f.redact {
+		o.Literals, o.Values, o.Raw = redact.Hash, redact.Hash, true
+	}
+	var err error
+	if f.literals != "" {
+		if o.Literals, err = redact.ParseAction(f.literals); err != nil {
+			return o, err
+		}
+	}
+	if f.values != "" {
+		if o.Values, err = redact.ParseAction(f.values); err != nil {
+			return o, err
+		}
+	}
+	for _, r := range f.rules {
+		rule, err := redact.ParseRule(r)
+		if err != nil {
+			return o, err
+		}
+		o.Rules = append(o.Rules, rule)
+	}
+	return o, nil
 }
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"runtime/debug"
+
+	"github.com/spf13/pflag"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/mock"
+)
+
+func main() {
+	var displayVersion bool
+	var exact bool
+	var listen string
+	pflag.BoolVar(&displayVersion, "version", false, "Display program version")
+	pflag.BoolVar(&exact, "exact", false, "Only answer queries that exactly match the capture")
+	pflag.StringVar(&listen, "listen", "127.0.0.1:3306", "Address to listen on")
+	pflag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage %s [--listen address] [files]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nWith no files reads stdin.\n")
+		pflag.PrintDefaults()
+	}
+
+	pflag.Parse()
+
+	if displayVersion {
+		buildVersion := "unknown"
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			buildVersion = bi.Main.Version
+		}
+
+		fmt.Printf("Version: %s %s\n", Version, buildVersion)
+		return
+	}
+
+	recordings := mock.NewRecordings()
+	recordings.Fingerprints = !exact
+	files := pflag.Args()
+	if len(files) == 0 {
+		if err := recordings.Load(os.Stdin); err != nil {
+			log.Fatal(err)
+		}
+	}
+	for _, file := range files {
+		rdr, err := os.Open(file)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %s", file, err)
+		}
+		if err := recordings.Load(rdr); err != nil {
+			log.Fatalf("Failed to process %s: %s", file, err)
+		}
+		rdr.Close()
+	}
+
+	l, err := net.Listen("tcp", listen)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Listening on %s", l.Addr())
+	if err := mock.NewServer(recordings).Serve(l); err != nil {
+		log.Fatal(err)
+	}
+}
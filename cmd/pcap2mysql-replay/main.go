@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/spf13/pflag"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/replay"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/transcript"
+)
+
+func main() {
+	var displayVersion bool
+	var jsonOutput bool
+	var dsn string
+	var timing string
+	var speed float64
+	pflag.BoolVar(&displayVersion, "version", false, "Display program version")
+	pflag.BoolVar(&jsonOutput, "json", false, "Output the results as json")
+	pflag.StringVar(&dsn, "dsn", "", "Server to replay against, e.g. user:password@tcp(localhost:3306)/")
+	pflag.StringVar(&timing, "timing", "original", "Timing of the requests (original, scaled or fast)")
+	pflag.Float64Var(&speed, "speed", 1, "How many times faster than the capture to go with scaled timing")
+	pflag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage %s --dsn dsn [files]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nWith no files reads stdin.\n")
+		pflag.PrintDefaults()
+	}
+
+	pflag.Parse()
+
+	if displayVersion {
+		buildVersion := "unknown"
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			buildVersion = bi.Main.Version
+		}
+
+		fmt.Printf("Version: %s %s\n", Version, buildVersion)
+		return
+	}
+
+	if dsn == "" {
+		pflag.Usage()
+		os.Exit(1)
+	}
+	options := replay.Options{Speed: speed}
+	var err error
+	if options.Timing, err = replay.ParseTiming(timing); err != nil {
+		log.Fatal(err)
+	}
+	if options.Timing == replay.Scaled && speed <= 0 {
+		log.Fatal("--speed needs to be more than 0")
+	}
+
+	var conns []transcript.Connection
+	collect := func(c transcript.Connection) error {
+		conns = append(conns, c)
+		return nil
+	}
+	files := pflag.Args()
+	if len(files) == 0 {
+		if err := transcript.Read(os.Stdin, collect); err != nil {
+			log.Fatal(err)
+		}
+	}
+	for _, file := range files {
+		rdr, err := os.Open(file)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %s", file, err)
+		}
+		if err := transcript.Read(rdr, collect); err != nil {
+			log.Fatalf("Failed to process %s: %s", file, err)
+		}
+		rdr.Close()
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	results := replay.New(db, options).Run(context.Background(), conns)
+	if jsonOutput {
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+		if err := e.Encode(results); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := replay.WriteText(os.Stdout, results); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,6 @@
+package main
+
+// Version number that is baked in as the program is built.
+//
+//nolint:gochecknoglobals
+var Version = "No version defined at build time"
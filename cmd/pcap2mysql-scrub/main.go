@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime/debug"
+
+	"github.com/spf13/pflag"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/redact"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/scrub"
+)
+
+func main() {
+	var displayVersion bool
+	var output, literals, values, key string
+	var rules []string
+	var o scrub.Options
+	pflag.BoolVar(&displayVersion, "version", false, "Display program version")
+	pflag.StringVarP(&output, "output", "o", "", "File to write the scrubbed capture to (default stdout)")
+	pflag.StringVar(&literals, "redact-literals", "hash", "What to do with literals in SQL, keep, hash, mask or drop")
+	pflag.StringVar(&values, "redact-values", "hash",
+		"What to do with result values and parameters, keep, hash, mask or drop")
+	pflag.StringArrayVar(&rules, "redact-column", nil,
+		"Action for result columns matching schema.table.column=action, * wildcards allowed")
+	pflag.StringVar(&key, "redact-key", "", "Key for the hashes, so they can't be reversed by guessing")
+	pflag.Int32SliceVar(&o.ServerPorts, "server-ports", nil, "Only scrub connections to these ports")
+	pflag.BoolVar(&o.Verbose, "verbose", false, "Verbose about things that go wrong")
+	pflag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage %s [--output scrubbed.pcap] [file]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nWith no file reads stdin.\n")
+		pflag.PrintDefaults()
+	}
+
+	pflag.Parse()
+
+	if displayVersion {
+		buildVersion := "unknown"
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			buildVersion = bi.Main.Version
+		}
+
+		fmt.Printf("Version: %s %s\n", Version, buildVersion)
+		return
+	}
+
+	var err error
+	o.Redact.Key = []byte(key)
+	if o.Redact.Literals, err = redact.ParseAction(literals); err != nil {
+		log.Fatal(err)
+	}
+	if o.Redact.Values, err = redact.ParseAction(values); err != nil {
+		log.Fatal(err)
+	}
+	for _, r := range rules {
+		rule, err := redact.ParseRule(r)
+		if err != nil {
+			log.Fatal(err)
+		}
+		o.Redact.Rules = append(o.Redact.Rules, rule)
+	}
+
+	var rdr io.Reader = os.Stdin
+	switch files := pflag.Args(); len(files) {
+	case 0:
+	case 1:
+		f, err := os.Open(files[0])
+		if err != nil {
+			log.Fatalf("Failed to read %s: %s", files[0], err)
+		}
+		defer f.Close()
+		rdr = f
+	default:
+		pflag.Usage()
+		os.Exit(1)
+	}
+
+	var wrt io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		wrt = f
+	}
+	buffered := bufio.NewWriter(wrt)
+	result, err := scrub.Scrub(o, rdr, buffered)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := buffered.Flush(); err != nil {
+		log.Fatal(err)
+	}
+	for _, u := range result.Unscrubbed {
+		log.Println(u)
+	}
+	log.Printf("Scrubbed %d connections, %d packets changed", result.Connections, result.Frames)
+	if result.Skipped > 0 {
+		log.Printf("Left %d connections that didn't look like MySQL as they were", result.Skipped)
+	}
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	_ "embed"
-	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -11,6 +10,8 @@ import (
 	"text/template"
 
 	"github.com/spf13/pflag"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/transcript"
 )
 
 //go:embed text.tmpl
@@ -100,31 +101,13 @@ func processFiles(tmpl *template.Template, files []string) {
 	}
 }
 
+// processTemplate runs the template for each connection.
 func processTemplate(rdr io.Reader, output io.Writer, tmpl *template.Template) error {
-	d := json.NewDecoder(rdr)
-	d.UseNumber()
-	t, err := d.Token()
-	if err == io.EOF {
-		return nil
-	}
+	err := transcript.Read(rdr, func(c map[string]interface{}) error {
+		return tmpl.Execute(output, c)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to start reading ports json: %w", err)
-	}
-	// expecting delim to start
-	if d, ok := t.(json.Delim); !ok || d.String() != "[" {
-		//nolint:goerr113
-		return fmt.Errorf("unexpected start to the json")
-	}
-
-	for d.More() {
-		v := map[string]interface{}{}
-		if err := d.Decode(&v); err != nil {
-			return fmt.Errorf("decode failure %w", err)
-		}
-		if err := tmpl.Execute(output, v); err != nil {
-			return err
-		}
+		return fmt.Errorf("decode failure %w", err)
 	}
-
 	return nil
 }
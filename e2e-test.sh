@@ -32,3 +32,69 @@ then
     cp $FILE.actual $FILE.expected
 fi
 diff -q $FILE.expected $FILE.actual || (echo Failed diff $FILE.expected $FILE.actual && exit 1)
+
+# the newline delimited json should summarise the same way
+for format in ndjson ndjson-items
+do
+    FILE=test/captures/compressed.$format.txt
+    TZ= ./pcap2mysql-log --format $format test/captures/compressed.pcap | ./pcap2mysql-summaries > $FILE.actual
+    diff -q test/captures/compressed.txt.expected $FILE.actual || (echo Failed diff test/captures/compressed.txt.expected $FILE.actual && exit 1)
+done
+
+FILE=test/captures/execute.slow
+TZ= ./pcap2mysql-log --format slow-log test/captures/execute.pcap > $FILE.actual
+if [ ! -f $FILE.expected ]
+then
+    cp $FILE.actual $FILE.expected
+fi
+diff -q $FILE.expected $FILE.actual || (echo Failed diff $FILE.expected $FILE.actual && exit 1)
+
+FILE=test/captures/execute.digest
+./pcap2mysql-digest test/captures/execute.expected > $FILE.actual
+if [ ! -f $FILE.expected ]
+then
+    cp $FILE.actual $FILE.expected
+fi
+diff -q $FILE.expected $FILE.actual || (echo Failed diff $FILE.expected $FILE.actual && exit 1)
+# the transmission per line form should give the same report.
+TZ= ./pcap2mysql-log --format ndjson-items test/captures/execute.pcap | ./pcap2mysql-digest > $FILE.actual
+diff -q $FILE.expected $FILE.actual || (echo Failed diff $FILE.expected $FILE.actual && exit 1)
+
+# scrubbing should leave the captures decoding the same way, with the values
+# changed
+for capture in execute compressed
+do
+    FILE=test/captures/$capture.scrubbed
+    ./pcap2mysql-scrub --redact-literals mask --redact-values mask --redact-column '*.*.id=keep' \
+        -o $FILE.pcap.actual test/captures/$capture.pcap
+    TZ= ./pcap2mysql-log $FILE.pcap.actual | jq 'sort_by (.Address)' > $FILE.actual
+    if [ ! -f $FILE.expected ]
+    then
+        cp $FILE.actual $FILE.expected
+    fi
+    diff -q $FILE.expected $FILE.actual || (echo Failed diff $FILE.expected $FILE.actual && exit 1)
+done
+
+FILE=test/captures/edge-cases.audit
+TZ= ./pcap2mysql-log --audit test/captures/edge-cases.pcap > $FILE.actual
+if [ ! -f $FILE.expected ]
+then
+    cp $FILE.actual $FILE.expected
+fi
+diff -q $FILE.expected $FILE.actual || (echo Failed diff $FILE.expected $FILE.actual && exit 1)
+
+FILE=test/captures/compressed.summaries
+TZ= ./pcap2mysql-log --summaries-only test/captures/compressed.pcap | jq 'sort_by (.Address)' > $FILE.actual
+if [ ! -f $FILE.expected ]
+then
+    cp $FILE.actual $FILE.expected
+fi
+diff -q $FILE.expected $FILE.actual || (echo Failed diff $FILE.expected $FILE.actual && exit 1)
+
+FILE=test/captures/error.errors
+./pcap2mysql-digest --errors test/captures/error.expected > $FILE.actual
+if [ ! -f $FILE.expected ]
+then
+    cp $FILE.actual $FILE.expected
+fi
+diff -q $FILE.expected $FILE.actual || (echo Failed diff $FILE.expected $FILE.actual && exit 1)
@@ -6,6 +6,7 @@ toolchain go1.24.1
 
 require (
 	github.com/colinnewell/pcap-cli v0.0.6
+	github.com/go-sql-driver/mysql v1.8.1
 	github.com/google/go-cmp v0.5.6
 	github.com/google/gopacket v1.1.19
 	github.com/pkg/errors v0.9.1
@@ -13,6 +14,7 @@ require (
 )
 
 require (
+	filippo.io/edwards25519 v1.1.0 // indirect
 	github.com/json-iterator/go v1.1.12 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
@@ -0,0 +1,388 @@
+// Package audit looks through the decoded connections for signs of
+// insecure set ups and attacks: logins in the clear or with weak password
+// plugins, privileged users, bursts of failed logins, local file access and
+// queries that look like SQL injection.  Each finding carries the evidence
+// for it so it can be checked by hand.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/sqltext"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+// The kinds of finding.
+const (
+	NoTLS             = "no-tls"
+	ClearPassword     = "clear-password"
+	OldPassword       = "old-password"
+	PrivilegedLogin   = "privileged-login"
+	BruteForce        = "brute-force"
+	LocalInfile       = "local-infile"
+	IntoOutfile       = "into-outfile"
+	SQLInjection      = "sql-injection"
+	accessDeniedError = 1045
+	maxEvidence       = 200
+)
+
+// The severities.
+const (
+	High   = "high"
+	Medium = "medium"
+)
+
+// Finding is something worth a look, with the evidence for it.
+type Finding struct {
+	Time       time.Time
+	Kind       string
+	Severity   string
+	Connection string
+	Client     string
+	User       string `json:"User,omitempty"`
+	Detail     string
+	Evidence   string `json:"Evidence,omitempty"`
+}
+
+// Options tune what counts as a finding.
+type Options struct {
+	// PrivilegedUsers are the users whose logins are reported.
+	PrivilegedUsers []string
+	// BruteForceAttempts access denied errors for a client, each no more
+	// than BruteForceWindow after the last, count as a brute force attempt.
+	BruteForceAttempts int
+	BruteForceWindow   time.Duration
+}
+
+// DefaultOptions are the options used by pcap2mysql-log.
+func DefaultOptions() Options {
+	return Options{
+		PrivilegedUsers:    []string{"root"},
+		BruteForceAttempts: 5,
+		BruteForceWindow:   time.Minute,
+	}
+}
+
+// denial is a failed login.
+type denial struct {
+	time       time.Time
+	connection string
+	user       string
+	message    string
+}
+
+// Audit collects the findings from connections as they're added.
+type Audit struct {
+	o        Options
+	findings []Finding
+	denials  map[string][]denial
+}
+
+// New creates an empty audit.
+func New(o Options) *Audit {
+	return &Audit{o: o, denials: make(map[string][]denial)}
+}
+
+//nolint:gochecknoglobals
+var (
+	localInfile = regexp.MustCompile(`\bload data (?:low_priority |concurrent )?local infile\b`)
+	intoOutfile = regexp.MustCompile(`\binto (?:outfile|dumpfile)\b`)
+	stacked     = regexp.MustCompile(`; *[a-z(]`)
+	unionSelect = regexp.MustCompile(`\bunion(?: all| distinct)? select (?:null|\?)(?:,(?:null|\?))*(?: from| limit|$)`)
+	schemaProbe = regexp.MustCompile(`\bunion(?: all| distinct)? select\b.*\b(?:information_schema|mysql\.user)\b`)
+	tautology   = regexp.MustCompile(`(?i)\b(?:or|\|\|)\s+(\x00\d+|\w+)\s*=\s*(\x00\d+|\w+)`)
+	alwaysTrue  = regexp.MustCompile(`(?i)\b(?:or|\|\|)\s+true\b`)
+)
+
+// Add audits a connection.
+func (a *Audit) Add(c structure.Connection) {
+	s := scan{a: a, c: c, address: c.Address.String(), client: c.Address.IP.Src().String()}
+	offeredTLS := false
+	for _, t := range c.Items {
+		if g, ok := unwrap(t.Data).(structure.Greeting); ok {
+			offeredTLS = g.Capabilities&structure.CCAP_SSL != 0
+		}
+	}
+
+	var lastQuery string
+	for _, e := range c.Exchanges {
+		t := c.Items[e.Request]
+		switch v := unwrap(t.Data).(type) {
+		case structure.LoginRequest:
+			s.user = v.Username
+			// once TLS starts the login can't be seen.
+			detail := "login in the clear"
+			if offeredTLS {
+				detail += ", the server offered TLS"
+			}
+			caps := fmt.Sprintf("client capabilities 0x%08x without SSL", uint32(v.ClientCapabilities))
+			s.finding(t, NoTLS, Medium, detail, caps)
+			if v.ClientCapabilities&structure.CCAP_SECURE_CONNECTION == 0 {
+				s.finding(t, OldPassword, High, "login with the pre 4.1 password hashing", "")
+			}
+			s.plugin(t, v.AuthPlugin)
+			s.login(e, t)
+		case structure.ChangeUserRequest:
+			s.user = v.Username
+			s.plugin(t, v.AuthPlugin)
+			s.login(e, t)
+		case structure.Request:
+			if v.Type == "Query" || v.Type == "Prepare" {
+				lastQuery = v.Query
+				s.query(t, v.Query)
+			}
+		}
+		for _, r := range e.Responses {
+			switch v := unwrap(c.Items[r].Data).(type) {
+			case structure.AuthSwitchResponse:
+				s.plugin(c.Items[r], v.AuthPlugin)
+			case structure.Response:
+				if v.Type == "In file" && !localInfile.MatchString(sqltext.Fingerprint(lastQuery)) {
+					s.finding(c.Items[r], LocalInfile, High,
+						"the server asked for a local file the query didn't load", lastQuery)
+				}
+			}
+		}
+	}
+}
+
+// scan is the state while auditing a connection.
+type scan struct {
+	a               *Audit
+	c               structure.Connection
+	address, client string
+	// user is the user logged in at the time.
+	user string
+}
+
+func (s *scan) finding(t structure.Transmission, kind, severity, detail, evidence string) {
+	s.a.findings = append(s.a.findings, Finding{
+		Time:       seen(t),
+		Kind:       kind,
+		Severity:   severity,
+		Connection: s.address,
+		Client:     s.client,
+		User:       s.user,
+		Detail:     detail,
+		Evidence:   truncate(evidence),
+	})
+}
+
+// plugin reports the weak password plugins.
+func (s *scan) plugin(t structure.Transmission, plugin string) {
+	switch plugin {
+	case "mysql_clear_password":
+		s.finding(t, ClearPassword, High, "password sent in the clear", plugin)
+	case "mysql_old_password":
+		s.finding(t, OldPassword, High, "login with the pre 4.1 password hashing", plugin)
+	}
+}
+
+// login reports privileged users and notes failures for the brute force
+// check.
+func (s *scan) login(e structure.Exchange, t structure.Transmission) {
+	outcome := "outcome not seen"
+	for _, r := range e.Responses {
+		switch v := unwrap(s.c.Items[r].Data).(type) {
+		case structure.OKResponse:
+			outcome = "succeeded"
+		case structure.ErrorResponse:
+			outcome = fmt.Sprintf("failed, %d %s", v.Code, v.Message)
+			if v.Code == accessDeniedError {
+				s.a.denials[s.client] = append(s.a.denials[s.client], denial{
+					time:       seen(s.c.Items[r]),
+					connection: s.address,
+					user:       s.user,
+					message:    v.Message,
+				})
+			}
+		}
+	}
+	if contains(s.a.o.PrivilegedUsers, s.user) {
+		s.finding(t, PrivilegedLogin, Medium, "login as a privileged user", outcome)
+	}
+}
+
+// query checks the SQL.  The keywords are looked for in the fingerprint so
+// the literals and comments can't confuse the checks.
+func (s *scan) query(t structure.Transmission, query string) {
+	fingerprint := sqltext.Fingerprint(query)
+	if localInfile.MatchString(fingerprint) {
+		s.finding(t, LocalInfile, Medium, "LOAD DATA LOCAL INFILE reads a file from the client", query)
+	}
+	if intoOutfile.MatchString(fingerprint) {
+		s.finding(t, IntoOutfile, High, "writes a file on the server", query)
+	}
+	if reason := injection(query, fingerprint); reason != "" {
+		s.finding(t, SQLInjection, High, "looks like SQL injection, "+reason, query)
+	}
+}
+
+// injection gives the reason a query looks like SQL injection, if it does.
+// These are heuristics, legitimate multi statement queries will be caught.
+func injection(query, fingerprint string) string {
+	if stacked.MatchString(fingerprint) {
+		return "stacked queries"
+	}
+	if unionSelect.MatchString(fingerprint) || schemaProbe.MatchString(fingerprint) {
+		return "UNION based"
+	}
+	// the literals are numbered by their value so comparing them is a
+	// matter of comparing the numbers.
+	values := make(map[string]int)
+	numbered := sqltext.ReplaceLiterals(query, func(literal string) string {
+		value := sqltext.Unquote(literal)
+		if _, ok := values[value]; !ok {
+			values[value] = len(values)
+		}
+		return fmt.Sprintf("\x00%d", values[value])
+	})
+	if alwaysTrue.MatchString(numbered) {
+		return "tautology"
+	}
+	for _, m := range tautology.FindAllStringSubmatch(numbered, -1) {
+		if strings.EqualFold(m[1], m[2]) {
+			return "tautology"
+		}
+	}
+	return ""
+}
+
+// Findings gives everything found, in time order, along with the brute force
+// attempts across all the connections.
+func (a *Audit) Findings() []Finding {
+	findings := append([]Finding{}, a.findings...)
+	clients := make([]string, 0, len(a.denials))
+	for client := range a.denials {
+		clients = append(clients, client)
+	}
+	sort.Strings(clients)
+	for _, client := range clients {
+		findings = append(findings, a.bruteForce(client)...)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Time.Before(findings[j].Time)
+	})
+	return findings
+}
+
+// bruteForce looks for bursts of access denied errors for a client.
+func (a *Audit) bruteForce(client string) []Finding {
+	denials := a.denials[client]
+	sort.SliceStable(denials, func(i, j int) bool {
+		return denials[i].time.Before(denials[j].time)
+	})
+	var findings []Finding
+	for start := 0; start < len(denials); {
+		end := start + 1
+		for end < len(denials) && denials[end].time.Sub(denials[end-1].time) <= a.o.BruteForceWindow {
+			end++
+		}
+		if burst := denials[start:end]; len(burst) >= a.o.BruteForceAttempts {
+			var users []string
+			connections := make(map[string]bool)
+			for _, d := range burst {
+				if !contains(users, d.user) {
+					users = append(users, d.user)
+				}
+				connections[d.connection] = true
+			}
+			first, last := burst[0], burst[len(burst)-1]
+			findings = append(findings, Finding{
+				Time:       first.time,
+				Kind:       BruteForce,
+				Severity:   High,
+				Connection: first.connection,
+				Client:     client,
+				User:       strings.Join(users, ","),
+				Detail: fmt.Sprintf("%d access denied errors over %d connections in %s",
+					len(burst), len(connections), last.time.Sub(first.time)),
+				Evidence: truncate(last.message),
+			})
+		}
+		start = end
+	}
+	return findings
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+func seen(t structure.Transmission) time.Time {
+	if len(t.Seen) == 0 {
+		return time.Time{}
+	}
+	return t.Seen[0]
+}
+
+func truncate(s string) string {
+	if len(s) <= maxEvidence {
+		return s
+	}
+	return s[:maxEvidence] + "..."
+}
+
+func unwrap(data interface{}) interface{} {
+	if rawPacket, ok := data.(structure.WithRawPacket); ok {
+		return rawPacket.Transmission
+	}
+	return data
+}
+
+// WriteText writes the findings in a form intended for people to read.
+func WriteText(w io.Writer, findings []Finding) error {
+	for _, f := range findings {
+		user := ""
+		if f.User != "" {
+			user = " user " + f.User
+		}
+		_, err := fmt.Fprintf(w, "%s [%s] %s %s%s: %s\n",
+			f.Time.Format(time.RFC3339Nano), f.Severity, f.Kind, f.Connection, user, f.Detail)
+		if err != nil {
+			return err
+		}
+		if f.Evidence != "" {
+			if _, err := fmt.Fprintf(w, "    %s\n", f.Evidence); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Output audits all the connections and writes the findings to stdout, as
+// json or text.
+func Output(o Options, jsonOutput bool, completed chan interface{}) {
+	a := New(o)
+	for c := range completed {
+		if conn, ok := c.(structure.Connection); ok {
+			a.Add(conn)
+		}
+	}
+	findings := a.Findings()
+	if jsonOutput {
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+		if err := e.Encode(findings); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := WriteText(os.Stdout, findings); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,210 @@
+package audit_test
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"github.com/colinnewell/pcap-cli/tcp"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/audit"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+var start = time.Date(2021, 4, 4, 17, 28, 50, 0, time.UTC)
+
+// connection builds a connection from the client port given out of
+// requests and their responses, a second apart.
+func connection(port byte, exchanges ...[]interface{}) structure.Connection {
+	c := structure.Connection{
+		Address: tcp.ConnectionAddress{
+			IP:   gopacket.NewFlow(layers.EndpointIPv4, net.IP{10, 0, 0, 2}, net.IP{10, 0, 0, 1}),
+			Port: gopacket.NewFlow(layers.EndpointTCPPort, []byte{0xc3, port}, []byte{0x0c, 0xea}),
+		},
+	}
+	caps := structure.CCAP_CLIENT_PROTOCOL_41 | structure.CCAP_SECURE_CONNECTION | structure.CCAP_PLUGIN_AUTH
+	c.Items = append(c.Items, structure.Transmission{
+		Data: structure.Greeting{Type: "Greeting", Capabilities: caps | structure.CCAP_SSL},
+		Seen: []time.Time{start},
+	})
+	for i, e := range exchanges {
+		at := start.Add(time.Duration(i+1) * time.Second)
+		exchange := structure.Exchange{Request: len(c.Items), RequestStart: at}
+		request := len(c.Items)
+		c.Items = append(c.Items, structure.Transmission{Data: e[0], Seen: []time.Time{at}})
+		for _, r := range e[1:] {
+			exchange.Responses = append(exchange.Responses, len(c.Items))
+			c.Items = append(c.Items, structure.Transmission{Data: r, Seen: []time.Time{at}, ResponseTo: &request})
+		}
+		c.Exchanges = append(c.Exchanges, exchange)
+	}
+	return c
+}
+
+func login(user string) structure.LoginRequest {
+	return structure.LoginRequest{
+		Type:               "Login",
+		ClientCapabilities: structure.CCAP_CLIENT_PROTOCOL_41 | structure.CCAP_SECURE_CONNECTION,
+		Username:           user,
+		AuthPlugin:         "mysql_native_password",
+	}
+}
+
+func query(sql string) structure.Request {
+	return structure.Request{Type: "Query", Query: sql}
+}
+
+var (
+	ok     = structure.OKResponse{Type: "OK"}
+	denied = structure.ErrorResponse{Type: "Error", Code: 1045, Message: "Access denied for user"}
+)
+
+type summary struct {
+	Kind, Severity, User, Detail, Evidence string
+}
+
+func summarise(findings []audit.Finding) []summary {
+	var s []summary
+	for _, f := range findings {
+		s = append(s, summary{f.Kind, f.Severity, f.User, f.Detail, f.Evidence})
+	}
+	return s
+}
+
+func TestLogins(t *testing.T) {
+	a := audit.New(audit.DefaultOptions())
+	clear := login("root")
+	clear.AuthPlugin = "mysql_clear_password"
+	old := login("site")
+	old.ClientCapabilities &^= structure.CCAP_SECURE_CONNECTION
+	a.Add(connection(0x50,
+		[]interface{}{clear, ok},
+		[]interface{}{structure.ChangeUserRequest{Type: "MYSQL_CHANGE_USER", Username: "site"},
+			structure.AuthSwitchResponse{Type: "Auth switch", AuthPlugin: "mysql_old_password"}, ok},
+	))
+	a.Add(connection(0x51, []interface{}{old, denied}))
+
+	expected := []summary{
+		{"no-tls", "medium", "root", "login in the clear, the server offered TLS",
+			"client capabilities 0x00002200 without SSL"},
+		{"clear-password", "high", "root", "password sent in the clear", "mysql_clear_password"},
+		{"privileged-login", "medium", "root", "login as a privileged user", "succeeded"},
+		{"no-tls", "medium", "site", "login in the clear, the server offered TLS",
+			"client capabilities 0x00000200 without SSL"},
+		{"old-password", "high", "site", "login with the pre 4.1 password hashing", ""},
+		{"old-password", "high", "site", "login with the pre 4.1 password hashing", "mysql_old_password"},
+	}
+	if diff := cmp.Diff(summarise(a.Findings()), expected); diff != "" {
+		t.Errorf("Findings don't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestBruteForce(t *testing.T) {
+	o := audit.DefaultOptions()
+	o.BruteForceAttempts = 3
+	a := audit.New(o)
+	a.Add(connection(0x50, []interface{}{login("admin"), denied}, []interface{}{login("site"), denied}))
+	a.Add(connection(0x51, []interface{}{login("site"), denied}))
+	// a lone failure much later isn't part of it.
+	late := connection(0x52, []interface{}{login("site"), denied})
+	late.Items[2].Seen[0] = start.Add(time.Hour)
+	a.Add(late)
+
+	var brute []audit.Finding
+	for _, f := range a.Findings() {
+		if f.Kind == audit.BruteForce {
+			brute = append(brute, f)
+		}
+	}
+	expected := []audit.Finding{{
+		Time:       start.Add(time.Second),
+		Kind:       "brute-force",
+		Severity:   "high",
+		Connection: "10.0.0.2:50000 - 10.0.0.1:3306",
+		Client:     "10.0.0.2",
+		User:       "admin,site",
+		Detail:     "3 access denied errors over 2 connections in 1s",
+		Evidence:   "Access denied for user",
+	}}
+	if diff := cmp.Diff(brute, expected); diff != "" {
+		t.Errorf("Findings don't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestQueries(t *testing.T) {
+	tests := []struct {
+		sql    string
+		kind   string
+		detail string
+	}{
+		{"SELECT * FROM users WHERE id = 1", "", ""},
+		{"SELECT * FROM users WHERE name = 'a' OR b = 'a'", "", ""},
+		{"SELECT * FROM users WHERE id = 1 OR 1=1", "sql-injection", "looks like SQL injection, tautology"},
+		{"SELECT * FROM users WHERE name = '' OR 'a'='a'", "sql-injection", "looks like SQL injection, tautology"},
+		{"SELECT * FROM users WHERE name = '' OR true -- '", "sql-injection", "looks like SQL injection, tautology"},
+		{"SELECT name FROM users WHERE id = 1 UNION SELECT NULL, NULL#", "sql-injection",
+			"looks like SQL injection, UNION based"},
+		{"SELECT name FROM t WHERE id = 1 UNION ALL SELECT table_name FROM information_schema.tables",
+			"sql-injection", "looks like SQL injection, UNION based"},
+		{"SELECT name FROM a UNION SELECT name FROM b", "", ""},
+		{"SELECT * FROM users WHERE id = 1; DROP TABLE users", "sql-injection",
+			"looks like SQL injection, stacked queries"},
+		{"SELECT 'a; b'; ", "", ""},
+		{"SELECT * FROM users INTO OUTFILE '/tmp/users'", "into-outfile", "writes a file on the server"},
+		{"LOAD DATA LOCAL INFILE '/etc/passwd' INTO TABLE t", "local-infile",
+			"LOAD DATA LOCAL INFILE reads a file from the client"},
+		{"SELECT 'load data local infile'", "", ""},
+	}
+	for _, test := range tests {
+		a := audit.New(audit.DefaultOptions())
+		a.Add(connection(0x50, []interface{}{query(test.sql), ok}))
+		var expected []summary
+		if test.kind != "" {
+			expected = []summary{{test.kind, "", "", test.detail, test.sql}}
+		}
+		var got []summary
+		for _, s := range summarise(a.Findings()) {
+			s.Severity = ""
+			got = append(got, s)
+		}
+		if diff := cmp.Diff(got, expected); diff != "" {
+			t.Errorf("Findings for %s don't match (-got +expected):\n%s\n", test.sql, diff)
+		}
+	}
+}
+
+func TestUnpromptedInfile(t *testing.T) {
+	a := audit.New(audit.DefaultOptions())
+	a.Add(connection(0x50, []interface{}{query("SELECT 1"), structure.Response{Type: "In file"}}))
+	expected := []summary{{
+		"local-infile", "high", "", "the server asked for a local file the query didn't load", "SELECT 1",
+	}}
+	if diff := cmp.Diff(summarise(a.Findings()), expected); diff != "" {
+		t.Errorf("Findings don't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var b bytes.Buffer
+	err := audit.WriteText(&b, []audit.Finding{{
+		Time:       start,
+		Kind:       "into-outfile",
+		Severity:   "high",
+		Connection: "10.0.0.2:50000 - 10.0.0.1:3306",
+		User:       "site",
+		Detail:     "writes a file on the server",
+		Evidence:   "SELECT * FROM t INTO OUTFILE '/tmp/t'",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "2021-04-04T17:28:50Z [high] into-outfile 10.0.0.2:50000 - 10.0.0.1:3306 user site: " +
+		"writes a file on the server\n    SELECT * FROM t INTO OUTFILE '/tmp/t'\n"
+	if diff := cmp.Diff(b.String(), expected); diff != "" {
+		t.Errorf("Text doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
@@ -0,0 +1,50 @@
+package decoding
+
+import (
+	"github.com/colinnewell/pcap-cli/tcp"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+// Assemble puts the connections back together from the items handed on as
+// they were decoded, for the output that needs whole connections.  Anything
+// else is passed on as it is.  The memory budget the rows hold isn't handed
+// back until their connection has been passed on, so the rows of long lived
+// connections are spilled rather than piling up.
+func Assemble(completed chan interface{}) chan interface{} {
+	assembled := make(chan interface{})
+	go func() {
+		defer close(assembled)
+		partial := make(map[tcp.ConnectionAddress]*structure.Connection)
+		releases := make(map[tcp.ConnectionAddress][]func())
+		for c := range completed {
+			var release []func()
+			switch v := c.(type) {
+			case structure.ConnectionItems:
+				conn, ok := partial[v.Address]
+				if !ok {
+					conn = &structure.Connection{Address: v.Address}
+					partial[v.Address] = conn
+				}
+				conn.Items = append(conn.Items, v.Items...)
+				conn.Exchanges = append(conn.Exchanges, v.Exchanges...)
+				if v.Release != nil {
+					releases[v.Address] = append(releases[v.Address], v.Release)
+				}
+				continue
+			case structure.Connection:
+				if conn, ok := partial[v.Address]; ok {
+					v.Items, v.Exchanges = conn.Items, conn.Exchanges
+					delete(partial, v.Address)
+				}
+				release = releases[v.Address]
+				delete(releases, v.Address)
+				c = v
+			}
+			assembled <- c
+			for _, r := range release {
+				r()
+			}
+		}
+	}()
+	return assembled
+}
@@ -23,6 +23,11 @@ func New(data []byte, params int, width int) *NullBitMap {
 	return &NullBitMap{Data: data, Params: params, Width: width}
 }
 
+// Empty creates a bitmap for the columns with none of them null.
+func Empty(paramCount int, width int) *NullBitMap {
+	return New(make([]byte, (paramCount+width)/byteWidth), paramCount, width)
+}
+
 func ReadNullMap(buf io.Reader, paramCount int, width int) (*NullBitMap, error) {
 	neededBytes := (paramCount + width) / byteWidth
 	data := make([]byte, neededBytes)
@@ -35,6 +40,17 @@ func ReadNullMap(buf io.Reader, paramCount int, width int) (*NullBitMap, error)
 }
 
 func (nm *NullBitMap) IsNull(column int) bool {
+	i, mask := nm.position(column)
+	return nm.Data[i]&mask > 0
+}
+
+// SetNull marks the column as null.
+func (nm *NullBitMap) SetNull(column int) {
+	i, mask := nm.position(column)
+	nm.Data[i] |= mask
+}
+
+func (nm *NullBitMap) position(column int) (int, byte) {
 	// FIXME: am I picking out the correct bit?
 	// expecting column to start at 0
 	if column >= nm.Params {
@@ -47,8 +63,7 @@ func (nm *NullBitMap) IsNull(column int) bool {
 
 	bit := column % byteWidth
 	i := column / byteWidth
-	mask := byte(1 << bit)
-	return nm.Data[i]&mask > 0
+	return i, byte(1 << bit)
 }
 
 func (nm *NullBitMap) String() string {
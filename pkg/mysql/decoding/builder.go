@@ -2,7 +2,6 @@ package decoding
 
 import (
 	"io"
-	"sort"
 	"sync"
 	"time"
 
@@ -11,8 +10,6 @@ import (
 	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
 )
 
-const bothSides = 2
-
 type ConnectionBuilder interface {
 	AddToConnection(
 		request bool, seen []time.Time, typeName string, item interface{},
@@ -21,183 +18,308 @@ type ConnectionBuilder interface {
 	JustSeenGreeting() bool
 	PreviousRequestType() string
 	ParamsForQuery(query uint32) uint16
+	StatementQuery(statementID uint32) string
+	QueryAttributes() bool
+	OptionalMetadata() bool
+	DeprecateEOF() bool
+	CachedColumns() []structure.ColumnInfo
+	Capabilities() structure.ClientCapabilities
+	Authenticating() bool
 }
 
 type MySQLConnectionBuilder struct {
 	Address             tcp.ConnectionAddress
 	Readers             *MySQLConnectionReaders
-	Requests            []structure.Transmission
-	Responses           []structure.Transmission
+	items               []structure.Transmission
+	timings             []itemTiming
+	first               int
+	sessions            sessions
+	transactions        transactions
+	summary             connectionSummary
+	requestTiming       itemTiming
+	responseTiming      itemTiming
+	lastRequest         int
 	compressed          bool
+	capabilities        structure.ClientCapabilities
+	authenticating      bool
+	queryAttributes     bool
+	optionalMetadata    bool
+	deprecateEOF        bool
 	previousRequestType string
+	previousStatementID uint32
 	justSeenGreeting    bool
 	queryParams         map[uint32]uint16
+	preparing           string
+	statementQueries    map[uint32]string
+	statementColumns    map[uint32][]structure.ColumnInfo
+	session             sessionState
 	requestBuffer       *packet.Buffer
 	responseBuffer      *packet.Buffer
-	readsCompleted      int
-	decoded             bool
-	completed           chan interface{}
-	mu                  sync.Mutex
-	noSort              bool
+	requests            *packetQueue
+	responses           *packetQueue
+	rqd                 *RequestDecoder
+	resd                *ResponseDecoder
+	requestDecoder      io.Writer
+	responseDecoder     io.Writer
+	exchange            int
+	awaitingResponse    bool
+	compressionSet      bool
+	// the first response after compression is turned on is
+	// uncompressed.
+	responsesSinceCompression int
+	requestsDone              bool
+	responsesDone             bool
+	decoded                   bool
+	completed                 chan interface{}
+	mu                        sync.Mutex
 }
 
 func NewBuilder(
 	address tcp.ConnectionAddress,
 	readers *MySQLConnectionReaders,
-	noSort bool,
 	completed chan interface{},
 ) *MySQLConnectionBuilder {
-	return &MySQLConnectionBuilder{
-		Address:        address,
-		Readers:        readers,
-		requestBuffer:  &packet.Buffer{},
-		responseBuffer: &packet.Buffer{},
-		queryParams:    make(map[uint32]uint16),
-		noSort:         noSort,
-		completed:      completed,
+	b := &MySQLConnectionBuilder{
+		Address:          address,
+		Readers:          readers,
+		requestBuffer:    &packet.Buffer{},
+		responseBuffer:   &packet.Buffer{},
+		queryParams:      make(map[uint32]uint16),
+		statementQueries: make(map[uint32]string),
+		statementColumns: make(map[uint32][]structure.ColumnInfo),
+		lastRequest:      -1,
+		exchange:         -1,
+		completed:        completed,
 	}
+
+	// unless we've been asked for the intermediate data there's no
+	// need to keep the stream once it's been decoded.
+	keep := *readers.IntermediateData
+	b.requests = newPacketQueue(b.requestBuffer, keep)
+	b.responses = newPacketQueue(b.responseBuffer, keep)
+
+	b.requestBuffer.SetSpill(readers.spill)
+	b.responseBuffer.SetSpill(readers.spill)
+
+	b.rqd = &RequestDecoder{Emit: &TransmissionEmitter{
+		Request: true,
+		Times:   b.requests,
+		Builder: b,
+	}}
+	b.requestDecoder = b.rqd
+	b.resd = &ResponseDecoder{Emit: &TransmissionEmitter{
+		Request: false,
+		Times:   b.responses,
+		Builder: b,
+	}, Spill: readers.spill}
+	b.responseDecoder = b.resd
+
+	if *readers.RawData {
+		b.requestDecoder, b.rqd.Emit = SetupRawDataEmitter(b.rqd.Emit, b.requestDecoder)
+		b.responseDecoder, b.resd.Emit = SetupRawDataEmitter(b.resd.Emit, b.responseDecoder)
+	}
+	return b
 }
 
 func (b *MySQLConnectionBuilder) AddToConnection(
 	request bool, seen []time.Time, typeName string, item interface{},
 ) {
 	t := structure.Transmission{Data: item, Seen: seen}
-	//nolint:nestif
+	if rawPacket, ok := item.(structure.WithRawPacket); ok {
+		item = rawPacket.Transmission
+	}
 	if request {
-		b.Requests = append(b.Requests, t)
+		if typeName != "DECODE_ERROR" {
+			// any responses to the previous command have all been
+			// seen.
+			b.handOn()
+			b.lastRequest = b.first + len(b.items)
+		}
+		b.timings = append(b.timings, b.requestTiming.take(request, typeName, seen))
+		t.Session = b.session.request(item)
+		b.items = append(b.items, t)
 		b.previousRequestType = typeName
-		if typeName == "Login" {
-			b.compressed = true
-			if rawPacket, ok := item.(structure.WithRawPacket); ok {
-				item = rawPacket.Transmission
-			}
+		switch typeName {
+		case "Login":
 			login := item.(structure.LoginRequest)
+			b.authenticating = true
+			b.capabilities = login.ClientCapabilities
 			b.compressed = login.ClientCapabilities&structure.CCAP_COMPRESS != 0
+			b.queryAttributes = login.ClientCapabilities&structure.CCAP_CLIENT_QUERY_ATTRIBUTES != 0
+			b.deprecateEOF = login.ClientCapabilities&structure.CCAP_CLIENT_DEPRECATE_EOF != 0
+			b.optionalMetadata =
+				login.ClientCapabilities&structure.CCAP_CLIENT_OPTIONAL_RESULTSET_METADATA != 0 ||
+					login.ExtendedCapabilities&structure.MARIADB_CLIENT_CACHE_METADATA != 0
+		case "SSL request":
+			// the rest is encrypted so it's no good trying to read the
+			// login from it.
+			b.justSeenGreeting = false
+		case "Prepare":
+			b.preparing = item.(structure.Request).Query
+		case "Execute":
+			b.previousStatementID = item.(structure.ExecuteRequest).StatementID
+		case reqChangeUser.String():
+			b.authenticating = true
+			b.resetSession()
+		case reqResetConnection.String():
+			b.resetSession()
 		}
 	} else {
-		b.Responses = append(b.Responses, t)
+		b.timings = append(b.timings, b.responseTiming.take(request, typeName, seen))
+		if b.lastRequest >= 0 {
+			// responses are decoded straight after the request
+			// they answer.
+			request := b.lastRequest
+			t.ResponseTo = &request
+		}
+		b.session.response(item)
+		b.items = append(b.items, t)
 		b.justSeenGreeting = typeName == "Greeting"
-		if typeName == "PREPARE_OK" {
-			if rawPacket, ok := item.(structure.WithRawPacket); ok {
-				item = rawPacket.Transmission
-			}
-			// might be neat to store more info, and also be able to join up to
-			// the query too.
+		switch typeName {
+		case "OK", "Error":
+			b.authenticating = false
+		case "PREPARE_OK":
 			prepare := item.(structure.PrepareOKResponse)
 			b.queryParams[prepare.StatementID] = prepare.NumParams
+			b.statementQueries[prepare.StatementID] = b.preparing
+			if len(prepare.Columns) > 0 {
+				b.statementColumns[prepare.StatementID] = prepare.Columns
+			}
+		case "SQL results":
+			// remember the latest metadata in case the server decides not
+			// to send it next time.
+			results := item.(structure.ResultSetResponse)
+			if b.previousRequestType == "Execute" && !results.CachedColumns {
+				b.statementColumns[b.previousStatementID] = results.Columns
+			}
 		}
 	}
 }
 
-//nolint:gocognit
-func (b *MySQLConnectionBuilder) DecodeConnection() {
-	b.mu.Lock()
-	defer b.mu.Unlock()
-	if b.decoded {
-		return
-	}
-
-	var reqE, resE Emitter
-	reqE = &TransmissionEmitter{
-		Request: true,
-		Times:   b.requestBuffer,
-		Builder: b,
-	}
-	resE = &TransmissionEmitter{
-		Request: false,
-		Times:   b.responseBuffer,
-		Builder: b,
-	}
-
-	var requestDecoder, responseDecoder io.Writer
-	rqd := &RequestDecoder{Emit: reqE}
-	requestDecoder = rqd
-	resd := &ResponseDecoder{Emit: resE}
-	responseDecoder = resd
-
-	if *b.Readers.RawData {
-		requestDecoder, rqd.Emit = SetupRawDataEmitter(rqd.Emit, requestDecoder)
-		responseDecoder, resd.Emit = SetupRawDataEmitter(resd.Emit, responseDecoder)
-	}
-
-	// now loop through the packets and emit
-	// them to the decoders in order we saw
-	// them.
-	var requestPacket, responsePacket *packet.Packet
-
-	reqSplitter := packet.NewSplitter(requestDecoder)
-	resSplitter := packet.NewSplitter(responseDecoder)
+// resetSession forgets the state that doesn't survive the connection being
+// handed on by a connection pool.
+func (b *MySQLConnectionBuilder) resetSession() {
+	b.queryParams = make(map[uint32]uint16)
+	b.statementQueries = make(map[uint32]string)
+	b.statementColumns = make(map[uint32][]structure.ColumnInfo)
+	b.previousStatementID = 0
+}
 
-	compressionSet := false
-	responsesSinceCompression := 0
+// decodeAvailable decodes as much of the conversation as the data read so
+// far allows.  When only one side has data waiting the protocol state is
+// used to decide whether it's safe to carry on, otherwise it waits for the
+// other side to catch up.
+func (b *MySQLConnectionBuilder) decodeAvailable() {
 	for {
-		requestPacket = b.requestBuffer.CurrentPacket()
-		responsePacket = b.responseBuffer.CurrentPacket()
-
-		if responsePacket == nil && requestPacket == nil {
-			break
+		requestPacket, badChunk, err := b.requests.Peek()
+		if err != nil {
+			b.requestDecodeError(err, badChunk)
+			continue
 		}
-
-		if b.compressed && !compressionSet {
-			reqSplitter.CompressionDetected()
-			compressionSet = true
+		responsePacket, badChunk, err := b.responses.Peek()
+		if err != nil {
+			b.responseDecodeError(err, badChunk)
+			continue
 		}
 
-		var writeRequest, writeResponse bool
+		var response bool
 		switch {
-		case responsePacket == nil:
-			writeRequest = true
-		case requestPacket == nil:
-			writeResponse = true
-		case responsePacket.FirstSeen().Before(requestPacket.FirstSeen()):
-			writeResponse = true
-		default:
-			writeRequest = true
-		}
-
-		switch {
-		case writeRequest:
-			if _, err := reqSplitter.Write(requestPacket.Data); err != nil && err != io.EOF {
-				rqd.Emit.Transmission("DECODE_ERROR",
-					structure.DecodeError{
-						CompressionOn:     b.compressed,
-						DecodeError:       err,
-						DecodeErrorString: err.Error(),
-						DecoderState:      rqd.String(),
-						Direction:         "Request",
-						JustSeenGreeting:  b.justSeenGreeting,
-						Packet:            requestPacket,
-					},
-				)
-			}
-			b.requestBuffer.Next()
-		case writeResponse:
-			if _, err := resSplitter.Write(responsePacket.Data); err != nil && err != io.EOF {
-				resd.Emit.Transmission("DECODE_ERROR",
-					structure.DecodeError{
-						CompressionOn:       b.compressed,
-						DecodeError:         err,
-						DecodeErrorString:   err.Error(),
-						DecoderState:        resd.String(),
-						Direction:           "Response",
-						Packet:              responsePacket,
-						PreviousRequestType: b.previousRequestType,
-					},
-				)
+		case requestPacket == nil && responsePacket == nil:
+			return
+		case requestPacket == nil && !b.requestsDone:
+			if !responseDue(responsePacket, b.exchange, b.awaitingResponse) {
+				return
 			}
-			b.responseBuffer.Next()
-			if compressionSet {
-				responsesSinceCompression++
-				// first response from server is uncompressed
-				if responsesSinceCompression == 1 {
-					resSplitter.CompressionDetected()
-				}
+			response = true
+		case responsePacket == nil && !b.responsesDone:
+			if !requestDue(requestPacket, b.exchange, b.awaitingResponse) ||
+				b.resd.State != start {
+				return
 			}
 		default:
-			panic("wat")
+			response = responseNext(requestPacket, responsePacket, b.exchange, b.awaitingResponse)
+		}
+
+		if response {
+			b.decodeResponse()
+		} else {
+			b.decodeRequest()
 		}
 	}
-	resd.FlushResponse()
+}
+
+func (b *MySQLConnectionBuilder) decodeRequest() {
+	p := b.requests.Next()
+	b.requestTiming.add(p)
+	b.exchange = int(p.Data[packet.PacketNo])
+	b.awaitingResponse = expectsResponse(p)
+	if !b.awaitingResponse {
+		b.exchange = noExchange
+	}
+	if _, err := b.requestDecoder.Write(p.Data); err != nil && err != io.EOF {
+		b.requestDecodeError(err, p)
+	}
+	if b.compressed && !b.compressionSet {
+		b.requests.splitter.CompressionDetected()
+		b.compressionSet = true
+	}
+}
+
+func (b *MySQLConnectionBuilder) decodeResponse() {
+	p := b.responses.Next()
+	b.responseTiming.add(p)
+	b.exchange = int(p.Data[packet.PacketNo])
+	b.awaitingResponse = false
+	if _, err := b.responseDecoder.Write(p.Data); err != nil && err != io.EOF {
+		b.responseDecodeError(err, p)
+	}
+	if b.compressionSet {
+		b.responsesSinceCompression++
+		// first response from server is uncompressed
+		if b.responsesSinceCompression == 1 {
+			b.responses.splitter.CompressionDetected()
+		}
+	}
+}
+
+func (b *MySQLConnectionBuilder) requestDecodeError(err error, p *packet.Packet) {
+	b.rqd.Emit.Transmission("DECODE_ERROR",
+		structure.DecodeError{
+			CompressionOn:     b.compressed,
+			DecodeError:       err,
+			DecodeErrorString: err.Error(),
+			DecoderState:      b.rqd.String(),
+			Direction:         "Request",
+			JustSeenGreeting:  b.justSeenGreeting,
+			Packet:            p,
+		},
+	)
+}
+
+func (b *MySQLConnectionBuilder) responseDecodeError(err error, p *packet.Packet) {
+	b.resd.Emit.Transmission("DECODE_ERROR",
+		structure.DecodeError{
+			CompressionOn:       b.compressed,
+			DecodeError:         err,
+			DecodeErrorString:   err.Error(),
+			DecoderState:        b.resd.String(),
+			Direction:           "Response",
+			Packet:              p,
+			PreviousRequestType: b.previousRequestType,
+		},
+	)
+}
+
+// finish decodes whatever is left once both sides of the connection have
+// been read and sends the connection on.
+func (b *MySQLConnectionBuilder) finish() {
+	if b.decoded {
+		return
+	}
+	b.decodeAvailable()
+	b.requests.Done()
+	b.responses.Done()
+	b.resd.FlushResponse()
 	b.decoded = true
 	if !*b.Readers.IntermediateData {
 		// don't need to hang onto these.
@@ -207,12 +329,12 @@ func (b *MySQLConnectionBuilder) DecodeConnection() {
 	// only emit the incomplete packets if we're after raw data.
 	// for things that aren't MySQL we end up basically emitting all
 	// the data as an incomplete packet which spams the transcript.
-	if *b.Readers.RawData && resSplitter.IncompletePacket() {
+	if *b.Readers.RawData && b.responses.splitter.IncompletePacket() {
 		err := packet.ErrIncompletePacket
 		p := &packet.Packet{
-			Data: resSplitter.Bytes(),
+			Data: b.responses.splitter.Bytes(),
 		}
-		resd.Emit.Transmission("DECODE_ERROR",
+		b.resd.Emit.Transmission("DECODE_ERROR",
 			structure.DecodeError{
 				CompressionOn:       b.compressed,
 				DecodeError:         err,
@@ -224,12 +346,12 @@ func (b *MySQLConnectionBuilder) DecodeConnection() {
 			},
 		)
 	}
-	if *b.Readers.RawData && reqSplitter.IncompletePacket() {
+	if *b.Readers.RawData && b.requests.splitter.IncompletePacket() {
 		err := packet.ErrIncompletePacket
 		p := &packet.Packet{
-			Data: reqSplitter.Bytes(),
+			Data: b.requests.splitter.Bytes(),
 		}
-		rqd.Emit.Transmission("DECODE_ERROR",
+		b.rqd.Emit.Transmission("DECODE_ERROR",
 			structure.DecodeError{
 				CompressionOn:       b.compressed,
 				DecodeError:         err,
@@ -242,29 +364,93 @@ func (b *MySQLConnectionBuilder) DecodeConnection() {
 		)
 	}
 
-	var items []structure.Transmission
-	items = append(items, b.Requests...)
-	items = append(items, b.Responses...)
-
-	if !b.noSort {
-		sort.Slice(items, func(i, j int) bool {
-			if len(items[i].Seen) > 0 && len(items[j].Seen) > 0 {
-				return items[i].Seen[0].Before(items[j].Seen[0])
-			} else if len(items[i].Seen) > 0 {
-				return true
-			}
-			return false
-		})
-	}
-
+	b.handOn()
 	b.completed <- structure.Connection{
 		Address:            b.Address,
-		Items:              items,
+		Sessions:           b.sessions.end(),
+		Transactions:       b.transactions.end(),
+		Summary:            b.summary.end(),
 		RawRequestPackets:  b.requestBuffer,
 		RawResponsePackets: b.responseBuffer,
 	}
 }
 
+// handOn passes on the items decoded since it was last called, now the
+// exchanges they make up are complete, and forgets about them.  The items
+// still held start at index first within the connection.
+func (b *MySQLConnectionBuilder) handOn() {
+	if len(b.items) == 0 {
+		return
+	}
+	exchanges := buildExchanges(b.items, b.timings, b.first)
+	for _, e := range exchanges {
+		b.transactions.add(b.items, b.first, e)
+	}
+	b.sessions.add(b.items, b.first)
+	b.summary.add(b.items, exchanges)
+	items := structure.ConnectionItems{
+		Address:   b.Address,
+		First:     b.first,
+		Items:     b.items,
+		Exchanges: exchanges,
+	}
+	if reserved := b.resd.takeSent(); reserved > 0 {
+		store := b.Readers.spill
+		items.Release = func() { store.Release(reserved) }
+	}
+	b.completed <- items
+	b.first += len(b.items)
+	b.items, b.timings = nil, nil
+}
+
+// sessions looks for the points where a connection was handed on to a new
+// user of the connection via COM_CHANGE_USER or COM_RESET_CONNECTION.
+type sessions struct {
+	found  []structure.Session
+	reused bool
+}
+
+// add checks the items, which start at index first within the connection.
+func (s *sessions) add(items []structure.Transmission, first int) {
+	for i, t := range items {
+		data := t.Data
+		if rawPacket, ok := data.(structure.WithRawPacket); ok {
+			data = rawPacket.Transmission
+		}
+		var session structure.Session
+		switch v := data.(type) {
+		case structure.LoginRequest:
+			session = structure.Session{Reason: v.Type, Username: v.Username, Database: v.Database}
+		case structure.ChangeUserRequest:
+			session = structure.Session{Reason: v.Type, Username: v.Username, Database: v.Database}
+			s.reused = true
+		case structure.Request:
+			if v.Type != reqResetConnection.String() {
+				continue
+			}
+			// same user, same database.
+			session = structure.Session{Reason: v.Type}
+			if len(s.found) > 0 {
+				session.Username = s.found[len(s.found)-1].Username
+				session.Database = s.found[len(s.found)-1].Database
+			}
+			s.reused = true
+		default:
+			continue
+		}
+		session.FirstItem = first + i
+		s.found = append(s.found, session)
+	}
+}
+
+// end gives the sessions once the connection is over, if it was reused.
+func (s *sessions) end() []structure.Session {
+	if !s.reused {
+		return nil
+	}
+	return s.found
+}
+
 func (b *MySQLConnectionBuilder) PreviousRequestType() string {
 	return b.previousRequestType
 }
@@ -273,6 +459,37 @@ func (b *MySQLConnectionBuilder) Compressed() bool {
 	return b.compressed
 }
 
+func (b *MySQLConnectionBuilder) QueryAttributes() bool {
+	return b.queryAttributes
+}
+
+func (b *MySQLConnectionBuilder) OptionalMetadata() bool {
+	return b.optionalMetadata
+}
+
+func (b *MySQLConnectionBuilder) DeprecateEOF() bool {
+	return b.deprecateEOF
+}
+
+// CachedColumns returns the column definitions last seen for the statement
+// being executed.
+func (b *MySQLConnectionBuilder) CachedColumns() []structure.ColumnInfo {
+	if b.previousRequestType != "Execute" {
+		return nil
+	}
+	return b.statementColumns[b.previousStatementID]
+}
+
+func (b *MySQLConnectionBuilder) Capabilities() structure.ClientCapabilities {
+	return b.capabilities
+}
+
+// Authenticating is true between a Login or COM_CHANGE_USER and the OK or
+// Error that ends the authentication exchange.
+func (b *MySQLConnectionBuilder) Authenticating() bool {
+	return b.authenticating
+}
+
 func (b *MySQLConnectionBuilder) JustSeenGreeting() bool {
 	return b.justSeenGreeting
 }
@@ -284,21 +501,58 @@ func (b *MySQLConnectionBuilder) ParamsForQuery(query uint32) uint16 {
 	return 0
 }
 
-func (b *MySQLConnectionBuilder) ResponsePacketBuffer(t packet.TimesSeen) *packet.Buffer {
-	b.responseBuffer.SetTimes(t)
-	return b.responseBuffer
+// StatementQuery returns the SQL a statement was prepared with.
+func (b *MySQLConnectionBuilder) StatementQuery(statementID uint32) string {
+	return b.statementQueries[statementID]
 }
 
-func (b *MySQLConnectionBuilder) RequestPacketBuffer(t packet.TimesSeen) *packet.Buffer {
+// RequestWriter returns the writer for the client side of the connection.
+func (b *MySQLConnectionBuilder) RequestWriter(t packet.TimesSeen) io.WriteCloser {
 	b.requestBuffer.SetTimes(t)
-	return b.requestBuffer
+	return &streamWriter{builder: b, buffer: b.requestBuffer, request: true}
 }
 
-func (b *MySQLConnectionBuilder) ReadDone() {
+// ResponseWriter returns the writer for the server side of the connection.
+func (b *MySQLConnectionBuilder) ResponseWriter(t packet.TimesSeen) io.WriteCloser {
+	b.responseBuffer.SetTimes(t)
+	return &streamWriter{builder: b, buffer: b.responseBuffer}
+}
+
+// streamWriter takes one side of the connection as it's read and decodes
+// as much of the conversation as it can each time more data arrives.
+type streamWriter struct {
+	builder *MySQLConnectionBuilder
+	buffer  *packet.Buffer
+	request bool
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	b := w.builder
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n, err := w.buffer.Write(p)
+	if err != nil {
+		return n, err
+	}
+	b.decodeAvailable()
+	return n, nil
+}
+
+// Close marks this side of the connection as read.  Once both sides are the
+// connection is complete.
+func (w *streamWriter) Close() error {
+	b := w.builder
 	b.mu.Lock()
-	b.readsCompleted++
-	b.mu.Unlock()
-	if b.readsCompleted == bothSides {
-		b.DecodeConnection()
+	defer b.mu.Unlock()
+	if w.request {
+		b.requestsDone = true
+	} else {
+		b.responsesDone = true
+	}
+	if b.requestsDone && b.responsesDone {
+		b.finish()
+	} else {
+		b.decodeAvailable()
 	}
+	return nil
 }
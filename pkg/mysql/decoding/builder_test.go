@@ -0,0 +1,290 @@
+package decoding_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/colinnewell/pcap-cli/tcp"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/decoding"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+	"github.com/google/go-cmp/cmp"
+)
+
+type fixedTime struct {
+	seen time.Time
+}
+
+func (f *fixedTime) Reset() {}
+
+func (f *fixedTime) Seen() []time.Time {
+	return []time.Time{f.seen}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestPipelinedQueries(t *testing.T) {
+	off, budget, dir := false, 0, ""
+	readers := decoding.New(&off, &off, &off, &budget, &dir)
+	completed := make(chan interface{})
+	defer close(completed)
+	connections := decoding.Assemble(completed)
+	b := decoding.NewBuilder(tcp.ConnectionAddress{}, readers, completed)
+
+	// the client sends both queries before the server replies, and the
+	// clock on the server side is behind.
+	start := time.Date(2021, 10, 23, 9, 52, 9, 0, time.UTC)
+	requests := b.RequestWriter(&fixedTime{seen: start})
+	responses := b.ResponseWriter(&fixedTime{seen: start.Add(-time.Second)})
+	if _, err := requests.Write([]byte{
+		0x09, 0x00, 0x00, 0x00, 0x03, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x20, 0x31,
+		0x09, 0x00, 0x00, 0x00, 0x03, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x20, 0x32,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := responses.Write([]byte{
+		0x07, 0x00, 0x00, 0x01, 0x00, 0x00, 0x01, 0x02, 0x00, 0x00, 0x00,
+		0x07, 0x00, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x00, 0x00, 0x00,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	requests.Close()
+	responses.Close()
+
+	conn := (<-connections).(structure.Connection)
+	requestSeen := []time.Time{start}
+	responseSeen := []time.Time{start.Add(-time.Second)}
+	autocommit := true
+	expected := []structure.Transmission{
+		{
+			Data: structure.Request{Type: "Query", Query: "select 1"},
+			Seen: requestSeen,
+		},
+		{
+			Data:       structure.OKResponse{Type: "OK", LastInsertID: 1, ServerStatus: 2},
+			Seen:       responseSeen,
+			ResponseTo: intPtr(0),
+		},
+		{
+			Data: structure.Request{Type: "Query", Query: "select 2"},
+			Seen: requestSeen,
+			// autocommit is known from the first OK.
+			Session: &structure.SessionState{Autocommit: &autocommit},
+		},
+		{
+			Data:       structure.OKResponse{Type: "OK", LastInsertID: 2, ServerStatus: 2},
+			Seen:       responseSeen,
+			ResponseTo: intPtr(2),
+		},
+	}
+	if diff := cmp.Diff(conn.Items, expected); diff != "" {
+		t.Fatalf("Items don't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestResponseReadBeforeRequest(t *testing.T) {
+	off, budget, dir := false, 0, ""
+	readers := decoding.New(&off, &off, &off, &budget, &dir)
+	completed := make(chan interface{})
+	defer close(completed)
+	connections := decoding.Assemble(completed)
+	b := decoding.NewBuilder(tcp.ConnectionAddress{}, readers, completed)
+
+	start := time.Date(2021, 10, 23, 9, 52, 9, 0, time.UTC)
+	requests := b.RequestWriter(&fixedTime{seen: start})
+	responses := b.ResponseWriter(&fixedTime{seen: start.Add(time.Millisecond)})
+
+	// the reader for the server side gets going first, the response
+	// needs to wait for the query it answers.
+	if _, err := responses.Write([]byte{
+		0x07, 0x00, 0x00, 0x01, 0x00, 0x00, 0x01, 0x02, 0x00, 0x00, 0x00,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := requests.Write([]byte{
+		0x09, 0x00, 0x00, 0x00, 0x03, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x20, 0x31,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	responses.Close()
+	requests.Close()
+
+	conn := (<-connections).(structure.Connection)
+	expected := []structure.Transmission{
+		{
+			Data: structure.Request{Type: "Query", Query: "select 1"},
+			Seen: []time.Time{start},
+		},
+		{
+			Data:       structure.OKResponse{Type: "OK", LastInsertID: 1, ServerStatus: 2},
+			Seen:       []time.Time{start.Add(time.Millisecond)},
+			ResponseTo: intPtr(0),
+		},
+	}
+	if diff := cmp.Diff(conn.Items, expected); diff != "" {
+		t.Fatalf("Items don't match (-got +expected):\n%s\n", diff)
+	}
+
+	responded := start.Add(time.Millisecond)
+	exchanges := []structure.Exchange{
+		{
+			Request:         0,
+			Responses:       []int{1},
+			Command:         "Query",
+			RequestStart:    start,
+			RequestEnd:      start,
+			FirstResponse:   &responded,
+			LastResponse:    &responded,
+			TimeToFirstByte: time.Millisecond,
+			TimeToLastByte:  time.Millisecond,
+			RequestBytes:    13,
+			ResponseBytes:   11,
+		},
+	}
+	if diff := cmp.Diff(conn.Exchanges, exchanges); diff != "" {
+		t.Fatalf("Exchanges don't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestItemsHandedOn(t *testing.T) {
+	off, budget, dir := false, 0, ""
+	readers := decoding.New(&off, &off, &off, &budget, &dir)
+	completed := make(chan interface{}, 2)
+	b := decoding.NewBuilder(tcp.ConnectionAddress{}, readers, completed)
+
+	start := time.Date(2021, 10, 23, 9, 52, 9, 0, time.UTC)
+	requests := b.RequestWriter(&fixedTime{seen: start})
+	responses := b.ResponseWriter(&fixedTime{seen: start})
+	if _, err := requests.Write([]byte{
+		0x09, 0x00, 0x00, 0x00, 0x03, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x20, 0x31,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := responses.Write([]byte{
+		0x07, 0x00, 0x00, 0x01, 0x00, 0x00, 0x01, 0x02, 0x00, 0x00, 0x00,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case c := <-completed:
+		t.Fatalf("Nothing should be handed on until the next command: %#v", c)
+	default:
+	}
+
+	// the next command shows the first exchange is over.
+	if _, err := requests.Write([]byte{
+		0x09, 0x00, 0x00, 0x00, 0x03, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x20, 0x32,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	first := (<-completed).(structure.ConnectionItems)
+	if len(first.Items) != 2 || len(first.Exchanges) != 1 || first.First != 0 {
+		t.Fatalf("Expected the first exchange: %#v", first)
+	}
+
+	requests.Close()
+	responses.Close()
+	second := (<-completed).(structure.ConnectionItems)
+	if len(second.Items) != 1 || second.First != 2 || second.Exchanges[0].Request != 2 {
+		t.Fatalf("Expected the second command: %#v", second)
+	}
+	conn := (<-completed).(structure.Connection)
+	if conn.Items != nil || conn.Summary.Commands["Query"] != 2 {
+		t.Fatalf("Expected the rest of the connection: %#v", conn)
+	}
+}
+
+func TestRowsHeldAgainstBudget(t *testing.T) {
+	off, budget, dir := false, 1, t.TempDir()
+	readers := decoding.New(&off, &off, &off, &budget, &dir)
+	defer readers.Close()
+	completed := make(chan interface{}, 3)
+	b := readers.ConnectionBuilder(tcp.ConnectionAddress{}, completed)
+
+	start := time.Date(2021, 10, 23, 9, 52, 9, 0, time.UTC)
+	requests := b.RequestWriter(&fixedTime{seen: start})
+	responses := b.ResponseWriter(&fixedTime{seen: start})
+	query := []byte{0x09, 0x00, 0x00, 0x00, 0x03, 0x63, 0x61, 0x6c, 0x6c, 0x20, 0x62, 0x69, 0x67}
+	if _, err := requests.Write(query); err != nil {
+		t.Fatal(err)
+	}
+	// three result sets of a 400k row each, more than the 1MB budget
+	// between them.
+	if _, err := responses.Write(bigResultSets(3, 400000)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := requests.Write(query); err != nil {
+		t.Fatal(err)
+	}
+	first := (<-completed).(structure.ConnectionItems)
+	spilled := func(items structure.ConnectionItems) []int {
+		var n []int
+		for _, t := range items.Items {
+			if r, ok := t.Data.(structure.ResultSetResponse); ok {
+				n = append(n, r.SpilledResults.Len())
+			}
+		}
+		return n
+	}
+	if diff := cmp.Diff(spilled(first), []int{0, 0, 1}); diff != "" {
+		t.Fatalf("Spilled rows don't match (-got +expected):\n%s\n", diff)
+	}
+	if first.Release == nil {
+		t.Fatal("Expected the items to hold some of the budget")
+	}
+
+	// until the first lot of rows are dropped there's no room.
+	if _, err := responses.Write(bigResultSets(1, 400000)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := requests.Write(query); err != nil {
+		t.Fatal(err)
+	}
+	second := (<-completed).(structure.ConnectionItems)
+	if diff := cmp.Diff(spilled(second), []int{1}); diff != "" {
+		t.Fatalf("Spilled rows don't match (-got +expected):\n%s\n", diff)
+	}
+
+	first.Release()
+	if _, err := responses.Write(bigResultSets(1, 400000)); err != nil {
+		t.Fatal(err)
+	}
+	requests.Close()
+	responses.Close()
+	third := (<-completed).(structure.ConnectionItems)
+	if diff := cmp.Diff(spilled(third), []int{0}); diff != "" {
+		t.Fatalf("Spilled rows don't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+// bigResultSets returns the response to a CALL with count result sets, each
+// with a single row holding a string size bytes long.
+func bigResultSets(count, size int) []byte {
+	var response []byte
+	var seq byte
+	add := func(payload ...byte) {
+		// the sequence numbers carry on through all the result sets.
+		seq++
+		response = append(response, byte(len(payload)), byte(len(payload)>>8), byte(len(payload)>>16), seq)
+		response = append(response, payload...)
+	}
+	column := []byte{
+		0x03, 0x64, 0x65, 0x66, 0x00, 0x00, 0x00, 0x01, 0x61, 0x00, 0x0c, 0x21, 0x00,
+		0xff, 0xff, 0xff, 0x00, 0xfd, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+	row := append([]byte{0xfd, byte(size), byte(size >> 8), byte(size >> 16)}, make([]byte, size)...)
+	for i := 0; i < count; i++ {
+		// the server status says whether more result sets follow.
+		status := byte(0x0a)
+		if i == count-1 {
+			status = 0x02
+		}
+		add(0x01)
+		add(column...)
+		add(0xfe, 0x00, 0x00, status, 0x00)
+		add(row...)
+		add(0xfe, 0x00, 0x00, status, 0x00)
+	}
+	return response
+}
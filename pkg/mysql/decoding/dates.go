@@ -85,11 +85,26 @@ type timeInfo struct {
 	Seconds  uint8
 }
 
+// String gives the time the way MySQL shows it, with the days added to
+// the hours.
+func (t timeInfo) String() string {
+	sign := ""
+	if t.Negative != 0 {
+		sign = "-"
+	}
+	//nolint:mnd
+	return fmt.Sprintf("%s%02d:%02d:%02d", sign, t.Date*24+uint32(t.Hour), t.Minutes, t.Seconds)
+}
+
 type timeInfoMs struct {
 	timeInfo
 	MicroSeconds uint32
 }
 
+func (t timeInfoMs) String() string {
+	return fmt.Sprintf("%s.%06d", t.timeInfo.String(), t.MicroSeconds)
+}
+
 func readDate(buf io.Reader) (interface{}, error) {
 	var d date
 	if err := binary.Read(buf, binary.LittleEndian, &d); err != nil {
@@ -0,0 +1,98 @@
+package decoding
+
+import (
+	"time"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/packet"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+// itemTiming records when the packets making up an item were seen and how
+// many bytes they took up.
+type itemTiming struct {
+	request  bool
+	typeName string
+	start    time.Time
+	end      time.Time
+	bytes    int
+}
+
+// add accounts for a packet fed to the decoder.
+func (t *itemTiming) add(p *packet.Packet) {
+	if len(p.Seen) > 0 {
+		if t.start.IsZero() {
+			t.start = p.Seen[0]
+		}
+		t.end = p.Seen[len(p.Seen)-1]
+	}
+	t.bytes += len(p.Data)
+}
+
+// take hands over the timing gathered for the item just emitted, falling
+// back to the times it was emitted with if there weren't any packets since
+// the last one.
+func (t *itemTiming) take(request bool, typeName string, seen []time.Time) itemTiming {
+	taken := *t
+	*t = itemTiming{}
+	taken.request = request
+	taken.typeName = typeName
+	if taken.start.IsZero() && len(seen) > 0 {
+		taken.start = seen[0]
+		taken.end = seen[len(seen)-1]
+	}
+	return taken
+}
+
+// buildExchanges groups each command with the responses to it.  The items
+// start at index first within the connection.
+func buildExchanges(items []structure.Transmission, timings []itemTiming, first int) []structure.Exchange {
+	var exchanges []structure.Exchange
+	byRequest := make(map[int]int)
+	for i, t := range items {
+		timing := timings[i]
+		if timing.request {
+			if timing.typeName == "DECODE_ERROR" {
+				continue
+			}
+			byRequest[first+i] = len(exchanges)
+			exchanges = append(exchanges, structure.Exchange{
+				Request:      first + i,
+				Command:      timing.typeName,
+				RequestStart: timing.start,
+				RequestEnd:   timing.end,
+				RequestBytes: timing.bytes,
+			})
+			continue
+		}
+		if t.ResponseTo == nil {
+			continue
+		}
+		n, ok := byRequest[*t.ResponseTo]
+		if !ok {
+			continue
+		}
+		e := &exchanges[n]
+		if len(e.Responses) == 0 {
+			firstResponse := timing.start
+			e.FirstResponse = &firstResponse
+			e.TimeToFirstByte = firstResponse.Sub(e.RequestEnd)
+		}
+		last := timing.end
+		e.LastResponse = &last
+		e.TimeToLastByte = last.Sub(e.RequestEnd)
+		e.Responses = append(e.Responses, first+i)
+		e.ResponseBytes += timing.bytes
+		e.Rows += rowCount(t.Data)
+	}
+	return exchanges
+}
+
+func rowCount(data interface{}) int {
+	if rawPacket, ok := data.(structure.WithRawPacket); ok {
+		data = rawPacket.Transmission
+	}
+	if results, ok := data.(structure.ResultSetResponse); ok {
+		return len(results.Results) + results.SpilledResults.Len()
+	}
+	return 0
+}
@@ -0,0 +1,157 @@
+package decoding
+
+import (
+	"time"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/packet"
+)
+
+// noExchange is the sequence state when nothing is in flight, for
+// example after a command the server doesn't answer.
+const noExchange = -2
+
+// packetQueue splits one side of the conversation into MySQL packets as
+// they are needed so that the two sides can be paired up a packet at a
+// time.
+type packetQueue struct {
+	buffer   *packet.Buffer
+	splitter *packet.Splitter
+	packets  []packet.Packet
+	seen     []time.Time
+	current  *packet.Packet
+	keep     bool
+}
+
+// newPacketQueue creates a queue reading from the buffer.  Unless keep is
+// set the chunks are dropped from the buffer once they've been split.
+func newPacketQueue(buffer *packet.Buffer, keep bool) *packetQueue {
+	q := &packetQueue{buffer: buffer, keep: keep}
+	q.splitter = packet.NewSplitter(q)
+	return q
+}
+
+// Write receives complete MySQL packets from the splitter.
+func (q *packetQueue) Write(p []byte) (int, error) {
+	q.packets = append(q.packets, packet.Packet{Data: p, Seen: q.seen})
+	return len(p), nil
+}
+
+// Peek returns the next MySQL packet without consuming it, splitting more of
+// the stream if necessary.  It returns nil once the stream is exhausted.  If
+// a chunk of the stream can't be split it is returned along with the error.
+// More data may still arrive after nil has been returned if the stream
+// hasn't been fully read yet.
+func (q *packetQueue) Peek() (*packet.Packet, *packet.Packet, error) {
+	for len(q.packets) == 0 {
+		current := q.buffer.CurrentPacket()
+		if current == nil {
+			return nil, nil, nil
+		}
+		// take a copy so that if the chunk has been spilled the
+		// buffer doesn't hang onto the data once it's read back.
+		chunk := *current
+		q.seen = chunk.Seen
+		q.buffer.Next()
+		if !q.keep {
+			q.buffer.Discard()
+		}
+		if err := chunk.Load(); err != nil {
+			return nil, &chunk, err
+		}
+		if _, err := q.splitter.Write(chunk.Data); err != nil {
+			return nil, &chunk, err
+		}
+	}
+	return &q.packets[0], nil, nil
+}
+
+// Next moves on to the next packet, making the one just peeked at the
+// current packet for the purposes of Seen.
+func (q *packetQueue) Next() *packet.Packet {
+	p := q.packets[0]
+	q.packets = q.packets[1:]
+	q.current = &p
+	return q.current
+}
+
+// Done is called once everything has been decoded.
+func (q *packetQueue) Done() {
+	q.current = nil
+}
+
+func (*packetQueue) Reset() {
+}
+
+func (q *packetQueue) Seen() []time.Time {
+	if q.current == nil {
+		return []time.Time{}
+	}
+	return q.current.Seen
+}
+
+// responseNext decides which side of the conversation goes next using the
+// sequence ids of the packets.  Within an exchange the sequence id goes up by
+// one with each packet regardless of which side sent it, and a client
+// starts a new command with a sequence id of 0.  The timestamps are only
+// used when that doesn't settle it, as the clocks on either side of the
+// capture aren't necessarily in agreement.
+//
+// With compression the sequence ids can jump, so while a command is still
+// waiting for its response the next packet from the server is assumed to
+// be it.
+func responseNext(request, response *packet.Packet, exchange int, awaitingResponse bool) bool {
+	switch {
+	case response == nil:
+		return false
+	case request == nil:
+		return true
+	}
+
+	requestSeq := request.Data[packet.PacketNo]
+	responseSeq := response.Data[packet.PacketNo]
+	next := byte(exchange + 1)
+	requestFollows := exchange != noExchange && requestSeq == next
+	responseFollows := exchange != noExchange && responseSeq == next
+
+	switch {
+	case responseFollows && !requestFollows:
+		return true
+	case requestFollows && !responseFollows:
+		return false
+	case awaitingResponse:
+		return true
+	case !requestFollows && !responseFollows && requestSeq == 0 && responseSeq != 0:
+		// client is starting a new command.
+		return false
+	}
+	return response.FirstSeen().Before(request.FirstSeen())
+}
+
+// responseDue is true when the protocol says it's the server's turn, so the
+// response can be decoded without waiting to see what the client sends.
+func responseDue(response *packet.Packet, exchange int, awaitingResponse bool) bool {
+	return awaitingResponse ||
+		(exchange != noExchange && response.Data[packet.PacketNo] == byte(exchange+1))
+}
+
+// requestDue is true when the protocol says it's the client's turn.  A new
+// command can only be sent once the previous one has been answered, which
+// the caller also needs to check the response decoder agrees with.
+func requestDue(request *packet.Packet, exchange int, awaitingResponse bool) bool {
+	seq := request.Data[packet.PacketNo]
+	return (exchange != noExchange && seq == byte(exchange+1)) ||
+		(seq == 0 && !awaitingResponse)
+}
+
+// expectsResponse returns false for the commands the server doesn't reply
+// to.
+func expectsResponse(p *packet.Packet) bool {
+	if len(p.Data) <= packet.HeaderLen || p.Data[packet.PacketNo] != 0 {
+		return true
+	}
+	switch CommandCode(p.Data[packet.HeaderLen]) {
+	case reqQuit, reqStmtClose, reqStmtSendLongData:
+		return false
+	}
+	return true
+}
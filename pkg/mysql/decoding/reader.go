@@ -6,26 +6,31 @@ import (
 	"sync"
 
 	"github.com/colinnewell/pcap-cli/tcp"
-	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/packet"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/spill"
 
 	"github.com/google/gopacket"
 	"github.com/google/gopacket/tcpassembly/tcpreader"
 )
 
+const megabyte = 1 << 20
+
 type MySQLConnectionReaders struct {
 	mu               sync.Mutex
 	builders         map[tcp.ConnectionAddress]*MySQLConnectionBuilder
 	IntermediateData *bool
 	RawData          *bool
 	verbose          *bool
-	noSort           *bool
+	memoryBudget     *int
+	spillDir         *string
+	spill            *spill.Store
 }
 
 func New(
 	intermediateData *bool,
 	rawData *bool,
 	verbose *bool,
-	noSort *bool,
+	memoryBudget *int,
+	spillDir *string,
 ) *MySQLConnectionReaders {
 	builders := make(map[tcp.ConnectionAddress]*MySQLConnectionBuilder)
 	return &MySQLConnectionReaders{
@@ -33,7 +38,8 @@ func New(
 		IntermediateData: intermediateData,
 		RawData:          rawData,
 		verbose:          verbose,
-		noSort:           noSort,
+		memoryBudget:     memoryBudget,
+		spillDir:         spillDir,
 	}
 }
 
@@ -59,13 +65,13 @@ func (h *MySQLConnectionReaders) ReadStream(
 	}
 
 	builder := h.ConnectionBuilder(address, completed)
-	var buf *packet.Buffer
+	var buf io.WriteCloser
 	if response {
-		buf = builder.ResponsePacketBuffer(t)
+		buf = builder.ResponseWriter(t)
 	} else {
-		buf = builder.RequestPacketBuffer(t)
+		buf = builder.RequestWriter(t)
 	}
-	defer builder.ReadDone()
+	defer buf.Close()
 
 	for {
 		n, err := io.Copy(buf, t)
@@ -92,10 +98,21 @@ func (h *MySQLConnectionReaders) ConnectionBuilder(
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	if h.spill == nil {
+		// the flags have been parsed by now.
+		h.spill = spill.New(int64(*h.memoryBudget)*megabyte, *h.spillDir)
+	}
+
 	b, ok := h.builders[address]
 	if !ok || b.decoded {
-		b = NewBuilder(address, h, *h.noSort, completed)
+		b = NewBuilder(address, h, completed)
 		h.builders[address] = b
 	}
 	return b
 }
+
+// Close removes anything that was spilled to disk.  It should be called
+// once the output has been written.
+func (h *MySQLConnectionReaders) Close() error {
+	return h.spill.Close()
+}
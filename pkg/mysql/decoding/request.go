@@ -6,14 +6,37 @@ import (
 
 	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/decoding/bitmap"
 	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/packet"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/sqltext"
 	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
 	"github.com/pkg/errors"
 )
 
 const INT24Width = 3
 
+// paramCountAvailable is the COM_STMT_EXECUTE flag that says a parameter
+// count precedes the parameters.  This is how query attributes are sent
+// along with the statement parameters.
+const paramCountAvailable = 0x08
+
+const unsignedParam = 128
+
+type paramType struct {
+	FieldType structure.FieldType
+	ParamFlag byte
+}
+
+// boundParams are the parameter types, and the query attribute names, last
+// sent for a statement.  Executes that don't set the new params bound flag
+// reuse them.
+type boundParams struct {
+	types []paramType
+	names []string
+}
+
 type RequestDecoder struct {
 	Emit Emitter
+
+	bound map[uint32]*boundParams
 }
 
 func (m *RequestDecoder) String() string {
@@ -22,23 +45,71 @@ func (m *RequestDecoder) String() string {
 
 func (m *RequestDecoder) Write(p []byte) (int, error) {
 	// FIXME: check we have enough bytes
+	builder := m.Emit.ConnectionBuilder()
+	if builder.JustSeenGreeting() ||
+		(builder.PreviousRequestType() == "" && p[packet.PacketNo] == 1) {
+		return m.decodeLoginPacket(p)
+	}
+	if builder.Authenticating() {
+		// the rest of the authentication exchange, the contents depend on
+		// the auth plugin.
+		m.Emit.Transmission("Auth response", structure.AuthResponse{
+			Type: "Auth response",
+			Data: p[packet.HeaderLen:],
+		})
+		return len(p), nil
+	}
 	switch t := CommandCode(p[packet.HeaderLen]); t {
 	case reqStmtPrepare:
 		query := p[packet.HeaderLen+1:]
 		m.Emit.Transmission("Prepare", structure.Request{Type: "Prepare", Query: string(query)})
 	case reqQuery:
+		if builder.QueryAttributes() {
+			return m.decodeQueryWithAttributes(p)
+		}
 		query := p[packet.HeaderLen+1:]
 		m.Emit.Transmission("Query", structure.Request{Type: "Query", Query: string(query)})
 	case reqQuit:
 		m.Emit.Transmission("QUIT", structure.Request{Type: "QUIT"})
+	case reqStmtClose:
+		if len(p) >= packet.HeaderLen+5 {
+			delete(m.bound, binary.LittleEndian.Uint32(p[packet.HeaderLen+1:]))
+		}
+		m.Emit.Transmission(t.String(), structure.Request{Type: t.String()})
+	case reqResetConnection:
+		// the server closes all the prepared statements.
+		m.bound = nil
+		m.Emit.Transmission(t.String(), structure.Request{Type: t.String()})
 	case reqStmtExecute:
 		return m.decodeExecute(p)
-	default:
-		builder := m.Emit.ConnectionBuilder()
-		if builder.JustSeenGreeting() ||
-			(builder.PreviousRequestType() == "" && p[packet.PacketNo] == 1) {
-			return m.decodeLoginPacket(p)
+	case reqInitDB:
+		schema := p[packet.HeaderLen+1:]
+		m.Emit.Transmission(t.String(), structure.InitDBRequest{Type: t.String(), Schema: string(schema)})
+	case reqFieldList:
+		return m.decodeFieldList(p)
+	case reqChangeUser:
+		return m.decodeChangeUser(p)
+	case reqProcessKill:
+		return m.decodeProcessKill(p)
+	case reqSetOption:
+		return m.decodeSetOption(p)
+	case reqRefresh:
+		// the flags byte is mandatory, but be lenient.
+		var flags structure.RefreshFlags
+		if len(p) > packet.HeaderLen+1 {
+			flags = structure.RefreshFlags(p[packet.HeaderLen+1])
 		}
+		m.Emit.Transmission(t.String(), structure.RefreshRequest{Type: t.String(), Flags: flags})
+	case reqShutdown:
+		// older clients don't send the level.
+		level := structure.SHUTDOWN_DEFAULT
+		if len(p) > packet.HeaderLen+1 {
+			level = structure.ShutdownLevel(p[packet.HeaderLen+1])
+		}
+		m.Emit.Transmission(t.String(), structure.ShutdownRequest{Type: t.String(), Level: level})
+	default:
+		// COM_DEBUG along with the likes of COM_PING and COM_STATISTICS
+		// have no payload beyond the command.
 		m.Emit.Transmission(t.String(), structure.Request{Type: t.String()})
 	}
 	return len(p), nil
@@ -57,6 +128,17 @@ func (m *RequestDecoder) decodeLoginPacket(p []byte) (int, error) {
 	if err := binary.Read(b, binary.LittleEndian, &v); err != nil {
 		return 0, errors.Wrap(err, "decode-login-packet")
 	}
+	if b.Len() == 0 && v.ClientCapabilities&structure.CCAP_SSL != 0 {
+		// just the header, the client wants TLS first.
+		m.Emit.Transmission("SSL request", structure.SSLRequest{
+			Type:                 "SSL request",
+			ClientCapabilities:   v.ClientCapabilities,
+			Collation:            v.Collation,
+			ExtendedCapabilities: v.ExtendedCapabilities,
+			MaxPacketSize:        v.MaxPacketSize,
+		})
+		return len(p), nil
+	}
 	login.ClientCapabilities = v.ClientCapabilities
 	login.Collation = v.Collation
 	login.ExtendedCapabilities = v.ExtendedCapabilities
@@ -69,11 +151,114 @@ func (m *RequestDecoder) decodeLoginPacket(p []byte) (int, error) {
 
 	login.Username = username
 
+	// the rest is optional depending on the capabilities, and older
+	// clients have been known to be sloppy, so don't treat problems
+	// reading it as fatal.
+	caps := login.ClientCapabilities
+	login.AuthData, err = readAuthData(b, caps)
+	if err == nil && caps&structure.CCAP_CONNECT_WITH_DB != 0 && b.Len() > 0 {
+		login.Database, err = readNulString(b)
+	}
+	if err == nil && caps&structure.CCAP_PLUGIN_AUTH != 0 && b.Len() > 0 {
+		login.AuthPlugin, err = readNulString(b)
+	}
+	if err == nil && caps&structure.CCAP_CONNECT_ATTRS != 0 && b.Len() > 0 {
+		login.Attributes, _ = readConnectAttributes(b)
+	}
+
 	m.Emit.Transmission(login.Type, login)
 
 	return len(p), nil
 }
 
+func (m *RequestDecoder) decodeChangeUser(p []byte) (int, error) {
+	cu := structure.ChangeUserRequest{Type: reqChangeUser.String()}
+	b := bytes.NewBuffer(p[packet.HeaderLen+1:])
+	caps := m.Emit.ConnectionBuilder().Capabilities()
+
+	username, err := readNulString(b)
+	if err != nil {
+		return 0, errors.Wrap(err, "decode-change-user")
+	}
+	cu.Username = username
+
+	// unlike the login packet the length is never lenenc here.
+	if cu.AuthData, err = readAuthData(b, caps&^structure.CCAP_PLUGIN_AUTH_LENENC_CLIENT_DATA); err != nil {
+		return 0, errors.Wrap(err, "decode-change-user")
+	}
+	if cu.Database, err = readNulString(b); err != nil {
+		return 0, errors.Wrap(err, "decode-change-user")
+	}
+	if b.Len() >= 2 {
+		if err := binary.Read(b, binary.LittleEndian, &cu.Collation); err != nil {
+			return 0, errors.Wrap(err, "decode-change-user")
+		}
+	}
+	if caps&structure.CCAP_PLUGIN_AUTH != 0 && b.Len() > 0 {
+		if cu.AuthPlugin, err = readNulString(b); err != nil {
+			return 0, errors.Wrap(err, "decode-change-user")
+		}
+	}
+	if caps&structure.CCAP_CONNECT_ATTRS != 0 && b.Len() > 0 {
+		if cu.Attributes, err = readConnectAttributes(b); err != nil {
+			return 0, errors.Wrap(err, "decode-change-user")
+		}
+	}
+
+	m.Emit.Transmission(cu.Type, cu)
+
+	return len(p), nil
+}
+
+func readAuthData(b *bytes.Buffer, caps structure.ClientCapabilities) ([]byte, error) {
+	switch {
+	case caps&structure.CCAP_PLUGIN_AUTH_LENENC_CLIENT_DATA != 0:
+		return readLenEncBytes(b)
+	case caps&structure.CCAP_SECURE_CONNECTION != 0:
+		length, err := b.ReadByte()
+		if err != nil {
+			return nil, errors.Wrap(err, "read-auth-data")
+		}
+		data := b.Next(int(length))
+		if len(data) < int(length) {
+			return nil, errors.Wrap(errRequestTooFewBytes, "read-auth-data")
+		}
+		return data, nil
+	default:
+		data, err := readNulString(b)
+		if err != nil {
+			return nil, errors.Wrap(err, "read-auth-data")
+		}
+		return []byte(data), nil
+	}
+}
+
+func readConnectAttributes(b *bytes.Buffer) (map[string]string, error) {
+	length, _, err := readLenEncInt(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "read-connect-attributes")
+	}
+	if length > uint64(b.Len()) {
+		return nil, errors.Wrap(errRequestTooManyBytes, "read-connect-attributes")
+	}
+	attrs := bytes.NewBuffer(b.Next(int(length)))
+	values := map[string]string{}
+	for attrs.Len() > 0 {
+		key, err := readLenEncString(attrs)
+		if err != nil {
+			return values, errors.Wrap(err, "read-connect-attributes")
+		}
+		value, err := readLenEncString(attrs)
+		if err != nil {
+			return values, errors.Wrap(err, "read-connect-attributes")
+		}
+		if key != nil && value != nil {
+			values[*key] = *value
+		}
+	}
+	return values, nil
+}
+
 func (m *RequestDecoder) decodeExecute(p []byte) (int, error) {
 	buf := bytes.NewBuffer(p[packet.HeaderLen+1:])
 	hdr := struct {
@@ -91,41 +276,172 @@ func (m *RequestDecoder) decodeExecute(p []byte) (int, error) {
 		IterationCount: hdr.IterationCount,
 	}
 
-	paramCount := m.Emit.ConnectionBuilder().ParamsForQuery(hdr.StatementID)
+	builder := m.Emit.ConnectionBuilder()
+	paramCount := builder.ParamsForQuery(hdr.StatementID)
+	total := uint64(paramCount)
+	withAttributes := builder.QueryAttributes() && hdr.Flags&paramCountAvailable != 0
+	if withAttributes {
+		count, _, err := readLenEncInt(buf)
+		if err != nil {
+			return 0, errors.Wrap(err, "decode-execute")
+		}
+		total = count
+	}
 
-	//nolint:nestif
-	if buf.Len() > 1 && paramCount > 0 {
-		var send uint8
-		nullMap, err := bitmap.ReadNullMap(buf, int(paramCount), bitmap.ExecuteParams)
+	if buf.Len() > 1 && total > 0 {
+		nullMap, bound, values, err := readParams(buf, int(total), withAttributes, m.bound[hdr.StatementID])
 		if err != nil {
-			return 0, err
+			return 0, errors.Wrap(err, "decode-execute")
 		}
 		er.NullMap = nullMap
+		if bound != nil {
+			if m.bound == nil {
+				m.bound = make(map[uint32]*boundParams)
+			}
+			m.bound[hdr.StatementID] = bound
+		}
+		// the query attributes follow on from the regular parameters.
+		for i, val := range values {
+			if i < int(paramCount) {
+				er.Params = append(er.Params, val)
+				continue
+			}
+			if er.Attributes == nil {
+				er.Attributes = make(map[string]interface{})
+			}
+			er.Attributes[bound.names[i]] = val
+		}
+	}
+	if query := builder.StatementQuery(hdr.StatementID); query != "" {
+		er.Query = query
+		er.SQL = sqltext.Inline(query, er.Params)
+	}
+	m.Emit.Transmission(er.Type, er)
 
-		if err := binary.Read(buf, binary.LittleEndian, &send); err != nil {
-			return 0, errors.Wrap(err, "decode-execute")
+	return len(p), nil
+}
+
+func (m *RequestDecoder) decodeFieldList(p []byte) (int, error) {
+	fl := structure.FieldListRequest{Type: reqFieldList.String()}
+	b := bytes.NewBuffer(p[packet.HeaderLen+1:])
+	table, err := readNulString(b)
+	if err != nil {
+		return 0, errors.Wrap(err, "decode-field-list")
+	}
+	fl.Table = table
+	fl.Wildcard = b.String()
+
+	m.Emit.Transmission(fl.Type, fl)
+
+	return len(p), nil
+}
+
+func (m *RequestDecoder) decodeProcessKill(p []byte) (int, error) {
+	kill := structure.ProcessKillRequest{Type: reqProcessKill.String()}
+	b := bytes.NewBuffer(p[packet.HeaderLen+1:])
+	if err := binary.Read(b, binary.LittleEndian, &kill.ConnectionID); err != nil {
+		return 0, errors.Wrap(err, "decode-process-kill")
+	}
+
+	m.Emit.Transmission(kill.Type, kill)
+
+	return len(p), nil
+}
+
+func (m *RequestDecoder) decodeSetOption(p []byte) (int, error) {
+	option := structure.SetOptionRequest{Type: reqSetOption.String()}
+	b := bytes.NewBuffer(p[packet.HeaderLen+1:])
+	if err := binary.Read(b, binary.LittleEndian, &option.Option); err != nil {
+		return 0, errors.Wrap(err, "decode-set-option")
+	}
+
+	m.Emit.Transmission(option.Type, option)
+
+	return len(p), nil
+}
+
+func (m *RequestDecoder) decodeQueryWithAttributes(p []byte) (int, error) {
+	q := structure.Request{Type: "Query"}
+	buf := bytes.NewBuffer(p[packet.HeaderLen+1:])
+
+	count, _, err := readLenEncInt(buf)
+	if err != nil {
+		return 0, errors.Wrap(err, "decode-query-attributes")
+	}
+	// parameter set count, always 1 currently.
+	if _, _, err := readLenEncInt(buf); err != nil {
+		return 0, errors.Wrap(err, "decode-query-attributes")
+	}
+	if count > 0 {
+		_, bound, values, err := readParams(buf, int(count), true, nil)
+		if err != nil {
+			return 0, errors.Wrap(err, "decode-query-attributes")
+		}
+		if len(values) > 0 {
+			q.Attributes = make(map[string]interface{}, len(values))
+			for i, val := range values {
+				q.Attributes[bound.names[i]] = val
+			}
 		}
-		if send == 1 {
-			params := make([]struct {
-				FieldType structure.FieldType
-				ParamFlag byte
-			}, paramCount)
+	}
+	q.Query = buf.String()
 
-			for n := uint16(0); n < paramCount; n++ {
-				if err := binary.Read(buf, binary.LittleEndian, &params[n]); err != nil {
-					return 0, errors.Wrap(err, "decode-execute")
-				}
+	m.Emit.Transmission(q.Type, q)
+
+	return len(p), nil
+}
+
+// readParams reads the null bitmap, parameter types and the values that make
+// up both statement parameters and query attributes.  The types, and so the
+// values are only sent along with the types when the new params bound flag is
+// set, otherwise the types bound earlier, if there are any, are used.
+func readParams(
+	buf *bytes.Buffer, count int, withNames bool, previous *boundParams,
+) (*bitmap.NullBitMap, *boundParams, []interface{}, error) {
+	nullMap, err := bitmap.ReadNullMap(buf, count, bitmap.ExecuteParams)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var send uint8
+	if err := binary.Read(buf, binary.LittleEndian, &send); err != nil {
+		return nil, nil, nil, errors.Wrap(err, "read-params")
+	}
+	bound := previous
+	switch {
+	case send == 1:
+		bound = &boundParams{types: make([]paramType, count), names: make([]string, count)}
+		for n := range bound.types {
+			if err := binary.Read(buf, binary.LittleEndian, &bound.types[n]); err != nil {
+				return nil, nil, nil, errors.Wrap(err, "read-params")
 			}
-			for n := uint16(0); n < paramCount; n++ {
-				val, err := readType(buf, params[n].FieldType, params[n].ParamFlag&128 != 0)
+			if withNames {
+				name, err := readLenEncString(buf)
 				if err != nil {
-					return 0, errors.Wrap(err, "decode-execute")
+					return nil, nil, nil, errors.Wrap(err, "read-params")
+				}
+				if name != nil {
+					bound.names[n] = *name
 				}
-				er.Params = append(er.Params, val)
 			}
 		}
+	case bound == nil || len(bound.types) != count:
+		// the types were bound before the capture started.
+		return nullMap, nil, nil, nil
 	}
-	m.Emit.Transmission(er.Type, er)
+	params := bound.types
 
-	return len(p), nil
+	values := make([]interface{}, count)
+	for n := range params {
+		if nullMap.IsNull(n) {
+			continue
+		}
+		val, err := readType(buf, params[n].FieldType, params[n].ParamFlag&unsignedParam != 0)
+		if err != nil {
+			return nil, nil, nil, errors.Wrap(err, "read-params")
+		}
+		values[n] = val
+	}
+
+	return nullMap, bound, values, nil
 }
@@ -57,6 +57,81 @@ func TestDecodeExecute(t *testing.T) {
 	testRequestDecodeEx(t, e, input, expected)
 }
 
+func TestDecodeExecuteResolved(t *testing.T) {
+	input := []byte{
+		0x15, 0x00, 0x00, 0x00, 0x17, 0x17, 0x00, 0x00, // ........
+		0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x01, // ........
+		0xfe, 0x00, 0x06, 0x4a, 0x6f, 0x62, 0x62, 0x62, // ...Jobbb
+		0x62, // b
+	}
+	expected := []interface{}{
+		structure.ExecuteRequest{
+			Type:           "Execute",
+			StatementID:    23,
+			IterationCount: 1,
+			NullMap: bitmap.New(
+				[]uint8{0}, 1, bitmap.ExecuteParams,
+			),
+			Params: []interface{}{"Jobbbb"},
+			Query:  "SELECT * FROM peeps WHERE name = ? AND note = '?'",
+			SQL:    "SELECT * FROM peeps WHERE name = 'Jobbbb' AND note = '?'",
+		},
+	}
+	e := testEmitter{Builder: &prevRequestBuilder{
+		Params: 1,
+		Query:  "SELECT * FROM peeps WHERE name = ? AND note = '?'",
+	}}
+	testRequestDecodeEx(t, e, input, expected)
+}
+
+func TestDecodeExecuteBoundEarlier(t *testing.T) {
+	inputs := [][]byte{
+		{
+			0x15, 0x00, 0x00, 0x00, 0x17, 0x17, 0x00, 0x00, // ........
+			0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x01, // ........
+			0xfe, 0x00, 0x06, 0x4a, 0x6f, 0x62, 0x62, 0x62, // ...Jobbb
+			0x62, // b
+		},
+		// the same statement again, without the types.
+		{
+			0x10, 0x00, 0x00, 0x00, 0x17, 0x17, 0x00, 0x00, // ........
+			0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, // ........
+			0x03, 0x42, 0x6f, 0x62, // .Bob
+		},
+	}
+	query := "SELECT * FROM peeps WHERE name = ?"
+	expected := []interface{}{
+		structure.ExecuteRequest{
+			Type:           "Execute",
+			StatementID:    23,
+			IterationCount: 1,
+			NullMap:        bitmap.New([]uint8{0}, 1, bitmap.ExecuteParams),
+			Params:         []interface{}{"Jobbbb"},
+			Query:          query,
+			SQL:            "SELECT * FROM peeps WHERE name = 'Jobbbb'",
+		},
+		structure.ExecuteRequest{
+			Type:           "Execute",
+			StatementID:    23,
+			IterationCount: 1,
+			NullMap:        bitmap.New([]uint8{0}, 1, bitmap.ExecuteParams),
+			Params:         []interface{}{"Bob"},
+			Query:          query,
+			SQL:            "SELECT * FROM peeps WHERE name = 'Bob'",
+		},
+	}
+	e := testEmitter{Builder: &prevRequestBuilder{Params: 1, Query: query}}
+	r := decoding.RequestDecoder{Emit: &e}
+	for _, input := range inputs {
+		if _, err := r.Write(input); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if diff := cmp.Diff(e.transmissions, expected); diff != "" {
+		t.Fatalf("Transmission does not match (-got +expected):\n%s\n", diff)
+	}
+}
+
 func TestDecodeExecuteNilParam(t *testing.T) {
 	input := []byte{
 		0x25, 0x00, 0x00, 0x00, 0x17, 0x01, 0x00, 0x00, 0x00,
@@ -127,6 +202,22 @@ func TestDecodeLogin(t *testing.T) {
 			Collation:          8,
 			MaxPacketSize:      1073741824,
 			Username:           "site",
+			AuthData: []byte{
+				0x84, 0x20, 0x6b, 0x76, 0xdb, 0xd1, 0x45, 0xb1, 0x07, 0xfd,
+				0x8f, 0x72, 0xba, 0xd7, 0x24, 0xb9, 0x96, 0x00, 0x78, 0x22,
+			},
+			Database:   "demo",
+			AuthPlugin: "mysql_native_password",
+			Attributes: map[string]string{
+				"_client_name":    "libmariadb",
+				"_client_version": "3.1.7",
+				"_os":             "Linux",
+				"_pid":            "7",
+				"_platform":       "x86_64",
+				"_server_host":    "mysql",
+				"program_name": "starman worker -MCarp::Always -I /opt/insecure-demo/lib/ " +
+					"/opt/insecure-demo/bin/app.psgi",
+			},
 		},
 	}
 	testRequestDecode(t, input, expected)
@@ -153,6 +244,165 @@ func TestDecodeExecWithParams(t *testing.T) {
 	testRequestDecodeEx(t, e, input, expected)
 }
 
+func TestDecodeInitDB(t *testing.T) {
+	input := []byte{
+		0x05, 0x00, 0x00, 0x00, 0x02, 0x64, 0x65, 0x6d, // .....dem
+		0x6f, // o
+	}
+	expected := []interface{}{
+		structure.InitDBRequest{Type: "MYSQL_INIT_DB", Schema: "demo"},
+	}
+	testRequestDecode(t, input, expected)
+}
+
+func TestDecodeFieldList(t *testing.T) {
+	input := []byte{
+		0x08, 0x00, 0x00, 0x00, 0x04, 0x75, 0x73, 0x65, // .....use
+		0x72, 0x73, 0x00, 0x25, // rs.%
+	}
+	expected := []interface{}{
+		structure.FieldListRequest{Type: "MYSQL_FIELD_LIST", Table: "users", Wildcard: "%"},
+	}
+	testRequestDecode(t, input, expected)
+}
+
+func TestDecodeProcessKill(t *testing.T) {
+	input := []byte{
+		0x05, 0x00, 0x00, 0x00, 0x0c, 0x2a, 0x01, 0x00, // .....*..
+		0x00, // .
+	}
+	expected := []interface{}{
+		structure.ProcessKillRequest{Type: "MYSQL_PROCESS_KILL", ConnectionID: 298},
+	}
+	testRequestDecode(t, input, expected)
+}
+
+func TestDecodeSetOption(t *testing.T) {
+	input := []byte{0x03, 0x00, 0x00, 0x00, 0x1b, 0x01, 0x00}
+	expected := []interface{}{
+		structure.SetOptionRequest{Type: "MYSQL_SET_OPTION", Option: structure.MultiStatementsOff},
+	}
+	testRequestDecode(t, input, expected)
+}
+
+func TestDecodeRefresh(t *testing.T) {
+	input := []byte{0x02, 0x00, 0x00, 0x00, 0x07, 0x05}
+	expected := []interface{}{
+		structure.RefreshRequest{Type: "MYSQL_REFRESH", Flags: 5},
+	}
+	testRequestDecode(t, input, expected)
+}
+
+func TestDecodeShutdown(t *testing.T) {
+	input := []byte{0x01, 0x00, 0x00, 0x00, 0x08}
+	expected := []interface{}{
+		structure.ShutdownRequest{Type: "MYSQL_SHUTDOWN", Level: structure.SHUTDOWN_DEFAULT},
+	}
+	testRequestDecode(t, input, expected)
+}
+
+func TestDecodeDebug(t *testing.T) {
+	input := []byte{0x01, 0x00, 0x00, 0x00, 0x0d}
+	expected := []interface{}{
+		structure.Request{Type: "MYSQL_DEBUG"},
+	}
+	testRequestDecode(t, input, expected)
+}
+
+func TestDecodeQueryWithAttributes(t *testing.T) {
+	input := []byte{
+		0x19, 0x00, 0x00, 0x00, 0x03, 0x01, 0x01, 0x00, // ........
+		0x01, 0xfe, 0x00, 0x05, 0x74, 0x72, 0x61, 0x63, // ....trac
+		0x65, 0x03, 0x61, 0x62, 0x63, 0x53, 0x45, 0x4c, // e.abcSEL
+		0x45, 0x43, 0x54, 0x20, 0x31, // ECT 1
+	}
+	expected := []interface{}{
+		structure.Request{
+			Type:       "Query",
+			Query:      "SELECT 1",
+			Attributes: map[string]interface{}{"trace": "abc"},
+		},
+	}
+	e := testEmitter{Builder: &prevRequestBuilder{Attributes: true}}
+	testRequestDecodeEx(t, e, input, expected)
+}
+
+func TestDecodeQueryNoAttributes(t *testing.T) {
+	input := []byte{
+		0x0b, 0x00, 0x00, 0x00, 0x03, 0x00, 0x01, 0x53, // .....SEL
+		0x45, 0x4c, 0x45, 0x43, 0x54, 0x20, 0x31, // ECT 1
+	}
+	expected := []interface{}{
+		structure.Request{Type: "Query", Query: "SELECT 1"},
+	}
+	e := testEmitter{Builder: &prevRequestBuilder{Attributes: true}}
+	testRequestDecodeEx(t, e, input, expected)
+}
+
+func TestDecodeExecuteWithAttributes(t *testing.T) {
+	input := []byte{
+		0x24, 0x00, 0x00, 0x00, 0x17, 0x01, 0x00, 0x00, // $.......
+		0x00, 0x08, 0x01, 0x00, 0x00, 0x00, 0x02, 0x00, // ........
+		0x01, 0x08, 0x00, 0x00, 0xfe, 0x00, 0x05, 0x74, // .......t
+		0x72, 0x61, 0x63, 0x65, 0x21, 0x00, 0x00, 0x00, // race!...
+		0x00, 0x00, 0x00, 0x00, 0x03, 0x61, 0x62, 0x63, // .....abc
+	}
+	expected := []interface{}{
+		structure.ExecuteRequest{
+			Type:           "Execute",
+			StatementID:    1,
+			Flags:          8,
+			IterationCount: 1,
+			NullMap: bitmap.New(
+				[]uint8{0}, 2, bitmap.ExecuteParams,
+			),
+			Params:     []interface{}{int64(33)},
+			Attributes: map[string]interface{}{"trace": "abc"},
+		},
+	}
+	e := testEmitter{Builder: &prevRequestBuilder{Params: 1, Attributes: true}}
+	testRequestDecodeEx(t, e, input, expected)
+}
+
+func TestDecodeChangeUser(t *testing.T) {
+	input := []byte{
+		0x2f, 0x00, 0x00, 0x00, 0x11, 0x73, 0x69, 0x74, // /....sit
+		0x65, 0x00, 0x04, 0x01, 0x02, 0x03, 0x04, 0x64, // e......d
+		0x65, 0x6d, 0x6f, 0x00, 0x2d, 0x00, 0x6d, 0x79, // emo.-.my
+		0x73, 0x71, 0x6c, 0x5f, 0x6e, 0x61, 0x74, 0x69, // sql_nati
+		0x76, 0x65, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x77, // ve_passw
+		0x6f, 0x72, 0x64, 0x00, 0x06, 0x03, 0x5f, 0x6f, // ord..._o
+		0x73, 0x01, 0x78, // s.x
+	}
+	expected := []interface{}{
+		structure.ChangeUserRequest{
+			Type:       "MYSQL_CHANGE_USER",
+			Username:   "site",
+			AuthData:   []byte{1, 2, 3, 4},
+			Database:   "demo",
+			Collation:  45,
+			AuthPlugin: "mysql_native_password",
+			Attributes: map[string]string{"_os": "x"},
+		},
+	}
+	e := testEmitter{Builder: &prevRequestBuilder{
+		PreviousRequest: "Query",
+		ClientCaps: structure.CCAP_SECURE_CONNECTION |
+			structure.CCAP_PLUGIN_AUTH |
+			structure.CCAP_CONNECT_ATTRS,
+	}}
+	testRequestDecodeEx(t, e, input, expected)
+}
+
+func TestDecodeAuthResponse(t *testing.T) {
+	input := []byte{0x04, 0x00, 0x00, 0x03, 0x01, 0x02, 0x03, 0x04}
+	expected := []interface{}{
+		structure.AuthResponse{Type: "Auth response", Data: []byte{1, 2, 3, 4}},
+	}
+	e := testEmitter{Builder: &prevRequestBuilder{PreviousRequest: "MYSQL_CHANGE_USER", Auth: true}}
+	testRequestDecodeEx(t, e, input, expected)
+}
+
 func testRequestDecode(t *testing.T, input []byte, expected []interface{}) {
 	t.Helper()
 
@@ -6,12 +6,15 @@ import (
 	"fmt"
 
 	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/decoding/bitmap"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/errorcodes"
 	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/packet"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/spill"
 	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
 	"github.com/pkg/errors"
 )
 
 var errUnexpectedValue = errors.New("unexpected value")
+var errUnknownColumns = errors.New("column definitions not known")
 
 type readState byte
 
@@ -20,6 +23,7 @@ const (
 	fieldInfo
 	fieldInfoColumns
 	fieldInfoParams
+	fieldList
 	data
 
 	encodedNull         = 0xfb
@@ -28,14 +32,38 @@ const (
 	encodedInNext8Bytes = 0xfe
 )
 
+// The types of session state change in an OK.
+const (
+	sessionTrackSystemVariables            = 0
+	sessionTrackSchema                     = 1
+	sessionTrackStateChange                = 2
+	sessionTrackGTIDs                      = 3
+	sessionTrackTransactionCharacteristics = 4
+	sessionTrackTransactionState           = 5
+)
+
 // ResponseDecoder - dealing with the response.
 type ResponseDecoder struct {
 	Emit Emitter
 
-	Fields    []structure.ColumnInfo
-	State     readState
-	Results   [][]interface{}
-	prepareOK structure.PrepareOKResponse
+	Fields        []structure.ColumnInfo
+	State         readState
+	Results       [][]interface{}
+	prepareOK     structure.PrepareOKResponse
+	columnCount   uint64
+	cachedColumns bool
+	// unknownColumns is set when the server left out the column
+	// definitions and we don't have the right ones cached.
+	unknownColumns bool
+	// Spill is where rows go once the memory budget is used up.
+	Spill   *spill.Store
+	spilled *spill.Rows
+	// reserved is how much of the Spill budget the Results are holding,
+	// and sent how much the result sets already sent on are.
+	reserved int
+	sent     int
+	// serverVersion from the greeting says whose error codes are used.
+	serverVersion string
 }
 
 func (m *ResponseDecoder) String() string {
@@ -63,10 +91,28 @@ func (m *ResponseDecoder) Write(p []byte) (int, error) {
 			}
 			break
 		}
+		if builder.Authenticating() {
+			switch packetType {
+			case structure.MySQLEOF:
+				m.decodeAuthSwitch(p[packet.HeaderLen+1:])
+				return len(p), nil
+			case structure.MySQLAuthMoreData:
+				m.Emit.Transmission("Auth more data", structure.AuthMoreDataResponse{
+					Type: "Auth more data",
+					Data: p[packet.HeaderLen+1:],
+				})
+				return len(p), nil
+			}
+		}
 		switch packetType {
 		case structure.MySQLError:
 			m.decodeError(p)
 		case structure.MySQLEOF:
+			if builder.PreviousRequestType() == reqFieldList.String() {
+				// table with no matching fields.
+				m.Emit.Transmission("Field list", structure.FieldListResponse{Type: "Field list"})
+				break
+			}
 			// check if it's really an EOF
 			m.Emit.Transmission("EOF", structure.Response{Type: "EOF"})
 		case structure.MySQLOK:
@@ -78,9 +124,23 @@ func (m *ResponseDecoder) Write(p []byte) (int, error) {
 			// check if it's really an EOF
 			m.Emit.Transmission("In file", structure.Response{Type: "In file"})
 		default:
-			m.State = fieldInfo
-			m.Fields = []structure.ColumnInfo{}
-			m.Results = [][]interface{}{}
+			switch builder.PreviousRequestType() {
+			case reqStatistics.String():
+				m.Emit.Transmission("Statistics", structure.StatisticsResponse{
+					Type:   "Statistics",
+					Status: string(p[packet.HeaderLen:]),
+				})
+			case reqFieldList.String():
+				// no column count, the column definitions come straight
+				// away.
+				m.State = fieldList
+				m.Fields = []structure.ColumnInfo{}
+				return m.Write(p)
+			default:
+				if err := m.decodeColumnCount(p[packet.HeaderLen:]); err != nil {
+					return 0, errors.Wrap(err, "response-write")
+				}
+			}
 		}
 	case data:
 		if structure.ResponseType(p[packet.HeaderLen]) == structure.MySQLEOF {
@@ -113,13 +173,16 @@ func (m *ResponseDecoder) Write(p []byte) (int, error) {
 				)
 			}
 		}
-		m.Results = append(m.Results, r)
+		m.addRow(r, len(p))
 
-	case fieldInfo, fieldInfoColumns, fieldInfoParams:
+	case fieldInfo, fieldInfoColumns, fieldInfoParams, fieldList:
 		if structure.ResponseType(p[packet.HeaderLen]) == structure.MySQLEOF {
 			switch {
 			case m.State == fieldInfo:
 				m.State = data
+			case m.State == fieldList:
+				m.FlushResponse()
+				m.ResetState()
 			case m.State == fieldInfoParams && m.prepareOK.NumColumns > 0:
 				m.State = fieldInfoColumns
 			default:
@@ -130,38 +193,46 @@ func (m *ResponseDecoder) Write(p []byte) (int, error) {
 		}
 
 		buf := bytes.NewBuffer(p[packet.HeaderLen:])
-		field := structure.ColumnInfo{}
-
-		for _, val := range []*string{
-			&field.Catalog,
-			&field.Schema,
-			&field.TableAlias,
-			&field.Table,
-			&field.ColumnAlias,
-			&field.Column,
-		} {
-			s, err := readLenEncString(buf)
-
-			if err != nil {
-				return 0, errors.Wrap(err, "response-write fieldinfo")
-			}
-
-			if s != nil {
-				*val = *s
-			}
-		}
-
-		if err := binary.Read(buf, binary.LittleEndian, &field.TypeInfo); err != nil {
-			return 0, errors.Wrap(err, "response-write")
+		field, err := readColumnInfo(buf)
+		if err != nil {
+			return 0, errors.Wrap(err, "response-write fieldinfo")
 		}
 
+		deprecateEOF := m.Emit.ConnectionBuilder().DeprecateEOF()
 		switch m.State {
 		case fieldInfoColumns:
 			m.prepareOK.Columns = append(m.prepareOK.Columns, field)
+			if deprecateEOF && len(m.prepareOK.Columns) == int(m.prepareOK.NumColumns) {
+				m.Emit.Transmission("PREPARE_OK", m.prepareOK)
+				m.ResetState()
+			}
 		case fieldInfoParams:
 			m.prepareOK.Params = append(m.prepareOK.Params, field)
+			if deprecateEOF && len(m.prepareOK.Params) == int(m.prepareOK.NumParams) {
+				if m.prepareOK.NumColumns > 0 {
+					m.State = fieldInfoColumns
+				} else {
+					m.Emit.Transmission("PREPARE_OK", m.prepareOK)
+					m.ResetState()
+				}
+			}
+		case fieldList:
+			// COM_FIELD_LIST also sends the default value.
+			if buf.Len() > 0 {
+				def, err := readLenEncString(buf)
+				if err != nil {
+					return 0, errors.Wrap(err, "response-write fieldlist")
+				}
+				field.Default = def
+			}
+			m.Fields = append(m.Fields, field)
 		case fieldInfo:
 			m.Fields = append(m.Fields, field)
+			// without the EOF we need to count the columns to know when
+			// the rows start.
+			if deprecateEOF && len(m.Fields) == int(m.columnCount) {
+				m.State = data
+			}
 		}
 	}
 
@@ -169,6 +240,7 @@ func (m *ResponseDecoder) Write(p []byte) (int, error) {
 }
 
 func (m *ResponseDecoder) DecodeBinaryResult(b *bytes.Buffer) error {
+	size := b.Len()
 	h, err := b.ReadByte()
 	if err != nil {
 		return errors.Wrap(err, "decode-binary-result")
@@ -176,6 +248,10 @@ func (m *ResponseDecoder) DecodeBinaryResult(b *bytes.Buffer) error {
 	if h != 0 {
 		return errors.Wrap(errUnexpectedValue, "decode-binary-result")
 	}
+	if m.unknownColumns {
+		// binary rows can't be read without the column types.
+		return errors.Wrap(errUnknownColumns, "decode-binary-result")
+	}
 
 	// null bitmap
 	nullMap, err := bitmap.ReadNullMap(b, len(m.Fields), bitmap.ResultSetRow)
@@ -203,21 +279,142 @@ func (m *ResponseDecoder) DecodeBinaryResult(b *bytes.Buffer) error {
 			r[i] = val
 		}
 	}
-	m.Results = append(m.Results, r)
+	m.addRow(r, size)
 	return nil
 }
 
+// addRow adds a row to the result set, or spills it to disk if there isn't
+// room for it.  Once a result set starts spilling the rest of it follows so
+// the rows stay in order.
+func (m *ResponseDecoder) addRow(r []interface{}, size int) {
+	if m.spilled == nil && m.Spill.Reserve(size) {
+		m.Results = append(m.Results, r)
+		m.reserved += size
+		return
+	}
+	if m.spilled == nil {
+		m.spilled = &spill.Rows{}
+	}
+	if err := m.spilled.Add(m.Spill, r); err != nil {
+		// hang onto it if it can't be written out.
+		m.Results = append(m.Results, r)
+	}
+}
+
 func (m *ResponseDecoder) FlushResponse() {
 	if m.State == start {
 		// nothing banked up.
 		return
 	}
+	if m.State == fieldList {
+		m.Emit.Transmission("Field list", structure.FieldListResponse{
+			Type:    "Field list",
+			Columns: m.Fields,
+		})
+		return
+	}
 	// flush out all the data we have stored up.
 	m.Emit.Transmission("SQL results", structure.ResultSetResponse{
-		Type:    "SQL results",
-		Columns: m.Fields,
-		Results: m.Results,
+		Type:           "SQL results",
+		Columns:        m.Fields,
+		Results:        m.Results,
+		SpilledResults: m.spilled,
+		CachedColumns:  m.cachedColumns,
 	})
+	// the rows are still in memory, so the budget stays reserved until
+	// they're dropped.
+	m.sent += m.reserved
+	m.reserved = 0
+}
+
+// releaseRows hands back the budget reserved for rows that are being
+// dropped without being sent on.
+func (m *ResponseDecoder) releaseRows() {
+	m.Spill.Release(m.reserved)
+	m.reserved = 0
+}
+
+// takeSent returns how much of the budget the result sets sent on since it
+// was last called are holding, for whoever ends up with them to hand back.
+func (m *ResponseDecoder) takeSent() int {
+	sent := m.sent
+	m.sent = 0
+	return sent
+}
+
+// decodeColumnCount reads the packet that starts a result set.  When
+// optional metadata has been negotiated the count is followed by a flag
+// saying whether the column definitions will be sent.  If they aren't we
+// fall back to the ones the connection has already seen for the statement.
+func (m *ResponseDecoder) decodeColumnCount(p []byte) error {
+	b := bytes.NewBuffer(p)
+	count, _, err := readLenEncInt(b)
+	if err != nil {
+		return errors.Wrap(err, "decode-column-count")
+	}
+	m.columnCount = count
+	m.State = fieldInfo
+	m.Fields = []structure.ColumnInfo{}
+	m.releaseRows()
+	m.Results = [][]interface{}{}
+	m.spilled = nil
+	m.cachedColumns = false
+	m.unknownColumns = false
+
+	builder := m.Emit.ConnectionBuilder()
+	if !builder.OptionalMetadata() || b.Len() == 0 {
+		return nil
+	}
+	metadataFollows, err := b.ReadByte()
+	if err != nil {
+		return errors.Wrap(err, "decode-column-count")
+	}
+	if metadataFollows != 0 {
+		return nil
+	}
+
+	m.cachedColumns = true
+	m.Fields = builder.CachedColumns()
+	if len(m.Fields) != int(count) {
+		// we don't know what they are, but we at least know how many
+		// there are, which is enough for text results.
+		m.Fields = make([]structure.ColumnInfo, count)
+		m.unknownColumns = true
+	}
+	if builder.DeprecateEOF() {
+		m.State = data
+	}
+	// otherwise there is still an EOF to come before the rows.
+	return nil
+}
+
+func readColumnInfo(buf *bytes.Buffer) (structure.ColumnInfo, error) {
+	field := structure.ColumnInfo{}
+
+	for _, val := range []*string{
+		&field.Catalog,
+		&field.Schema,
+		&field.TableAlias,
+		&field.Table,
+		&field.ColumnAlias,
+		&field.Column,
+	} {
+		s, err := readLenEncString(buf)
+
+		if err != nil {
+			return field, errors.Wrap(err, "read-column-info")
+		}
+
+		if s != nil {
+			*val = *s
+		}
+	}
+
+	if err := binary.Read(buf, binary.LittleEndian, &field.TypeInfo); err != nil {
+		return field, errors.Wrap(err, "read-column-info")
+	}
+
+	return field, nil
 }
 
 func (m *ResponseDecoder) ResetState() {
@@ -240,11 +437,32 @@ func (m *ResponseDecoder) decodeError(p []byte) {
 				data = data[6:]
 			}
 			errorMsg.Message = string(data)
+			entry := errorcodes.Lookup(errorCode, errorMsg.State, m.serverVersion)
+			errorMsg.Name, errorMsg.Category = entry.Name, entry.Category
 		}
 	}
 	m.Emit.Transmission(errorMsg.Type, errorMsg)
 }
 
+func (m *ResponseDecoder) decodeAuthSwitch(p []byte) {
+	as := structure.AuthSwitchResponse{Type: "Auth switch"}
+	if len(p) == 0 {
+		// the old style switch request from before plugins.
+		as.AuthPlugin = "mysql_old_password"
+		m.Emit.Transmission(as.Type, as)
+		return
+	}
+	b := bytes.NewBuffer(p)
+	plugin, err := readNulString(b)
+	if err != nil {
+		// no terminator, so it's all plugin name.
+		plugin = string(p)
+	}
+	as.AuthPlugin = plugin
+	as.Data = b.Bytes()
+	m.Emit.Transmission(as.Type, as)
+}
+
 func (m *ResponseDecoder) decodeGreeting(p []byte) error {
 	protocol := p[0]
 	b := bytes.NewBuffer(p[1:])
@@ -271,6 +489,9 @@ func (m *ResponseDecoder) decodeGreeting(p []byte) error {
 	if err != nil {
 		return errors.Wrap(err, "decode-greeting")
 	}
+	// the status flags sit between the two halves of the capabilities.
+	//nolint:mnd
+	b.Next(2)
 	if n, err := b.Read(capabilityBytes[2:4]); err != nil || n < 2 {
 		if err != nil {
 			return errors.Wrap(err, "decode-greeting")
@@ -280,8 +501,8 @@ func (m *ResponseDecoder) decodeGreeting(p []byte) error {
 			fmt.Sprintf("decodeGreeting capabilityBytes part 2 only read %d bytes", b.Len()),
 		)
 	}
-	// FIXME: not sure if this is the best way to decode the capability info
 	capabilities := binary.LittleEndian.Uint32(capabilityBytes[:])
+	m.serverVersion = version
 	m.Emit.Transmission("Greeting", structure.Greeting{
 		Capabilities: structure.ClientCapabilities(capabilities),
 		Collation:    collation,
@@ -318,10 +539,99 @@ func (m *ResponseDecoder) decodeOK(p []byte) error {
 		}
 	}
 	ok.ServerStatus = structure.StatusFlags(serverStatus)
+	if err := m.decodeOKInfo(&ok, b); err != nil {
+		return errors.Wrap(err, "decode-ok")
+	}
 	m.Emit.Transmission(ok.Type, ok)
 	return nil
 }
 
+// decodeOKInfo reads the human readable info at the end of an OK and, when
+// the client asked for session tracking, the changes to the session state.
+// The server only says the state changed with session tracking on so that
+// is believed even when the login wasn't captured.
+func (m *ResponseDecoder) decodeOKInfo(ok *structure.OKResponse, b *bytes.Buffer) error {
+	tracking := m.Emit.ConnectionBuilder().Capabilities()&structure.CCAP_CLIENT_SESSION_TRACK != 0 ||
+		ok.ServerStatus&structure.SERVER_SESSION_STATE_CHANGED != 0
+	if !tracking {
+		ok.Info = b.String()
+		return nil
+	}
+	if b.Len() == 0 {
+		return nil
+	}
+	info, err := readLenEncString(b)
+	if err != nil {
+		return err
+	}
+	if info != nil {
+		ok.Info = *info
+	}
+	if ok.ServerStatus&structure.SERVER_SESSION_STATE_CHANGED == 0 || b.Len() == 0 {
+		return nil
+	}
+	state, err := readLenEncBytes(b)
+	if err != nil {
+		return err
+	}
+	ok.StateChanges, err = decodeStateChanges(bytes.NewBuffer(state))
+	return err
+}
+
+// decodeStateChanges reads the session state changes from an OK.  Each
+// is a type and then the data for it, with the length first so that types
+// that aren't known can be skipped over.
+func decodeStateChanges(b *bytes.Buffer) ([]structure.StateChange, error) {
+	var changes []structure.StateChange
+	for b.Len() > 0 {
+		t, err := b.ReadByte()
+		if err != nil {
+			return nil, errors.Wrap(err, "decode-state-changes")
+		}
+		data, err := readLenEncBytes(b)
+		if err != nil {
+			return nil, errors.Wrap(err, "decode-state-changes")
+		}
+		d := bytes.NewBuffer(data)
+		c := structure.StateChange{}
+		switch t {
+		case sessionTrackSystemVariables:
+			c.Type = structure.StateSystemVariable
+			name, err := readLenEncString(d)
+			if err != nil || name == nil {
+				return nil, errors.Wrap(errUnexpectedValue, "decode-state-changes")
+			}
+			c.Name = *name
+		case sessionTrackSchema:
+			c.Type = structure.StateSchema
+		case sessionTrackStateChange:
+			c.Type = structure.StateChanged
+		case sessionTrackGTIDs:
+			c.Type = structure.StateGTIDs
+			// the encoding the GTIDs are in, only one is defined.
+			d.Next(1)
+		case sessionTrackTransactionCharacteristics:
+			c.Type = structure.StateTransactionCharacteristics
+		case sessionTrackTransactionState:
+			c.Type = structure.StateTransactionState
+		default:
+			c.Type = fmt.Sprintf("unknown %d", t)
+			c.Value = fmt.Sprintf("%x", data)
+			changes = append(changes, c)
+			continue
+		}
+		value, err := readLenEncString(d)
+		if err != nil {
+			return nil, errors.Wrap(err, "decode-state-changes")
+		}
+		if value != nil {
+			c.Value = *value
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
 func (m *ResponseDecoder) decodePrepareOK(p []byte) error {
 	buf := bytes.NewBuffer(p)
 
@@ -343,6 +653,16 @@ func (m *ResponseDecoder) decodePrepareOK(p []byte) error {
 		NumParams:   b.NumParams,
 		Warnings:    b.Warnings,
 	}
+	if m.Emit.ConnectionBuilder().OptionalMetadata() && buf.Len() > 0 {
+		metadataFollows, err := buf.ReadByte()
+		if err != nil {
+			return errors.Wrap(err, "decode-prepare-ok")
+		}
+		if metadataFollows == 0 {
+			m.Emit.Transmission("PREPARE_OK", ok)
+			return nil
+		}
+	}
 	switch {
 	case b.NumParams > 0:
 		m.State = fieldInfoParams
@@ -18,9 +18,34 @@ func TestNotAllowedGreeting(t *testing.T) {
 
 	expected := []interface{}{
 		structure.ErrorResponse{
-			Code:    1130,
-			Type:    "Error",
-			Message: "Host '127.0.0.1' is not allowed to connect to this MySQL server",
+			Code:     1130,
+			Type:     "Error",
+			Message:  "Host '127.0.0.1' is not allowed to connect to this MySQL server",
+			Name:     "ER_HOST_NOT_PRIVILEGED",
+			Category: "permission",
+		},
+	}
+
+	testResponse(t, input, expected)
+}
+
+func TestGreetingCapabilities(t *testing.T) {
+	input := []byte{
+		0x4a, 0x00, 0x00, 0x00, 0x0a, 0x35, 0x2e, 0x37, 0x2e, 0x33, 0x33, 0x00, 0x08, 0x00, 0x00, 0x00, // J....5.7.33.....
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x00, 0xff, 0xf7, 0x21, 0x02, 0x00, 0xff, 0xc1, // ...........!....
+		0x15, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, // ................
+		0x0e, 0x0f, 0x10, 0x11, 0x12, 0x13, 0x14, 0x00, 0x6d, 0x79, 0x73, 0x71, 0x6c, 0x5f, 0x6e, 0x61, // ........mysql_na
+		0x74, 0x69, 0x76, 0x65, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x00, // tive_password.
+	}
+
+	// the upper half of the capabilities follows the status flags (0x0002).
+	expected := []interface{}{
+		structure.Greeting{
+			Capabilities: structure.ClientCapabilities(0xc1fff7ff),
+			Collation:    0x21,
+			Protocol:     0x0a,
+			Type:         "Greeting",
+			Version:      "5.7.33",
 		},
 	}
 
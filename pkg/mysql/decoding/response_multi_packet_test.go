@@ -2,11 +2,13 @@ package decoding_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
 	"testing"
 
 	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/decoding"
 	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/packet"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/spill"
 	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
 	"github.com/google/go-cmp/cmp"
 )
@@ -297,6 +299,179 @@ func TestPrepareOKResponse(t *testing.T) {
 	testResponsePackets(t, e, input, expected)
 }
 
+//nolint:gochecknoglobals
+var idColumn = structure.ColumnInfo{
+	Catalog:     "def",
+	TableAlias:  "users",
+	Table:       "users",
+	Schema:      "demo",
+	Column:      "id",
+	ColumnAlias: "id",
+	TypeInfo: structure.TypeInfo{
+		LengthOfFixedFields: 12,
+		CharacterSetNumber:  63,
+		MaxColumnSize:       11,
+		FieldTypes:          structure.LONG,
+		FieldDetail: structure.DETAIL_NOT_NULL |
+			structure.DETAIL_PRIMARY_KEY |
+			structure.DETAIL_AUTO_INCREMENT |
+			structure.DETAIL_PART_KEY_FLAG,
+	},
+}
+
+func TestResultsWithMetadataNoEOF(t *testing.T) {
+	input := []byte{
+		0x02, 0x00, 0x00, 0x01, 0x01, 0x01, 0x28, 0x00, // ......(.
+		0x00, 0x02, 0x03, 0x64, 0x65, 0x66, 0x04, 0x64, // ...def.d
+		0x65, 0x6d, 0x6f, 0x05, 0x75, 0x73, 0x65, 0x72, // emo.user
+		0x73, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x02, // s.users.
+		0x69, 0x64, 0x02, 0x69, 0x64, 0x0c, 0x3f, 0x00, // id.id.?.
+		0x0b, 0x00, 0x00, 0x00, 0x03, 0x03, 0x42, 0x00, // ......B.
+		0x00, 0x00, 0x02, 0x00, 0x00, 0x03, 0x01, 0x31, // .......1
+		0x07, 0x00, 0x00, 0x04, 0xfe, 0x00, 0x00, 0x02, // ........
+		0x00, 0x00, 0x00, // ...
+	}
+	one := "1"
+	expected := []interface{}{
+		structure.ResultSetResponse{
+			Type:    "SQL results",
+			Columns: []structure.ColumnInfo{idColumn},
+			Results: [][]interface{}{{&one}},
+		},
+	}
+
+	e := testEmitter{Builder: &prevRequestBuilder{
+		PreviousRequest: "Query",
+		Metadata:        true,
+		NoEOF:           true,
+	}}
+
+	testResponsePackets(t, e, input, expected)
+}
+
+func TestTextResultsWithoutMetadata(t *testing.T) {
+	input := []byte{
+		0x02, 0x00, 0x00, 0x01, 0x01, 0x00, 0x02, 0x00, // ........
+		0x00, 0x02, 0x01, 0x31, 0x07, 0x00, 0x00, 0x03, // ...1....
+		0xfe, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, // .......
+	}
+	one := "1"
+	expected := []interface{}{
+		structure.ResultSetResponse{
+			Type:          "SQL results",
+			Columns:       []structure.ColumnInfo{{}},
+			Results:       [][]interface{}{{&one}},
+			CachedColumns: true,
+		},
+	}
+
+	e := testEmitter{Builder: &prevRequestBuilder{
+		PreviousRequest: "Query",
+		Metadata:        true,
+		NoEOF:           true,
+	}}
+
+	testResponsePackets(t, e, input, expected)
+}
+
+func TestExecuteResultsFromCachedMetadata(t *testing.T) {
+	input := []byte{
+		0x02, 0x00, 0x00, 0x01, 0x01, 0x00, 0x05, 0x00, // ........
+		0x00, 0x02, 0xfe, 0x00, 0x00, 0x02, 0x00, 0x06, // ........
+		0x00, 0x00, 0x03, 0x00, 0x00, 0x21, 0x00, 0x00, // .....!..
+		0x00, 0x05, 0x00, 0x00, 0x04, 0xfe, 0x00, 0x00, // ........
+		0x02, 0x00, // ..
+	}
+	expected := []interface{}{
+		structure.ResultSetResponse{
+			Type:          "SQL results",
+			Columns:       []structure.ColumnInfo{idColumn},
+			Results:       [][]interface{}{{int32(33)}},
+			CachedColumns: true,
+		},
+	}
+
+	e := testEmitter{Builder: &prevRequestBuilder{
+		PreviousRequest: "Execute",
+		Metadata:        true,
+		Columns:         []structure.ColumnInfo{idColumn},
+	}}
+
+	testResponsePackets(t, e, input, expected)
+}
+
+func TestExecuteResultsWithoutCachedMetadata(t *testing.T) {
+	input := []byte{
+		0x02, 0x00, 0x00, 0x01, 0x01, 0x00, 0x05, 0x00, // ........
+		0x00, 0x02, 0xfe, 0x00, 0x00, 0x02, 0x00, 0x04, // ........
+		0x00, 0x00, 0x03, 0x00, 0x00, 0x01, 0x31, 0x05, // ......1.
+		0x00, 0x00, 0x04, 0xfe, 0x00, 0x00, 0x02, 0x00, // ........
+	}
+	e := testEmitter{Builder: &prevRequestBuilder{
+		PreviousRequest: "Execute",
+		Metadata:        true,
+	}}
+
+	// without the column types the binary row can't be read.
+	r := decoding.ResponseDecoder{Emit: &e}
+	_, err := packet.Copy(bytes.NewBuffer(input), &r)
+	if err == nil || err == io.EOF {
+		t.Fatalf("Expected an error decoding the row: %#v", e.transmissions)
+	}
+}
+
+func TestResultsSpilled(t *testing.T) {
+	input := []byte{
+		0x02, 0x00, 0x00, 0x01, 0x01, 0x00, 0x02, 0x00, // ........
+		0x00, 0x02, 0x01, 0x31, 0x02, 0x00, 0x00, 0x03, // ...1....
+		0x01, 0x32, 0x07, 0x00, 0x00, 0x04, 0xfe, 0x00, // .2......
+		0x00, 0x02, 0x00, 0x00, 0x00, // .....
+	}
+	e := testEmitter{Builder: &prevRequestBuilder{
+		PreviousRequest: "Query",
+		Metadata:        true,
+		NoEOF:           true,
+	}}
+
+	// only room for the first row.
+	s := spill.New(6, t.TempDir())
+	defer s.Close()
+	r := decoding.ResponseDecoder{Emit: &e, Spill: s}
+	if _, err := packet.Copy(bytes.NewBuffer(input), &r); err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	r.FlushResponse()
+
+	results := e.transmissions[0].(structure.ResultSetResponse)
+	if len(results.Results) != 1 || results.SpilledResults.Len() != 1 {
+		t.Fatalf("Expected one row in memory and one spilled: %#v", results)
+	}
+	all, err := json.Marshal(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var output struct{ Results []interface{} }
+	if err := json.Unmarshal(all, &output); err != nil {
+		t.Fatal(err)
+	}
+	expected := []interface{}{[]interface{}{"1"}, []interface{}{"2"}}
+	if diff := cmp.Diff(output.Results, expected); diff != "" {
+		t.Fatalf("Results don't match (-got +expected):\n%s\n", diff)
+	}
+
+	// the first row is still held once the results are sent on, so the
+	// next result set doesn't get any room in memory.
+	e.transmissions = nil
+	if _, err := packet.Copy(bytes.NewBuffer(input), &r); err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	r.FlushResponse()
+	results = e.transmissions[0].(structure.ResultSetResponse)
+	if len(results.Results) != 0 || results.SpilledResults.Len() != 2 {
+		t.Fatalf("Expected both rows spilled: %#v", results)
+	}
+}
+
 func testResponsePackets(t *testing.T, e testEmitter, input []byte, expected []interface{}) {
 	t.Helper()
 
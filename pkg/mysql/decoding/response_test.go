@@ -80,6 +80,34 @@ func (b *testOneSidedConnectionBuilder) ParamsForQuery(_ uint32) uint16 {
 	return 0
 }
 
+func (b *testOneSidedConnectionBuilder) QueryAttributes() bool {
+	return false
+}
+
+func (b *testOneSidedConnectionBuilder) OptionalMetadata() bool {
+	return false
+}
+
+func (b *testOneSidedConnectionBuilder) DeprecateEOF() bool {
+	return false
+}
+
+func (b *testOneSidedConnectionBuilder) StatementQuery(_ uint32) string {
+	return ""
+}
+
+func (b *testOneSidedConnectionBuilder) CachedColumns() []structure.ColumnInfo {
+	return nil
+}
+
+func (b *testOneSidedConnectionBuilder) Capabilities() structure.ClientCapabilities {
+	return 0
+}
+
+func (b *testOneSidedConnectionBuilder) Authenticating() bool {
+	return false
+}
+
 type testEmitter struct {
 	transmissions []interface{}
 	Builder       decoding.ConnectionBuilder
@@ -197,13 +225,13 @@ func TestOKResponseOnLogin(t *testing.T) {
 		0x40, 0x00, 0x00, 0x00, 0x07, 0x01, 0x05, 0x04, // @.......
 		0x64, 0x65, 0x6d, 0x6f, // demo
 	}
-	// TODO: read more of the trailing info.
 	expected := []interface{}{
 		structure.OKResponse{
 			AffectedRows: 0,
 			LastInsertID: 0,
 			ServerStatus: 0x4002,
 			Type:         "OK",
+			StateChanges: []structure.StateChange{{Type: "schema", Value: "demo"}},
 		},
 	}
 	testResponse(t, input, expected)
@@ -247,10 +275,103 @@ func TestDecodeExecuteOKBug(t *testing.T) {
 	testResponseEx(t, e, input, expected)
 }
 
+func TestStatisticsResponse(t *testing.T) {
+	input := []byte{
+		0x0a, 0x00, 0x00, 0x01, 0x55, 0x70, 0x74, 0x69, // ....Upti
+		0x6d, 0x65, 0x3a, 0x20, 0x34, 0x32, // me: 42
+	}
+	expected := []interface{}{
+		structure.StatisticsResponse{
+			Type:   "Statistics",
+			Status: "Uptime: 42",
+		},
+	}
+	e := testEmitter{
+		Builder: &prevRequestBuilder{PreviousRequest: "MYSQL_STATISTICS"},
+	}
+	testResponseEx(t, e, input, expected)
+}
+
+func TestFieldListResponse(t *testing.T) {
+	input := []byte{
+		0x29, 0x00, 0x00, 0x01, 0x03, 0x64, 0x65, 0x66, // )....def
+		0x04, 0x64, 0x65, 0x6d, 0x6f, 0x05, 0x75, 0x73, // .demo.us
+		0x65, 0x72, 0x73, 0x05, 0x75, 0x73, 0x65, 0x72, // ers.user
+		0x73, 0x02, 0x69, 0x64, 0x02, 0x69, 0x64, 0x0c, // s.id.id.
+		0x3f, 0x00, 0x0b, 0x00, 0x00, 0x00, 0x03, 0x03, // ?.......
+		0x42, 0x00, 0x00, 0x00, 0xfb, // B....
+		0x05, 0x00, 0x00, 0x02, 0xfe, 0x00, 0x00, 0x02, // ........
+		0x00, // .
+	}
+	expected := []interface{}{
+		structure.FieldListResponse{
+			Type: "Field list",
+			Columns: []structure.ColumnInfo{
+				{
+					Catalog:     "def",
+					TableAlias:  "users",
+					Table:       "users",
+					Schema:      "demo",
+					Column:      "id",
+					ColumnAlias: "id",
+					TypeInfo: structure.TypeInfo{
+						LengthOfFixedFields: 12,
+						CharacterSetNumber:  63,
+						MaxColumnSize:       11,
+						FieldTypes:          structure.LONG,
+						FieldDetail: structure.DETAIL_NOT_NULL |
+							structure.DETAIL_PRIMARY_KEY |
+							structure.DETAIL_AUTO_INCREMENT |
+							structure.DETAIL_PART_KEY_FLAG,
+					},
+				},
+			},
+		},
+	}
+	e := testEmitter{
+		Builder: &prevRequestBuilder{PreviousRequest: "MYSQL_FIELD_LIST"},
+	}
+	testResponsePackets(t, e, input, expected)
+}
+
+func TestAuthSwitchResponse(t *testing.T) {
+	input := []byte{
+		0x1a, 0x00, 0x00, 0x02, 0xfe, 0x6d, 0x79, 0x73, // ....mys
+		0x71, 0x6c, 0x5f, 0x6e, 0x61, 0x74, 0x69, 0x76, // ql_nativ
+		0x65, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, // e_passwo
+		0x72, 0x64, 0x00, 0x01, 0x02, 0x03, // rd....
+	}
+	expected := []interface{}{
+		structure.AuthSwitchResponse{
+			Type:       "Auth switch",
+			AuthPlugin: "mysql_native_password",
+			Data:       []byte{1, 2, 3},
+		},
+	}
+	e := testEmitter{Builder: &prevRequestBuilder{PreviousRequest: "MYSQL_CHANGE_USER", Auth: true}}
+	testResponseEx(t, e, input, expected)
+}
+
+func TestAuthMoreDataResponse(t *testing.T) {
+	input := []byte{0x02, 0x00, 0x00, 0x02, 0x01, 0x03}
+	expected := []interface{}{
+		structure.AuthMoreDataResponse{Type: "Auth more data", Data: []byte{3}},
+	}
+	e := testEmitter{Builder: &prevRequestBuilder{PreviousRequest: "Login", Auth: true}}
+	testResponseEx(t, e, input, expected)
+}
+
 type prevRequestBuilder struct {
 	PreviousRequest  string
 	PreviousRequests []string
 	Params           uint16
+	Query            string
+	Attributes       bool
+	Metadata         bool
+	NoEOF            bool
+	Columns          []structure.ColumnInfo
+	ClientCaps       structure.ClientCapabilities
+	Auth             bool
 }
 
 func (b *prevRequestBuilder) AddToConnection(
@@ -279,6 +400,34 @@ func (b *prevRequestBuilder) ParamsForQuery(_ uint32) uint16 {
 	return b.Params
 }
 
+func (b *prevRequestBuilder) QueryAttributes() bool {
+	return b.Attributes
+}
+
+func (b *prevRequestBuilder) OptionalMetadata() bool {
+	return b.Metadata
+}
+
+func (b *prevRequestBuilder) DeprecateEOF() bool {
+	return b.NoEOF
+}
+
+func (b *prevRequestBuilder) StatementQuery(_ uint32) string {
+	return b.Query
+}
+
+func (b *prevRequestBuilder) CachedColumns() []structure.ColumnInfo {
+	return b.Columns
+}
+
+func (b *prevRequestBuilder) Capabilities() structure.ClientCapabilities {
+	return b.ClientCaps
+}
+
+func (b *prevRequestBuilder) Authenticating() bool {
+	return b.Auth
+}
+
 func testResponse(t *testing.T, input []byte, expected []interface{}) {
 	t.Helper()
 
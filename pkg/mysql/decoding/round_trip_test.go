@@ -0,0 +1,488 @@
+package decoding_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/decoding"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/decoding/bitmap"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/encoder"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/packet"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+// These tests encode structures, decode them again and check nothing was
+// lost.  Re-encoding what was decoded should also give the same packets.
+
+func encodeRequest(
+	t *testing.T, caps structure.ClientCapabilities, r interface{},
+) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	e := encoder.New(&b, caps)
+	var err error
+	switch r := r.(type) {
+	case structure.Request:
+		err = e.WriteRequest(r)
+	case structure.ExecuteRequest:
+		err = e.WriteExecute(r)
+	case structure.LoginRequest:
+		e.SetSequence(1)
+		err = e.WriteLogin(r)
+	default:
+		t.Fatalf("unexpected request %#v", r)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func decodeRequest(t *testing.T, builder *prevRequestBuilder, input []byte) interface{} {
+	t.Helper()
+
+	e := testEmitter{Builder: builder}
+	r := decoding.RequestDecoder{Emit: &e}
+	if _, err := r.Write(input); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.transmissions) != 1 {
+		t.Fatalf("expected one request, got %#v", e.transmissions)
+	}
+	return e.transmissions[0]
+}
+
+func roundTripRequest(
+	t *testing.T,
+	builder *prevRequestBuilder,
+	caps structure.ClientCapabilities,
+	r interface{},
+) interface{} {
+	t.Helper()
+
+	encoded := encodeRequest(t, caps, r)
+	decoded := decodeRequest(t, builder, encoded)
+	if diff := cmp.Diff(encodeRequest(t, caps, decoded), encoded); diff != "" {
+		t.Errorf("Re-encoded request doesn't match (-got +expected):\n%s\n", diff)
+	}
+	return decoded
+}
+
+func TestRoundTripRequests(t *testing.T) {
+	for _, r := range []structure.Request{
+		{Type: "Query", Query: "SELECT * FROM peeps"},
+		{Type: "Prepare", Query: "SELECT * FROM peeps WHERE id = ?"},
+		{Type: "QUIT"},
+		{Type: "MYSQL_PING"},
+		{Type: "MYSQL_STATISTICS"},
+	} {
+		got := roundTripRequest(t, &prevRequestBuilder{PreviousRequest: "Query"}, 0, r)
+		if diff := cmp.Diff(got, r); diff != "" {
+			t.Errorf("Request doesn't match (-got +expected):\n%s\n", diff)
+		}
+	}
+}
+
+func TestRoundTripQueryAttributes(t *testing.T) {
+	r := structure.Request{
+		Type:  "Query",
+		Query: "SELECT 1",
+		Attributes: map[string]interface{}{
+			"trace": "abc123",
+			"span":  int64(42),
+		},
+	}
+	got := roundTripRequest(
+		t,
+		&prevRequestBuilder{PreviousRequest: "Query", Attributes: true},
+		structure.CCAP_CLIENT_QUERY_ATTRIBUTES,
+		r,
+	)
+	if diff := cmp.Diff(got, r); diff != "" {
+		t.Errorf("Request doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestRoundTripExecute(t *testing.T) {
+	params := []interface{}{
+		"Jobbbb",
+		nil,
+		int8(-1),
+		uint16(65535),
+		int32(-7),
+		uint64(18446744073709551615),
+		float32(1.5),
+		3.25,
+		struct{ Text string }{Text: "3.46"},
+		struct{ Base64 []byte }{Base64: []byte{0, 1, 2}},
+	}
+	r := structure.ExecuteRequest{
+		Type:           "Execute",
+		StatementID:    23,
+		IterationCount: 1,
+		NullMap:        bitmap.New([]byte{0x02, 0x00}, len(params), bitmap.ExecuteParams),
+		Params:         params,
+	}
+	got := roundTripRequest(
+		t, &prevRequestBuilder{Params: uint16(len(params))}, 0, r,
+	)
+	if diff := cmp.Diff(got, r); diff != "" {
+		t.Errorf("Execute doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestRoundTripExecuteDates(t *testing.T) {
+	// the decoder has its own types for these, so just check they come
+	// back out the same way.
+	r := structure.ExecuteRequest{
+		Type:           "Execute",
+		StatementID:    1,
+		IterationCount: 1,
+		Params: []interface{}{
+			time.Date(2021, 9, 25, 17, 21, 23, 0, time.UTC),
+			time.Date(2021, 9, 25, 17, 21, 23, 500000000, time.UTC),
+			-(26*time.Hour + 3*time.Minute),
+		},
+	}
+	got := roundTripRequest(t, &prevRequestBuilder{
+		Params: 3,
+		Query:  "INSERT INTO demo.dates VALUES (?, ?, ?)",
+	}, 0, r)
+	execute, ok := got.(structure.ExecuteRequest)
+	if !ok {
+		t.Fatalf("expected an execute, got %#v", got)
+	}
+	expected := "INSERT INTO demo.dates VALUES " +
+		"('2021-09-25 17:21:23', '2021-09-25 17:21:23.500000', '-26:03:00')"
+	if diff := cmp.Diff(execute.SQL, expected); diff != "" {
+		t.Errorf("SQL doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestRoundTripLogin(t *testing.T) {
+	r := structure.LoginRequest{
+		Type: "Login",
+		ClientCapabilities: structure.CCAP_CLIENT_MYSQL |
+			structure.CCAP_CONNECT_WITH_DB |
+			structure.CCAP_CLIENT_PROTOCOL_41 |
+			structure.CCAP_SECURE_CONNECTION |
+			structure.CCAP_PLUGIN_AUTH |
+			structure.CCAP_CONNECT_ATTRS |
+			structure.CCAP_PLUGIN_AUTH_LENENC_CLIENT_DATA,
+		Collation:     45,
+		MaxPacketSize: 16777216,
+		Username:      "root",
+		AuthData:      []byte("0123456789abcdefghij"),
+		Database:      "demo",
+		AuthPlugin:    "mysql_native_password",
+		Attributes: map[string]string{
+			"_client_name": "libmysql",
+			"_os":          "Linux",
+		},
+	}
+	got := roundTripRequest(t, &prevRequestBuilder{}, 0, r)
+	if diff := cmp.Diff(got, r); diff != "" {
+		t.Errorf("Login doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+type encodeOptions struct {
+	caps     structure.ClientCapabilities
+	status   structure.StatusFlags
+	binary   bool
+	compress bool
+}
+
+func encodeResponse(t *testing.T, o encodeOptions, responses ...interface{}) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	e := encoder.New(&b, o.caps)
+	e.Status = o.status
+	e.Compress = o.compress
+	e.SetSequence(1)
+	for _, r := range responses {
+		var err error
+		switch r := r.(type) {
+		case structure.Greeting:
+			e.SetSequence(0)
+			err = e.WriteGreeting(r, encoder.Handshake{
+				ConnectionID: 7,
+				Status:       structure.SERVER_STATUS_AUTOCOMMIT,
+				AuthData:     []byte("0123456789abcdefghij"),
+				AuthPlugin:   "mysql_native_password",
+			})
+		case structure.OKResponse:
+			err = e.WriteOK(r)
+		case structure.ErrorResponse:
+			err = e.WriteError(r)
+		case structure.PrepareOKResponse:
+			err = e.WritePrepareOK(r)
+		case structure.ResultSetResponse:
+			err = e.WriteResultSet(r, o.binary)
+		default:
+			t.Fatalf("unexpected response %#v", r)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return b.Bytes()
+}
+
+func decodeResponses(t *testing.T, builder decoding.ConnectionBuilder, input []byte) []interface{} {
+	t.Helper()
+
+	e := testEmitter{Builder: builder}
+	r := decoding.ResponseDecoder{Emit: &e}
+	if _, err := packet.Copy(bytes.NewBuffer(input), &r); err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	r.FlushResponse()
+	return e.transmissions
+}
+
+func roundTripResponses(
+	t *testing.T,
+	builder decoding.ConnectionBuilder,
+	o encodeOptions,
+	responses ...interface{},
+) []interface{} {
+	t.Helper()
+
+	encoded := encodeResponse(t, o, responses...)
+	decoded := decodeResponses(t, builder, encoded)
+	if diff := cmp.Diff(encodeResponse(t, o, decoded...), encoded); diff != "" {
+		t.Errorf("Re-encoded responses don't match (-got +expected):\n%s\n", diff)
+	}
+	return decoded
+}
+
+func TestEncodeMatchesCapture(t *testing.T) {
+	// decoding the captured packets and encoding them again should give
+	// exactly the same bytes back.
+	var captured []byte
+	for _, p := range packets {
+		captured = append(captured, p...)
+	}
+	decoded := decodeResponses(t, &testOneSidedConnectionBuilder{}, captured)
+	encoded := encodeResponse(t, encodeOptions{status: 0x22}, decoded...)
+	if diff := cmp.Diff(encoded, captured); diff != "" {
+		t.Fatalf("Encoded packets don't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestRoundTripGreeting(t *testing.T) {
+	g := structure.Greeting{
+		Capabilities: structure.CCAP_CLIENT_MYSQL |
+			structure.CCAP_CLIENT_PROTOCOL_41 |
+			structure.CCAP_SECURE_CONNECTION |
+			structure.CCAP_MULTI_RESULTS |
+			structure.CCAP_PLUGIN_AUTH |
+			structure.CCAP_CLIENT_DEPRECATE_EOF,
+		Collation: 45,
+		Protocol:  10,
+		Version:   "8.0.36",
+		Type:      "Greeting",
+	}
+	got := roundTripResponses(t, &testOneSidedConnectionBuilder{}, encodeOptions{}, g)
+	if diff := cmp.Diff(got, []interface{}{g}); diff != "" {
+		t.Errorf("Greeting doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestRoundTripOKAndError(t *testing.T) {
+	for _, r := range []interface{}{
+		structure.OKResponse{
+			Type:         "OK",
+			AffectedRows: 3,
+			LastInsertID: 70000,
+			ServerStatus: structure.SERVER_STATUS_AUTOCOMMIT,
+			WarningCount: 1,
+		},
+		structure.ErrorResponse{
+			Type:    "Error",
+			Code:    1146,
+			State:   "42S02",
+			Message: "Table 'demo.test' doesn't exist",
+			// not sent, the decoder looks them up.
+			Name:     "ER_NO_SUCH_TABLE",
+			Category: "client error",
+		},
+	} {
+		got := roundTripResponses(
+			t, &prevRequestBuilder{PreviousRequest: "Query"}, encodeOptions{}, r,
+		)
+		if diff := cmp.Diff(got, []interface{}{r}); diff != "" {
+			t.Errorf("Response doesn't match (-got +expected):\n%s\n", diff)
+		}
+	}
+}
+
+func TestRoundTripSessionTracking(t *testing.T) {
+	caps := structure.CCAP_CLIENT_PROTOCOL_41 | structure.CCAP_CLIENT_SESSION_TRACK
+	ok := structure.OKResponse{
+		Type:         "OK",
+		AffectedRows: 1,
+		ServerStatus: structure.SERVER_STATUS_AUTOCOMMIT | structure.SERVER_SESSION_STATE_CHANGED,
+		Info:         "Rows matched: 1  Changed: 1  Warnings: 0",
+		StateChanges: []structure.StateChange{
+			{Type: "system variable", Name: "sql_mode", Value: "ANSI_QUOTES"},
+			{Type: "schema", Value: "demo"},
+			{Type: "state change", Value: "1"},
+			{Type: "gtids", Value: "3e11fa47-71ca-11e1-9e33-c80aa9429562:23"},
+			{Type: "transaction characteristics", Value: "START TRANSACTION READ ONLY;"},
+			{Type: "transaction state", Value: "T___W___"},
+		},
+	}
+	got := roundTripResponses(
+		t, &prevRequestBuilder{PreviousRequest: "Query", ClientCaps: caps}, encodeOptions{caps: caps}, ok,
+	)
+	if diff := cmp.Diff(got, []interface{}{ok}); diff != "" {
+		t.Errorf("Response doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestRoundTripPrepareOK(t *testing.T) {
+	param := structure.ColumnInfo{
+		Catalog:     "def",
+		ColumnAlias: "?",
+		TypeInfo: structure.TypeInfo{
+			LengthOfFixedFields: 12,
+			CharacterSetNumber:  63,
+			FieldTypes:          structure.VAR_STRING,
+			FieldDetail:         structure.DETAIL_BINARY_COLLATION,
+		},
+	}
+	r := structure.PrepareOKResponse{
+		Type:        "PREPARE_OK",
+		StatementID: 5,
+		NumColumns:  1,
+		NumParams:   2,
+		Columns:     []structure.ColumnInfo{idColumn},
+		Params:      []structure.ColumnInfo{param, param},
+	}
+	got := roundTripResponses(
+		t, &prevRequestBuilder{PreviousRequest: "Prepare"}, encodeOptions{}, r,
+	)
+	if diff := cmp.Diff(got, []interface{}{r}); diff != "" {
+		t.Errorf("PREPARE_OK doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func column(name string, fieldType structure.FieldType, detail structure.FieldDetail) structure.ColumnInfo {
+	return structure.ColumnInfo{
+		Catalog:     "def",
+		Schema:      "demo",
+		TableAlias:  "peeps",
+		Table:       "peeps",
+		ColumnAlias: name,
+		Column:      name,
+		TypeInfo: structure.TypeInfo{
+			LengthOfFixedFields: 12,
+			CharacterSetNumber:  63,
+			MaxColumnSize:       20,
+			FieldTypes:          fieldType,
+			FieldDetail:         detail,
+		},
+	}
+}
+
+func peepsColumns() []structure.ColumnInfo {
+	return []structure.ColumnInfo{
+		column("id", structure.LONG, structure.DETAIL_NOT_NULL|structure.DETAIL_PRIMARY_KEY),
+		column("name", structure.VAR_STRING, 0),
+		column("age", structure.TINY, structure.DETAIL_UNSIGNED),
+		column("balance", structure.DOUBLE, 0),
+		column("visits", structure.LONGLONG, 0),
+	}
+}
+
+func TestRoundTripTextResults(t *testing.T) {
+	cells := []string{"1", "Jo", "33", "1.5", "9223372036854775807", "2", "", "0"}
+	r := structure.ResultSetResponse{
+		Type:    "SQL results",
+		Columns: peepsColumns(),
+		Results: [][]interface{}{
+			{&cells[0], &cells[1], &cells[2], &cells[3], &cells[4]},
+			{&cells[5], &cells[6], (*string)(nil), &cells[7], (*string)(nil)},
+		},
+	}
+	for _, o := range []struct {
+		name    string
+		builder *prevRequestBuilder
+		options encodeOptions
+	}{
+		{
+			name:    "eof",
+			builder: &prevRequestBuilder{PreviousRequest: "Query"},
+		},
+		{
+			name:    "deprecate eof",
+			builder: &prevRequestBuilder{PreviousRequest: "Query", NoEOF: true},
+			options: encodeOptions{caps: structure.CCAP_CLIENT_DEPRECATE_EOF},
+		},
+	} {
+		got := roundTripResponses(t, o.builder, o.options, r)
+		if diff := cmp.Diff(got, []interface{}{r}); diff != "" {
+			t.Errorf("%s results don't match (-got +expected):\n%s\n", o.name, diff)
+		}
+	}
+}
+
+func TestRoundTripBinaryResults(t *testing.T) {
+	r := structure.ResultSetResponse{
+		Type:    "SQL results",
+		Columns: peepsColumns(),
+		Results: [][]interface{}{
+			{int32(1), "Jo", uint8(200), 1.5, int64(-9223372036854775808)},
+			{int32(2), nil, nil, 0.0, int64(5)},
+		},
+	}
+	got := roundTripResponses(
+		t,
+		&prevRequestBuilder{PreviousRequest: "Execute"},
+		encodeOptions{binary: true},
+		r,
+	)
+	if diff := cmp.Diff(got, []interface{}{r}); diff != "" {
+		t.Errorf("Results don't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestCompressedEncoding(t *testing.T) {
+	long := string(bytes.Repeat([]byte("compress me "), 20))
+	r := structure.ResultSetResponse{
+		Type:    "SQL results",
+		Columns: peepsColumns()[:2],
+		Results: [][]interface{}{{&long, &long}},
+	}
+	plain := encodeResponse(t, encodeOptions{}, r)
+	compressed := encodeResponse(t, encodeOptions{compress: true}, r)
+	if bytes.Equal(plain, compressed) {
+		t.Fatal("expected the packets to be compressed")
+	}
+
+	var b bytes.Buffer
+	s := packet.NewSplitter(&b)
+	s.CompressionDetected()
+	// the splitter takes a compressed packet at a time.
+	for len(compressed) > 0 {
+		n, err := s.Write(compressed)
+		if err != nil && err != io.EOF {
+			t.Fatal(err)
+		}
+		if n == 0 {
+			t.Fatal("incomplete compressed packet")
+		}
+		compressed = compressed[n:]
+	}
+	if diff := cmp.Diff(b.Bytes(), plain); diff != "" {
+		t.Fatalf("Decompressed packets don't match (-got +expected):\n%s\n", diff)
+	}
+}
@@ -0,0 +1,205 @@
+package decoding
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/sqltext"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+// sessionState follows the state of the session through the commands and
+// the responses to them so each request can be given the state it was
+// sent in.
+type sessionState struct {
+	state structure.SessionState
+	// snapshot is shared by the requests until the state changes.
+	snapshot *structure.SessionState
+	// pending are the changes the last command makes if it succeeds.
+	pending []structure.StateChange
+}
+
+// request gives the state a command was sent in and notes what it will
+// change.
+func (s *sessionState) request(item interface{}) *structure.SessionState {
+	current := s.current()
+	s.pending = nil
+	switch v := item.(type) {
+	case structure.LoginRequest:
+		s.state = structure.SessionState{User: v.Username, Database: v.Database}
+	case structure.ChangeUserRequest:
+		s.state = structure.SessionState{User: v.Username, Database: v.Database}
+	case structure.InitDBRequest:
+		s.pending = []structure.StateChange{{Type: structure.StateSchema, Value: v.Schema}}
+	case structure.Request:
+		switch v.Type {
+		case "Query":
+			s.pending = stateChanges(v.Query)
+		case reqResetConnection.String():
+			// same user, same database, the variables go back to
+			// their defaults.
+			s.state = structure.SessionState{User: s.state.User, Database: s.state.Database}
+		}
+	}
+	return current
+}
+
+// response makes the changes once a command has succeeded.  Anything the
+// server tracks for us is taken from the OK, along with autocommit from
+// the status.
+func (s *sessionState) response(item interface{}) {
+	switch v := item.(type) {
+	case structure.OKResponse:
+		for _, c := range s.pending {
+			s.apply(c)
+		}
+		s.pending = nil
+		for _, c := range v.StateChanges {
+			s.apply(c)
+		}
+		autocommit := v.ServerStatus&structure.SERVER_STATUS_AUTOCOMMIT != 0
+		s.state.Autocommit = &autocommit
+	case structure.ErrorResponse:
+		s.pending = nil
+	}
+}
+
+func (s *sessionState) apply(c structure.StateChange) {
+	switch c.Type {
+	case structure.StateSchema:
+		s.state.Database = c.Value
+	case structure.StateSystemVariable:
+		switch strings.ToLower(c.Name) {
+		case "autocommit":
+			s.state.Autocommit = onOff(c.Value)
+		case "character_set_client":
+			s.state.CharacterSet = c.Value
+		case "sql_mode":
+			s.state.SQLMode = c.Value
+		case "transaction_isolation", "tx_isolation":
+			s.state.IsolationLevel = strings.ToUpper(c.Value)
+		}
+	}
+}
+
+// current gives the state as it is now, or nil if nothing is known.
+func (s *sessionState) current() *structure.SessionState {
+	if s.state == (structure.SessionState{}) {
+		return nil
+	}
+	if s.snapshot == nil || !sameState(*s.snapshot, s.state) {
+		snapshot := s.state
+		s.snapshot = &snapshot
+	}
+	return s.snapshot
+}
+
+func sameState(a, b structure.SessionState) bool {
+	autocommit := func(s structure.SessionState) string {
+		if s.Autocommit == nil {
+			return ""
+		}
+		return strconv.FormatBool(*s.Autocommit)
+	}
+	return a.User == b.User && a.Database == b.Database && a.CharacterSet == b.CharacterSet &&
+		a.SQLMode == b.SQLMode && a.IsolationLevel == b.IsolationLevel && autocommit(a) == autocommit(b)
+}
+
+func onOff(value string) *bool {
+	var on bool
+	switch strings.ToLower(value) {
+	case "1", "on", "true":
+		on = true
+	case "0", "off", "false":
+	default:
+		return nil
+	}
+	return &on
+}
+
+//nolint:gochecknoglobals
+var (
+	useStatement = regexp.MustCompile("(?is)^(?:\\s|/\\*.*?\\*/)*use\\s+(`[^`]+`|[^\\s;`]+)[\\s;]*$")
+	setStatement = regexp.MustCompile(`(?is)^(?:\s|/\*.*?\*/)*set\s+(.*?)[\s;]*$`)
+	setNames     = regexp.MustCompile(`(?is)^(?:names|character\s+set|charset)\s+(\S+)`)
+	setIsolation = regexp.MustCompile(`(?is)^(?:session|local)\s+transaction\s+isolation\s+level\s+` +
+		`(read\s+uncommitted|read\s+committed|repeatable\s+read|serializable)$`)
+	setVariable = regexp.MustCompile(`(?is)^(?:@@(?:session\.|local\.)?|(?:session|local)\s+)?(\w+)\s*:?=\s*(.+)$`)
+	placeholder = regexp.MustCompile("\x00(\\d+)\x00")
+)
+
+// stateChanges finds the changes a query makes to the session state, from
+// USE and the SET statements for the variables followed.  The literals are
+// taken out first so commas and quotes in them can't confuse things.
+func stateChanges(query string) []structure.StateChange {
+	if m := useStatement.FindStringSubmatch(query); m != nil {
+		return []structure.StateChange{{Type: structure.StateSchema, Value: strings.Trim(m[1], "`")}}
+	}
+	var literals []string
+	masked := sqltext.ReplaceLiterals(query, func(literal string) string {
+		literals = append(literals, literal)
+		return fmt.Sprintf("\x00%d\x00", len(literals)-1)
+	})
+	m := setStatement.FindStringSubmatch(masked)
+	if m == nil {
+		return nil
+	}
+	value := func(v string) string {
+		v = strings.TrimSpace(v)
+		if l := placeholder.FindStringSubmatch(v); l != nil && l[0] == v {
+			n, _ := strconv.Atoi(l[1])
+			return sqltext.Unquote(literals[n])
+		}
+		if placeholder.MatchString(v) {
+			// an expression, the value can't be known.
+			return ""
+		}
+		return v
+	}
+	var changes []structure.StateChange
+	variable := func(name, v string) {
+		changes = append(changes, structure.StateChange{Type: structure.StateSystemVariable, Name: name, Value: v})
+	}
+	for _, part := range splitAssignments(m[1]) {
+		if n := setNames.FindStringSubmatch(part); n != nil {
+			variable("character_set_client", value(n[1]))
+			continue
+		}
+		if i := setIsolation.FindStringSubmatch(part); i != nil {
+			variable("transaction_isolation", strings.Join(strings.Fields(strings.ToUpper(i[1])), "-"))
+			continue
+		}
+		if a := setVariable.FindStringSubmatch(part); a != nil {
+			name := strings.ToLower(a[1])
+			if name == "names" {
+				variable("character_set_client", value(a[2]))
+				continue
+			}
+			variable(name, value(a[2]))
+		}
+	}
+	return changes
+}
+
+// splitAssignments splits the list of assignments in a SET on the commas
+// that aren't inside brackets.
+func splitAssignments(list string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range list {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(list[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(list[start:]))
+}
@@ -0,0 +1,106 @@
+package decoding_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/colinnewell/pcap-cli/tcp"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/decoding"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+func lenEnc(s string) []byte {
+	return append([]byte{byte(len(s))}, s...)
+}
+
+func stateChange(t byte, data ...[]byte) []byte {
+	var d []byte
+	for _, b := range data {
+		d = append(d, b...)
+	}
+	return append([]byte{t, byte(len(d))}, d...)
+}
+
+// trackedOKPacket is an OK with the session state changes given.
+func trackedOKPacket(changes ...[]byte) []byte {
+	var state []byte
+	for _, c := range changes {
+		state = append(state, c...)
+	}
+	status := structure.SERVER_STATUS_AUTOCOMMIT | structure.SERVER_SESSION_STATE_CHANGED
+	payload := []byte{0x00, 0x00, 0x00, byte(status), byte(status >> 8), 0x00, 0x00, 0x00, byte(len(state))}
+	payload = append(payload, state...)
+	return append([]byte{byte(len(payload)), 0x00, 0x00, 0x01}, payload...)
+}
+
+func TestSessionState(t *testing.T) {
+	off, budget, dir := false, 0, ""
+	readers := decoding.New(&off, &off, &off, &budget, &dir)
+	completed := make(chan interface{})
+	defer close(completed)
+	connections := decoding.Assemble(completed)
+	b := decoding.NewBuilder(tcp.ConnectionAddress{}, readers, completed)
+	seen := &fixedTime{seen: time.Date(2021, 10, 23, 9, 52, 9, 0, time.UTC)}
+	requests, responses := b.RequestWriter(seen), b.ResponseWriter(seen)
+
+	sqlMode := stateChange(0x00, lenEnc("sql_mode"), lenEnc("ANSI,STRICT_ALL_TABLES"))
+	schema := stateChange(0x01, lenEnc("archive"))
+	script := []struct {
+		sql      string
+		response []byte
+	}{
+		{"USE `demo`", okPacket(0, structure.SERVER_STATUS_AUTOCOMMIT)},
+		{"/* driver */ SET NAMES 'utf8mb4', sql_mode = 'ANSI', @@session.autocommit = 0", okPacket(0, 0)},
+		{"SET SESSION TRANSACTION ISOLATION LEVEL READ COMMITTED", okPacket(0, 0)},
+		// failures change nothing.
+		{"SET sql_mode = 'TRADITIONAL'", errorPacket(1231)},
+		{"SET sql_mode = CONCAT(@@sql_mode, ',STRICT_ALL_TABLES')", trackedOKPacket(sqlMode, schema)},
+		{"SELECT 1", okPacket(0, structure.SERVER_STATUS_AUTOCOMMIT)},
+	}
+	for _, s := range script {
+		if _, err := requests.Write(queryPacket(s.sql)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := responses.Write(s.response); err != nil {
+			t.Fatal(err)
+		}
+	}
+	requests.Close()
+	responses.Close()
+
+	conn := (<-connections).(structure.Connection)
+	var got []*structure.SessionState
+	for _, e := range conn.Exchanges {
+		got = append(got, conn.Items[e.Request].Session)
+	}
+	on, off := true, false
+	configured := &structure.SessionState{
+		Database:       "demo",
+		CharacterSet:   "utf8mb4",
+		Autocommit:     &off,
+		SQLMode:        "ANSI",
+		IsolationLevel: "READ-COMMITTED",
+	}
+	expected := []*structure.SessionState{
+		nil,
+		{Database: "demo", Autocommit: &on},
+		{Database: "demo", CharacterSet: "utf8mb4", Autocommit: &off, SQLMode: "ANSI"},
+		configured,
+		configured,
+		{
+			Database:       "archive",
+			CharacterSet:   "utf8mb4",
+			Autocommit:     &on,
+			SQLMode:        "ANSI,STRICT_ALL_TABLES",
+			IsolationLevel: "READ-COMMITTED",
+		},
+	}
+	if diff := cmp.Diff(got, expected); diff != "" {
+		t.Fatalf("Session state doesn't match (-got +expected):\n%s\n", diff)
+	}
+	if got[3] != got[4] {
+		t.Error("Session state should be shared until it changes")
+	}
+}
@@ -0,0 +1,76 @@
+package decoding
+
+import (
+	"time"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+// connectionSummary adds up the figures for the connection as its items
+// are handed on.
+type connectionSummary struct {
+	s               structure.ConnectionSummary
+	server          structure.ClientCapabilities
+	greeting, login bool
+}
+
+func (c *connectionSummary) add(items []structure.Transmission, exchanges []structure.Exchange) {
+	s := &c.s
+	for _, t := range items {
+		for _, seen := range t.Seen {
+			if s.Start.IsZero() || seen.Before(s.Start) {
+				s.Start = seen
+			}
+			if seen.After(s.End) {
+				s.End = seen
+			}
+		}
+		data := t.Data
+		if rawPacket, ok := data.(structure.WithRawPacket); ok {
+			data = rawPacket.Transmission
+		}
+		switch v := data.(type) {
+		case structure.Greeting:
+			s.ServerVersion, c.server, c.greeting = v.Version, v.Capabilities, true
+		case structure.LoginRequest:
+			if c.login {
+				continue
+			}
+			c.login = true
+			s.User, s.Database, s.Capabilities = v.Username, v.Database, v.ClientCapabilities
+		case structure.SSLRequest:
+			// the rest of the login happens once TLS is running.
+			c.login, s.TLS, s.Capabilities = true, true, v.ClientCapabilities
+		case structure.Request:
+			if v.Type == "QUIT" {
+				s.Ending = structure.EndingQuit
+			}
+		case structure.ErrorResponse:
+			s.Errors++
+		}
+	}
+
+	for _, e := range exchanges {
+		if s.Commands == nil {
+			s.Commands = make(map[string]int)
+		}
+		s.Commands[e.Command]++
+		s.Rows += e.Rows
+		s.RequestBytes += e.RequestBytes
+		s.ResponseBytes += e.ResponseBytes
+		s.ServerTime += e.TimeToLastByte
+	}
+}
+
+// end gives the summary once the connection is over.
+func (c *connectionSummary) end() structure.ConnectionSummary {
+	s := c.s
+	if c.greeting && c.login {
+		s.Capabilities &= c.server
+	}
+	s.Compressed = s.Capabilities&structure.CCAP_COMPRESS != 0
+	// pipelined commands overlap so the server time can be more than the
+	// time the connection was open.
+	s.IdleTime = max(s.End.Sub(s.Start)-s.ServerTime, time.Duration(0))
+	return s
+}
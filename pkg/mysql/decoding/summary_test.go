@@ -0,0 +1,117 @@
+package decoding_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/colinnewell/pcap-cli/tcp"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/decoding"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+func TestSummary(t *testing.T) {
+	off, budget, dir := false, 0, ""
+	readers := decoding.New(&off, &off, &off, &budget, &dir)
+	completed := make(chan interface{})
+	defer close(completed)
+	connections := decoding.Assemble(completed)
+	b := decoding.NewBuilder(tcp.ConnectionAddress{}, readers, completed)
+
+	start := time.Date(2021, 10, 23, 9, 52, 9, 0, time.UTC)
+	requestTime, responseTime := &fixedTime{}, &fixedTime{}
+	requests, responses := b.RequestWriter(requestTime), b.ResponseWriter(responseTime)
+	script := []struct {
+		sql      string
+		response []byte
+	}{
+		{"BEGIN", okPacket(0, structure.SERVER_STATUS_IN_TRANS)},
+		{"UPDATE peeps SET age = age + 1", okPacket(2, structure.SERVER_STATUS_IN_TRANS)},
+		{"DELETE FROM peeps", errorPacket(1213)},
+	}
+	at := func(i int) time.Time { return start.Add(time.Duration(i) * time.Second) }
+	for i, s := range script {
+		requestTime.seen, responseTime.seen = at(i), at(i).Add(time.Millisecond)
+		if _, err := requests.Write(queryPacket(s.sql)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := responses.Write(s.response); err != nil {
+			t.Fatal(err)
+		}
+	}
+	requestTime.seen = at(len(script))
+	if _, err := requests.Write([]byte{0x01, 0x00, 0x00, 0x00, 0x01}); err != nil {
+		t.Fatal(err)
+	}
+	requests.Close()
+	responses.Close()
+
+	conn := (<-connections).(structure.Connection)
+	expected := structure.ConnectionSummary{
+		Start:         at(0),
+		End:           at(3),
+		Ending:        structure.EndingQuit,
+		Commands:      map[string]int{"Query": 3, "QUIT": 1},
+		Errors:        1,
+		RequestBytes:  72,
+		ResponseBytes: 73,
+		ServerTime:    3 * time.Millisecond,
+		IdleTime:      3*time.Second - 3*time.Millisecond,
+	}
+	if diff := cmp.Diff(conn.Summary, expected); diff != "" {
+		t.Fatalf("Summary doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestSummaryTLS(t *testing.T) {
+	off, budget, dir := false, 0, ""
+	readers := decoding.New(&off, &off, &off, &budget, &dir)
+	completed := make(chan interface{})
+	defer close(completed)
+	connections := decoding.Assemble(completed)
+	b := decoding.NewBuilder(tcp.ConnectionAddress{}, readers, completed)
+
+	requests, responses := b.RequestWriter(&fixedTime{}), b.ResponseWriter(&fixedTime{})
+	if _, err := responses.Write([]byte{
+		0x4a, 0x00, 0x00, 0x00, 0x0a, 0x35, 0x2e, 0x37, 0x2e, 0x33, 0x33, 0x00, 0x08, 0x00, 0x00, 0x00, // J....5.7.33.....
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x00, 0xff, 0xff, 0x21, 0x02, 0x00, 0xff, 0xc1, // ...........!....
+		0x15, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, // ................
+		0x0e, 0x0f, 0x10, 0x11, 0x12, 0x13, 0x14, 0x00, 0x6d, 0x79, 0x73, 0x71, 0x6c, 0x5f, 0x6e, 0x61, // ........mysql_na
+		0x74, 0x69, 0x76, 0x65, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x00, // tive_password.
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// the SSL request from the mysql client, followed by the start of the
+	// TLS client hello.
+	if _, err := requests.Write([]byte{
+		0x20, 0x00, 0x00, 0x01, 0x05, 0xae, 0xff, 0x01, 0x00, 0x00, 0x00, 0x01, 0xff, 0x00, 0x00, 0x00, // ...............
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // ................
+		0x00, 0x00, 0x00, 0x00, // ....
+		0x16, 0x03, 0x01, 0x02, 0x00, 0x01, 0x00, 0x01, 0xfc, 0x03, 0x03, // ...........
+	}); err != nil {
+		t.Fatal(err)
+	}
+	requests.Close()
+	responses.Close()
+
+	conn := (<-connections).(structure.Connection)
+	expected := structure.SSLRequest{
+		Type:               "SSL request",
+		ClientCapabilities: 0x01ffae05,
+		Collation:          0xff,
+		MaxPacketSize:      0x01000000,
+	}
+	if diff := cmp.Diff(conn.Items[1].Data, expected); diff != "" {
+		t.Fatalf("SSL request doesn't match (-got +expected):\n%s\n", diff)
+	}
+	s := conn.Summary
+	if !s.TLS || s.User != "" || s.Capabilities != 0x01ffae05&0xc1ffffff {
+		t.Fatalf("Expected a TLS connection with the login hidden: %#v", s)
+	}
+	for _, item := range conn.Items {
+		if _, ok := item.Data.(structure.LoginRequest); ok {
+			t.Fatalf("The encrypted data shouldn't be read as a login: %#v", item)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package decoding
+
+import (
+	"strings"
+	"time"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/sqltext"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+const errDeadlock = 1213
+
+// transaction control statements, from their fingerprints.
+const (
+	notControl = iota
+	begin
+	commit
+	rollback
+)
+
+// transactions follows the transactions through the exchanges as they
+// complete.  They are started by BEGIN or START TRANSACTION, or by the
+// server saying it's in a transaction, as it does once autocommit is turned
+// off, and ended by COMMIT, ROLLBACK or the server saying it isn't any
+// more, as after statements that commit implicitly.  The server status is
+// only sent with OK packets so a transaction started by a SELECT with
+// autocommit off is only seen from the next statement with an OK.
+type transactions struct {
+	found   []structure.Transaction
+	current *structure.Transaction
+}
+
+func (t *transactions) finish(outcome string) {
+	t.current.Outcome = outcome
+	t.current.Duration = t.current.End.Sub(t.current.Start)
+	t.found = append(t.found, *t.current)
+	t.current = nil
+}
+
+// add follows an exchange.  The items it's made from start at index first
+// within the connection.
+func (t *transactions) add(items []structure.Transmission, first int, e structure.Exchange) {
+	data := items[e.Request-first].Data
+	if rawPacket, ok := data.(structure.WithRawPacket); ok {
+		data = rawPacket.Transmission
+	}
+	if t.current != nil && endsSession(data) {
+		t.finish(structure.TransactionSessionEnded)
+		return
+	}
+	sql := statement(data)
+	control := transactionControl(sql)
+	summary, known := responses(items, first, e)
+	inTransaction := summary.status&structure.SERVER_STATUS_IN_TRANS != 0
+	if t.current != nil && control == begin {
+		t.finish(structure.TransactionImplicitCommit)
+	}
+	if t.current == nil {
+		if control != begin && (!known || !inTransaction) {
+			return
+		}
+		t.current = &structure.Transaction{Start: e.RequestStart}
+	} else {
+		t.current.Idle += e.RequestStart.Sub(t.current.End)
+	}
+	t.current.Requests = append(t.current.Requests, e.Request)
+	if sql != "" {
+		t.current.Statements = append(t.current.Statements, sql)
+	}
+	t.current.End = exchangeEnd(e)
+	t.current.RowsAffected += summary.rowsAffected
+
+	switch {
+	case control == commit && summary.errorCode == 0:
+		t.finish(structure.TransactionCommit)
+	case control == rollback && summary.errorCode == 0:
+		t.finish(structure.TransactionRollback)
+	case summary.errorCode == errDeadlock:
+		t.finish(structure.TransactionDeadlock)
+	case known && !inTransaction:
+		t.finish(structure.TransactionImplicitCommit)
+	}
+}
+
+// end gives the transactions found once the connection is over, with any
+// still going left open.
+func (t *transactions) end() []structure.Transaction {
+	if t.current != nil {
+		t.finish(structure.TransactionOpen)
+	}
+	return t.found
+}
+
+// endsSession is true for the commands that roll back any transaction
+// left open.
+func endsSession(data interface{}) bool {
+	switch v := data.(type) {
+	case structure.ChangeUserRequest:
+		return true
+	case structure.Request:
+		return v.Type == "QUIT" || v.Type == reqResetConnection.String()
+	}
+	return false
+}
+
+// statement gives the SQL run by a command.
+func statement(data interface{}) string {
+	switch v := data.(type) {
+	case structure.Request:
+		if v.Type == "Query" {
+			return v.Query
+		}
+	case structure.ExecuteRequest:
+		if v.SQL != "" {
+			return v.SQL
+		}
+		return v.Query
+	}
+	return ""
+}
+
+func transactionControl(sql string) int {
+	if sql == "" {
+		return notControl
+	}
+	words := strings.Fields(sqltext.Fingerprint(sql))
+	if len(words) == 0 {
+		return notControl
+	}
+	switch words[0] {
+	case "begin":
+		return begin
+	case "start":
+		if len(words) > 1 && words[1] == "transaction" {
+			return begin
+		}
+	case "commit":
+		return commit
+	case "rollback":
+		// rolling back to a savepoint leaves the transaction open.
+		for _, w := range words[1:] {
+			if w == "to" {
+				return notControl
+			}
+		}
+		return rollback
+	}
+	return notControl
+}
+
+// responses gives what the responses to a command say about the
+// transaction: the server status from the last OK, if there was one, any
+// error code and the rows affected.
+func responses(items []structure.Transmission, first int, e structure.Exchange) (responseSummary, bool) {
+	var summary responseSummary
+	known := false
+	for _, r := range e.Responses {
+		data := items[r-first].Data
+		if rawPacket, ok := data.(structure.WithRawPacket); ok {
+			data = rawPacket.Transmission
+		}
+		switch v := data.(type) {
+		case structure.OKResponse:
+			summary.status, known = v.ServerStatus, true
+			summary.rowsAffected += v.AffectedRows
+		case structure.ErrorResponse:
+			summary.errorCode = v.Code
+		}
+	}
+	return summary, known
+}
+
+type responseSummary struct {
+	status       structure.StatusFlags
+	errorCode    uint16
+	rowsAffected uint64
+}
+
+func exchangeEnd(e structure.Exchange) time.Time {
+	if e.LastResponse != nil {
+		return *e.LastResponse
+	}
+	return e.RequestEnd
+}
@@ -0,0 +1,117 @@
+package decoding_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/colinnewell/pcap-cli/tcp"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/decoding"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+func queryPacket(sql string) []byte {
+	n := len(sql) + 1
+	return append([]byte{byte(n), byte(n >> 8), byte(n >> 16), 0, 0x03}, sql...)
+}
+
+func okPacket(affected byte, status structure.StatusFlags) []byte {
+	return []byte{0x07, 0x00, 0x00, 0x01, 0x00, affected, 0x00, byte(status), byte(status >> 8), 0x00, 0x00}
+}
+
+func errorPacket(code uint16) []byte {
+	message := "#40001Deadlock found when trying to get lock"
+	n := len(message) + 3
+	p := []byte{byte(n), byte(n >> 8), byte(n >> 16), 1, 0xff, byte(code), byte(code >> 8)}
+	return append(p, message...)
+}
+
+func TestTransactions(t *testing.T) {
+	off, budget, dir := false, 0, ""
+	readers := decoding.New(&off, &off, &off, &budget, &dir)
+	completed := make(chan interface{})
+	defer close(completed)
+	connections := decoding.Assemble(completed)
+	b := decoding.NewBuilder(tcp.ConnectionAddress{}, readers, completed)
+
+	start := time.Date(2021, 10, 23, 9, 52, 9, 0, time.UTC)
+	requestTime, responseTime := &fixedTime{}, &fixedTime{}
+	requests, responses := b.RequestWriter(requestTime), b.ResponseWriter(responseTime)
+	inTrans := structure.SERVER_STATUS_IN_TRANS | structure.SERVER_STATUS_AUTOCOMMIT
+	script := []struct {
+		sql      string
+		response []byte
+	}{
+		{"BEGIN", okPacket(0, inTrans)},
+		{"UPDATE peeps SET age = age + 1", okPacket(2, inTrans)},
+		{"COMMIT", okPacket(0, structure.SERVER_STATUS_AUTOCOMMIT)},
+		{"SET autocommit = 0", okPacket(0, 0)},
+		{"INSERT INTO peeps (name) VALUES ('Jo')", okPacket(1, structure.SERVER_STATUS_IN_TRANS)},
+		{"CREATE TABLE t (id INT)", okPacket(0, 0)},
+		{"start transaction", okPacket(0, structure.SERVER_STATUS_IN_TRANS)},
+		{"ROLLBACK TO SAVEPOINT a", okPacket(0, structure.SERVER_STATUS_IN_TRANS)},
+		{"DELETE FROM peeps", errorPacket(1213)},
+		{"BEGIN", okPacket(0, structure.SERVER_STATUS_IN_TRANS)},
+	}
+	at := func(i int) time.Time { return start.Add(time.Duration(i) * time.Second) }
+	for i, s := range script {
+		requestTime.seen, responseTime.seen = at(i), at(i).Add(time.Millisecond)
+		if _, err := requests.Write(queryPacket(s.sql)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := responses.Write(s.response); err != nil {
+			t.Fatal(err)
+		}
+	}
+	requestTime.seen = at(len(script))
+	if _, err := requests.Write([]byte{0x01, 0x00, 0x00, 0x00, 0x01}); err != nil {
+		t.Fatal(err)
+	}
+	requests.Close()
+	responses.Close()
+
+	conn := (<-connections).(structure.Connection)
+	expected := []structure.Transaction{
+		{
+			Requests:     []int{0, 2, 4},
+			Statements:   []string{"BEGIN", "UPDATE peeps SET age = age + 1", "COMMIT"},
+			Start:        at(0),
+			End:          at(2).Add(time.Millisecond),
+			Duration:     2*time.Second + time.Millisecond,
+			Idle:         2*time.Second - 2*time.Millisecond,
+			Outcome:      "COMMIT",
+			RowsAffected: 2,
+		},
+		{
+			Requests:     []int{8, 10},
+			Statements:   []string{"INSERT INTO peeps (name) VALUES ('Jo')", "CREATE TABLE t (id INT)"},
+			Start:        at(4),
+			End:          at(5).Add(time.Millisecond),
+			Duration:     time.Second + time.Millisecond,
+			Idle:         time.Second - time.Millisecond,
+			Outcome:      "implicit commit",
+			RowsAffected: 1,
+		},
+		{
+			Requests:   []int{12, 14, 16},
+			Statements: []string{"start transaction", "ROLLBACK TO SAVEPOINT a", "DELETE FROM peeps"},
+			Start:      at(6),
+			End:        at(8).Add(time.Millisecond),
+			Duration:   2*time.Second + time.Millisecond,
+			Idle:       2*time.Second - 2*time.Millisecond,
+			Outcome:    "deadlock",
+		},
+		{
+			Requests:   []int{18},
+			Statements: []string{"BEGIN"},
+			Start:      at(9),
+			End:        at(9).Add(time.Millisecond),
+			Duration:   time.Millisecond,
+			Outcome:    "session ended",
+		},
+	}
+	if diff := cmp.Diff(conn.Transactions, expected); diff != "" {
+		t.Fatalf("Transactions don't match (-got +expected):\n%s\n", diff)
+	}
+}
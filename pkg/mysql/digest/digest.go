@@ -0,0 +1,168 @@
+package digest
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/sqltext"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/transcript"
+)
+
+// Entry is the summary for a single fingerprint.
+type Entry struct {
+	ID          string
+	Fingerprint string
+	Example     string
+	Count       int
+	Errors      int
+	Rows        int
+	Total       time.Duration
+	Mean        time.Duration
+	P50         time.Duration
+	P95         time.Duration
+	P99         time.Duration
+	Max         time.Duration
+	Clients     []string
+}
+
+type stats struct {
+	entry     Entry
+	latencies []time.Duration
+	clients   map[string]bool
+}
+
+// Digest aggregates the queries seen by their fingerprint.
+type Digest struct {
+	queries map[string]*stats
+}
+
+// New creates an empty digest.
+func New() *Digest {
+	return &Digest{queries: make(map[string]*stats)}
+}
+
+// Add accounts for the queries and executes in a connection.  The latency
+// is from the start of the request to the last of the response.
+func (d *Digest) Add(c transcript.Connection) {
+	client := clientHost(c.Address)
+	for _, e := range c.Exchanges {
+		if e.Request >= len(c.Items) {
+			continue
+		}
+		// executes carry the SQL they were prepared with.
+		request := c.Items[e.Request].Data.Unwrap()
+		if (request.Type != "Query" && request.Type != "Execute") || request.Query == "" {
+			continue
+		}
+		d.add(request.Query, client, c, e)
+	}
+}
+
+func (d *Digest) add(query, client string, c transcript.Connection, e structure.Exchange) {
+	fingerprint := sqltext.Fingerprint(query)
+	s, ok := d.queries[fingerprint]
+	if !ok {
+		s = &stats{
+			entry: Entry{
+				ID:          sqltext.ID(fingerprint),
+				Fingerprint: fingerprint,
+				Example:     query,
+			},
+			clients: make(map[string]bool),
+		}
+		d.queries[fingerprint] = s
+	}
+	s.entry.Count++
+	s.entry.Rows += e.Rows
+	for _, r := range e.Responses {
+		if c.Items[r].Data.Unwrap().Type == "Error" {
+			s.entry.Errors++
+		}
+	}
+	if e.LastResponse != nil {
+		latency := e.LastResponse.Sub(e.RequestStart)
+		s.latencies = append(s.latencies, latency)
+		s.entry.Total += latency
+	}
+	if client != "" {
+		s.clients[client] = true
+	}
+}
+
+// Report gives the entries with the most total time spent first.
+func (d *Digest) Report() []Entry {
+	entries := make([]Entry, 0, len(d.queries))
+	for _, s := range d.queries {
+		entry := s.entry
+		latencies := append([]time.Duration(nil), s.latencies...)
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		if len(latencies) > 0 {
+			entry.Mean = entry.Total / time.Duration(len(latencies))
+			entry.P50 = percentile(latencies, 50) //nolint:mnd
+			entry.P95 = percentile(latencies, 95) //nolint:mnd
+			entry.P99 = percentile(latencies, 99) //nolint:mnd
+			entry.Max = latencies[len(latencies)-1]
+		}
+		entry.Clients = make([]string, 0, len(s.clients))
+		for client := range s.clients {
+			entry.Clients = append(entry.Clients, client)
+		}
+		sort.Strings(entry.Clients)
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Total != entries[j].Total {
+			return entries[i].Total > entries[j].Total
+		}
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Fingerprint < entries[j].Fingerprint
+	})
+	return entries
+}
+
+// percentile uses the nearest rank method on sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted)))) //nolint:mnd
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// clientHost takes the client ip from a connection address.
+func clientHost(address string) string {
+	src, _, _ := strings.Cut(address, " - ")
+	if i := strings.LastIndexByte(src, ':'); i >= 0 {
+		return src[:i]
+	}
+	return src
+}
+
+// WriteText writes the report in a form intended for people to read.
+func WriteText(w io.Writer, entries []Entry) error {
+	for i, e := range entries {
+		_, err := fmt.Fprintf(w,
+			"# Query %d: ID %s\n"+
+				"# Count: %d  Errors: %d  Rows: %d\n"+
+				"# Time: total %s  mean %s  p50 %s  p95 %s  p99 %s  max %s\n"+
+				"# Clients: %s\n"+
+				"%s\n\n",
+			i+1, e.ID,
+			e.Count, e.Errors, e.Rows,
+			e.Total, e.Mean, e.P50, e.P95, e.P99, e.Max,
+			strings.Join(e.Clients, ", "),
+			e.Fingerprint,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package digest_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/digest"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/sqltext"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/transcript"
+)
+
+const connections = `[
+{
+  "Address": "10.0.0.1:40000 - 10.0.0.9:3306",
+  "Items": [
+    {"Data": {"Type": "Query", "Query": "SELECT * FROM t WHERE id = 1"}},
+    {"Data": {"Type": "SELECT"}, "ResponseTo": 0},
+    {"Data": {"Type": "Prepare", "Query": "SELECT * FROM t WHERE id = ?"}},
+    {"Data": {"Type": "PREPARE_OK", "StatementID": 4}, "ResponseTo": 2},
+    {"Data": {"Type": "Execute", "StatementID": 4, "Query": "SELECT * FROM t WHERE id = ?"}},
+    {"Data": {"Type": "Error"}, "ResponseTo": 4}
+  ],
+  "Exchanges": [
+    {"Request": 0, "Responses": [1], "Command": "Query", "Rows": 2,
+     "RequestStart": "2021-04-04T17:28:50Z", "LastResponse": "2021-04-04T17:28:50.010Z"},
+    {"Request": 2, "Responses": [3], "Command": "Prepare",
+     "RequestStart": "2021-04-04T17:28:51Z", "LastResponse": "2021-04-04T17:28:51.001Z"},
+    {"Request": 4, "Responses": [5], "Command": "Execute",
+     "RequestStart": "2021-04-04T17:28:52Z", "LastResponse": "2021-04-04T17:28:52.030Z"}
+  ]
+},
+{
+  "Address": "10.0.0.2:40000 - 10.0.0.9:3306",
+  "Items": [
+    {"Data": {"RawData": "", "Transmission": {"Type": "Query", "Query": "select * from t where id = 99"}}},
+    {"Data": {"RawData": "", "Transmission": {"Type": "SELECT"}}, "ResponseTo": 0},
+    {"Data": {"Type": "Query", "Query": "COMMIT"}},
+    {"Data": {"Type": "OK"}, "ResponseTo": 2}
+  ],
+  "Exchanges": [
+    {"Request": 0, "Responses": [1], "Command": "Query", "Rows": 1,
+     "RequestStart": "2021-04-04T17:28:50Z", "LastResponse": "2021-04-04T17:28:50.020Z"},
+    {"Request": 2, "Responses": [3], "Command": "Query",
+     "RequestStart": "2021-04-04T17:28:53Z", "LastResponse": "2021-04-04T17:28:53.001Z"}
+  ]
+}
+]`
+
+func TestDigest(t *testing.T) {
+	d := digest.New()
+	err := transcript.Read(strings.NewReader(connections), func(c transcript.Connection) error {
+		d.Add(c)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := time.Millisecond
+	expected := []digest.Entry{
+		{
+			ID:          sqltext.ID("select * from t where id = ?"),
+			Fingerprint: "select * from t where id = ?",
+			Example:     "SELECT * FROM t WHERE id = 1",
+			Count:       3,
+			Errors:      1,
+			Rows:        3,
+			Total:       60 * ms,
+			Mean:        20 * ms,
+			P50:         20 * ms,
+			P95:         30 * ms,
+			P99:         30 * ms,
+			Max:         30 * ms,
+			Clients:     []string{"10.0.0.1", "10.0.0.2"},
+		},
+		{
+			ID:          sqltext.ID("commit"),
+			Fingerprint: "commit",
+			Example:     "COMMIT",
+			Count:       1,
+			Total:       ms,
+			Mean:        ms,
+			P50:         ms,
+			P95:         ms,
+			P99:         ms,
+			Max:         ms,
+			Clients:     []string{"10.0.0.2"},
+		},
+	}
+	if diff := cmp.Diff(d.Report(), expected); diff != "" {
+		t.Fatalf("Report doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+const errorConnections = `[
+{
+  "Address": "10.0.0.1:40000 - 10.0.0.9:3306",
+  "Items": [
+    {"Data": {"Type": "Query", "Query": "DELETE FROM t WHERE id = 1"}, "Seen": ["2021-04-04T17:28:50Z"]},
+    {"Data": {"Type": "Error", "Code": 1213, "Name": "ER_LOCK_DEADLOCK", "Category": "lock",
+     "Message": "Deadlock found when trying to get lock"}, "Seen": ["2021-04-04T17:28:50.010Z"], "ResponseTo": 0},
+    {"Data": {"Type": "Query", "Query": "DELETE FROM t WHERE id = 2"}, "Seen": ["2021-04-04T17:28:55Z"]},
+    {"Data": {"Type": "Error", "Code": 1213, "Name": "ER_LOCK_DEADLOCK", "Category": "lock",
+     "Message": "Deadlock found when trying to get lock"}, "Seen": ["2021-04-04T17:28:55.010Z"], "ResponseTo": 2},
+    {"Data": {"Type": "Query", "Query": "SELEC 1"}, "Seen": ["2021-04-04T17:28:56Z"]},
+    {"Data": {"Type": "Error", "Code": 1064, "State": "42000", "Message": "You have an error in your SQL syntax"},
+     "Seen": ["2021-04-04T17:28:56.001Z"], "ResponseTo": 4}
+  ]
+},
+{
+  "Address": "10.0.0.2:40000 - 10.0.0.9:3306",
+  "Items": [
+    {"Data": {"Type": "Error", "Code": 1130, "Name": "ER_HOST_NOT_PRIVILEGED", "Category": "permission",
+     "Message": "Host '10.0.0.2' is not allowed to connect"}, "Seen": ["2021-04-04T17:28:57Z"]}
+  ]
+}
+]`
+
+func TestErrors(t *testing.T) {
+	d := digest.NewErrors()
+	err := transcript.Read(strings.NewReader(errorConnections), func(c transcript.Connection) error {
+		d.Add(c)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := func(s string) time.Time {
+		when, err := time.Parse(time.RFC3339Nano, "2021-04-04T17:28:"+s+"Z")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return when
+	}
+	expected := []digest.ErrorEntry{
+		{
+			Code:        1213,
+			Name:        "ER_LOCK_DEADLOCK",
+			Category:    "lock",
+			Command:     "Query",
+			Fingerprint: "delete from t where id = ?",
+			Client:      "10.0.0.1",
+			Count:       2,
+			First:       at("50.01"),
+			Last:        at("55.01"),
+			Message:     "Deadlock found when trying to get lock",
+			Example:     "DELETE FROM t WHERE id = 1",
+		},
+		{
+			// looked up as the output is from before the catalogue.
+			Code:        1064,
+			Name:        "ER_PARSE_ERROR",
+			Category:    "syntax",
+			Command:     "Query",
+			Fingerprint: "selec ?",
+			Client:      "10.0.0.1",
+			Count:       1,
+			First:       at("56.001"),
+			Last:        at("56.001"),
+			Message:     "You have an error in your SQL syntax",
+			Example:     "SELEC 1",
+		},
+		{
+			Code:     1130,
+			Name:     "ER_HOST_NOT_PRIVILEGED",
+			Category: "permission",
+			Client:   "10.0.0.2",
+			Count:    1,
+			First:    at("57"),
+			Last:     at("57"),
+			Message:  "Host '10.0.0.2' is not allowed to connect",
+		},
+	}
+	if diff := cmp.Diff(d.Report(), expected); diff != "" {
+		t.Fatalf("Report doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
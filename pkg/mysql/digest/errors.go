@@ -0,0 +1,158 @@
+package digest
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/errorcodes"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/sqltext"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/transcript"
+)
+
+// ErrorEntry is the summary for an error code from a client, for a single
+// query fingerprint or for another command.
+type ErrorEntry struct {
+	Code        uint16
+	Name        string
+	Category    string
+	Command     string
+	Fingerprint string
+	Client      string
+	Count       int
+	First       time.Time
+	Last        time.Time
+	Message     string
+	Example     string
+}
+
+type errorKey struct {
+	code                         uint16
+	command, fingerprint, client string
+}
+
+// Errors aggregates the errors seen by their code, what got them and the
+// client.
+type Errors struct {
+	groups map[errorKey]*ErrorEntry
+}
+
+// NewErrors creates an empty error report.
+func NewErrors() *Errors {
+	return &Errors{groups: make(map[errorKey]*ErrorEntry)}
+}
+
+// Add accounts for the errors in a connection.  Errors sent before any
+// request, like a server turning the connection away, have no command.
+func (d *Errors) Add(c transcript.Connection) {
+	client := clientHost(c.Address)
+	for _, item := range c.Items {
+		data := item.Data.Unwrap()
+		if data.Type != "Error" {
+			continue
+		}
+		key := errorKey{code: data.Code, client: client}
+		var example string
+		if item.ResponseTo != nil && *item.ResponseTo < len(c.Items) {
+			request := c.Items[*item.ResponseTo].Data.Unwrap()
+			key.command = request.Type
+			if (request.Type == "Query" || request.Type == "Execute") && request.Query != "" {
+				key.fingerprint = sqltext.Fingerprint(request.Query)
+				example = request.Query
+			}
+		}
+		g, ok := d.groups[key]
+		if !ok {
+			name, category := data.Name, data.Category
+			if name == "" && category == "" {
+				// output from before the errors were looked up.
+				entry := errorcodes.Lookup(data.Code, data.State, "")
+				name, category = entry.Name, entry.Category
+			}
+			g = &ErrorEntry{
+				Code:        data.Code,
+				Name:        name,
+				Category:    category,
+				Command:     key.command,
+				Fingerprint: key.fingerprint,
+				Client:      client,
+				Message:     data.Message,
+				Example:     example,
+			}
+			d.groups[key] = g
+		}
+		g.Count++
+		for _, seen := range item.Seen {
+			if g.First.IsZero() || seen.Before(g.First) {
+				g.First = seen
+			}
+			if seen.After(g.Last) {
+				g.Last = seen
+			}
+		}
+	}
+}
+
+// Report gives the entries with the most errors first.
+func (d *Errors) Report() []ErrorEntry {
+	entries := make([]ErrorEntry, 0, len(d.groups))
+	for _, g := range d.groups {
+		entries = append(entries, *g)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch {
+		case a.Count != b.Count:
+			return a.Count > b.Count
+		case a.Code != b.Code:
+			return a.Code < b.Code
+		case a.Command != b.Command:
+			return a.Command < b.Command
+		case a.Fingerprint != b.Fingerprint:
+			return a.Fingerprint < b.Fingerprint
+		}
+		return a.Client < b.Client
+	})
+	return entries
+}
+
+// WriteErrorsText writes the error report in a form intended for people to
+// read.
+func WriteErrorsText(w io.Writer, entries []ErrorEntry) error {
+	for i, e := range entries {
+		name := e.Name
+		if name == "" {
+			name = "unknown"
+		}
+		if e.Category != "" {
+			name += ", " + e.Category
+		}
+		command := e.Command
+		if command == "" {
+			command = "none"
+		}
+		_, err := fmt.Fprintf(w,
+			"# Error %d: %d %s\n"+
+				"# Count: %d  First: %s  Last: %s\n"+
+				"# Client: %s  Command: %s\n"+
+				"# Message: %s\n",
+			i+1, e.Code, name,
+			e.Count, e.First.Format(time.RFC3339Nano), e.Last.Format(time.RFC3339Nano),
+			e.Client, command,
+			e.Message,
+		)
+		if err != nil {
+			return err
+		}
+		if e.Fingerprint != "" {
+			if _, err := fmt.Fprintln(w, e.Fingerprint); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
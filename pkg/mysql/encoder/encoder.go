@@ -0,0 +1,447 @@
+package encoder
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/packet"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+const (
+	// maxPayload is the most a single packet can carry, longer payloads
+	// are split over several packets.
+	maxPayload = 0xffffff
+	// minCompressLength is the shortest packet MySQL bothers compressing.
+	minCompressLength = 50
+
+	protocolVersion = 10
+	headerOK        = 0x00
+	headerEOF       = 0xfe
+	headerError     = 0xff
+
+	encodedNull         = 0xfb
+	encodedInNext2Bytes = 0xfc
+	encodedInNext3Bytes = 0xfd
+	encodedInNext8Bytes = 0xfe
+
+	// columnDefinitionLength is the length of the fixed fields of a
+	// column definition.
+	columnDefinitionLength = 0x0c
+	binaryCollation        = 63
+)
+
+// Encoder writes structures out as MySQL packets, numbering them as it
+// goes.  The capabilities decide the likes of whether EOF packets are
+// sent.
+type Encoder struct {
+	w            io.Writer
+	sequence     byte
+	Capabilities structure.ClientCapabilities
+	// Status is the server status sent at the end of a result set.
+	Status structure.StatusFlags
+	// Compress sends the packets with the compressed protocol.
+	Compress           bool
+	compressedSequence byte
+}
+
+// New creates an encoder writing to w.
+func New(w io.Writer, capabilities structure.ClientCapabilities) *Encoder {
+	return &Encoder{w: w, Capabilities: capabilities}
+}
+
+// SetSequence sets the sequence id for the next packet.  Commands start at
+// 0 and the responses follow on from the last packet of the command.  With
+// compression the compressed packets are numbered from there too.
+func (e *Encoder) SetSequence(sequence byte) {
+	e.sequence = sequence
+	e.compressedSequence = sequence
+}
+
+// Sequence returns the sequence id the next packet will have.
+func (e *Encoder) Sequence() byte {
+	return e.sequence
+}
+
+// WritePacket writes a payload out with its header, splitting it up if it
+// is too long for a single packet.
+func (e *Encoder) WritePacket(payload []byte) error {
+	for {
+		chunk := payload
+		if len(chunk) > maxPayload {
+			chunk = chunk[:maxPayload]
+		}
+		header := make([]byte, packet.HeaderLen)
+		header[0] = byte(len(chunk))
+		header[1] = byte(len(chunk) >> 8)  //nolint:mnd
+		header[2] = byte(len(chunk) >> 16) //nolint:mnd
+		header[packet.PacketNo] = e.sequence
+		e.sequence++
+		if err := e.write(append(header, chunk...)); err != nil {
+			return errors.Wrap(err, "write-packet")
+		}
+		payload = payload[len(chunk):]
+		// a payload of exactly the maximum is followed by an empty
+		// packet so the other side knows it's finished.
+		if len(chunk) < maxPayload {
+			return nil
+		}
+	}
+}
+
+func (e *Encoder) write(p []byte) error {
+	if !e.Compress {
+		_, err := e.w.Write(p)
+		return err
+	}
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > maxPayload {
+			chunk = chunk[:maxPayload]
+		}
+		p = p[len(chunk):]
+		if err := e.writeCompressed(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeCompressed wraps a packet up for the compressed protocol.  Like
+// MySQL small packets aren't worth compressing and are sent as they are,
+// with an uncompressed length of 0 to say so.
+func (e *Encoder) writeCompressed(p []byte) error {
+	body := p
+	uncompressed := 0
+	if len(p) >= minCompressLength {
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		if _, err := zw.Write(p); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		body = z.Bytes()
+		uncompressed = len(p)
+	}
+	var frame bytes.Buffer
+	writeUint24(&frame, len(body))
+	frame.WriteByte(e.compressedSequence)
+	e.compressedSequence++
+	writeUint24(&frame, uncompressed)
+	frame.Write(body)
+	_, err := e.w.Write(frame.Bytes())
+	return err
+}
+
+func (e *Encoder) deprecateEOF() bool {
+	return e.Capabilities&structure.CCAP_CLIENT_DEPRECATE_EOF != 0
+}
+
+// Handshake holds the parts of the greeting that aren't recorded in
+// structure.Greeting.
+type Handshake struct {
+	ConnectionID uint32
+	Status       structure.StatusFlags
+	// AuthData is the scramble used for the password, usually 20 bytes.
+	AuthData   []byte
+	AuthPlugin string
+}
+
+// WriteGreeting writes the initial handshake the server sends.
+func (e *Encoder) WriteGreeting(g structure.Greeting, h Handshake) error {
+	var b bytes.Buffer
+	protocol := g.Protocol
+	if protocol == 0 {
+		protocol = protocolVersion
+	}
+	b.WriteByte(protocol)
+	writeNulString(&b, g.Version)
+	writeUint32(&b, h.ConnectionID)
+	authData := h.AuthData
+	//nolint:mnd
+	if len(authData) < 8 {
+		authData = append(authData, make([]byte, 8-len(authData))...)
+	}
+	b.Write(authData[:8])
+	b.WriteByte(0)
+	writeUint16(&b, uint16(g.Capabilities))
+	b.WriteByte(g.Collation)
+	writeUint16(&b, uint16(h.Status))
+	writeUint16(&b, uint16(g.Capabilities>>16)) //nolint:mnd
+	if g.Capabilities&structure.CCAP_PLUGIN_AUTH != 0 {
+		b.WriteByte(byte(len(authData) + 1))
+	} else {
+		b.WriteByte(0)
+	}
+	b.Write(make([]byte, 10)) //nolint:mnd
+	if g.Capabilities&structure.CCAP_SECURE_CONNECTION != 0 {
+		rest := authData[8:]
+		//nolint:mnd
+		if len(rest) < 12 {
+			rest = append(rest, make([]byte, 12-len(rest))...)
+		}
+		b.Write(rest)
+		b.WriteByte(0)
+	}
+	if g.Capabilities&structure.CCAP_PLUGIN_AUTH != 0 {
+		writeNulString(&b, h.AuthPlugin)
+	}
+	return e.WritePacket(b.Bytes())
+}
+
+// WriteOK writes an OK packet.
+func (e *Encoder) WriteOK(ok structure.OKResponse) error {
+	return e.WritePacket(e.okPayload(headerOK, ok))
+}
+
+// okPayload lays out an OK.  With session tracking the info has its length
+// first so that the state changes can follow it.
+func (e *Encoder) okPayload(header byte, ok structure.OKResponse) []byte {
+	var b bytes.Buffer
+	b.WriteByte(header)
+	writeLenEncInt(&b, ok.AffectedRows)
+	writeLenEncInt(&b, ok.LastInsertID)
+	writeUint16(&b, uint16(ok.ServerStatus))
+	writeUint16(&b, ok.WarningCount)
+	if e.Capabilities&structure.CCAP_CLIENT_SESSION_TRACK == 0 {
+		b.WriteString(ok.Info)
+		return b.Bytes()
+	}
+	writeLenEncString(&b, ok.Info)
+	if ok.ServerStatus&structure.SERVER_SESSION_STATE_CHANGED != 0 {
+		writeLenEncString(&b, stateChanges(ok.StateChanges))
+	}
+	return b.Bytes()
+}
+
+// stateChanges lays out the session state changes, skipping any of types
+// that aren't known.
+func stateChanges(changes []structure.StateChange) string {
+	var b bytes.Buffer
+	for _, c := range changes {
+		t, ok := stateChangeTypes[c.Type]
+		if !ok {
+			continue
+		}
+		var data bytes.Buffer
+		switch c.Type {
+		case structure.StateSystemVariable:
+			writeLenEncString(&data, c.Name)
+		case structure.StateGTIDs:
+			// the only encoding of the GTIDs there is.
+			data.WriteByte(0)
+		}
+		writeLenEncString(&data, c.Value)
+		b.WriteByte(t)
+		writeLenEncString(&b, data.String())
+	}
+	return b.String()
+}
+
+//nolint:gochecknoglobals
+var stateChangeTypes = map[string]byte{
+	structure.StateSystemVariable:             0,
+	structure.StateSchema:                     1,
+	structure.StateChanged:                    2,
+	structure.StateGTIDs:                      3,
+	structure.StateTransactionCharacteristics: 4,
+	structure.StateTransactionState:           5,
+}
+
+// WriteEOF writes the packet that ends a list of columns or rows.  When
+// CLIENT_DEPRECATE_EOF is set that is an OK packet with the EOF header.
+func (e *Encoder) WriteEOF(status structure.StatusFlags) error {
+	if e.deprecateEOF() {
+		return e.WritePacket(e.okPayload(headerEOF, structure.OKResponse{ServerStatus: status}))
+	}
+	var b bytes.Buffer
+	b.WriteByte(headerEOF)
+	writeUint16(&b, 0)
+	writeUint16(&b, uint16(status))
+	return e.WritePacket(b.Bytes())
+}
+
+// WriteError writes an error packet.
+func (e *Encoder) WriteError(er structure.ErrorResponse) error {
+	var b bytes.Buffer
+	b.WriteByte(headerError)
+	writeUint16(&b, er.Code)
+	if er.State != "" {
+		b.WriteByte('#')
+		b.WriteString(er.State)
+	}
+	b.WriteString(er.Message)
+	return e.WritePacket(b.Bytes())
+}
+
+// WritePrepareOK writes the response to COM_STMT_PREPARE, along with the
+// parameter and column definitions.  Parameters without definitions are
+// sent the way MySQL describes them.
+func (e *Encoder) WritePrepareOK(p structure.PrepareOKResponse) error {
+	var b bytes.Buffer
+	b.WriteByte(headerOK)
+	writeUint32(&b, p.StatementID)
+	writeUint16(&b, p.NumColumns)
+	writeUint16(&b, p.NumParams)
+	b.WriteByte(0)
+	writeUint16(&b, uint16(p.Warnings))
+	if err := e.WritePacket(b.Bytes()); err != nil {
+		return err
+	}
+	params := p.Params
+	for len(params) < int(p.NumParams) {
+		params = append(params, structure.ColumnInfo{
+			Catalog:     "def",
+			ColumnAlias: "?",
+			TypeInfo: structure.TypeInfo{
+				CharacterSetNumber: binaryCollation,
+				FieldTypes:         structure.VAR_STRING,
+				FieldDetail:        structure.DETAIL_BINARY_COLLATION,
+			},
+		})
+	}
+	for _, defs := range [][]structure.ColumnInfo{params, p.Columns} {
+		if len(defs) == 0 {
+			continue
+		}
+		if err := e.writeColumns(defs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Encoder) writeColumns(columns []structure.ColumnInfo) error {
+	for _, c := range columns {
+		if err := e.WritePacket(columnDefinition(c)); err != nil {
+			return err
+		}
+	}
+	if e.deprecateEOF() {
+		return nil
+	}
+	return e.WriteEOF(e.Status)
+}
+
+func columnDefinition(c structure.ColumnInfo) []byte {
+	var b bytes.Buffer
+	for _, s := range []string{
+		c.Catalog,
+		c.Schema,
+		c.TableAlias,
+		c.Table,
+		c.ColumnAlias,
+		c.Column,
+	} {
+		writeLenEncString(&b, s)
+	}
+	typeInfo := c.TypeInfo
+	if typeInfo.LengthOfFixedFields == 0 {
+		typeInfo.LengthOfFixedFields = columnDefinitionLength
+	}
+	// writing to a bytes.Buffer can't fail.
+	_ = binary.Write(&b, binary.LittleEndian, typeInfo)
+	if c.Default != nil {
+		writeLenEncString(&b, *c.Default)
+	}
+	return b.Bytes()
+}
+
+// WriteResultSet writes a result set, with the rows in the binary form used
+// for the results of prepared statements if binary is set, otherwise as
+// text.
+func (e *Encoder) WriteResultSet(r structure.ResultSetResponse, binary bool) error {
+	var b bytes.Buffer
+	writeLenEncInt(&b, uint64(len(r.Columns)))
+	if err := e.WritePacket(b.Bytes()); err != nil {
+		return err
+	}
+	if err := e.writeColumns(r.Columns); err != nil {
+		return err
+	}
+	rows, err := allRows(r)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		var payload []byte
+		if binary {
+			payload, err = binaryRow(r.Columns, row)
+		} else {
+			payload, err = textRow(row)
+		}
+		if err != nil {
+			return err
+		}
+		if err := e.WritePacket(payload); err != nil {
+			return err
+		}
+	}
+	return e.WriteEOF(e.Status)
+}
+
+// allRows gathers the rows, including any that were spilled to disk.
+func allRows(r structure.ResultSetResponse) ([][]interface{}, error) {
+	if r.SpilledResults.Len() == 0 {
+		return r.Results, nil
+	}
+	spilled, err := r.SpilledResults.JSON()
+	if err != nil {
+		return nil, err
+	}
+	rows := append([][]interface{}(nil), r.Results...)
+	for _, data := range spilled {
+		var row []interface{}
+		if err := unmarshalNumbers(data, &row); err != nil {
+			return nil, errors.Wrap(err, "encode-spilled-row")
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func writeUint24(b *bytes.Buffer, v int) {
+	b.WriteByte(byte(v))
+	b.WriteByte(byte(v >> 8))  //nolint:mnd
+	b.WriteByte(byte(v >> 16)) //nolint:mnd
+}
+
+func writeUint16(b *bytes.Buffer, v uint16) {
+	_ = binary.Write(b, binary.LittleEndian, v)
+}
+
+func writeUint32(b *bytes.Buffer, v uint32) {
+	_ = binary.Write(b, binary.LittleEndian, v)
+}
+
+func writeNulString(b *bytes.Buffer, s string) {
+	b.WriteString(s)
+	b.WriteByte(0)
+}
+
+func writeLenEncString(b *bytes.Buffer, s string) {
+	writeLenEncInt(b, uint64(len(s)))
+	b.WriteString(s)
+}
+
+func writeLenEncInt(b *bytes.Buffer, v uint64) {
+	switch {
+	case v < encodedNull:
+		b.WriteByte(byte(v))
+	case v <= 0xffff:
+		b.WriteByte(encodedInNext2Bytes)
+		writeUint16(b, uint16(v))
+	case v <= 0xffffff:
+		b.WriteByte(encodedInNext3Bytes)
+		b.Write([]byte{byte(v), byte(v >> 8), byte(v >> 16)}) //nolint:mnd
+	default:
+		b.WriteByte(encodedInNext8Bytes)
+		_ = binary.Write(b, binary.LittleEndian, v)
+	}
+}
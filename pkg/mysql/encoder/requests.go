@@ -0,0 +1,251 @@
+package encoder
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/decoding"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/decoding/bitmap"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+const (
+	comQuit        = 0x01
+	comQuery       = 0x03
+	comStmtPrepare = 0x16
+	comStmtExecute = 0x17
+	// lastCommand is the highest command code there is a name for.
+	lastCommand = 0x1f
+
+	// paramCountAvailable is the COM_STMT_EXECUTE flag that says a
+	// parameter count precedes the parameters, used to send query
+	// attributes.
+	paramCountAvailable = 0x08
+	newParamsBound      = 1
+	unsignedParam       = 0x80
+	// loginFiller is the reserved space in the login packet, the last 4
+	// bytes are used by MariaDB for its extended capabilities.
+	loginFiller = 19
+)
+
+var errUnknownCommand = errors.New("unknown command")
+
+// WriteRequest writes a command from the client.  The Type is the name the
+// decoder gives it.  Each command starts a new sequence.
+func (e *Encoder) WriteRequest(r structure.Request) error {
+	var b bytes.Buffer
+	switch r.Type {
+	case "Query":
+		b.WriteByte(comQuery)
+		if e.Capabilities&structure.CCAP_CLIENT_QUERY_ATTRIBUTES != 0 {
+			names, values := attributes(r.Attributes)
+			writeLenEncInt(&b, uint64(len(values)))
+			// the parameter set count, always 1.
+			writeLenEncInt(&b, 1)
+			if len(values) > 0 {
+				if err := writeParams(&b, names, values); err != nil {
+					return err
+				}
+			}
+		}
+		b.WriteString(r.Query)
+	case "Prepare":
+		b.WriteByte(comStmtPrepare)
+		b.WriteString(r.Query)
+	case "QUIT":
+		b.WriteByte(comQuit)
+	default:
+		code, ok := commandCode(r.Type)
+		if !ok {
+			return errors.Wrap(errUnknownCommand, r.Type)
+		}
+		b.WriteByte(code)
+	}
+	e.SetSequence(0)
+	return e.WritePacket(b.Bytes())
+}
+
+// commandCode finds the command with the name the decoder gives it.
+func commandCode(name string) (byte, bool) {
+	for c := decoding.CommandCode(0); c <= lastCommand; c++ {
+		if c.String() == name {
+			return byte(c), true
+		}
+	}
+	return 0, false
+}
+
+// WriteExecute writes a COM_STMT_EXECUTE, always sending the types of the
+// parameters.  The types are picked to suit the values.  Attributes are
+// sent if CLIENT_QUERY_ATTRIBUTES is in the capabilities.
+func (e *Encoder) WriteExecute(r structure.ExecuteRequest) error {
+	var b bytes.Buffer
+	b.WriteByte(comStmtExecute)
+	writeUint32(&b, r.StatementID)
+	values := r.Params
+	names := make([]string, len(values))
+	flags := r.Flags
+	withAttributes := e.Capabilities&structure.CCAP_CLIENT_QUERY_ATTRIBUTES != 0
+	if withAttributes {
+		attributeNames, attributeValues := attributes(r.Attributes)
+		names = append(names, attributeNames...)
+		values = append(append([]interface{}(nil), values...), attributeValues...)
+		flags |= paramCountAvailable
+	}
+	b.WriteByte(flags)
+	writeUint32(&b, r.IterationCount)
+	if withAttributes {
+		writeLenEncInt(&b, uint64(len(values)))
+	} else {
+		names = nil
+	}
+	if len(values) > 0 {
+		if err := writeParams(&b, names, values); err != nil {
+			return err
+		}
+	}
+	e.SetSequence(0)
+	return e.WritePacket(b.Bytes())
+}
+
+// attributes sorts the query attributes by name to keep the output stable.
+func attributes(a map[string]interface{}) ([]string, []interface{}) {
+	names := make([]string, 0, len(a))
+	for name := range a {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	values := make([]interface{}, len(names))
+	for i, name := range names {
+		values[i] = a[name]
+	}
+	return names, values
+}
+
+// writeParams writes the null bitmap, types and values used by both
+// statement parameters and query attributes.  The names are only written
+// when they are given.
+func writeParams(b *bytes.Buffer, names []string, values []interface{}) error {
+	nulls := bitmap.Empty(len(values), bitmap.ExecuteParams)
+	for i, v := range values {
+		if isNull(v) {
+			nulls.SetNull(i)
+		}
+	}
+	b.Write(nulls.Data)
+	b.WriteByte(newParamsBound)
+	types := make([]structure.FieldType, len(values))
+	unsigned := make([]bool, len(values))
+	for i, v := range values {
+		types[i], unsigned[i] = paramType(v)
+		b.WriteByte(byte(types[i]))
+		if unsigned[i] {
+			b.WriteByte(unsignedParam)
+		} else {
+			b.WriteByte(0)
+		}
+		if names != nil {
+			writeLenEncString(b, names[i])
+		}
+	}
+	for i, v := range values {
+		if isNull(v) {
+			continue
+		}
+		if err := writeBinaryValue(b, types[i], unsigned[i], v); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("param %d", i))
+		}
+	}
+	return nil
+}
+
+// paramType picks the type to send a parameter as.
+func paramType(v interface{}) (structure.FieldType, bool) {
+	if isNull(v) {
+		return structure.NULL, false
+	}
+	switch v.(type) {
+	case int8:
+		return structure.TINY, false
+	case uint8:
+		return structure.TINY, true
+	case int16:
+		return structure.SHORT, false
+	case uint16:
+		return structure.SHORT, true
+	case int32:
+		return structure.LONG, false
+	case uint32:
+		return structure.LONG, true
+	case int, int64:
+		return structure.LONGLONG, false
+	case uint, uint64:
+		return structure.LONGLONG, true
+	case float32:
+		return structure.FLOAT, false
+	case float64:
+		return structure.DOUBLE, false
+	case string, *string:
+		return structure.VAR_STRING, false
+	case time.Time:
+		return structure.DATETIME, false
+	case time.Duration:
+		return structure.TIME, false
+	}
+	if _, ok := asTime(v); ok {
+		return structure.TIME, false
+	}
+	if asDate(v) {
+		return structure.DATETIME, false
+	}
+	// the likes of decimals and blobs, that the decoder presents as text
+	// or base64.
+	return structure.BLOB, false
+}
+
+// WriteLogin writes the login packet the client sends in response to the
+// greeting, following the capabilities in it.
+func (e *Encoder) WriteLogin(l structure.LoginRequest) error {
+	var b bytes.Buffer
+	caps := l.ClientCapabilities
+	writeUint32(&b, uint32(caps))
+	writeUint32(&b, l.MaxPacketSize)
+	b.WriteByte(l.Collation)
+	b.Write(make([]byte, loginFiller))
+	writeUint32(&b, l.ExtendedCapabilities)
+	writeNulString(&b, l.Username)
+	switch {
+	case caps&structure.CCAP_PLUGIN_AUTH_LENENC_CLIENT_DATA != 0:
+		writeLenEncString(&b, string(l.AuthData))
+	case caps&structure.CCAP_SECURE_CONNECTION != 0:
+		b.WriteByte(byte(len(l.AuthData)))
+		b.Write(l.AuthData)
+	default:
+		writeNulString(&b, string(l.AuthData))
+	}
+	if caps&structure.CCAP_CONNECT_WITH_DB != 0 {
+		writeNulString(&b, l.Database)
+	}
+	if caps&structure.CCAP_PLUGIN_AUTH != 0 {
+		writeNulString(&b, l.AuthPlugin)
+	}
+	if caps&structure.CCAP_CONNECT_ATTRS != 0 {
+		keys := make([]string, 0, len(l.Attributes))
+		for k := range l.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var attrs bytes.Buffer
+		for _, k := range keys {
+			writeLenEncString(&attrs, k)
+			writeLenEncString(&attrs, l.Attributes[k])
+		}
+		writeLenEncInt(&b, uint64(attrs.Len()))
+		b.Write(attrs.Bytes())
+	}
+	return e.WritePacket(b.Bytes())
+}
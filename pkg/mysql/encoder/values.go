@@ -0,0 +1,464 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/decoding/bitmap"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+var errUnsupportedValue = errors.New("unsupported value")
+
+const (
+	dateLength           = 4
+	dateTimeLength       = 7
+	dateTimeMicroLength  = 11
+	timeLength           = 8
+	timeMicroLength      = 12
+	dateTimeFieldsNeeded = 3
+)
+
+// The values can be in the form the decoder produced them, or as they come
+// back from the json output.  Numbers from the json are best read with
+// UseNumber so that large integers aren't mangled.
+
+func unmarshalNumbers(data []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	return d.Decode(v)
+}
+
+func textRow(row []interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	for _, v := range row {
+		s, null, err := text(v)
+		if err != nil {
+			return nil, err
+		}
+		if null {
+			b.WriteByte(encodedNull)
+			continue
+		}
+		writeLenEncString(&b, s)
+	}
+	return b.Bytes(), nil
+}
+
+// text renders a value the way it's sent in a text result set.
+func text(v interface{}) (string, bool, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", true, nil
+	case *string:
+		if v == nil {
+			return "", true, nil
+		}
+		return *v, false, nil
+	case string:
+		return v, false, nil
+	case []byte:
+		return string(v), false, nil
+	case json.Number:
+		return string(v), false, nil
+	case bool:
+		if v {
+			return "1", false, nil
+		}
+		return "0", false, nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32), false, nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), false, nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v), false, nil
+	case struct{ Text string }:
+		return v.Text, false, nil
+	case struct{ Base64 []byte }:
+		return string(v.Base64), false, nil
+	case map[string]interface{}:
+		if s, ok := v["Text"].(string); ok {
+			return s, false, nil
+		}
+		if s, ok := v["Base64"].(string); ok {
+			data, err := base64.StdEncoding.DecodeString(s)
+			return string(data), false, errors.Wrap(err, "encode-text")
+		}
+	case json.Marshaler:
+		// the dates from the decoder present themselves as strings.
+		data, err := v.MarshalJSON()
+		if err != nil {
+			return "", false, errors.Wrap(err, "encode-text")
+		}
+		var s string
+		if err := json.Unmarshal(data, &s); err == nil {
+			return s, false, nil
+		}
+		return string(data), false, nil
+	case fmt.Stringer:
+		return v.String(), false, nil
+	}
+	if t, ok := asTime(v); ok {
+		return t.String(), false, nil
+	}
+	return "", false, errors.Wrap(errUnsupportedValue, fmt.Sprintf("encode-text %T", v))
+}
+
+func binaryRow(columns []structure.ColumnInfo, row []interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte(headerOK)
+	nulls := bitmap.Empty(len(columns), bitmap.ResultSetRow)
+	var values bytes.Buffer
+	for i, c := range columns {
+		var v interface{}
+		if i < len(row) {
+			v = row[i]
+		}
+		if isNull(v) {
+			nulls.SetNull(i)
+			continue
+		}
+		unsigned := c.TypeInfo.FieldDetail&structure.DETAIL_UNSIGNED != 0
+		if err := writeBinaryValue(&values, c.TypeInfo.FieldTypes, unsigned, v); err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("encode-binary-row %s", c.ColumnAlias))
+		}
+	}
+	b.Write(nulls.Data)
+	b.Write(values.Bytes())
+	return b.Bytes(), nil
+}
+
+func isNull(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	s, ok := v.(*string)
+	return ok && s == nil
+}
+
+// writeBinaryValue writes a value in the binary protocol form for the
+// type of the column.
+func writeBinaryValue(b *bytes.Buffer, fieldType structure.FieldType, unsigned bool, v interface{}) error {
+	switch fieldType {
+	case structure.NULL:
+		return nil
+	case structure.TINY, structure.SHORT, structure.YEAR,
+		structure.INT24, structure.LONG, structure.LONGLONG:
+		return writeInteger(b, fieldType, unsigned, v)
+	case structure.FLOAT:
+		f, err := toFloat(v)
+		if err != nil {
+			return err
+		}
+		return binary.Write(b, binary.LittleEndian, float32(f))
+	case structure.DOUBLE:
+		f, err := toFloat(v)
+		if err != nil {
+			return err
+		}
+		return binary.Write(b, binary.LittleEndian, f)
+	case structure.DATE, structure.DATETIME, structure.TIMESTAMP:
+		return writeDate(b, v)
+	case structure.TIME:
+		return writeTime(b, v)
+	}
+	s, _, err := text(v)
+	if err != nil {
+		return err
+	}
+	writeLenEncString(b, s)
+	return nil
+}
+
+func writeInteger(b *bytes.Buffer, fieldType structure.FieldType, unsigned bool, v interface{}) error {
+	var n uint64
+	if unsigned {
+		u, err := toUint(v)
+		if err != nil {
+			return err
+		}
+		n = u
+	} else {
+		i, err := toInt(v)
+		if err != nil {
+			return err
+		}
+		n = uint64(i)
+	}
+	var data [8]byte
+	binary.LittleEndian.PutUint64(data[:], n)
+	switch fieldType {
+	case structure.TINY:
+		b.Write(data[:1])
+	case structure.SHORT, structure.YEAR:
+		b.Write(data[:2])
+	case structure.INT24, structure.LONG:
+		b.Write(data[:4])
+	default:
+		b.Write(data[:])
+	}
+	return nil
+}
+
+func toInt(v interface{}) (int64, error) {
+	switch v := v.(type) {
+	case int:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint8:
+		return int64(v), nil
+	case uint16:
+		return int64(v), nil
+	case uint32:
+		return int64(v), nil
+	case float64:
+		return int64(v), nil
+	}
+	s, _, err := text(v)
+	if err != nil {
+		return 0, err
+	}
+	i, err := strconv.ParseInt(s, 10, 64)
+	return i, errors.Wrap(err, "encode-int")
+}
+
+func toUint(v interface{}) (uint64, error) {
+	switch v := v.(type) {
+	case uint:
+		return uint64(v), nil
+	case uint8:
+		return uint64(v), nil
+	case uint16:
+		return uint64(v), nil
+	case uint32:
+		return uint64(v), nil
+	case uint64:
+		return v, nil
+	case float64:
+		return uint64(v), nil
+	}
+	s, _, err := text(v)
+	if err != nil {
+		return 0, err
+	}
+	u, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		// the decoder gives signed values for the likes of YEAR.
+		i, ierr := strconv.ParseInt(s, 10, 64)
+		if ierr != nil {
+			return 0, errors.Wrap(err, "encode-uint")
+		}
+		return uint64(i), nil
+	}
+	return u, nil
+}
+
+func toFloat(v interface{}) (float64, error) {
+	switch v := v.(type) {
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	}
+	s, _, err := text(v)
+	if err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil && !strings.EqualFold(s, "nan") {
+		return 0, errors.Wrap(err, "encode-float")
+	}
+	if err != nil {
+		return math.NaN(), nil
+	}
+	return f, nil
+}
+
+// writeDate writes a DATE, DATETIME or TIMESTAMP.  The decoder presents
+// these as "2021-04-04", "2021-04-04 17:28:50" or "2021-04-04 17:28:50.0123"
+// and the length used depends on how much of that there is.
+func writeDate(b *bytes.Buffer, v interface{}) error {
+	var s string
+	if t, ok := v.(time.Time); ok {
+		s = t.Format("2006-01-02 15:04:05")
+		if t.Nanosecond() != 0 {
+			s = t.Format("2006-01-02 15:04:05.000000")
+		}
+	} else {
+		var err error
+		if s, _, err = text(v); err != nil {
+			return err
+		}
+	}
+	day, clock, hasTime := strings.Cut(s, " ")
+	clock, fraction, hasFraction := strings.Cut(clock, ".")
+	dateParts, err := numbers(day, "-")
+	if err != nil {
+		return errors.Wrap(err, "encode-date")
+	}
+	length := byte(dateLength)
+	var timeParts []uint64
+	if hasTime {
+		length = dateTimeLength
+		if timeParts, err = numbers(clock, ":"); err != nil {
+			return errors.Wrap(err, "encode-date")
+		}
+	}
+	var micro uint64
+	if hasFraction {
+		length = dateTimeMicroLength
+		if micro, err = strconv.ParseUint(fraction, 10, 32); err != nil {
+			return errors.Wrap(err, "encode-date")
+		}
+	}
+	b.WriteByte(length)
+	writeUint16(b, uint16(dateParts[0]))
+	b.WriteByte(byte(dateParts[1]))
+	b.WriteByte(byte(dateParts[2]))
+	if hasTime {
+		for _, n := range timeParts {
+			b.WriteByte(byte(n))
+		}
+	}
+	if hasFraction {
+		writeUint32(b, uint32(micro))
+	}
+	return nil
+}
+
+// asDate picks out the dates the decoder produces, which present
+// themselves as strings in the json.
+func asDate(v interface{}) bool {
+	m, ok := v.(json.Marshaler)
+	if !ok {
+		return false
+	}
+	data, err := m.MarshalJSON()
+	if err != nil {
+		return false
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return false
+	}
+	day, _, _ := strings.Cut(s, " ")
+	_, err = numbers(day, "-")
+	return err == nil
+}
+
+func numbers(s string, sep string) ([]uint64, error) {
+	parts := strings.Split(s, sep)
+	if len(parts) != dateTimeFieldsNeeded {
+		return nil, errors.Wrap(errUnsupportedValue, s)
+	}
+	values := make([]uint64, len(parts))
+	for i, p := range parts {
+		n, err := strconv.ParseUint(p, 10, 16)
+		if err != nil {
+			return nil, errors.Wrap(err, s)
+		}
+		values[i] = n
+	}
+	return values, nil
+}
+
+// timeValue is how the decoder presents a TIME.
+type timeValue struct {
+	Length       uint8
+	Negative     uint8
+	Date         uint32
+	Hour         uint8
+	Minutes      uint8
+	Seconds      uint8
+	MicroSeconds uint32
+}
+
+// String renders the time the way it's sent in a text result set.
+func (t timeValue) String() string {
+	sign := ""
+	if t.Negative != 0 {
+		sign = "-"
+	}
+	hours := t.Date*24 + uint32(t.Hour) //nolint:mnd
+	s := fmt.Sprintf("%s%02d:%02d:%02d", sign, hours, t.Minutes, t.Seconds)
+	if t.Length == timeMicroLength {
+		s += fmt.Sprintf(".%06d", t.MicroSeconds)
+	}
+	return s
+}
+
+// asTime picks out the struct the decoder produces for a TIME, as well as
+// the map read back from the json.  It's easiest to go via json to cover
+// both.
+func asTime(v interface{}) (timeValue, bool) {
+	var t timeValue
+	data, err := json.Marshal(v)
+	if err != nil {
+		return t, false
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return t, false
+	}
+	if _, ok := fields["Hour"]; !ok {
+		return t, false
+	}
+	if err := json.Unmarshal(data, &t); err != nil {
+		return t, false
+	}
+	if t.Length != timeMicroLength {
+		t.Length = timeLength
+	}
+	return t, true
+}
+
+func writeTime(b *bytes.Buffer, v interface{}) error {
+	var t timeValue
+	if d, ok := v.(time.Duration); ok {
+		if d < 0 {
+			t.Negative = 1
+			d = -d
+		}
+		//nolint:mnd
+		t.Date = uint32(d / (24 * time.Hour))
+		t.Hour = uint8((d / time.Hour) % 24)
+		t.Minutes = uint8((d / time.Minute) % 60)
+		t.Seconds = uint8((d / time.Second) % 60)
+		t.MicroSeconds = uint32((d % time.Second) / time.Microsecond)
+		t.Length = timeLength
+		if t.MicroSeconds > 0 {
+			t.Length = timeMicroLength
+		}
+	} else {
+		var ok bool
+		if t, ok = asTime(v); !ok {
+			return errors.Wrap(errUnsupportedValue, fmt.Sprintf("encode-time %T", v))
+		}
+	}
+	b.WriteByte(t.Length)
+	b.WriteByte(t.Negative)
+	writeUint32(b, t.Date)
+	b.WriteByte(t.Hour)
+	b.WriteByte(t.Minutes)
+	b.WriteByte(t.Seconds)
+	if t.Length == timeMicroLength {
+		writeUint32(b, t.MicroSeconds)
+	}
+	return nil
+}
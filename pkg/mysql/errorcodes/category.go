@@ -0,0 +1,72 @@
+package errorcodes
+
+import "strings"
+
+//nolint:gochecknoglobals
+var (
+	// overrides are the errors the rules below get wrong.
+	overrides = map[string]string{
+		"ER_ACCOUNT_HAS_BEEN_LOCKED":     Permission,
+		"ER_CLIENT_LOCAL_FILES_DISABLED": Permission,
+		"ER_HOST_IS_BLOCKED":             Permission,
+		"ER_OPTION_PREVENTS_STATEMENT":   Permission,
+		"ER_NET_PACKET_TOO_LARGE":        ClientError,
+		"ER_TOO_MANY_USER_CONNECTIONS":   Server,
+		"ER_USER_LIMIT_REACHED":          Server,
+	}
+
+	// words in the names that say what the error is about.
+	words = []struct {
+		category string
+		words    []string
+	}{
+		{Permission, []string{"DENIED", "PRIVILEGE", "PRIVILEGES", "PRIVILEGED", "GRANT", "PASSWORD"}},
+		{Lock, []string{"LOCK", "LOCKS", "LOCKED", "DEADLOCK", "READLOCK", "NOWAIT"}},
+		{Constraint, []string{"FK", "FOREIGN", "CONSTRAINT"}},
+		{Syntax, []string{"PARSE", "SYNTAX"}},
+		{Server, []string{
+			"FILE", "DIR", "DISK", "ERRNO", "MEMORY", "RESOURCES", "SHUTDOWN", "KILLED", "TIMEOUT",
+			"INTERRUPTED", "NET", "MASTER", "SLAVE", "REPLICA", "BINLOG", "RELAY", "CRASHED", "CORRUPT",
+			"THREAD", "STACK", "ZLIB", "FAIL", "FAILED", "UPGRADE", "DURING", "INNODB",
+		}},
+	}
+)
+
+// Category decides what kind of error it is from its name and SQL state.
+// The state is the best guide where it says something specific, otherwise
+// the words in the name are, and whatever's left is put down to the
+// client.  The name is empty for codes that aren't in the tables, which
+// only get a category if the state says enough.
+func Category(name, state string) string {
+	if c, ok := overrides[name]; ok {
+		return c
+	}
+	switch {
+	case strings.HasPrefix(state, "23"):
+		return Constraint
+	case strings.HasPrefix(state, "28"):
+		return Permission
+	case strings.HasPrefix(state, "40"):
+		return Lock
+	case strings.HasPrefix(state, "08"), strings.HasPrefix(state, "70"), state == "HY001":
+		// connection problems, interruptions and running out of memory.
+		return Server
+	}
+	parts := strings.Split(name, "_")
+	for _, w := range words {
+		for _, word := range w.words {
+			for _, part := range parts[1:] {
+				if part == word {
+					return w.category
+				}
+			}
+		}
+	}
+	switch {
+	case state == "42000":
+		return Syntax
+	case name == "":
+		return ""
+	}
+	return ClientError
+}
@@ -0,0 +1,49 @@
+// Package errorcodes is the catalogue of MySQL and MariaDB server errors,
+// giving the symbolic name for each code along with a broad category so
+// that errors can be grouped by what went wrong.
+package errorcodes
+
+import "strings"
+
+// The tables are generated from the error lists in the server sources,
+// mysqld_error.h or the message files it's made from for MySQL, and
+// sql/share/errmsg-utf8.txt for MariaDB.  The MySQL 8.0 errors in
+// gen/mysql-8.0.txt are added to MySQL's until the list is generated from
+// an 8.0 or later source.
+//go:generate go run ./gen -o tables.go --mysql $MYSQL_ERRORS --mysql gen/mysql-8.0.txt --mariadb $MARIADB_ERRORS
+
+// The categories errors are put into.
+const (
+	ClientError = "client error"
+	Constraint  = "constraint"
+	Lock        = "lock"
+	Permission  = "permission"
+	Syntax      = "syntax"
+	Server      = "server"
+)
+
+// Entry is what the catalogue knows about an error code.
+type Entry struct {
+	Name     string
+	Category string
+}
+
+// Lookup finds an error code in the catalogue for the server the version
+// string from the greeting belongs to, MySQL if it isn't known.  Codes
+// that aren't in the catalogue are given a category from their SQL state
+// where it says enough, by the same rules the tables were made with.
+func Lookup(code uint16, state, serverVersion string) Entry {
+	table := mysql
+	if IsMariaDB(serverVersion) {
+		table = mariadb
+	}
+	if e, ok := table[code]; ok {
+		return e
+	}
+	return Entry{Category: Category("", state)}
+}
+
+// IsMariaDB is true for the version strings MariaDB servers send.
+func IsMariaDB(serverVersion string) bool {
+	return strings.Contains(serverVersion, "MariaDB")
+}
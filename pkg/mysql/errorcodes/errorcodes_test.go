@@ -0,0 +1,115 @@
+package errorcodes_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/errorcodes"
+)
+
+func TestLookup(t *testing.T) {
+	const mariadb = "5.5.5-10.6.5-MariaDB-1:10.6.5+maria~focal"
+	tests := []struct {
+		code     uint16
+		state    string
+		version  string
+		expected errorcodes.Entry
+	}{
+		{1062, "23000", "8.0.36", errorcodes.Entry{Name: "ER_DUP_ENTRY", Category: "constraint"}},
+		{1213, "40001", mariadb, errorcodes.Entry{Name: "ER_LOCK_DEADLOCK", Category: "lock"}},
+		{1045, "28000", "", errorcodes.Entry{Name: "ER_ACCESS_DENIED_ERROR", Category: "permission"}},
+		{1064, "42000", "5.7.25", errorcodes.Entry{Name: "ER_PARSE_ERROR", Category: "syntax"}},
+		{1146, "42S02", "5.7.25", errorcodes.Entry{Name: "ER_NO_SUCH_TABLE", Category: "client error"}},
+		{1021, "HY000", "5.7.25", errorcodes.Entry{Name: "ER_DISK_FULL", Category: "server"}},
+		// the same number means different things to the two servers.
+		{1076, "HY000", "8.0.36", errorcodes.Entry{Name: "ER_READY", Category: "client error"}},
+		{1076, "HY000", mariadb, errorcodes.Entry{Name: "ER_BINLOG_CANT_DELETE_GTID_DOMAIN", Category: "server"}},
+		{3024, "HY000", "8.0.36", errorcodes.Entry{Name: "ER_QUERY_TIMEOUT", Category: "server"}},
+		{4025, "23000", mariadb, errorcodes.Entry{Name: "ER_CONSTRAINT_FAILED", Category: "constraint"}},
+		{1969, "70100", mariadb, errorcodes.Entry{Name: "ER_STATEMENT_TIMEOUT", Category: "server"}},
+		{1969, "", "8.0.36", errorcodes.Entry{}},
+		// unknown codes go by their SQL state.
+		{3819, "23000", mariadb, errorcodes.Entry{Category: "constraint"}},
+		{9999, "40001", "8.0.36", errorcodes.Entry{Category: "lock"}},
+		{9999, "HY000", "8.0.36", errorcodes.Entry{}},
+		{9999, "70100", "8.0.36", errorcodes.Entry{Category: "server"}},
+		// the 8.0 errors 5.7 doesn't have.
+		{3572, "HY000", "8.0.36", errorcodes.Entry{Name: "ER_LOCK_NOWAIT", Category: "lock"}},
+		{3819, "HY000", "8.0.36", errorcodes.Entry{Name: "ER_CHECK_CONSTRAINT_VIOLATED", Category: "constraint"}},
+		{3948, "42000", "8.0.36", errorcodes.Entry{Name: "ER_CLIENT_LOCAL_FILES_DISABLED", Category: "permission"}},
+		{4031, "HY000", "8.0.36", errorcodes.Entry{Name: "ER_CLIENT_INTERACTION_TIMEOUT", Category: "server"}},
+	}
+	for _, test := range tests {
+		got := errorcodes.Lookup(test.code, test.state, test.version)
+		if diff := cmp.Diff(got, test.expected); diff != "" {
+			t.Errorf("%d %s doesn't match (-got +expected):\n%s\n", test.code, test.version, diff)
+		}
+	}
+}
+
+// TestCoverage checks the tables have every error the servers still use
+// in each range of codes, and none of the client library's, 2000 to 2999.
+func TestCoverage(t *testing.T) {
+	const mariadb = "5.5.5-10.6.5-MariaDB-1:10.6.5+maria~focal"
+	ranges := []struct {
+		version     string
+		first, last uint16
+		retired     []uint16
+	}{
+		{"8.0.36", 1000, 1888, []uint16{1150, 1151, 1165, 1349, 1557, 1611, 1669, 1749, 1784, 1834, 1852}},
+		{"8.0.36", 3000, 3238, nil},
+		{mariadb, 1000, 1982, []uint16{
+			1101, 1185, 1448, 1547, 1548, 1557, 1561, 1608, 1625, 1720, 1721, 1725, 1749, 1807,
+			1881, 1882, 1883, 1884, 1885, 1886, 1887, 1888, 1889, 1890, 1891, 1892, 1893, 1894,
+			1895, 1896, 1897, 1898, 1899, 1900, 1902, 1908, 1909, 1913, 1914, 1915, 1928, 1972,
+		}},
+		{mariadb, 4002, 4208, []uint16{4117, 4136}},
+	}
+	for _, r := range ranges {
+		var missing []uint16
+		for code := r.first; code <= r.last; code++ {
+			if errorcodes.Lookup(code, "", r.version).Name == "" {
+				missing = append(missing, code)
+			}
+		}
+		if diff := cmp.Diff(missing, r.retired); diff != "" {
+			t.Errorf("%s %d to %d missing codes don't match (-got +expected):\n%s\n", r.version, r.first, r.last, diff)
+		}
+	}
+	for _, version := range []string{"8.0.36", mariadb} {
+		for code := uint16(2000); code < 3000; code++ {
+			if e := errorcodes.Lookup(code, "", version); e.Name != "" {
+				t.Errorf("client library error %d %s is in the %s table", code, e.Name, version)
+			}
+		}
+	}
+}
+
+func TestCategory(t *testing.T) {
+	tests := []struct {
+		name, state, expected string
+	}{
+		{"ER_DUP_ENTRY", "23000", errorcodes.Constraint},
+		{"ER_LOCK_DEADLOCK", "40001", errorcodes.Lock},
+		{"ER_ACCESS_DENIED_ERROR", "28000", errorcodes.Permission},
+		{"ER_QUERY_INTERRUPTED", "70100", errorcodes.Server},
+		{"ER_TABLEACCESS_DENIED_ERROR", "42000", errorcodes.Permission},
+		{"ER_LOCK_WAIT_TIMEOUT", "HY000", errorcodes.Lock},
+		{"ER_PARSE_ERROR", "42000", errorcodes.Syntax},
+		{"ER_BAD_DB_ERROR", "42000", errorcodes.Syntax},
+		{"ER_DISK_FULL", "", errorcodes.Server},
+		{"ER_NO_SUCH_TABLE", "42S02", errorcodes.ClientError},
+		{"ER_HOST_IS_BLOCKED", "HY000", errorcodes.Permission},
+		// codes that aren't in the tables only have the state to go on.
+		{"", "HY001", errorcodes.Server},
+		{"", "42000", errorcodes.Syntax},
+		{"", "42S02", ""},
+		{"", "HY000", ""},
+	}
+	for _, test := range tests {
+		if got := errorcodes.Category(test.name, test.state); got != test.expected {
+			t.Errorf("%s %s: expected %s, got %s", test.name, test.state, test.expected, got)
+		}
+	}
+}
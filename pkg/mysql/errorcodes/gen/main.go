@@ -0,0 +1,200 @@
+// Command gen writes the error code tables for the errorcodes package from
+// the server sources.  MySQL's come from mysqld_error.h, as found in a
+// build or an install, or from the errmsg-utf8.txt (5.7) or
+// messages_to_clients.txt (8.0 on) it is generated from.  MariaDB's come
+// from sql/share/errmsg-utf8.txt.  --mysql can be given more than once to
+// merge the lists, with the later ones winning where they disagree.
+//
+//	go run ./gen -o tables.go --mysql mysqld_error.h --mariadb errmsg-utf8.txt
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/errorcodes"
+)
+
+func main() {
+	var output, mariadb string
+	var mysql []string
+	pflag.StringVarP(&output, "output", "o", "tables.go", "File to write the tables to")
+	pflag.StringArrayVar(&mysql, "mysql", nil, "MySQL's mysqld_error.h, errmsg-utf8.txt or messages_to_clients.txt")
+	pflag.StringVar(&mariadb, "mariadb", "", "MariaDB's errmsg-utf8.txt")
+	pflag.Parse()
+	if len(mysql) == 0 || mariadb == "" {
+		pflag.Usage()
+		os.Exit(1)
+	}
+
+	names := make([]string, len(mysql))
+	for i, name := range mysql {
+		names[i] = filepath.Base(name)
+	}
+	var tables bytes.Buffer
+	fmt.Fprintf(&tables, `// Code generated by gen from MySQL's %s and MariaDB's %s; DO NOT EDIT.
+
+package errorcodes
+
+//nolint:gochecknoglobals
+var (
+`, strings.Join(names, ", "), filepath.Base(mariadb))
+	for _, t := range []struct {
+		name  string
+		files []string
+	}{{"mysql", mysql}, {"mariadb", []string{mariadb}}} {
+		entries, err := readFiles(t.files)
+		if err != nil {
+			log.Fatal(err)
+		}
+		writeTable(&tables, t.name, entries)
+	}
+	tables.WriteString(")\n")
+
+	src, err := format.Source(tables.Bytes())
+	if err != nil {
+		log.Fatal(errors.Wrap(err, "gen-format"))
+	}
+	if err := os.WriteFile(output, src, 0o600); err != nil {
+		log.Fatal(errors.Wrap(err, "gen-write"))
+	}
+}
+
+// entry is an error from the sources.
+type entry struct {
+	code  int
+	name  string
+	state string
+}
+
+// readFiles merges the errors from the files, the later ones replacing the
+// earlier ones' errors with the same codes.
+func readFiles(names []string) ([]entry, error) {
+	byCode := make(map[int]entry)
+	for _, name := range names {
+		entries, err := readFile(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			byCode[e.code] = e
+		}
+	}
+	merged := make([]entry, 0, len(byCode))
+	for _, e := range byCode {
+		merged = append(merged, e)
+	}
+	return merged, nil
+}
+
+func readFile(name string) ([]entry, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "gen-open")
+	}
+	defer f.Close()
+	if strings.HasSuffix(name, ".h") {
+		return readHeader(f)
+	}
+	return readMessages(f)
+}
+
+// readHeader reads the #define for each error in mysqld_error.h.  The
+// header doesn't have the SQL states.
+func readHeader(r io.Reader) ([]entry, error) {
+	var entries []entry
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) != 3 || fields[0] != "#define" {
+			continue
+		}
+		code, err := strconv.Atoi(fields[2])
+		if err != nil || strings.HasSuffix(fields[1], "_FIRST") || strings.HasSuffix(fields[1], "_LAST") {
+			// the limits of the ranges aren't errors themselves.
+			continue
+		}
+		entries = add(entries, entry{code: code, name: fields[1]})
+	}
+	return entries, errors.Wrap(s.Err(), "gen-read-header")
+}
+
+// readMessages reads the message files the headers are generated from.
+// Each error is a name, optionally followed by its SQL state, on a line of
+// its own with the translations of the message indented below it.  The
+// codes count up from the last start-error-number or skip-to-error-number.
+func readMessages(r io.Reader) ([]entry, error) {
+	var entries []entry
+	code := 0
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20) //nolint:mnd
+	for s.Scan() {
+		line := s.Text()
+		fields := strings.Fields(line)
+		if len(fields) == 0 || line[0] == ' ' || line[0] == '\t' || line[0] == '#' {
+			continue
+		}
+		switch fields[0] {
+		case "start-error-number", "skip-to-error-number":
+			n, err := strconv.Atoi(fields[len(fields)-1])
+			if err != nil {
+				return nil, errors.Wrap(err, "gen-read-messages")
+			}
+			code = n
+			continue
+		case "languages", "default-language", "reserved-error-section":
+			continue
+		}
+		e := entry{code: code, name: fields[0]}
+		if len(fields) > 1 {
+			e.state = fields[1]
+		}
+		entries = add(entries, e)
+		code++
+	}
+	return entries, errors.Wrap(s.Err(), "gen-read-messages")
+}
+
+// add adds the error unless it's a placeholder for a number that isn't
+// used any more, or that MariaDB keeps clear because MySQL uses it.
+func add(entries []entry, e entry) []entry {
+	if e.code < 1 || e.code > 0xffff || strings.HasPrefix(e.name, "OBSOLETE_") ||
+		strings.HasPrefix(e.name, "ER_UNUSED") || strings.HasSuffix(e.name, "_UNUSED") ||
+		e.name == fmt.Sprintf("ER_MYSQL_%d", e.code) {
+		return entries
+	}
+	return append(entries, e)
+}
+
+// constants are the names of the errorcodes constants for the categories.
+//
+//nolint:gochecknoglobals
+var constants = map[string]string{
+	errorcodes.ClientError: "ClientError",
+	errorcodes.Constraint:  "Constraint",
+	errorcodes.Lock:        "Lock",
+	errorcodes.Permission:  "Permission",
+	errorcodes.Syntax:      "Syntax",
+	errorcodes.Server:      "Server",
+}
+
+func writeTable(w io.Writer, name string, entries []entry) {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].code < entries[j].code })
+	fmt.Fprintf(w, "%s = map[uint16]Entry{\n", name)
+	for _, e := range entries {
+		fmt.Fprintf(w, "%d: {%q, %s},\n", e.code, e.name, constants[errorcodes.Category(e.name, e.state)])
+	}
+	fmt.Fprintf(w, "}\n")
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadMessages(t *testing.T) {
+	input := `languages czech=cze latin2, english=eng latin1;
+
+default-language eng
+
+start-error-number 1000
+
+ER_HASHCHK
+        eng "hashchk"
+ER_DUP_KEY 23000
+        cze "Nemohu zapsat, zdvojený klíč v tabulce '%-.192s'"
+        eng "Can't write; duplicate key in table '%-.192s'"
+ER_UNUSED_1
+        eng "You should never see it"
+# a comment
+skip-to-error-number 3000
+
+ER_MYSQL_3000
+        eng "Reserved for MySQL"
+ER_FILE_CORRUPT
+        eng "File %s is corrupted"
+`
+	got, err := readMessages(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []entry{
+		{code: 1000, name: "ER_HASHCHK"},
+		{code: 1001, name: "ER_DUP_KEY", state: "23000"},
+		{code: 3001, name: "ER_FILE_CORRUPT"},
+	}
+	if diff := cmp.Diff(got, expected, cmp.AllowUnexported(entry{})); diff != "" {
+		t.Errorf("Entries don't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestReadHeader(t *testing.T) {
+	input := `#ifndef MYSQLD_ERROR_INCLUDED
+#define MYSQLD_ERROR_INCLUDED
+static const int errmsg_section_start[] = { 1000, 3000 };
+#define ER_ERROR_FIRST 1000
+#define ER_HASHCHK 1000
+#define OBSOLETE_ER_NISAMCHK 1001
+#define ER_CHECK_CONSTRAINT_VIOLATED 3819
+#define ER_ERROR_LAST 3819
+#endif
+`
+	got, err := readHeader(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []entry{
+		{code: 1000, name: "ER_HASHCHK"},
+		{code: 3819, name: "ER_CHECK_CONSTRAINT_VIOLATED"},
+	}
+	if diff := cmp.Diff(got, expected, cmp.AllowUnexported(entry{})); diff != "" {
+		t.Errorf("Entries don't match (-got +expected):\n%s\n", diff)
+	}
+}
@@ -0,0 +1,2341 @@
+// Code generated by gen from MySQL's errmsg-utf8.txt, mysql-8.0.txt and MariaDB's errmsg-utf8.txt; DO NOT EDIT.
+
+package errorcodes
+
+//nolint:gochecknoglobals
+var (
+	mysql = map[uint16]Entry{
+		1000: {"ER_HASHCHK", ClientError},
+		1001: {"ER_NISAMCHK", ClientError},
+		1002: {"ER_NO", ClientError},
+		1003: {"ER_YES", ClientError},
+		1004: {"ER_CANT_CREATE_FILE", Server},
+		1005: {"ER_CANT_CREATE_TABLE", ClientError},
+		1006: {"ER_CANT_CREATE_DB", ClientError},
+		1007: {"ER_DB_CREATE_EXISTS", ClientError},
+		1008: {"ER_DB_DROP_EXISTS", ClientError},
+		1009: {"ER_DB_DROP_DELETE", ClientError},
+		1010: {"ER_DB_DROP_RMDIR", ClientError},
+		1011: {"ER_CANT_DELETE_FILE", Server},
+		1012: {"ER_CANT_FIND_SYSTEM_REC", ClientError},
+		1013: {"ER_CANT_GET_STAT", ClientError},
+		1014: {"ER_CANT_GET_WD", ClientError},
+		1015: {"ER_CANT_LOCK", Lock},
+		1016: {"ER_CANT_OPEN_FILE", Server},
+		1017: {"ER_FILE_NOT_FOUND", Server},
+		1018: {"ER_CANT_READ_DIR", Server},
+		1019: {"ER_CANT_SET_WD", ClientError},
+		1020: {"ER_CHECKREAD", ClientError},
+		1021: {"ER_DISK_FULL", Server},
+		1022: {"ER_DUP_KEY", Constraint},
+		1023: {"ER_ERROR_ON_CLOSE", ClientError},
+		1024: {"ER_ERROR_ON_READ", ClientError},
+		1025: {"ER_ERROR_ON_RENAME", ClientError},
+		1026: {"ER_ERROR_ON_WRITE", ClientError},
+		1027: {"ER_FILE_USED", Server},
+		1028: {"ER_FILSORT_ABORT", ClientError},
+		1029: {"ER_FORM_NOT_FOUND", ClientError},
+		1030: {"ER_GET_ERRNO", Server},
+		1031: {"ER_ILLEGAL_HA", ClientError},
+		1032: {"ER_KEY_NOT_FOUND", ClientError},
+		1033: {"ER_NOT_FORM_FILE", Server},
+		1034: {"ER_NOT_KEYFILE", ClientError},
+		1035: {"ER_OLD_KEYFILE", ClientError},
+		1036: {"ER_OPEN_AS_READONLY", ClientError},
+		1037: {"ER_OUTOFMEMORY", Server},
+		1038: {"ER_OUT_OF_SORTMEMORY", Server},
+		1039: {"ER_UNEXPECTED_EOF", ClientError},
+		1040: {"ER_CON_COUNT_ERROR", Server},
+		1041: {"ER_OUT_OF_RESOURCES", Server},
+		1042: {"ER_BAD_HOST_ERROR", Server},
+		1043: {"ER_HANDSHAKE_ERROR", Server},
+		1044: {"ER_DBACCESS_DENIED_ERROR", Permission},
+		1045: {"ER_ACCESS_DENIED_ERROR", Permission},
+		1046: {"ER_NO_DB_ERROR", ClientError},
+		1047: {"ER_UNKNOWN_COM_ERROR", Server},
+		1048: {"ER_BAD_NULL_ERROR", Constraint},
+		1049: {"ER_BAD_DB_ERROR", Syntax},
+		1050: {"ER_TABLE_EXISTS_ERROR", ClientError},
+		1051: {"ER_BAD_TABLE_ERROR", ClientError},
+		1052: {"ER_NON_UNIQ_ERROR", Constraint},
+		1053: {"ER_SERVER_SHUTDOWN", Server},
+		1054: {"ER_BAD_FIELD_ERROR", ClientError},
+		1055: {"ER_WRONG_FIELD_WITH_GROUP", Syntax},
+		1056: {"ER_WRONG_GROUP_FIELD", Syntax},
+		1057: {"ER_WRONG_SUM_SELECT", Syntax},
+		1058: {"ER_WRONG_VALUE_COUNT", ClientError},
+		1059: {"ER_TOO_LONG_IDENT", Syntax},
+		1060: {"ER_DUP_FIELDNAME", ClientError},
+		1061: {"ER_DUP_KEYNAME", Syntax},
+		1062: {"ER_DUP_ENTRY", Constraint},
+		1063: {"ER_WRONG_FIELD_SPEC", Syntax},
+		1064: {"ER_PARSE_ERROR", Syntax},
+		1065: {"ER_EMPTY_QUERY", Syntax},
+		1066: {"ER_NONUNIQ_TABLE", Syntax},
+		1067: {"ER_INVALID_DEFAULT", Syntax},
+		1068: {"ER_MULTIPLE_PRI_KEY", Syntax},
+		1069: {"ER_TOO_MANY_KEYS", Syntax},
+		1070: {"ER_TOO_MANY_KEY_PARTS", Syntax},
+		1071: {"ER_TOO_LONG_KEY", Syntax},
+		1072: {"ER_KEY_COLUMN_DOES_NOT_EXITS", Syntax},
+		1073: {"ER_BLOB_USED_AS_KEY", Syntax},
+		1074: {"ER_TOO_BIG_FIELDLENGTH", Syntax},
+		1075: {"ER_WRONG_AUTO_KEY", Syntax},
+		1076: {"ER_READY", ClientError},
+		1077: {"ER_NORMAL_SHUTDOWN", Server},
+		1078: {"ER_GOT_SIGNAL", ClientError},
+		1079: {"ER_SHUTDOWN_COMPLETE", Server},
+		1080: {"ER_FORCING_CLOSE", Server},
+		1081: {"ER_IPSOCK_ERROR", Server},
+		1082: {"ER_NO_SUCH_INDEX", ClientError},
+		1083: {"ER_WRONG_FIELD_TERMINATORS", Syntax},
+		1084: {"ER_BLOBS_AND_NO_TERMINATED", Syntax},
+		1085: {"ER_TEXTFILE_NOT_READABLE", ClientError},
+		1086: {"ER_FILE_EXISTS_ERROR", Server},
+		1087: {"ER_LOAD_INFO", ClientError},
+		1088: {"ER_ALTER_INFO", ClientError},
+		1089: {"ER_WRONG_SUB_KEY", ClientError},
+		1090: {"ER_CANT_REMOVE_ALL_FIELDS", Syntax},
+		1091: {"ER_CANT_DROP_FIELD_OR_KEY", Syntax},
+		1092: {"ER_INSERT_INFO", ClientError},
+		1093: {"ER_UPDATE_TABLE_USED", ClientError},
+		1094: {"ER_NO_SUCH_THREAD", Server},
+		1095: {"ER_KILL_DENIED_ERROR", Permission},
+		1096: {"ER_NO_TABLES_USED", ClientError},
+		1097: {"ER_TOO_BIG_SET", ClientError},
+		1098: {"ER_NO_UNIQUE_LOGFILE", ClientError},
+		1099: {"ER_TABLE_NOT_LOCKED_FOR_WRITE", Lock},
+		1100: {"ER_TABLE_NOT_LOCKED", Lock},
+		1101: {"ER_BLOB_CANT_HAVE_DEFAULT", Syntax},
+		1102: {"ER_WRONG_DB_NAME", Syntax},
+		1103: {"ER_WRONG_TABLE_NAME", Syntax},
+		1104: {"ER_TOO_BIG_SELECT", Syntax},
+		1105: {"ER_UNKNOWN_ERROR", ClientError},
+		1106: {"ER_UNKNOWN_PROCEDURE", Syntax},
+		1107: {"ER_WRONG_PARAMCOUNT_TO_PROCEDURE", Syntax},
+		1108: {"ER_WRONG_PARAMETERS_TO_PROCEDURE", ClientError},
+		1109: {"ER_UNKNOWN_TABLE", ClientError},
+		1110: {"ER_FIELD_SPECIFIED_TWICE", Syntax},
+		1111: {"ER_INVALID_GROUP_FUNC_USE", ClientError},
+		1112: {"ER_UNSUPPORTED_EXTENSION", Syntax},
+		1113: {"ER_TABLE_MUST_HAVE_COLUMNS", Syntax},
+		1114: {"ER_RECORD_FILE_FULL", Server},
+		1115: {"ER_UNKNOWN_CHARACTER_SET", Syntax},
+		1116: {"ER_TOO_MANY_TABLES", ClientError},
+		1117: {"ER_TOO_MANY_FIELDS", ClientError},
+		1118: {"ER_TOO_BIG_ROWSIZE", Syntax},
+		1119: {"ER_STACK_OVERRUN", Server},
+		1120: {"ER_WRONG_OUTER_JOIN", Syntax},
+		1121: {"ER_NULL_COLUMN_IN_INDEX", Syntax},
+		1122: {"ER_CANT_FIND_UDF", ClientError},
+		1123: {"ER_CANT_INITIALIZE_UDF", ClientError},
+		1124: {"ER_UDF_NO_PATHS", ClientError},
+		1125: {"ER_UDF_EXISTS", ClientError},
+		1126: {"ER_CANT_OPEN_LIBRARY", ClientError},
+		1127: {"ER_CANT_FIND_DL_ENTRY", ClientError},
+		1128: {"ER_FUNCTION_NOT_DEFINED", ClientError},
+		1129: {"ER_HOST_IS_BLOCKED", Permission},
+		1130: {"ER_HOST_NOT_PRIVILEGED", Permission},
+		1131: {"ER_PASSWORD_ANONYMOUS_USER", Permission},
+		1132: {"ER_PASSWORD_NOT_ALLOWED", Permission},
+		1133: {"ER_PASSWORD_NO_MATCH", Permission},
+		1134: {"ER_UPDATE_INFO", ClientError},
+		1135: {"ER_CANT_CREATE_THREAD", Server},
+		1136: {"ER_WRONG_VALUE_COUNT_ON_ROW", ClientError},
+		1137: {"ER_CANT_REOPEN_TABLE", ClientError},
+		1138: {"ER_INVALID_USE_OF_NULL", ClientError},
+		1139: {"ER_REGEXP_ERROR", Syntax},
+		1140: {"ER_MIX_OF_GROUP_FUNC_AND_FIELDS", Syntax},
+		1141: {"ER_NONEXISTING_GRANT", Permission},
+		1142: {"ER_TABLEACCESS_DENIED_ERROR", Permission},
+		1143: {"ER_COLUMNACCESS_DENIED_ERROR", Permission},
+		1144: {"ER_ILLEGAL_GRANT_FOR_TABLE", Permission},
+		1145: {"ER_GRANT_WRONG_HOST_OR_USER", Permission},
+		1146: {"ER_NO_SUCH_TABLE", ClientError},
+		1147: {"ER_NONEXISTING_TABLE_GRANT", Permission},
+		1148: {"ER_NOT_ALLOWED_COMMAND", Syntax},
+		1149: {"ER_SYNTAX_ERROR", Syntax},
+		1152: {"ER_ABORTING_CONNECTION", Server},
+		1153: {"ER_NET_PACKET_TOO_LARGE", ClientError},
+		1154: {"ER_NET_READ_ERROR_FROM_PIPE", Server},
+		1155: {"ER_NET_FCNTL_ERROR", Server},
+		1156: {"ER_NET_PACKETS_OUT_OF_ORDER", Server},
+		1157: {"ER_NET_UNCOMPRESS_ERROR", Server},
+		1158: {"ER_NET_READ_ERROR", Server},
+		1159: {"ER_NET_READ_INTERRUPTED", Server},
+		1160: {"ER_NET_ERROR_ON_WRITE", Server},
+		1161: {"ER_NET_WRITE_INTERRUPTED", Server},
+		1162: {"ER_TOO_LONG_STRING", Syntax},
+		1163: {"ER_TABLE_CANT_HANDLE_BLOB", Syntax},
+		1164: {"ER_TABLE_CANT_HANDLE_AUTO_INCREMENT", Syntax},
+		1166: {"ER_WRONG_COLUMN_NAME", Syntax},
+		1167: {"ER_WRONG_KEY_COLUMN", Syntax},
+		1168: {"ER_WRONG_MRG_TABLE", ClientError},
+		1169: {"ER_DUP_UNIQUE", Constraint},
+		1170: {"ER_BLOB_KEY_WITHOUT_LENGTH", Syntax},
+		1171: {"ER_PRIMARY_CANT_HAVE_NULL", Syntax},
+		1172: {"ER_TOO_MANY_ROWS", Syntax},
+		1173: {"ER_REQUIRES_PRIMARY_KEY", Syntax},
+		1174: {"ER_NO_RAID_COMPILED", ClientError},
+		1175: {"ER_UPDATE_WITHOUT_KEY_IN_SAFE_MODE", ClientError},
+		1176: {"ER_KEY_DOES_NOT_EXITS", Syntax},
+		1177: {"ER_CHECK_NO_SUCH_TABLE", Syntax},
+		1178: {"ER_CHECK_NOT_IMPLEMENTED", Syntax},
+		1179: {"ER_CANT_DO_THIS_DURING_AN_TRANSACTION", Server},
+		1180: {"ER_ERROR_DURING_COMMIT", Server},
+		1181: {"ER_ERROR_DURING_ROLLBACK", Server},
+		1182: {"ER_ERROR_DURING_FLUSH_LOGS", Server},
+		1183: {"ER_ERROR_DURING_CHECKPOINT", Server},
+		1184: {"ER_NEW_ABORTING_CONNECTION", Server},
+		1185: {"ER_DUMP_NOT_IMPLEMENTED", ClientError},
+		1186: {"ER_FLUSH_MASTER_BINLOG_CLOSED", Server},
+		1187: {"ER_INDEX_REBUILD", ClientError},
+		1188: {"ER_MASTER", Server},
+		1189: {"ER_MASTER_NET_READ", Server},
+		1190: {"ER_MASTER_NET_WRITE", Server},
+		1191: {"ER_FT_MATCHING_KEY_NOT_FOUND", ClientError},
+		1192: {"ER_LOCK_OR_ACTIVE_TRANSACTION", Lock},
+		1193: {"ER_UNKNOWN_SYSTEM_VARIABLE", ClientError},
+		1194: {"ER_CRASHED_ON_USAGE", Server},
+		1195: {"ER_CRASHED_ON_REPAIR", Server},
+		1196: {"ER_WARNING_NOT_COMPLETE_ROLLBACK", ClientError},
+		1197: {"ER_TRANS_CACHE_FULL", ClientError},
+		1198: {"ER_SLAVE_MUST_STOP", Server},
+		1199: {"ER_SLAVE_NOT_RUNNING", Server},
+		1200: {"ER_BAD_SLAVE", Server},
+		1201: {"ER_MASTER_INFO", Server},
+		1202: {"ER_SLAVE_THREAD", Server},
+		1203: {"ER_TOO_MANY_USER_CONNECTIONS", Server},
+		1204: {"ER_SET_CONSTANTS_ONLY", ClientError},
+		1205: {"ER_LOCK_WAIT_TIMEOUT", Lock},
+		1206: {"ER_LOCK_TABLE_FULL", Lock},
+		1207: {"ER_READ_ONLY_TRANSACTION", ClientError},
+		1208: {"ER_DROP_DB_WITH_READ_LOCK", Lock},
+		1209: {"ER_CREATE_DB_WITH_READ_LOCK", Lock},
+		1210: {"ER_WRONG_ARGUMENTS", ClientError},
+		1211: {"ER_NO_PERMISSION_TO_CREATE_USER", Syntax},
+		1212: {"ER_UNION_TABLES_IN_DIFFERENT_DIR", Server},
+		1213: {"ER_LOCK_DEADLOCK", Lock},
+		1214: {"ER_TABLE_CANT_HANDLE_FT", ClientError},
+		1215: {"ER_CANNOT_ADD_FOREIGN", Constraint},
+		1216: {"ER_NO_REFERENCED_ROW", Constraint},
+		1217: {"ER_ROW_IS_REFERENCED", Constraint},
+		1218: {"ER_CONNECT_TO_MASTER", Server},
+		1219: {"ER_QUERY_ON_MASTER", Server},
+		1220: {"ER_ERROR_WHEN_EXECUTING_COMMAND", ClientError},
+		1221: {"ER_WRONG_USAGE", ClientError},
+		1222: {"ER_WRONG_NUMBER_OF_COLUMNS_IN_SELECT", ClientError},
+		1223: {"ER_CANT_UPDATE_WITH_READLOCK", Lock},
+		1224: {"ER_MIXING_NOT_ALLOWED", ClientError},
+		1225: {"ER_DUP_ARGUMENT", ClientError},
+		1226: {"ER_USER_LIMIT_REACHED", Server},
+		1227: {"ER_SPECIFIC_ACCESS_DENIED_ERROR", Permission},
+		1228: {"ER_LOCAL_VARIABLE", ClientError},
+		1229: {"ER_GLOBAL_VARIABLE", ClientError},
+		1230: {"ER_NO_DEFAULT", Syntax},
+		1231: {"ER_WRONG_VALUE_FOR_VAR", Syntax},
+		1232: {"ER_WRONG_TYPE_FOR_VAR", Syntax},
+		1233: {"ER_VAR_CANT_BE_READ", ClientError},
+		1234: {"ER_CANT_USE_OPTION_HERE", Syntax},
+		1235: {"ER_NOT_SUPPORTED_YET", Syntax},
+		1236: {"ER_MASTER_FATAL_ERROR_READING_BINLOG", Server},
+		1237: {"ER_SLAVE_IGNORED_TABLE", Server},
+		1238: {"ER_INCORRECT_GLOBAL_LOCAL_VAR", ClientError},
+		1239: {"ER_WRONG_FK_DEF", Constraint},
+		1240: {"ER_KEY_REF_DO_NOT_MATCH_TABLE_REF", ClientError},
+		1241: {"ER_OPERAND_COLUMNS", ClientError},
+		1242: {"ER_SUBQUERY_NO_1_ROW", ClientError},
+		1243: {"ER_UNKNOWN_STMT_HANDLER", ClientError},
+		1244: {"ER_CORRUPT_HELP_DB", Server},
+		1245: {"ER_CYCLIC_REFERENCE", ClientError},
+		1246: {"ER_AUTO_CONVERT", ClientError},
+		1247: {"ER_ILLEGAL_REFERENCE", ClientError},
+		1248: {"ER_DERIVED_MUST_HAVE_ALIAS", Syntax},
+		1249: {"ER_SELECT_REDUCED", ClientError},
+		1250: {"ER_TABLENAME_NOT_ALLOWED_HERE", Syntax},
+		1251: {"ER_NOT_SUPPORTED_AUTH_MODE", Server},
+		1252: {"ER_SPATIAL_CANT_HAVE_NULL", Syntax},
+		1253: {"ER_COLLATION_CHARSET_MISMATCH", Syntax},
+		1254: {"ER_SLAVE_WAS_RUNNING", Server},
+		1255: {"ER_SLAVE_WAS_NOT_RUNNING", Server},
+		1256: {"ER_TOO_BIG_FOR_UNCOMPRESS", ClientError},
+		1257: {"ER_ZLIB_Z_MEM_ERROR", Server},
+		1258: {"ER_ZLIB_Z_BUF_ERROR", Server},
+		1259: {"ER_ZLIB_Z_DATA_ERROR", Server},
+		1260: {"ER_CUT_VALUE_GROUP_CONCAT", ClientError},
+		1261: {"ER_WARN_TOO_FEW_RECORDS", ClientError},
+		1262: {"ER_WARN_TOO_MANY_RECORDS", ClientError},
+		1263: {"ER_WARN_NULL_TO_NOTNULL", ClientError},
+		1264: {"ER_WARN_DATA_OUT_OF_RANGE", ClientError},
+		1265: {"WARN_DATA_TRUNCATED", ClientError},
+		1266: {"ER_WARN_USING_OTHER_HANDLER", ClientError},
+		1267: {"ER_CANT_AGGREGATE_2COLLATIONS", ClientError},
+		1268: {"ER_DROP_USER", ClientError},
+		1269: {"ER_REVOKE_GRANTS", ClientError},
+		1270: {"ER_CANT_AGGREGATE_3COLLATIONS", ClientError},
+		1271: {"ER_CANT_AGGREGATE_NCOLLATIONS", ClientError},
+		1272: {"ER_VARIABLE_IS_NOT_STRUCT", ClientError},
+		1273: {"ER_UNKNOWN_COLLATION", ClientError},
+		1274: {"ER_SLAVE_IGNORED_SSL_PARAMS", Server},
+		1275: {"ER_SERVER_IS_IN_SECURE_AUTH_MODE", ClientError},
+		1276: {"ER_WARN_FIELD_RESOLVED", ClientError},
+		1277: {"ER_BAD_SLAVE_UNTIL_COND", Server},
+		1278: {"ER_MISSING_SKIP_SLAVE", Server},
+		1279: {"ER_UNTIL_COND_IGNORED", ClientError},
+		1280: {"ER_WRONG_NAME_FOR_INDEX", Syntax},
+		1281: {"ER_WRONG_NAME_FOR_CATALOG", Syntax},
+		1282: {"ER_WARN_QC_RESIZE", ClientError},
+		1283: {"ER_BAD_FT_COLUMN", ClientError},
+		1284: {"ER_UNKNOWN_KEY_CACHE", ClientError},
+		1285: {"ER_WARN_HOSTNAME_WONT_WORK", ClientError},
+		1286: {"ER_UNKNOWN_STORAGE_ENGINE", Syntax},
+		1287: {"ER_WARN_DEPRECATED_SYNTAX", Syntax},
+		1288: {"ER_NON_UPDATABLE_TABLE", ClientError},
+		1289: {"ER_FEATURE_DISABLED", ClientError},
+		1290: {"ER_OPTION_PREVENTS_STATEMENT", Permission},
+		1291: {"ER_DUPLICATED_VALUE_IN_TYPE", ClientError},
+		1292: {"ER_TRUNCATED_WRONG_VALUE", ClientError},
+		1293: {"ER_TOO_MUCH_AUTO_TIMESTAMP_COLS", ClientError},
+		1294: {"ER_INVALID_ON_UPDATE", ClientError},
+		1295: {"ER_UNSUPPORTED_PS", ClientError},
+		1296: {"ER_GET_ERRMSG", ClientError},
+		1297: {"ER_GET_TEMPORARY_ERRMSG", ClientError},
+		1298: {"ER_UNKNOWN_TIME_ZONE", ClientError},
+		1299: {"ER_WARN_INVALID_TIMESTAMP", ClientError},
+		1300: {"ER_INVALID_CHARACTER_STRING", ClientError},
+		1301: {"ER_WARN_ALLOWED_PACKET_OVERFLOWED", ClientError},
+		1302: {"ER_CONFLICTING_DECLARATIONS", ClientError},
+		1303: {"ER_SP_NO_RECURSIVE_CREATE", ClientError},
+		1304: {"ER_SP_ALREADY_EXISTS", Syntax},
+		1305: {"ER_SP_DOES_NOT_EXIST", Syntax},
+		1306: {"ER_SP_DROP_FAILED", Server},
+		1307: {"ER_SP_STORE_FAILED", Server},
+		1308: {"ER_SP_LILABEL_MISMATCH", Syntax},
+		1309: {"ER_SP_LABEL_REDEFINE", Syntax},
+		1310: {"ER_SP_LABEL_MISMATCH", Syntax},
+		1311: {"ER_SP_UNINIT_VAR", ClientError},
+		1312: {"ER_SP_BADSELECT", ClientError},
+		1313: {"ER_SP_BADRETURN", Syntax},
+		1314: {"ER_SP_BADSTATEMENT", ClientError},
+		1315: {"ER_UPDATE_LOG_DEPRECATED_IGNORED", Syntax},
+		1316: {"ER_UPDATE_LOG_DEPRECATED_TRANSLATED", Syntax},
+		1317: {"ER_QUERY_INTERRUPTED", Server},
+		1318: {"ER_SP_WRONG_NO_OF_ARGS", Syntax},
+		1319: {"ER_SP_COND_MISMATCH", Syntax},
+		1320: {"ER_SP_NORETURN", Syntax},
+		1321: {"ER_SP_NORETURNEND", ClientError},
+		1322: {"ER_SP_BAD_CURSOR_QUERY", Syntax},
+		1323: {"ER_SP_BAD_CURSOR_SELECT", Syntax},
+		1324: {"ER_SP_CURSOR_MISMATCH", Syntax},
+		1325: {"ER_SP_CURSOR_ALREADY_OPEN", ClientError},
+		1326: {"ER_SP_CURSOR_NOT_OPEN", ClientError},
+		1327: {"ER_SP_UNDECLARED_VAR", Syntax},
+		1328: {"ER_SP_WRONG_NO_OF_FETCH_ARGS", ClientError},
+		1329: {"ER_SP_FETCH_NO_DATA", ClientError},
+		1330: {"ER_SP_DUP_PARAM", Syntax},
+		1331: {"ER_SP_DUP_VAR", Syntax},
+		1332: {"ER_SP_DUP_COND", Syntax},
+		1333: {"ER_SP_DUP_CURS", Syntax},
+		1334: {"ER_SP_CANT_ALTER", ClientError},
+		1335: {"ER_SP_SUBSELECT_NYI", ClientError},
+		1336: {"ER_STMT_NOT_ALLOWED_IN_SF_OR_TRG", ClientError},
+		1337: {"ER_SP_VARCOND_AFTER_CURSHNDLR", Syntax},
+		1338: {"ER_SP_CURSOR_AFTER_HANDLER", Syntax},
+		1339: {"ER_SP_CASE_NOT_FOUND", ClientError},
+		1340: {"ER_FPARSER_TOO_BIG_FILE", Server},
+		1341: {"ER_FPARSER_BAD_HEADER", ClientError},
+		1342: {"ER_FPARSER_EOF_IN_COMMENT", ClientError},
+		1343: {"ER_FPARSER_ERROR_IN_PARAMETER", ClientError},
+		1344: {"ER_FPARSER_EOF_IN_UNKNOWN_PARAMETER", ClientError},
+		1345: {"ER_VIEW_NO_EXPLAIN", ClientError},
+		1346: {"ER_FRM_UNKNOWN_TYPE", ClientError},
+		1347: {"ER_WRONG_OBJECT", ClientError},
+		1348: {"ER_NONUPDATEABLE_COLUMN", ClientError},
+		1350: {"ER_VIEW_SELECT_CLAUSE", ClientError},
+		1351: {"ER_VIEW_SELECT_VARIABLE", ClientError},
+		1352: {"ER_VIEW_SELECT_TMPTABLE", ClientError},
+		1353: {"ER_VIEW_WRONG_LIST", ClientError},
+		1354: {"ER_WARN_VIEW_MERGE", ClientError},
+		1355: {"ER_WARN_VIEW_WITHOUT_KEY", ClientError},
+		1356: {"ER_VIEW_INVALID", ClientError},
+		1357: {"ER_SP_NO_DROP_SP", ClientError},
+		1358: {"ER_SP_GOTO_IN_HNDLR", ClientError},
+		1359: {"ER_TRG_ALREADY_EXISTS", ClientError},
+		1360: {"ER_TRG_DOES_NOT_EXIST", ClientError},
+		1361: {"ER_TRG_ON_VIEW_OR_TEMP_TABLE", ClientError},
+		1362: {"ER_TRG_CANT_CHANGE_ROW", ClientError},
+		1363: {"ER_TRG_NO_SUCH_ROW_IN_TRG", ClientError},
+		1364: {"ER_NO_DEFAULT_FOR_FIELD", ClientError},
+		1365: {"ER_DIVISION_BY_ZERO", ClientError},
+		1366: {"ER_TRUNCATED_WRONG_VALUE_FOR_FIELD", ClientError},
+		1367: {"ER_ILLEGAL_VALUE_FOR_TYPE", ClientError},
+		1368: {"ER_VIEW_NONUPD_CHECK", ClientError},
+		1369: {"ER_VIEW_CHECK_FAILED", Server},
+		1370: {"ER_PROCACCESS_DENIED_ERROR", Permission},
+		1371: {"ER_RELAY_LOG_FAIL", Server},
+		1372: {"ER_PASSWD_LENGTH", ClientError},
+		1373: {"ER_UNKNOWN_TARGET_BINLOG", Server},
+		1374: {"ER_IO_ERR_LOG_INDEX_READ", ClientError},
+		1375: {"ER_BINLOG_PURGE_PROHIBITED", Server},
+		1376: {"ER_FSEEK_FAIL", Server},
+		1377: {"ER_BINLOG_PURGE_FATAL_ERR", Server},
+		1378: {"ER_LOG_IN_USE", ClientError},
+		1379: {"ER_LOG_PURGE_UNKNOWN_ERR", ClientError},
+		1380: {"ER_RELAY_LOG_INIT", Server},
+		1381: {"ER_NO_BINARY_LOGGING", ClientError},
+		1382: {"ER_RESERVED_SYNTAX", Syntax},
+		1383: {"ER_WSAS_FAILED", Server},
+		1384: {"ER_DIFF_GROUPS_PROC", ClientError},
+		1385: {"ER_NO_GROUP_FOR_PROC", ClientError},
+		1386: {"ER_ORDER_WITH_PROC", ClientError},
+		1387: {"ER_LOGGING_PROHIBIT_CHANGING_OF", ClientError},
+		1388: {"ER_NO_FILE_MAPPING", Server},
+		1389: {"ER_WRONG_MAGIC", ClientError},
+		1390: {"ER_PS_MANY_PARAM", ClientError},
+		1391: {"ER_KEY_PART_0", ClientError},
+		1392: {"ER_VIEW_CHECKSUM", ClientError},
+		1393: {"ER_VIEW_MULTIUPDATE", ClientError},
+		1394: {"ER_VIEW_NO_INSERT_FIELD_LIST", ClientError},
+		1395: {"ER_VIEW_DELETE_MERGE_VIEW", ClientError},
+		1396: {"ER_CANNOT_USER", ClientError},
+		1397: {"ER_XAER_NOTA", ClientError},
+		1398: {"ER_XAER_INVAL", ClientError},
+		1399: {"ER_XAER_RMFAIL", ClientError},
+		1400: {"ER_XAER_OUTSIDE", ClientError},
+		1401: {"ER_XAER_RMERR", ClientError},
+		1402: {"ER_XA_RBROLLBACK", ClientError},
+		1403: {"ER_NONEXISTING_PROC_GRANT", Permission},
+		1404: {"ER_PROC_AUTO_GRANT_FAIL", Permission},
+		1405: {"ER_PROC_AUTO_REVOKE_FAIL", Server},
+		1406: {"ER_DATA_TOO_LONG", ClientError},
+		1407: {"ER_SP_BAD_SQLSTATE", Syntax},
+		1408: {"ER_STARTUP", ClientError},
+		1409: {"ER_LOAD_FROM_FIXED_SIZE_ROWS_TO_VAR", ClientError},
+		1410: {"ER_CANT_CREATE_USER_WITH_GRANT", Permission},
+		1411: {"ER_WRONG_VALUE_FOR_TYPE", ClientError},
+		1412: {"ER_TABLE_DEF_CHANGED", ClientError},
+		1413: {"ER_SP_DUP_HANDLER", Syntax},
+		1414: {"ER_SP_NOT_VAR_ARG", Syntax},
+		1415: {"ER_SP_NO_RETSET", ClientError},
+		1416: {"ER_CANT_CREATE_GEOMETRY_OBJECT", ClientError},
+		1417: {"ER_FAILED_ROUTINE_BREAK_BINLOG", Server},
+		1418: {"ER_BINLOG_UNSAFE_ROUTINE", Server},
+		1419: {"ER_BINLOG_CREATE_ROUTINE_NEED_SUPER", Server},
+		1420: {"ER_EXEC_STMT_WITH_OPEN_CURSOR", ClientError},
+		1421: {"ER_STMT_HAS_NO_OPEN_CURSOR", ClientError},
+		1422: {"ER_COMMIT_NOT_ALLOWED_IN_SF_OR_TRG", ClientError},
+		1423: {"ER_NO_DEFAULT_FOR_VIEW_FIELD", ClientError},
+		1424: {"ER_SP_NO_RECURSION", ClientError},
+		1425: {"ER_TOO_BIG_SCALE", Syntax},
+		1426: {"ER_TOO_BIG_PRECISION", Syntax},
+		1427: {"ER_M_BIGGER_THAN_D", Syntax},
+		1428: {"ER_WRONG_LOCK_OF_SYSTEM_TABLE", Lock},
+		1429: {"ER_CONNECT_TO_FOREIGN_DATA_SOURCE", Constraint},
+		1430: {"ER_QUERY_ON_FOREIGN_DATA_SOURCE", Constraint},
+		1431: {"ER_FOREIGN_DATA_SOURCE_DOESNT_EXIST", Constraint},
+		1432: {"ER_FOREIGN_DATA_STRING_INVALID_CANT_CREATE", Constraint},
+		1433: {"ER_FOREIGN_DATA_STRING_INVALID", Constraint},
+		1434: {"ER_CANT_CREATE_FEDERATED_TABLE", ClientError},
+		1435: {"ER_TRG_IN_WRONG_SCHEMA", ClientError},
+		1436: {"ER_STACK_OVERRUN_NEED_MORE", Server},
+		1437: {"ER_TOO_LONG_BODY", Syntax},
+		1438: {"ER_WARN_CANT_DROP_DEFAULT_KEYCACHE", ClientError},
+		1439: {"ER_TOO_BIG_DISPLAYWIDTH", Syntax},
+		1440: {"ER_XAER_DUPID", ClientError},
+		1441: {"ER_DATETIME_FUNCTION_OVERFLOW", ClientError},
+		1442: {"ER_CANT_UPDATE_USED_TABLE_IN_SF_OR_TRG", ClientError},
+		1443: {"ER_VIEW_PREVENT_UPDATE", ClientError},
+		1444: {"ER_PS_NO_RECURSION", ClientError},
+		1445: {"ER_SP_CANT_SET_AUTOCOMMIT", ClientError},
+		1446: {"ER_MALFORMED_DEFINER", ClientError},
+		1447: {"ER_VIEW_FRM_NO_USER", ClientError},
+		1448: {"ER_VIEW_OTHER_USER", ClientError},
+		1449: {"ER_NO_SUCH_USER", ClientError},
+		1450: {"ER_FORBID_SCHEMA_CHANGE", ClientError},
+		1451: {"ER_ROW_IS_REFERENCED_2", Constraint},
+		1452: {"ER_NO_REFERENCED_ROW_2", Constraint},
+		1453: {"ER_SP_BAD_VAR_SHADOW", Syntax},
+		1454: {"ER_TRG_NO_DEFINER", ClientError},
+		1455: {"ER_OLD_FILE_FORMAT", Server},
+		1456: {"ER_SP_RECURSION_LIMIT", ClientError},
+		1457: {"ER_SP_PROC_TABLE_CORRUPT", Server},
+		1458: {"ER_SP_WRONG_NAME", Syntax},
+		1459: {"ER_TABLE_NEEDS_UPGRADE", Server},
+		1460: {"ER_SP_NO_AGGREGATE", Syntax},
+		1461: {"ER_MAX_PREPARED_STMT_COUNT_REACHED", Syntax},
+		1462: {"ER_VIEW_RECURSIVE", ClientError},
+		1463: {"ER_NON_GROUPING_FIELD_USED", Syntax},
+		1464: {"ER_TABLE_CANT_HANDLE_SPKEYS", ClientError},
+		1465: {"ER_NO_TRIGGERS_ON_SYSTEM_SCHEMA", ClientError},
+		1466: {"ER_REMOVED_SPACES", ClientError},
+		1467: {"ER_AUTOINC_READ_FAILED", Server},
+		1468: {"ER_USERNAME", ClientError},
+		1469: {"ER_HOSTNAME", ClientError},
+		1470: {"ER_WRONG_STRING_LENGTH", ClientError},
+		1471: {"ER_NON_INSERTABLE_TABLE", ClientError},
+		1472: {"ER_ADMIN_WRONG_MRG_TABLE", ClientError},
+		1473: {"ER_TOO_HIGH_LEVEL_OF_NESTING_FOR_SELECT", ClientError},
+		1474: {"ER_NAME_BECOMES_EMPTY", ClientError},
+		1475: {"ER_AMBIGUOUS_FIELD_TERM", ClientError},
+		1476: {"ER_FOREIGN_SERVER_EXISTS", Constraint},
+		1477: {"ER_FOREIGN_SERVER_DOESNT_EXIST", Constraint},
+		1478: {"ER_ILLEGAL_HA_CREATE_OPTION", ClientError},
+		1479: {"ER_PARTITION_REQUIRES_VALUES_ERROR", ClientError},
+		1480: {"ER_PARTITION_WRONG_VALUES_ERROR", ClientError},
+		1481: {"ER_PARTITION_MAXVALUE_ERROR", ClientError},
+		1482: {"ER_PARTITION_SUBPARTITION_ERROR", ClientError},
+		1483: {"ER_PARTITION_SUBPART_MIX_ERROR", ClientError},
+		1484: {"ER_PARTITION_WRONG_NO_PART_ERROR", ClientError},
+		1485: {"ER_PARTITION_WRONG_NO_SUBPART_ERROR", ClientError},
+		1486: {"ER_WRONG_EXPR_IN_PARTITION_FUNC_ERROR", ClientError},
+		1487: {"ER_NO_CONST_EXPR_IN_RANGE_OR_LIST_ERROR", ClientError},
+		1488: {"ER_FIELD_NOT_FOUND_PART_ERROR", ClientError},
+		1489: {"ER_LIST_OF_FIELDS_ONLY_IN_HASH_ERROR", ClientError},
+		1490: {"ER_INCONSISTENT_PARTITION_INFO_ERROR", ClientError},
+		1491: {"ER_PARTITION_FUNC_NOT_ALLOWED_ERROR", ClientError},
+		1492: {"ER_PARTITIONS_MUST_BE_DEFINED_ERROR", ClientError},
+		1493: {"ER_RANGE_NOT_INCREASING_ERROR", ClientError},
+		1494: {"ER_INCONSISTENT_TYPE_OF_FUNCTIONS_ERROR", ClientError},
+		1495: {"ER_MULTIPLE_DEF_CONST_IN_LIST_PART_ERROR", ClientError},
+		1496: {"ER_PARTITION_ENTRY_ERROR", ClientError},
+		1497: {"ER_MIX_HANDLER_ERROR", ClientError},
+		1498: {"ER_PARTITION_NOT_DEFINED_ERROR", ClientError},
+		1499: {"ER_TOO_MANY_PARTITIONS_ERROR", ClientError},
+		1500: {"ER_SUBPARTITION_ERROR", ClientError},
+		1501: {"ER_CANT_CREATE_HANDLER_FILE", Server},
+		1502: {"ER_BLOB_FIELD_IN_PART_FUNC_ERROR", ClientError},
+		1503: {"ER_UNIQUE_KEY_NEED_ALL_FIELDS_IN_PF", ClientError},
+		1504: {"ER_NO_PARTS_ERROR", ClientError},
+		1505: {"ER_PARTITION_MGMT_ON_NONPARTITIONED", ClientError},
+		1506: {"ER_FOREIGN_KEY_ON_PARTITIONED", Constraint},
+		1507: {"ER_DROP_PARTITION_NON_EXISTENT", ClientError},
+		1508: {"ER_DROP_LAST_PARTITION", ClientError},
+		1509: {"ER_COALESCE_ONLY_ON_HASH_PARTITION", ClientError},
+		1510: {"ER_REORG_HASH_ONLY_ON_SAME_NO", ClientError},
+		1511: {"ER_REORG_NO_PARAM_ERROR", ClientError},
+		1512: {"ER_ONLY_ON_RANGE_LIST_PARTITION", ClientError},
+		1513: {"ER_ADD_PARTITION_SUBPART_ERROR", ClientError},
+		1514: {"ER_ADD_PARTITION_NO_NEW_PARTITION", ClientError},
+		1515: {"ER_COALESCE_PARTITION_NO_PARTITION", ClientError},
+		1516: {"ER_REORG_PARTITION_NOT_EXIST", ClientError},
+		1517: {"ER_SAME_NAME_PARTITION", ClientError},
+		1518: {"ER_NO_BINLOG_ERROR", Server},
+		1519: {"ER_CONSECUTIVE_REORG_PARTITIONS", ClientError},
+		1520: {"ER_REORG_OUTSIDE_RANGE", ClientError},
+		1521: {"ER_PARTITION_FUNCTION_FAILURE", ClientError},
+		1522: {"ER_PART_STATE_ERROR", ClientError},
+		1523: {"ER_LIMITED_PART_RANGE", ClientError},
+		1524: {"ER_PLUGIN_IS_NOT_LOADED", ClientError},
+		1525: {"ER_WRONG_VALUE", ClientError},
+		1526: {"ER_NO_PARTITION_FOR_GIVEN_VALUE", ClientError},
+		1527: {"ER_FILEGROUP_OPTION_ONLY_ONCE", ClientError},
+		1528: {"ER_CREATE_FILEGROUP_FAILED", Server},
+		1529: {"ER_DROP_FILEGROUP_FAILED", Server},
+		1530: {"ER_TABLESPACE_AUTO_EXTEND_ERROR", ClientError},
+		1531: {"ER_WRONG_SIZE_NUMBER", ClientError},
+		1532: {"ER_SIZE_OVERFLOW_ERROR", ClientError},
+		1533: {"ER_ALTER_FILEGROUP_FAILED", Server},
+		1534: {"ER_BINLOG_ROW_LOGGING_FAILED", Server},
+		1535: {"ER_BINLOG_ROW_WRONG_TABLE_DEF", Server},
+		1536: {"ER_BINLOG_ROW_RBR_TO_SBR", Server},
+		1537: {"ER_EVENT_ALREADY_EXISTS", ClientError},
+		1538: {"ER_EVENT_STORE_FAILED", Server},
+		1539: {"ER_EVENT_DOES_NOT_EXIST", ClientError},
+		1540: {"ER_EVENT_CANT_ALTER", ClientError},
+		1541: {"ER_EVENT_DROP_FAILED", Server},
+		1542: {"ER_EVENT_INTERVAL_NOT_POSITIVE_OR_TOO_BIG", ClientError},
+		1543: {"ER_EVENT_ENDS_BEFORE_STARTS", ClientError},
+		1544: {"ER_EVENT_EXEC_TIME_IN_THE_PAST", ClientError},
+		1545: {"ER_EVENT_OPEN_TABLE_FAILED", Server},
+		1546: {"ER_EVENT_NEITHER_M_EXPR_NOR_M_AT", ClientError},
+		1547: {"ER_OBSOLETE_COL_COUNT_DOESNT_MATCH_CORRUPTED", ClientError},
+		1548: {"ER_OBSOLETE_CANNOT_LOAD_FROM_TABLE", ClientError},
+		1549: {"ER_EVENT_CANNOT_DELETE", ClientError},
+		1550: {"ER_EVENT_COMPILE_ERROR", ClientError},
+		1551: {"ER_EVENT_SAME_NAME", ClientError},
+		1552: {"ER_EVENT_DATA_TOO_LONG", ClientError},
+		1553: {"ER_DROP_INDEX_FK", Constraint},
+		1554: {"ER_WARN_DEPRECATED_SYNTAX_WITH_VER", Syntax},
+		1555: {"ER_CANT_WRITE_LOCK_LOG_TABLE", Lock},
+		1556: {"ER_CANT_LOCK_LOG_TABLE", Lock},
+		1558: {"ER_COL_COUNT_DOESNT_MATCH_PLEASE_UPDATE", ClientError},
+		1559: {"ER_TEMP_TABLE_PREVENTS_SWITCH_OUT_OF_RBR", ClientError},
+		1560: {"ER_STORED_FUNCTION_PREVENTS_SWITCH_BINLOG_FORMAT", Server},
+		1561: {"ER_NDB_CANT_SWITCH_BINLOG_FORMAT", Server},
+		1562: {"ER_PARTITION_NO_TEMPORARY", ClientError},
+		1563: {"ER_PARTITION_CONST_DOMAIN_ERROR", ClientError},
+		1564: {"ER_PARTITION_FUNCTION_IS_NOT_ALLOWED", ClientError},
+		1565: {"ER_DDL_LOG_ERROR", ClientError},
+		1566: {"ER_NULL_IN_VALUES_LESS_THAN", ClientError},
+		1567: {"ER_WRONG_PARTITION_NAME", ClientError},
+		1568: {"ER_CANT_CHANGE_TX_CHARACTERISTICS", ClientError},
+		1569: {"ER_DUP_ENTRY_AUTOINCREMENT_CASE", ClientError},
+		1570: {"ER_EVENT_MODIFY_QUEUE_ERROR", ClientError},
+		1571: {"ER_EVENT_SET_VAR_ERROR", ClientError},
+		1572: {"ER_PARTITION_MERGE_ERROR", ClientError},
+		1573: {"ER_CANT_ACTIVATE_LOG", ClientError},
+		1574: {"ER_RBR_NOT_AVAILABLE", ClientError},
+		1575: {"ER_BASE64_DECODE_ERROR", ClientError},
+		1576: {"ER_EVENT_RECURSION_FORBIDDEN", ClientError},
+		1577: {"ER_EVENTS_DB_ERROR", ClientError},
+		1578: {"ER_ONLY_INTEGERS_ALLOWED", ClientError},
+		1579: {"ER_UNSUPORTED_LOG_ENGINE", ClientError},
+		1580: {"ER_BAD_LOG_STATEMENT", ClientError},
+		1581: {"ER_CANT_RENAME_LOG_TABLE", ClientError},
+		1582: {"ER_WRONG_PARAMCOUNT_TO_NATIVE_FCT", Syntax},
+		1583: {"ER_WRONG_PARAMETERS_TO_NATIVE_FCT", Syntax},
+		1584: {"ER_WRONG_PARAMETERS_TO_STORED_FCT", Syntax},
+		1585: {"ER_NATIVE_FCT_NAME_COLLISION", ClientError},
+		1586: {"ER_DUP_ENTRY_WITH_KEY_NAME", Constraint},
+		1587: {"ER_BINLOG_PURGE_EMFILE", Server},
+		1588: {"ER_EVENT_CANNOT_CREATE_IN_THE_PAST", ClientError},
+		1589: {"ER_EVENT_CANNOT_ALTER_IN_THE_PAST", ClientError},
+		1590: {"ER_SLAVE_INCIDENT", Server},
+		1591: {"ER_NO_PARTITION_FOR_GIVEN_VALUE_SILENT", ClientError},
+		1592: {"ER_BINLOG_UNSAFE_STATEMENT", Server},
+		1593: {"ER_SLAVE_FATAL_ERROR", Server},
+		1594: {"ER_SLAVE_RELAY_LOG_READ_FAILURE", Server},
+		1595: {"ER_SLAVE_RELAY_LOG_WRITE_FAILURE", Server},
+		1596: {"ER_SLAVE_CREATE_EVENT_FAILURE", Server},
+		1597: {"ER_SLAVE_MASTER_COM_FAILURE", Server},
+		1598: {"ER_BINLOG_LOGGING_IMPOSSIBLE", Server},
+		1599: {"ER_VIEW_NO_CREATION_CTX", ClientError},
+		1600: {"ER_VIEW_INVALID_CREATION_CTX", ClientError},
+		1601: {"ER_SR_INVALID_CREATION_CTX", ClientError},
+		1602: {"ER_TRG_CORRUPTED_FILE", Server},
+		1603: {"ER_TRG_NO_CREATION_CTX", ClientError},
+		1604: {"ER_TRG_INVALID_CREATION_CTX", ClientError},
+		1605: {"ER_EVENT_INVALID_CREATION_CTX", ClientError},
+		1606: {"ER_TRG_CANT_OPEN_TABLE", ClientError},
+		1607: {"ER_CANT_CREATE_SROUTINE", ClientError},
+		1608: {"ER_NEVER_USED", ClientError},
+		1609: {"ER_NO_FORMAT_DESCRIPTION_EVENT_BEFORE_BINLOG_STATEMENT", Server},
+		1610: {"ER_SLAVE_CORRUPT_EVENT", Server},
+		1612: {"ER_LOG_PURGE_NO_FILE", Server},
+		1613: {"ER_XA_RBTIMEOUT", ClientError},
+		1614: {"ER_XA_RBDEADLOCK", ClientError},
+		1615: {"ER_NEED_REPREPARE", ClientError},
+		1616: {"ER_DELAYED_NOT_SUPPORTED", ClientError},
+		1617: {"WARN_NO_MASTER_INFO", Server},
+		1618: {"WARN_OPTION_IGNORED", ClientError},
+		1619: {"ER_PLUGIN_DELETE_BUILTIN", ClientError},
+		1620: {"WARN_PLUGIN_BUSY", ClientError},
+		1621: {"ER_VARIABLE_IS_READONLY", ClientError},
+		1622: {"ER_WARN_ENGINE_TRANSACTION_ROLLBACK", ClientError},
+		1623: {"ER_SLAVE_HEARTBEAT_FAILURE", Server},
+		1624: {"ER_SLAVE_HEARTBEAT_VALUE_OUT_OF_RANGE", Server},
+		1625: {"ER_NDB_REPLICATION_SCHEMA_ERROR", ClientError},
+		1626: {"ER_CONFLICT_FN_PARSE_ERROR", Syntax},
+		1627: {"ER_EXCEPTIONS_WRITE_ERROR", ClientError},
+		1628: {"ER_TOO_LONG_TABLE_COMMENT", ClientError},
+		1629: {"ER_TOO_LONG_FIELD_COMMENT", ClientError},
+		1630: {"ER_FUNC_INEXISTENT_NAME_COLLISION", Syntax},
+		1631: {"ER_DATABASE_NAME", ClientError},
+		1632: {"ER_TABLE_NAME", ClientError},
+		1633: {"ER_PARTITION_NAME", ClientError},
+		1634: {"ER_SUBPARTITION_NAME", ClientError},
+		1635: {"ER_TEMPORARY_NAME", ClientError},
+		1636: {"ER_RENAMED_NAME", ClientError},
+		1637: {"ER_TOO_MANY_CONCURRENT_TRXS", ClientError},
+		1638: {"WARN_NON_ASCII_SEPARATOR_NOT_IMPLEMENTED", ClientError},
+		1639: {"ER_DEBUG_SYNC_TIMEOUT", Server},
+		1640: {"ER_DEBUG_SYNC_HIT_LIMIT", ClientError},
+		1641: {"ER_DUP_SIGNAL_SET", Syntax},
+		1642: {"ER_SIGNAL_WARN", ClientError},
+		1643: {"ER_SIGNAL_NOT_FOUND", ClientError},
+		1644: {"ER_SIGNAL_EXCEPTION", ClientError},
+		1645: {"ER_RESIGNAL_WITHOUT_ACTIVE_HANDLER", ClientError},
+		1646: {"ER_SIGNAL_BAD_CONDITION_TYPE", ClientError},
+		1647: {"WARN_COND_ITEM_TRUNCATED", ClientError},
+		1648: {"ER_COND_ITEM_TOO_LONG", ClientError},
+		1649: {"ER_UNKNOWN_LOCALE", ClientError},
+		1650: {"ER_SLAVE_IGNORE_SERVER_IDS", Server},
+		1651: {"ER_QUERY_CACHE_DISABLED", ClientError},
+		1652: {"ER_SAME_NAME_PARTITION_FIELD", ClientError},
+		1653: {"ER_PARTITION_COLUMN_LIST_ERROR", ClientError},
+		1654: {"ER_WRONG_TYPE_COLUMN_VALUE_ERROR", ClientError},
+		1655: {"ER_TOO_MANY_PARTITION_FUNC_FIELDS_ERROR", ClientError},
+		1656: {"ER_MAXVALUE_IN_VALUES_IN", ClientError},
+		1657: {"ER_TOO_MANY_VALUES_ERROR", ClientError},
+		1658: {"ER_ROW_SINGLE_PARTITION_FIELD_ERROR", ClientError},
+		1659: {"ER_FIELD_TYPE_NOT_ALLOWED_AS_PARTITION_FIELD", ClientError},
+		1660: {"ER_PARTITION_FIELDS_TOO_LONG", ClientError},
+		1661: {"ER_BINLOG_ROW_ENGINE_AND_STMT_ENGINE", Server},
+		1662: {"ER_BINLOG_ROW_MODE_AND_STMT_ENGINE", Server},
+		1663: {"ER_BINLOG_UNSAFE_AND_STMT_ENGINE", Server},
+		1664: {"ER_BINLOG_ROW_INJECTION_AND_STMT_ENGINE", Server},
+		1665: {"ER_BINLOG_STMT_MODE_AND_ROW_ENGINE", Server},
+		1666: {"ER_BINLOG_ROW_INJECTION_AND_STMT_MODE", Server},
+		1667: {"ER_BINLOG_MULTIPLE_ENGINES_AND_SELF_LOGGING_ENGINE", Server},
+		1668: {"ER_BINLOG_UNSAFE_LIMIT", Server},
+		1670: {"ER_BINLOG_UNSAFE_SYSTEM_TABLE", Server},
+		1671: {"ER_BINLOG_UNSAFE_AUTOINC_COLUMNS", Server},
+		1672: {"ER_BINLOG_UNSAFE_UDF", Server},
+		1673: {"ER_BINLOG_UNSAFE_SYSTEM_VARIABLE", Server},
+		1674: {"ER_BINLOG_UNSAFE_SYSTEM_FUNCTION", Server},
+		1675: {"ER_BINLOG_UNSAFE_NONTRANS_AFTER_TRANS", Server},
+		1676: {"ER_MESSAGE_AND_STATEMENT", ClientError},
+		1677: {"ER_SLAVE_CONVERSION_FAILED", Server},
+		1678: {"ER_SLAVE_CANT_CREATE_CONVERSION", Server},
+		1679: {"ER_INSIDE_TRANSACTION_PREVENTS_SWITCH_BINLOG_FORMAT", Server},
+		1680: {"ER_PATH_LENGTH", ClientError},
+		1681: {"ER_WARN_DEPRECATED_SYNTAX_NO_REPLACEMENT", Syntax},
+		1682: {"ER_WRONG_NATIVE_TABLE_STRUCTURE", ClientError},
+		1683: {"ER_WRONG_PERFSCHEMA_USAGE", ClientError},
+		1684: {"ER_WARN_I_S_SKIPPED_TABLE", ClientError},
+		1685: {"ER_INSIDE_TRANSACTION_PREVENTS_SWITCH_BINLOG_DIRECT", Server},
+		1686: {"ER_STORED_FUNCTION_PREVENTS_SWITCH_BINLOG_DIRECT", Server},
+		1687: {"ER_SPATIAL_MUST_HAVE_GEOM_COL", Syntax},
+		1688: {"ER_TOO_LONG_INDEX_COMMENT", ClientError},
+		1689: {"ER_LOCK_ABORTED", Lock},
+		1690: {"ER_DATA_OUT_OF_RANGE", ClientError},
+		1691: {"ER_WRONG_SPVAR_TYPE_IN_LIMIT", ClientError},
+		1692: {"ER_BINLOG_UNSAFE_MULTIPLE_ENGINES_AND_SELF_LOGGING_ENGINE", Server},
+		1693: {"ER_BINLOG_UNSAFE_MIXED_STATEMENT", Server},
+		1694: {"ER_INSIDE_TRANSACTION_PREVENTS_SWITCH_SQL_LOG_BIN", ClientError},
+		1695: {"ER_STORED_FUNCTION_PREVENTS_SWITCH_SQL_LOG_BIN", ClientError},
+		1696: {"ER_FAILED_READ_FROM_PAR_FILE", Server},
+		1697: {"ER_VALUES_IS_NOT_INT_TYPE_ERROR", ClientError},
+		1698: {"ER_ACCESS_DENIED_NO_PASSWORD_ERROR", Permission},
+		1699: {"ER_SET_PASSWORD_AUTH_PLUGIN", Permission},
+		1700: {"ER_GRANT_PLUGIN_USER_EXISTS", Permission},
+		1701: {"ER_TRUNCATE_ILLEGAL_FK", Constraint},
+		1702: {"ER_PLUGIN_IS_PERMANENT", ClientError},
+		1703: {"ER_SLAVE_HEARTBEAT_VALUE_OUT_OF_RANGE_MIN", Server},
+		1704: {"ER_SLAVE_HEARTBEAT_VALUE_OUT_OF_RANGE_MAX", Server},
+		1705: {"ER_STMT_CACHE_FULL", ClientError},
+		1706: {"ER_MULTI_UPDATE_KEY_CONFLICT", ClientError},
+		1707: {"ER_TABLE_NEEDS_REBUILD", ClientError},
+		1708: {"WARN_OPTION_BELOW_LIMIT", ClientError},
+		1709: {"ER_INDEX_COLUMN_TOO_LONG", ClientError},
+		1710: {"ER_ERROR_IN_TRIGGER_BODY", ClientError},
+		1711: {"ER_ERROR_IN_UNKNOWN_TRIGGER_BODY", ClientError},
+		1712: {"ER_INDEX_CORRUPT", Server},
+		1713: {"ER_UNDO_RECORD_TOO_BIG", ClientError},
+		1714: {"ER_BINLOG_UNSAFE_INSERT_IGNORE_SELECT", Server},
+		1715: {"ER_BINLOG_UNSAFE_INSERT_SELECT_UPDATE", Server},
+		1716: {"ER_BINLOG_UNSAFE_REPLACE_SELECT", Server},
+		1717: {"ER_BINLOG_UNSAFE_CREATE_IGNORE_SELECT", Server},
+		1718: {"ER_BINLOG_UNSAFE_CREATE_REPLACE_SELECT", Server},
+		1719: {"ER_BINLOG_UNSAFE_UPDATE_IGNORE", Server},
+		1720: {"ER_PLUGIN_NO_UNINSTALL", ClientError},
+		1721: {"ER_PLUGIN_NO_INSTALL", ClientError},
+		1722: {"ER_BINLOG_UNSAFE_WRITE_AUTOINC_SELECT", Server},
+		1723: {"ER_BINLOG_UNSAFE_CREATE_SELECT_AUTOINC", Server},
+		1724: {"ER_BINLOG_UNSAFE_INSERT_TWO_KEYS", Server},
+		1725: {"ER_TABLE_IN_FK_CHECK", Constraint},
+		1726: {"ER_UNSUPPORTED_ENGINE", ClientError},
+		1727: {"ER_BINLOG_UNSAFE_AUTOINC_NOT_FIRST", Server},
+		1728: {"ER_CANNOT_LOAD_FROM_TABLE_V2", ClientError},
+		1729: {"ER_MASTER_DELAY_VALUE_OUT_OF_RANGE", Server},
+		1730: {"ER_ONLY_FD_AND_RBR_EVENTS_ALLOWED_IN_BINLOG_STATEMENT", Server},
+		1731: {"ER_PARTITION_EXCHANGE_DIFFERENT_OPTION", ClientError},
+		1732: {"ER_PARTITION_EXCHANGE_PART_TABLE", ClientError},
+		1733: {"ER_PARTITION_EXCHANGE_TEMP_TABLE", ClientError},
+		1734: {"ER_PARTITION_INSTEAD_OF_SUBPARTITION", ClientError},
+		1735: {"ER_UNKNOWN_PARTITION", ClientError},
+		1736: {"ER_TABLES_DIFFERENT_METADATA", ClientError},
+		1737: {"ER_ROW_DOES_NOT_MATCH_PARTITION", ClientError},
+		1738: {"ER_BINLOG_CACHE_SIZE_GREATER_THAN_MAX", Server},
+		1739: {"ER_WARN_INDEX_NOT_APPLICABLE", ClientError},
+		1740: {"ER_PARTITION_EXCHANGE_FOREIGN_KEY", Constraint},
+		1741: {"ER_NO_SUCH_KEY_VALUE", ClientError},
+		1742: {"ER_RPL_INFO_DATA_TOO_LONG", ClientError},
+		1743: {"ER_NETWORK_READ_EVENT_CHECKSUM_FAILURE", ClientError},
+		1744: {"ER_BINLOG_READ_EVENT_CHECKSUM_FAILURE", Server},
+		1745: {"ER_BINLOG_STMT_CACHE_SIZE_GREATER_THAN_MAX", Server},
+		1746: {"ER_CANT_UPDATE_TABLE_IN_CREATE_TABLE_SELECT", ClientError},
+		1747: {"ER_PARTITION_CLAUSE_ON_NONPARTITIONED", ClientError},
+		1748: {"ER_ROW_DOES_NOT_MATCH_GIVEN_PARTITION_SET", ClientError},
+		1750: {"ER_CHANGE_RPL_INFO_REPOSITORY_FAILURE", ClientError},
+		1751: {"ER_WARNING_NOT_COMPLETE_ROLLBACK_WITH_CREATED_TEMP_TABLE", ClientError},
+		1752: {"ER_WARNING_NOT_COMPLETE_ROLLBACK_WITH_DROPPED_TEMP_TABLE", ClientError},
+		1753: {"ER_MTS_FEATURE_IS_NOT_SUPPORTED", ClientError},
+		1754: {"ER_MTS_UPDATED_DBS_GREATER_MAX", ClientError},
+		1755: {"ER_MTS_CANT_PARALLEL", ClientError},
+		1756: {"ER_MTS_INCONSISTENT_DATA", ClientError},
+		1757: {"ER_FULLTEXT_NOT_SUPPORTED_WITH_PARTITIONING", ClientError},
+		1758: {"ER_DA_INVALID_CONDITION_NUMBER", ClientError},
+		1759: {"ER_INSECURE_PLAIN_TEXT", ClientError},
+		1760: {"ER_INSECURE_CHANGE_MASTER", Server},
+		1761: {"ER_FOREIGN_DUPLICATE_KEY_WITH_CHILD_INFO", Constraint},
+		1762: {"ER_FOREIGN_DUPLICATE_KEY_WITHOUT_CHILD_INFO", Constraint},
+		1763: {"ER_SQLTHREAD_WITH_SECURE_SLAVE", Server},
+		1764: {"ER_TABLE_HAS_NO_FT", ClientError},
+		1765: {"ER_VARIABLE_NOT_SETTABLE_IN_SF_OR_TRIGGER", ClientError},
+		1766: {"ER_VARIABLE_NOT_SETTABLE_IN_TRANSACTION", ClientError},
+		1767: {"ER_GTID_NEXT_IS_NOT_IN_GTID_NEXT_LIST", ClientError},
+		1768: {"ER_CANT_CHANGE_GTID_NEXT_IN_TRANSACTION", ClientError},
+		1769: {"ER_SET_STATEMENT_CANNOT_INVOKE_FUNCTION", ClientError},
+		1770: {"ER_GTID_NEXT_CANT_BE_AUTOMATIC_IF_GTID_NEXT_LIST_IS_NON_NULL", ClientError},
+		1771: {"ER_SKIPPING_LOGGED_TRANSACTION", ClientError},
+		1772: {"ER_MALFORMED_GTID_SET_SPECIFICATION", ClientError},
+		1773: {"ER_MALFORMED_GTID_SET_ENCODING", ClientError},
+		1774: {"ER_MALFORMED_GTID_SPECIFICATION", ClientError},
+		1775: {"ER_GNO_EXHAUSTED", ClientError},
+		1776: {"ER_BAD_SLAVE_AUTO_POSITION", Server},
+		1777: {"ER_AUTO_POSITION_REQUIRES_GTID_MODE_NOT_OFF", ClientError},
+		1778: {"ER_CANT_DO_IMPLICIT_COMMIT_IN_TRX_WHEN_GTID_NEXT_IS_SET", ClientError},
+		1779: {"ER_GTID_MODE_ON_REQUIRES_ENFORCE_GTID_CONSISTENCY_ON", ClientError},
+		1780: {"ER_GTID_MODE_REQUIRES_BINLOG", Server},
+		1781: {"ER_CANT_SET_GTID_NEXT_TO_GTID_WHEN_GTID_MODE_IS_OFF", ClientError},
+		1782: {"ER_CANT_SET_GTID_NEXT_TO_ANONYMOUS_WHEN_GTID_MODE_IS_ON", ClientError},
+		1783: {"ER_CANT_SET_GTID_NEXT_LIST_TO_NON_NULL_WHEN_GTID_MODE_IS_OFF", ClientError},
+		1785: {"ER_GTID_UNSAFE_NON_TRANSACTIONAL_TABLE", ClientError},
+		1786: {"ER_GTID_UNSAFE_CREATE_SELECT", ClientError},
+		1787: {"ER_GTID_UNSAFE_CREATE_DROP_TEMPORARY_TABLE_IN_TRANSACTION", ClientError},
+		1788: {"ER_GTID_MODE_CAN_ONLY_CHANGE_ONE_STEP_AT_A_TIME", ClientError},
+		1789: {"ER_MASTER_HAS_PURGED_REQUIRED_GTIDS", Server},
+		1790: {"ER_CANT_SET_GTID_NEXT_WHEN_OWNING_GTID", ClientError},
+		1791: {"ER_UNKNOWN_EXPLAIN_FORMAT", ClientError},
+		1792: {"ER_CANT_EXECUTE_IN_READ_ONLY_TRANSACTION", ClientError},
+		1793: {"ER_TOO_LONG_TABLE_PARTITION_COMMENT", ClientError},
+		1794: {"ER_SLAVE_CONFIGURATION", Server},
+		1795: {"ER_INNODB_FT_LIMIT", Server},
+		1796: {"ER_INNODB_NO_FT_TEMP_TABLE", Server},
+		1797: {"ER_INNODB_FT_WRONG_DOCID_COLUMN", Server},
+		1798: {"ER_INNODB_FT_WRONG_DOCID_INDEX", Server},
+		1799: {"ER_INNODB_ONLINE_LOG_TOO_BIG", Server},
+		1800: {"ER_UNKNOWN_ALTER_ALGORITHM", ClientError},
+		1801: {"ER_UNKNOWN_ALTER_LOCK", Lock},
+		1802: {"ER_MTS_CHANGE_MASTER_CANT_RUN_WITH_GAPS", Server},
+		1803: {"ER_MTS_RECOVERY_FAILURE", ClientError},
+		1804: {"ER_MTS_RESET_WORKERS", ClientError},
+		1805: {"ER_COL_COUNT_DOESNT_MATCH_CORRUPTED_V2", ClientError},
+		1806: {"ER_SLAVE_SILENT_RETRY_TRANSACTION", Server},
+		1807: {"ER_DISCARD_FK_CHECKS_RUNNING", Constraint},
+		1808: {"ER_TABLE_SCHEMA_MISMATCH", ClientError},
+		1809: {"ER_TABLE_IN_SYSTEM_TABLESPACE", ClientError},
+		1810: {"ER_IO_READ_ERROR", ClientError},
+		1811: {"ER_IO_WRITE_ERROR", ClientError},
+		1812: {"ER_TABLESPACE_MISSING", ClientError},
+		1813: {"ER_TABLESPACE_EXISTS", ClientError},
+		1814: {"ER_TABLESPACE_DISCARDED", ClientError},
+		1815: {"ER_INTERNAL_ERROR", ClientError},
+		1816: {"ER_INNODB_IMPORT_ERROR", Server},
+		1817: {"ER_INNODB_INDEX_CORRUPT", Server},
+		1818: {"ER_INVALID_YEAR_COLUMN_LENGTH", ClientError},
+		1819: {"ER_NOT_VALID_PASSWORD", Permission},
+		1820: {"ER_MUST_CHANGE_PASSWORD", Permission},
+		1821: {"ER_FK_NO_INDEX_CHILD", Constraint},
+		1822: {"ER_FK_NO_INDEX_PARENT", Constraint},
+		1823: {"ER_FK_FAIL_ADD_SYSTEM", Constraint},
+		1824: {"ER_FK_CANNOT_OPEN_PARENT", Constraint},
+		1825: {"ER_FK_INCORRECT_OPTION", Constraint},
+		1826: {"ER_FK_DUP_NAME", Constraint},
+		1827: {"ER_PASSWORD_FORMAT", Permission},
+		1828: {"ER_FK_COLUMN_CANNOT_DROP", Constraint},
+		1829: {"ER_FK_COLUMN_CANNOT_DROP_CHILD", Constraint},
+		1830: {"ER_FK_COLUMN_NOT_NULL", Constraint},
+		1831: {"ER_DUP_INDEX", ClientError},
+		1832: {"ER_FK_COLUMN_CANNOT_CHANGE", Constraint},
+		1833: {"ER_FK_COLUMN_CANNOT_CHANGE_CHILD", Constraint},
+		1835: {"ER_MALFORMED_PACKET", ClientError},
+		1836: {"ER_READ_ONLY_MODE", ClientError},
+		1837: {"ER_GTID_NEXT_TYPE_UNDEFINED_GROUP", ClientError},
+		1838: {"ER_VARIABLE_NOT_SETTABLE_IN_SP", ClientError},
+		1839: {"ER_CANT_SET_GTID_PURGED_WHEN_GTID_MODE_IS_OFF", ClientError},
+		1840: {"ER_CANT_SET_GTID_PURGED_WHEN_GTID_EXECUTED_IS_NOT_EMPTY", ClientError},
+		1841: {"ER_CANT_SET_GTID_PURGED_WHEN_OWNED_GTIDS_IS_NOT_EMPTY", ClientError},
+		1842: {"ER_GTID_PURGED_WAS_CHANGED", ClientError},
+		1843: {"ER_GTID_EXECUTED_WAS_CHANGED", ClientError},
+		1844: {"ER_BINLOG_STMT_MODE_AND_NO_REPL_TABLES", Server},
+		1845: {"ER_ALTER_OPERATION_NOT_SUPPORTED", ClientError},
+		1846: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON", ClientError},
+		1847: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_COPY", ClientError},
+		1848: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_PARTITION", ClientError},
+		1849: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_FK_RENAME", Constraint},
+		1850: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_COLUMN_TYPE", ClientError},
+		1851: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_FK_CHECK", Constraint},
+		1853: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_NOPK", ClientError},
+		1854: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_AUTOINC", ClientError},
+		1855: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_HIDDEN_FTS", ClientError},
+		1856: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_CHANGE_FTS", ClientError},
+		1857: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_FTS", ClientError},
+		1858: {"ER_SQL_SLAVE_SKIP_COUNTER_NOT_SETTABLE_IN_GTID_MODE", Server},
+		1859: {"ER_DUP_UNKNOWN_IN_INDEX", Constraint},
+		1860: {"ER_IDENT_CAUSES_TOO_LONG_PATH", ClientError},
+		1861: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_NOT_NULL", ClientError},
+		1862: {"ER_MUST_CHANGE_PASSWORD_LOGIN", Permission},
+		1863: {"ER_ROW_IN_WRONG_PARTITION", ClientError},
+		1864: {"ER_MTS_EVENT_BIGGER_PENDING_JOBS_SIZE_MAX", ClientError},
+		1865: {"ER_INNODB_NO_FT_USES_PARSER", Server},
+		1866: {"ER_BINLOG_LOGICAL_CORRUPTION", Server},
+		1867: {"ER_WARN_PURGE_LOG_IN_USE", ClientError},
+		1868: {"ER_WARN_PURGE_LOG_IS_ACTIVE", ClientError},
+		1869: {"ER_AUTO_INCREMENT_CONFLICT", ClientError},
+		1870: {"WARN_ON_BLOCKHOLE_IN_RBR", ClientError},
+		1871: {"ER_SLAVE_MI_INIT_REPOSITORY", Server},
+		1872: {"ER_SLAVE_RLI_INIT_REPOSITORY", Server},
+		1873: {"ER_ACCESS_DENIED_CHANGE_USER_ERROR", Permission},
+		1874: {"ER_INNODB_READ_ONLY", Server},
+		1875: {"ER_STOP_SLAVE_SQL_THREAD_TIMEOUT", Server},
+		1876: {"ER_STOP_SLAVE_IO_THREAD_TIMEOUT", Server},
+		1877: {"ER_TABLE_CORRUPT", Server},
+		1878: {"ER_TEMP_FILE_WRITE_FAILURE", Server},
+		1879: {"ER_INNODB_FT_AUX_NOT_HEX_ID", Server},
+		1880: {"ER_OLD_TEMPORALS_UPGRADED", ClientError},
+		1881: {"ER_INNODB_FORCED_RECOVERY", Server},
+		1882: {"ER_AES_INVALID_IV", ClientError},
+		1883: {"ER_PLUGIN_CANNOT_BE_UNINSTALLED", ClientError},
+		1884: {"ER_GTID_UNSAFE_BINLOG_SPLITTABLE_STATEMENT_AND_GTID_GROUP", Server},
+		1885: {"ER_SLAVE_HAS_MORE_GTIDS_THAN_MASTER", Server},
+		1886: {"ER_MISSING_KEY", ClientError},
+		1887: {"WARN_NAMED_PIPE_ACCESS_EVERYONE", ClientError},
+		1888: {"ER_FOUND_MISSING_GTIDS", ClientError},
+		3000: {"ER_FILE_CORRUPT", Server},
+		3001: {"ER_ERROR_ON_MASTER", Server},
+		3002: {"ER_INCONSISTENT_ERROR", ClientError},
+		3003: {"ER_STORAGE_ENGINE_NOT_LOADED", ClientError},
+		3004: {"ER_GET_STACKED_DA_WITHOUT_ACTIVE_HANDLER", ClientError},
+		3005: {"ER_WARN_LEGACY_SYNTAX_CONVERTED", Syntax},
+		3006: {"ER_BINLOG_UNSAFE_FULLTEXT_PLUGIN", Server},
+		3007: {"ER_CANNOT_DISCARD_TEMPORARY_TABLE", ClientError},
+		3008: {"ER_FK_DEPTH_EXCEEDED", Constraint},
+		3009: {"ER_COL_COUNT_DOESNT_MATCH_PLEASE_UPDATE_V2", ClientError},
+		3010: {"ER_WARN_TRIGGER_DOESNT_HAVE_CREATED", ClientError},
+		3011: {"ER_REFERENCED_TRG_DOES_NOT_EXIST", ClientError},
+		3012: {"ER_EXPLAIN_NOT_SUPPORTED", ClientError},
+		3013: {"ER_INVALID_FIELD_SIZE", ClientError},
+		3014: {"ER_MISSING_HA_CREATE_OPTION", ClientError},
+		3015: {"ER_ENGINE_OUT_OF_MEMORY", Server},
+		3016: {"ER_PASSWORD_EXPIRE_ANONYMOUS_USER", Permission},
+		3017: {"ER_SLAVE_SQL_THREAD_MUST_STOP", Server},
+		3018: {"ER_NO_FT_MATERIALIZED_SUBQUERY", ClientError},
+		3019: {"ER_INNODB_UNDO_LOG_FULL", Server},
+		3020: {"ER_INVALID_ARGUMENT_FOR_LOGARITHM", ClientError},
+		3021: {"ER_SLAVE_CHANNEL_IO_THREAD_MUST_STOP", Server},
+		3022: {"ER_WARN_OPEN_TEMP_TABLES_MUST_BE_ZERO", ClientError},
+		3023: {"ER_WARN_ONLY_MASTER_LOG_FILE_NO_POS", Server},
+		3024: {"ER_QUERY_TIMEOUT", Server},
+		3025: {"ER_NON_RO_SELECT_DISABLE_TIMER", ClientError},
+		3026: {"ER_DUP_LIST_ENTRY", ClientError},
+		3027: {"ER_SQL_MODE_NO_EFFECT", ClientError},
+		3028: {"ER_AGGREGATE_ORDER_FOR_UNION", ClientError},
+		3029: {"ER_AGGREGATE_ORDER_NON_AGG_QUERY", ClientError},
+		3030: {"ER_SLAVE_WORKER_STOPPED_PREVIOUS_THD_ERROR", Server},
+		3031: {"ER_DONT_SUPPORT_SLAVE_PRESERVE_COMMIT_ORDER", Server},
+		3032: {"ER_SERVER_OFFLINE_MODE", ClientError},
+		3033: {"ER_GIS_DIFFERENT_SRIDS", ClientError},
+		3034: {"ER_GIS_UNSUPPORTED_ARGUMENT", ClientError},
+		3035: {"ER_GIS_UNKNOWN_ERROR", ClientError},
+		3036: {"ER_GIS_UNKNOWN_EXCEPTION", ClientError},
+		3037: {"ER_GIS_INVALID_DATA", ClientError},
+		3038: {"ER_BOOST_GEOMETRY_EMPTY_INPUT_EXCEPTION", ClientError},
+		3039: {"ER_BOOST_GEOMETRY_CENTROID_EXCEPTION", ClientError},
+		3040: {"ER_BOOST_GEOMETRY_OVERLAY_INVALID_INPUT_EXCEPTION", ClientError},
+		3041: {"ER_BOOST_GEOMETRY_TURN_INFO_EXCEPTION", ClientError},
+		3042: {"ER_BOOST_GEOMETRY_SELF_INTERSECTION_POINT_EXCEPTION", ClientError},
+		3043: {"ER_BOOST_GEOMETRY_UNKNOWN_EXCEPTION", ClientError},
+		3044: {"ER_STD_BAD_ALLOC_ERROR", ClientError},
+		3045: {"ER_STD_DOMAIN_ERROR", ClientError},
+		3046: {"ER_STD_LENGTH_ERROR", ClientError},
+		3047: {"ER_STD_INVALID_ARGUMENT", ClientError},
+		3048: {"ER_STD_OUT_OF_RANGE_ERROR", ClientError},
+		3049: {"ER_STD_OVERFLOW_ERROR", ClientError},
+		3050: {"ER_STD_RANGE_ERROR", ClientError},
+		3051: {"ER_STD_UNDERFLOW_ERROR", ClientError},
+		3052: {"ER_STD_LOGIC_ERROR", ClientError},
+		3053: {"ER_STD_RUNTIME_ERROR", ClientError},
+		3054: {"ER_STD_UNKNOWN_EXCEPTION", ClientError},
+		3055: {"ER_GIS_DATA_WRONG_ENDIANESS", ClientError},
+		3056: {"ER_CHANGE_MASTER_PASSWORD_LENGTH", Permission},
+		3057: {"ER_USER_LOCK_WRONG_NAME", Lock},
+		3058: {"ER_USER_LOCK_DEADLOCK", Lock},
+		3059: {"ER_REPLACE_INACCESSIBLE_ROWS", ClientError},
+		3060: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_GIS", ClientError},
+		3061: {"ER_ILLEGAL_USER_VAR", Syntax},
+		3062: {"ER_GTID_MODE_OFF", ClientError},
+		3063: {"ER_UNSUPPORTED_BY_REPLICATION_THREAD", Server},
+		3064: {"ER_INCORRECT_TYPE", ClientError},
+		3065: {"ER_FIELD_IN_ORDER_NOT_SELECT", ClientError},
+		3066: {"ER_AGGREGATE_IN_ORDER_NOT_SELECT", ClientError},
+		3067: {"ER_INVALID_RPL_WILD_TABLE_FILTER_PATTERN", ClientError},
+		3068: {"ER_NET_OK_PACKET_TOO_LARGE", Server},
+		3069: {"ER_INVALID_JSON_DATA", ClientError},
+		3070: {"ER_INVALID_GEOJSON_MISSING_MEMBER", ClientError},
+		3071: {"ER_INVALID_GEOJSON_WRONG_TYPE", ClientError},
+		3072: {"ER_INVALID_GEOJSON_UNSPECIFIED", ClientError},
+		3073: {"ER_DIMENSION_UNSUPPORTED", ClientError},
+		3074: {"ER_SLAVE_CHANNEL_DOES_NOT_EXIST", Server},
+		3075: {"ER_SLAVE_MULTIPLE_CHANNELS_HOST_PORT", Server},
+		3076: {"ER_SLAVE_CHANNEL_NAME_INVALID_OR_TOO_LONG", Server},
+		3077: {"ER_SLAVE_NEW_CHANNEL_WRONG_REPOSITORY", Server},
+		3078: {"ER_SLAVE_CHANNEL_DELETE", Server},
+		3079: {"ER_SLAVE_MULTIPLE_CHANNELS_CMD", Server},
+		3080: {"ER_SLAVE_MAX_CHANNELS_EXCEEDED", Server},
+		3081: {"ER_SLAVE_CHANNEL_MUST_STOP", Server},
+		3082: {"ER_SLAVE_CHANNEL_NOT_RUNNING", Server},
+		3083: {"ER_SLAVE_CHANNEL_WAS_RUNNING", Server},
+		3084: {"ER_SLAVE_CHANNEL_WAS_NOT_RUNNING", Server},
+		3085: {"ER_SLAVE_CHANNEL_SQL_THREAD_MUST_STOP", Server},
+		3086: {"ER_SLAVE_CHANNEL_SQL_SKIP_COUNTER", Server},
+		3087: {"ER_WRONG_FIELD_WITH_GROUP_V2", ClientError},
+		3088: {"ER_MIX_OF_GROUP_FUNC_AND_FIELDS_V2", ClientError},
+		3089: {"ER_WARN_DEPRECATED_SYSVAR_UPDATE", ClientError},
+		3090: {"ER_WARN_DEPRECATED_SQLMODE", ClientError},
+		3091: {"ER_CANNOT_LOG_PARTIAL_DROP_DATABASE_WITH_GTID", ClientError},
+		3092: {"ER_GROUP_REPLICATION_CONFIGURATION", ClientError},
+		3093: {"ER_GROUP_REPLICATION_RUNNING", ClientError},
+		3094: {"ER_GROUP_REPLICATION_APPLIER_INIT_ERROR", ClientError},
+		3095: {"ER_GROUP_REPLICATION_STOP_APPLIER_THREAD_TIMEOUT", Server},
+		3096: {"ER_GROUP_REPLICATION_COMMUNICATION_LAYER_SESSION_ERROR", ClientError},
+		3097: {"ER_GROUP_REPLICATION_COMMUNICATION_LAYER_JOIN_ERROR", ClientError},
+		3098: {"ER_BEFORE_DML_VALIDATION_ERROR", ClientError},
+		3099: {"ER_PREVENTS_VARIABLE_WITHOUT_RBR", ClientError},
+		3100: {"ER_RUN_HOOK_ERROR", ClientError},
+		3101: {"ER_TRANSACTION_ROLLBACK_DURING_COMMIT", Server},
+		3102: {"ER_GENERATED_COLUMN_FUNCTION_IS_NOT_ALLOWED", ClientError},
+		3103: {"ER_UNSUPPORTED_ALTER_INPLACE_ON_VIRTUAL_COLUMN", ClientError},
+		3104: {"ER_WRONG_FK_OPTION_FOR_GENERATED_COLUMN", Constraint},
+		3105: {"ER_NON_DEFAULT_VALUE_FOR_GENERATED_COLUMN", ClientError},
+		3106: {"ER_UNSUPPORTED_ACTION_ON_GENERATED_COLUMN", ClientError},
+		3107: {"ER_GENERATED_COLUMN_NON_PRIOR", ClientError},
+		3108: {"ER_DEPENDENT_BY_GENERATED_COLUMN", ClientError},
+		3109: {"ER_GENERATED_COLUMN_REF_AUTO_INC,", ClientError},
+		3110: {"ER_FEATURE_NOT_AVAILABLE", ClientError},
+		3111: {"ER_CANT_SET_GTID_MODE", ClientError},
+		3112: {"ER_CANT_USE_AUTO_POSITION_WITH_GTID_MODE_OFF", ClientError},
+		3113: {"ER_CANT_REPLICATE_ANONYMOUS_WITH_AUTO_POSITION", ClientError},
+		3114: {"ER_CANT_REPLICATE_ANONYMOUS_WITH_GTID_MODE_ON", ClientError},
+		3115: {"ER_CANT_REPLICATE_GTID_WITH_GTID_MODE_OFF", ClientError},
+		3116: {"ER_CANT_SET_ENFORCE_GTID_CONSISTENCY_ON_WITH_ONGOING_GTID_VIOLATING_TRANSACTIONS", ClientError},
+		3117: {"ER_SET_ENFORCE_GTID_CONSISTENCY_WARN_WITH_ONGOING_GTID_VIOLATING_TRANSACTIONS", ClientError},
+		3118: {"ER_ACCOUNT_HAS_BEEN_LOCKED", Permission},
+		3119: {"ER_WRONG_TABLESPACE_NAME", Syntax},
+		3120: {"ER_TABLESPACE_IS_NOT_EMPTY", ClientError},
+		3121: {"ER_WRONG_FILE_NAME", Server},
+		3122: {"ER_BOOST_GEOMETRY_INCONSISTENT_TURNS_EXCEPTION", ClientError},
+		3123: {"ER_WARN_OPTIMIZER_HINT_SYNTAX_ERROR", Syntax},
+		3124: {"ER_WARN_BAD_MAX_EXECUTION_TIME", ClientError},
+		3125: {"ER_WARN_UNSUPPORTED_MAX_EXECUTION_TIME", ClientError},
+		3126: {"ER_WARN_CONFLICTING_HINT", ClientError},
+		3127: {"ER_WARN_UNKNOWN_QB_NAME", ClientError},
+		3128: {"ER_UNRESOLVED_HINT_NAME", ClientError},
+		3129: {"ER_WARN_ON_MODIFYING_GTID_EXECUTED_TABLE", ClientError},
+		3130: {"ER_PLUGGABLE_PROTOCOL_COMMAND_NOT_SUPPORTED", ClientError},
+		3131: {"ER_LOCKING_SERVICE_WRONG_NAME", Syntax},
+		3132: {"ER_LOCKING_SERVICE_DEADLOCK", Lock},
+		3133: {"ER_LOCKING_SERVICE_TIMEOUT", Server},
+		3134: {"ER_GIS_MAX_POINTS_IN_GEOMETRY_OVERFLOWED", ClientError},
+		3135: {"ER_SQL_MODE_MERGED", ClientError},
+		3136: {"ER_VTOKEN_PLUGIN_TOKEN_MISMATCH", ClientError},
+		3137: {"ER_VTOKEN_PLUGIN_TOKEN_NOT_FOUND", ClientError},
+		3138: {"ER_CANT_SET_VARIABLE_WHEN_OWNING_GTID", ClientError},
+		3139: {"ER_SLAVE_CHANNEL_OPERATION_NOT_ALLOWED", Server},
+		3140: {"ER_INVALID_JSON_TEXT", ClientError},
+		3141: {"ER_INVALID_JSON_TEXT_IN_PARAM", ClientError},
+		3142: {"ER_INVALID_JSON_BINARY_DATA", ClientError},
+		3143: {"ER_INVALID_JSON_PATH", Syntax},
+		3144: {"ER_INVALID_JSON_CHARSET", ClientError},
+		3145: {"ER_INVALID_JSON_CHARSET_IN_FUNCTION", ClientError},
+		3146: {"ER_INVALID_TYPE_FOR_JSON", ClientError},
+		3147: {"ER_INVALID_CAST_TO_JSON", ClientError},
+		3148: {"ER_INVALID_JSON_PATH_CHARSET", Syntax},
+		3149: {"ER_INVALID_JSON_PATH_WILDCARD", Syntax},
+		3150: {"ER_JSON_VALUE_TOO_BIG", ClientError},
+		3151: {"ER_JSON_KEY_TOO_BIG", ClientError},
+		3152: {"ER_JSON_USED_AS_KEY", Syntax},
+		3153: {"ER_JSON_VACUOUS_PATH", Syntax},
+		3154: {"ER_JSON_BAD_ONE_OR_ALL_ARG", Syntax},
+		3155: {"ER_NUMERIC_JSON_VALUE_OUT_OF_RANGE", ClientError},
+		3156: {"ER_INVALID_JSON_VALUE_FOR_CAST", ClientError},
+		3157: {"ER_JSON_DOCUMENT_TOO_DEEP", ClientError},
+		3158: {"ER_JSON_DOCUMENT_NULL_KEY", ClientError},
+		3159: {"ER_SECURE_TRANSPORT_REQUIRED", ClientError},
+		3160: {"ER_NO_SECURE_TRANSPORTS_CONFIGURED", ClientError},
+		3161: {"ER_DISABLED_STORAGE_ENGINE", ClientError},
+		3162: {"ER_USER_DOES_NOT_EXIST", ClientError},
+		3163: {"ER_USER_ALREADY_EXISTS", ClientError},
+		3164: {"ER_AUDIT_API_ABORT", ClientError},
+		3165: {"ER_INVALID_JSON_PATH_ARRAY_CELL", Syntax},
+		3166: {"ER_BUFPOOL_RESIZE_INPROGRESS", ClientError},
+		3167: {"ER_FEATURE_DISABLED_SEE_DOC", ClientError},
+		3168: {"ER_SERVER_ISNT_AVAILABLE", ClientError},
+		3169: {"ER_SESSION_WAS_KILLED", Server},
+		3170: {"ER_CAPACITY_EXCEEDED", ClientError},
+		3171: {"ER_CAPACITY_EXCEEDED_IN_RANGE_OPTIMIZER", ClientError},
+		3172: {"ER_TABLE_NEEDS_UPG_PART", ClientError},
+		3173: {"ER_CANT_WAIT_FOR_EXECUTED_GTID_SET_WHILE_OWNING_A_GTID", ClientError},
+		3174: {"ER_CANNOT_ADD_FOREIGN_BASE_COL_VIRTUAL", Constraint},
+		3175: {"ER_CANNOT_CREATE_VIRTUAL_INDEX_CONSTRAINT", Constraint},
+		3176: {"ER_ERROR_ON_MODIFYING_GTID_EXECUTED_TABLE", ClientError},
+		3177: {"ER_LOCK_REFUSED_BY_ENGINE", Lock},
+		3178: {"ER_UNSUPPORTED_ALTER_ONLINE_ON_VIRTUAL_COLUMN", ClientError},
+		3179: {"ER_MASTER_KEY_ROTATION_NOT_SUPPORTED_BY_SE", Server},
+		3180: {"ER_MASTER_KEY_ROTATION_ERROR_BY_SE", Server},
+		3181: {"ER_MASTER_KEY_ROTATION_BINLOG_FAILED", Server},
+		3182: {"ER_MASTER_KEY_ROTATION_SE_UNAVAILABLE", Server},
+		3183: {"ER_TABLESPACE_CANNOT_ENCRYPT", ClientError},
+		3184: {"ER_INVALID_ENCRYPTION_OPTION", ClientError},
+		3185: {"ER_CANNOT_FIND_KEY_IN_KEYRING", ClientError},
+		3186: {"ER_CAPACITY_EXCEEDED_IN_PARSER", ClientError},
+		3187: {"ER_UNSUPPORTED_ALTER_ENCRYPTION_INPLACE", ClientError},
+		3188: {"ER_KEYRING_UDF_KEYRING_SERVICE_ERROR", ClientError},
+		3189: {"ER_USER_COLUMN_OLD_LENGTH", ClientError},
+		3190: {"ER_CANT_RESET_MASTER", Server},
+		3191: {"ER_GROUP_REPLICATION_MAX_GROUP_SIZE", ClientError},
+		3192: {"ER_CANNOT_ADD_FOREIGN_BASE_COL_STORED", Constraint},
+		3193: {"ER_TABLE_REFERENCED", ClientError},
+		3194: {"ER_PARTITION_ENGINE_DEPRECATED_FOR_TABLE", ClientError},
+		3195: {"ER_WARN_USING_GEOMFROMWKB_TO_SET_SRID_ZERO", ClientError},
+		3196: {"ER_WARN_USING_GEOMFROMWKB_TO_SET_SRID", ClientError},
+		3197: {"ER_XA_RETRY", ClientError},
+		3198: {"ER_KEYRING_AWS_UDF_AWS_KMS_ERROR", ClientError},
+		3199: {"ER_BINLOG_UNSAFE_XA", Server},
+		3200: {"ER_UDF_ERROR", ClientError},
+		3201: {"ER_KEYRING_MIGRATION_FAILURE", ClientError},
+		3202: {"ER_KEYRING_ACCESS_DENIED_ERROR", Permission},
+		3203: {"ER_KEYRING_MIGRATION_STATUS", ClientError},
+		3204: {"ER_PLUGIN_FAILED_TO_OPEN_TABLES", Server},
+		3205: {"ER_PLUGIN_FAILED_TO_OPEN_TABLE", Server},
+		3206: {"ER_AUDIT_LOG_NO_KEYRING_PLUGIN_INSTALLED", ClientError},
+		3207: {"ER_AUDIT_LOG_ENCRYPTION_PASSWORD_HAS_NOT_BEEN_SET", Permission},
+		3208: {"ER_AUDIT_LOG_COULD_NOT_CREATE_AES_KEY", ClientError},
+		3209: {"ER_AUDIT_LOG_ENCRYPTION_PASSWORD_CANNOT_BE_FETCHED", Permission},
+		3210: {"ER_AUDIT_LOG_JSON_FILTERING_NOT_ENABLED", ClientError},
+		3211: {"ER_AUDIT_LOG_UDF_INSUFFICIENT_PRIVILEGE", Permission},
+		3212: {"ER_AUDIT_LOG_SUPER_PRIVILEGE_REQUIRED", Permission},
+		3213: {"ER_COULD_NOT_REINITIALIZE_AUDIT_LOG_FILTERS", ClientError},
+		3214: {"ER_AUDIT_LOG_UDF_INVALID_ARGUMENT_TYPE", ClientError},
+		3215: {"ER_AUDIT_LOG_UDF_INVALID_ARGUMENT_COUNT", ClientError},
+		3216: {"ER_AUDIT_LOG_HAS_NOT_BEEN_INSTALLED", ClientError},
+		3217: {"ER_AUDIT_LOG_UDF_READ_INVALID_MAX_ARRAY_LENGTH_ARG_TYPE", ClientError},
+		3218: {"ER_AUDIT_LOG_UDF_READ_INVALID_MAX_ARRAY_LENGTH_ARG_VALUE", ClientError},
+		3219: {"ER_AUDIT_LOG_JSON_FILTER_PARSING_ERROR", ClientError},
+		3220: {"ER_AUDIT_LOG_JSON_FILTER_NAME_CANNOT_BE_EMPTY", ClientError},
+		3221: {"ER_AUDIT_LOG_JSON_USER_NAME_CANNOT_BE_EMPTY", ClientError},
+		3222: {"ER_AUDIT_LOG_JSON_FILTER_DOES_NOT_EXISTS", ClientError},
+		3223: {"ER_AUDIT_LOG_USER_FIRST_CHARACTER_MUST_BE_ALPHANUMERIC", ClientError},
+		3224: {"ER_AUDIT_LOG_USER_NAME_INVALID_CHARACTER", ClientError},
+		3225: {"ER_AUDIT_LOG_HOST_NAME_INVALID_CHARACTER", ClientError},
+		3226: {"WARN_DEPRECATED_MAXDB_SQL_MODE_FOR_TIMESTAMP", ClientError},
+		3227: {"ER_XA_REPLICATION_FILTERS", ClientError},
+		3228: {"ER_CANT_OPEN_ERROR_LOG", ClientError},
+		3229: {"ER_GROUPING_ON_TIMESTAMP_IN_DST", ClientError},
+		3230: {"ER_CANT_START_SERVER_NAMED_PIPE", ClientError},
+		3231: {"ER_WRITE_SET_EXCEEDS_LIMIT", ClientError},
+		3232: {"ER_DEPRECATED_TLS_VERSION_SESSION", ClientError},
+		3233: {"ER_WARN_DEPRECATED_TLS_VERSION", ClientError},
+		3234: {"ER_WARN_WRONG_NATIVE_TABLE_STRUCTURE", ClientError},
+		3235: {"ER_AES_INVALID_KDF_NAME", ClientError},
+		3236: {"ER_AES_INVALID_KDF_ITERATIONS", ClientError},
+		3237: {"WARN_AES_KEY_SIZE", ClientError},
+		3238: {"ER_AES_INVALID_KDF_OPTION_SIZE", ClientError},
+		3572: {"ER_LOCK_NOWAIT", Lock},
+		3730: {"ER_FK_CANNOT_DROP_PARENT", Constraint},
+		3780: {"ER_FK_INCOMPATIBLE_COLUMNS", Constraint},
+		3819: {"ER_CHECK_CONSTRAINT_VIOLATED", Constraint},
+		3948: {"ER_CLIENT_LOCAL_FILES_DISABLED", Permission},
+		4031: {"ER_CLIENT_INTERACTION_TIMEOUT", Server},
+	}
+	mariadb = map[uint16]Entry{
+		1000: {"ER_HASHCHK", ClientError},
+		1001: {"ER_NISAMCHK", ClientError},
+		1002: {"ER_NO", ClientError},
+		1003: {"ER_YES", ClientError},
+		1004: {"ER_CANT_CREATE_FILE", Server},
+		1005: {"ER_CANT_CREATE_TABLE", ClientError},
+		1006: {"ER_CANT_CREATE_DB", ClientError},
+		1007: {"ER_DB_CREATE_EXISTS", ClientError},
+		1008: {"ER_DB_DROP_EXISTS", ClientError},
+		1009: {"ER_DB_DROP_DELETE", ClientError},
+		1010: {"ER_DB_DROP_RMDIR", ClientError},
+		1011: {"ER_CANT_DELETE_FILE", Server},
+		1012: {"ER_CANT_FIND_SYSTEM_REC", ClientError},
+		1013: {"ER_CANT_GET_STAT", ClientError},
+		1014: {"ER_CANT_GET_WD", ClientError},
+		1015: {"ER_CANT_LOCK", Lock},
+		1016: {"ER_CANT_OPEN_FILE", Server},
+		1017: {"ER_FILE_NOT_FOUND", Server},
+		1018: {"ER_CANT_READ_DIR", Server},
+		1019: {"ER_CANT_SET_WD", ClientError},
+		1020: {"ER_CHECKREAD", ClientError},
+		1021: {"ER_DISK_FULL", Server},
+		1022: {"ER_DUP_KEY", Constraint},
+		1023: {"ER_ERROR_ON_CLOSE", ClientError},
+		1024: {"ER_ERROR_ON_READ", ClientError},
+		1025: {"ER_ERROR_ON_RENAME", ClientError},
+		1026: {"ER_ERROR_ON_WRITE", ClientError},
+		1027: {"ER_FILE_USED", Server},
+		1028: {"ER_FILSORT_ABORT", ClientError},
+		1029: {"ER_FORM_NOT_FOUND", ClientError},
+		1030: {"ER_GET_ERRNO", Server},
+		1031: {"ER_ILLEGAL_HA", ClientError},
+		1032: {"ER_KEY_NOT_FOUND", ClientError},
+		1033: {"ER_NOT_FORM_FILE", Server},
+		1034: {"ER_NOT_KEYFILE", ClientError},
+		1035: {"ER_OLD_KEYFILE", ClientError},
+		1036: {"ER_OPEN_AS_READONLY", ClientError},
+		1037: {"ER_OUTOFMEMORY", Server},
+		1038: {"ER_OUT_OF_SORTMEMORY", Server},
+		1039: {"ER_UNEXPECTED_EOF", ClientError},
+		1040: {"ER_CON_COUNT_ERROR", Server},
+		1041: {"ER_OUT_OF_RESOURCES", Server},
+		1042: {"ER_BAD_HOST_ERROR", Server},
+		1043: {"ER_HANDSHAKE_ERROR", Server},
+		1044: {"ER_DBACCESS_DENIED_ERROR", Permission},
+		1045: {"ER_ACCESS_DENIED_ERROR", Permission},
+		1046: {"ER_NO_DB_ERROR", ClientError},
+		1047: {"ER_UNKNOWN_COM_ERROR", Server},
+		1048: {"ER_BAD_NULL_ERROR", Constraint},
+		1049: {"ER_BAD_DB_ERROR", Syntax},
+		1050: {"ER_TABLE_EXISTS_ERROR", ClientError},
+		1051: {"ER_BAD_TABLE_ERROR", ClientError},
+		1052: {"ER_NON_UNIQ_ERROR", Constraint},
+		1053: {"ER_SERVER_SHUTDOWN", Server},
+		1054: {"ER_BAD_FIELD_ERROR", ClientError},
+		1055: {"ER_WRONG_FIELD_WITH_GROUP", Syntax},
+		1056: {"ER_WRONG_GROUP_FIELD", Syntax},
+		1057: {"ER_WRONG_SUM_SELECT", Syntax},
+		1058: {"ER_WRONG_VALUE_COUNT", ClientError},
+		1059: {"ER_TOO_LONG_IDENT", Syntax},
+		1060: {"ER_DUP_FIELDNAME", ClientError},
+		1061: {"ER_DUP_KEYNAME", Syntax},
+		1062: {"ER_DUP_ENTRY", Constraint},
+		1063: {"ER_WRONG_FIELD_SPEC", Syntax},
+		1064: {"ER_PARSE_ERROR", Syntax},
+		1065: {"ER_EMPTY_QUERY", Syntax},
+		1066: {"ER_NONUNIQ_TABLE", Syntax},
+		1067: {"ER_INVALID_DEFAULT", Syntax},
+		1068: {"ER_MULTIPLE_PRI_KEY", Syntax},
+		1069: {"ER_TOO_MANY_KEYS", Syntax},
+		1070: {"ER_TOO_MANY_KEY_PARTS", Syntax},
+		1071: {"ER_TOO_LONG_KEY", Syntax},
+		1072: {"ER_KEY_COLUMN_DOES_NOT_EXIST", Syntax},
+		1073: {"ER_BLOB_USED_AS_KEY", Syntax},
+		1074: {"ER_TOO_BIG_FIELDLENGTH", Syntax},
+		1075: {"ER_WRONG_AUTO_KEY", Syntax},
+		1076: {"ER_BINLOG_CANT_DELETE_GTID_DOMAIN", Server},
+		1077: {"ER_NORMAL_SHUTDOWN", Server},
+		1078: {"ER_GOT_SIGNAL", ClientError},
+		1079: {"ER_SHUTDOWN_COMPLETE", Server},
+		1080: {"ER_FORCING_CLOSE", Server},
+		1081: {"ER_IPSOCK_ERROR", Server},
+		1082: {"ER_NO_SUCH_INDEX", ClientError},
+		1083: {"ER_WRONG_FIELD_TERMINATORS", Syntax},
+		1084: {"ER_BLOBS_AND_NO_TERMINATED", Syntax},
+		1085: {"ER_TEXTFILE_NOT_READABLE", ClientError},
+		1086: {"ER_FILE_EXISTS_ERROR", Server},
+		1087: {"ER_LOAD_INFO", ClientError},
+		1088: {"ER_ALTER_INFO", ClientError},
+		1089: {"ER_WRONG_SUB_KEY", ClientError},
+		1090: {"ER_CANT_REMOVE_ALL_FIELDS", Syntax},
+		1091: {"ER_CANT_DROP_FIELD_OR_KEY", Syntax},
+		1092: {"ER_INSERT_INFO", ClientError},
+		1093: {"ER_UPDATE_TABLE_USED", ClientError},
+		1094: {"ER_NO_SUCH_THREAD", Server},
+		1095: {"ER_KILL_DENIED_ERROR", Permission},
+		1096: {"ER_NO_TABLES_USED", ClientError},
+		1097: {"ER_TOO_BIG_SET", ClientError},
+		1098: {"ER_NO_UNIQUE_LOGFILE", ClientError},
+		1099: {"ER_TABLE_NOT_LOCKED_FOR_WRITE", Lock},
+		1100: {"ER_TABLE_NOT_LOCKED", Lock},
+		1102: {"ER_WRONG_DB_NAME", Syntax},
+		1103: {"ER_WRONG_TABLE_NAME", Syntax},
+		1104: {"ER_TOO_BIG_SELECT", Syntax},
+		1105: {"ER_UNKNOWN_ERROR", ClientError},
+		1106: {"ER_UNKNOWN_PROCEDURE", Syntax},
+		1107: {"ER_WRONG_PARAMCOUNT_TO_PROCEDURE", Syntax},
+		1108: {"ER_WRONG_PARAMETERS_TO_PROCEDURE", ClientError},
+		1109: {"ER_UNKNOWN_TABLE", ClientError},
+		1110: {"ER_FIELD_SPECIFIED_TWICE", Syntax},
+		1111: {"ER_INVALID_GROUP_FUNC_USE", ClientError},
+		1112: {"ER_UNSUPPORTED_EXTENSION", Syntax},
+		1113: {"ER_TABLE_MUST_HAVE_COLUMNS", Syntax},
+		1114: {"ER_RECORD_FILE_FULL", Server},
+		1115: {"ER_UNKNOWN_CHARACTER_SET", Syntax},
+		1116: {"ER_TOO_MANY_TABLES", ClientError},
+		1117: {"ER_TOO_MANY_FIELDS", ClientError},
+		1118: {"ER_TOO_BIG_ROWSIZE", Syntax},
+		1119: {"ER_STACK_OVERRUN", Server},
+		1120: {"ER_WRONG_OUTER_JOIN", Syntax},
+		1121: {"ER_NULL_COLUMN_IN_INDEX", Syntax},
+		1122: {"ER_CANT_FIND_UDF", ClientError},
+		1123: {"ER_CANT_INITIALIZE_UDF", ClientError},
+		1124: {"ER_UDF_NO_PATHS", ClientError},
+		1125: {"ER_UDF_EXISTS", ClientError},
+		1126: {"ER_CANT_OPEN_LIBRARY", ClientError},
+		1127: {"ER_CANT_FIND_DL_ENTRY", ClientError},
+		1128: {"ER_FUNCTION_NOT_DEFINED", ClientError},
+		1129: {"ER_HOST_IS_BLOCKED", Permission},
+		1130: {"ER_HOST_NOT_PRIVILEGED", Permission},
+		1131: {"ER_PASSWORD_ANONYMOUS_USER", Permission},
+		1132: {"ER_PASSWORD_NOT_ALLOWED", Permission},
+		1133: {"ER_PASSWORD_NO_MATCH", Permission},
+		1134: {"ER_UPDATE_INFO", ClientError},
+		1135: {"ER_CANT_CREATE_THREAD", Server},
+		1136: {"ER_WRONG_VALUE_COUNT_ON_ROW", ClientError},
+		1137: {"ER_CANT_REOPEN_TABLE", ClientError},
+		1138: {"ER_INVALID_USE_OF_NULL", ClientError},
+		1139: {"ER_REGEXP_ERROR", Syntax},
+		1140: {"ER_MIX_OF_GROUP_FUNC_AND_FIELDS", Syntax},
+		1141: {"ER_NONEXISTING_GRANT", Permission},
+		1142: {"ER_TABLEACCESS_DENIED_ERROR", Permission},
+		1143: {"ER_COLUMNACCESS_DENIED_ERROR", Permission},
+		1144: {"ER_ILLEGAL_GRANT_FOR_TABLE", Permission},
+		1145: {"ER_GRANT_WRONG_HOST_OR_USER", Permission},
+		1146: {"ER_NO_SUCH_TABLE", ClientError},
+		1147: {"ER_NONEXISTING_TABLE_GRANT", Permission},
+		1148: {"ER_NOT_ALLOWED_COMMAND", Syntax},
+		1149: {"ER_SYNTAX_ERROR", Syntax},
+		1150: {"ER_DELAYED_CANT_CHANGE_LOCK", Lock},
+		1151: {"ER_TOO_MANY_DELAYED_THREADS", ClientError},
+		1152: {"ER_ABORTING_CONNECTION", Server},
+		1153: {"ER_NET_PACKET_TOO_LARGE", ClientError},
+		1154: {"ER_NET_READ_ERROR_FROM_PIPE", Server},
+		1155: {"ER_NET_FCNTL_ERROR", Server},
+		1156: {"ER_NET_PACKETS_OUT_OF_ORDER", Server},
+		1157: {"ER_NET_UNCOMPRESS_ERROR", Server},
+		1158: {"ER_NET_READ_ERROR", Server},
+		1159: {"ER_NET_READ_INTERRUPTED", Server},
+		1160: {"ER_NET_ERROR_ON_WRITE", Server},
+		1161: {"ER_NET_WRITE_INTERRUPTED", Server},
+		1162: {"ER_TOO_LONG_STRING", Syntax},
+		1163: {"ER_TABLE_CANT_HANDLE_BLOB", Syntax},
+		1164: {"ER_TABLE_CANT_HANDLE_AUTO_INCREMENT", Syntax},
+		1165: {"ER_DELAYED_INSERT_TABLE_LOCKED", Lock},
+		1166: {"ER_WRONG_COLUMN_NAME", Syntax},
+		1167: {"ER_WRONG_KEY_COLUMN", Syntax},
+		1168: {"ER_WRONG_MRG_TABLE", ClientError},
+		1169: {"ER_DUP_UNIQUE", Constraint},
+		1170: {"ER_BLOB_KEY_WITHOUT_LENGTH", Syntax},
+		1171: {"ER_PRIMARY_CANT_HAVE_NULL", Syntax},
+		1172: {"ER_TOO_MANY_ROWS", Syntax},
+		1173: {"ER_REQUIRES_PRIMARY_KEY", Syntax},
+		1174: {"ER_NO_RAID_COMPILED", ClientError},
+		1175: {"ER_UPDATE_WITHOUT_KEY_IN_SAFE_MODE", ClientError},
+		1176: {"ER_KEY_DOES_NOT_EXISTS", Syntax},
+		1177: {"ER_CHECK_NO_SUCH_TABLE", Syntax},
+		1178: {"ER_CHECK_NOT_IMPLEMENTED", Syntax},
+		1179: {"ER_CANT_DO_THIS_DURING_AN_TRANSACTION", Server},
+		1180: {"ER_ERROR_DURING_COMMIT", Server},
+		1181: {"ER_ERROR_DURING_ROLLBACK", Server},
+		1182: {"ER_ERROR_DURING_FLUSH_LOGS", Server},
+		1183: {"ER_ERROR_DURING_CHECKPOINT", Server},
+		1184: {"ER_NEW_ABORTING_CONNECTION", Server},
+		1186: {"ER_FLUSH_MASTER_BINLOG_CLOSED", Server},
+		1187: {"ER_INDEX_REBUILD", ClientError},
+		1188: {"ER_MASTER", Server},
+		1189: {"ER_MASTER_NET_READ", Server},
+		1190: {"ER_MASTER_NET_WRITE", Server},
+		1191: {"ER_FT_MATCHING_KEY_NOT_FOUND", ClientError},
+		1192: {"ER_LOCK_OR_ACTIVE_TRANSACTION", Lock},
+		1193: {"ER_UNKNOWN_SYSTEM_VARIABLE", ClientError},
+		1194: {"ER_CRASHED_ON_USAGE", Server},
+		1195: {"ER_CRASHED_ON_REPAIR", Server},
+		1196: {"ER_WARNING_NOT_COMPLETE_ROLLBACK", ClientError},
+		1197: {"ER_TRANS_CACHE_FULL", ClientError},
+		1198: {"ER_SLAVE_MUST_STOP", Server},
+		1199: {"ER_SLAVE_NOT_RUNNING", Server},
+		1200: {"ER_BAD_SLAVE", Server},
+		1201: {"ER_MASTER_INFO", Server},
+		1202: {"ER_SLAVE_THREAD", Server},
+		1203: {"ER_TOO_MANY_USER_CONNECTIONS", Server},
+		1204: {"ER_SET_CONSTANTS_ONLY", ClientError},
+		1205: {"ER_LOCK_WAIT_TIMEOUT", Lock},
+		1206: {"ER_LOCK_TABLE_FULL", Lock},
+		1207: {"ER_READ_ONLY_TRANSACTION", ClientError},
+		1208: {"ER_DROP_DB_WITH_READ_LOCK", Lock},
+		1209: {"ER_CREATE_DB_WITH_READ_LOCK", Lock},
+		1210: {"ER_WRONG_ARGUMENTS", ClientError},
+		1211: {"ER_NO_PERMISSION_TO_CREATE_USER", Syntax},
+		1212: {"ER_UNION_TABLES_IN_DIFFERENT_DIR", Server},
+		1213: {"ER_LOCK_DEADLOCK", Lock},
+		1214: {"ER_TABLE_CANT_HANDLE_FT", ClientError},
+		1215: {"ER_CANNOT_ADD_FOREIGN", Constraint},
+		1216: {"ER_NO_REFERENCED_ROW", Constraint},
+		1217: {"ER_ROW_IS_REFERENCED", Constraint},
+		1218: {"ER_CONNECT_TO_MASTER", Server},
+		1219: {"ER_QUERY_ON_MASTER", Server},
+		1220: {"ER_ERROR_WHEN_EXECUTING_COMMAND", ClientError},
+		1221: {"ER_WRONG_USAGE", ClientError},
+		1222: {"ER_WRONG_NUMBER_OF_COLUMNS_IN_SELECT", ClientError},
+		1223: {"ER_CANT_UPDATE_WITH_READLOCK", Lock},
+		1224: {"ER_MIXING_NOT_ALLOWED", ClientError},
+		1225: {"ER_DUP_ARGUMENT", ClientError},
+		1226: {"ER_USER_LIMIT_REACHED", Server},
+		1227: {"ER_SPECIFIC_ACCESS_DENIED_ERROR", Permission},
+		1228: {"ER_LOCAL_VARIABLE", ClientError},
+		1229: {"ER_GLOBAL_VARIABLE", ClientError},
+		1230: {"ER_NO_DEFAULT", Syntax},
+		1231: {"ER_WRONG_VALUE_FOR_VAR", Syntax},
+		1232: {"ER_WRONG_TYPE_FOR_VAR", Syntax},
+		1233: {"ER_VAR_CANT_BE_READ", ClientError},
+		1234: {"ER_CANT_USE_OPTION_HERE", Syntax},
+		1235: {"ER_NOT_SUPPORTED_YET", Syntax},
+		1236: {"ER_MASTER_FATAL_ERROR_READING_BINLOG", Server},
+		1237: {"ER_SLAVE_IGNORED_TABLE", Server},
+		1238: {"ER_INCORRECT_GLOBAL_LOCAL_VAR", ClientError},
+		1239: {"ER_WRONG_FK_DEF", Constraint},
+		1240: {"ER_KEY_REF_DO_NOT_MATCH_TABLE_REF", ClientError},
+		1241: {"ER_OPERAND_COLUMNS", ClientError},
+		1242: {"ER_SUBQUERY_NO_1_ROW", ClientError},
+		1243: {"ER_UNKNOWN_STMT_HANDLER", ClientError},
+		1244: {"ER_CORRUPT_HELP_DB", Server},
+		1245: {"ER_CYCLIC_REFERENCE", ClientError},
+		1246: {"ER_AUTO_CONVERT", ClientError},
+		1247: {"ER_ILLEGAL_REFERENCE", ClientError},
+		1248: {"ER_DERIVED_MUST_HAVE_ALIAS", Syntax},
+		1249: {"ER_SELECT_REDUCED", ClientError},
+		1250: {"ER_TABLENAME_NOT_ALLOWED_HERE", Syntax},
+		1251: {"ER_NOT_SUPPORTED_AUTH_MODE", Server},
+		1252: {"ER_INDEX_CANNOT_HAVE_NULL", Syntax},
+		1253: {"ER_COLLATION_CHARSET_MISMATCH", Syntax},
+		1254: {"ER_SLAVE_WAS_RUNNING", Server},
+		1255: {"ER_SLAVE_WAS_NOT_RUNNING", Server},
+		1256: {"ER_TOO_BIG_FOR_UNCOMPRESS", ClientError},
+		1257: {"ER_ZLIB_Z_MEM_ERROR", Server},
+		1258: {"ER_ZLIB_Z_BUF_ERROR", Server},
+		1259: {"ER_ZLIB_Z_DATA_ERROR", Server},
+		1260: {"ER_CUT_VALUE_GROUP_CONCAT", ClientError},
+		1261: {"ER_WARN_TOO_FEW_RECORDS", ClientError},
+		1262: {"ER_WARN_TOO_MANY_RECORDS", ClientError},
+		1263: {"ER_WARN_NULL_TO_NOTNULL", ClientError},
+		1264: {"ER_WARN_DATA_OUT_OF_RANGE", ClientError},
+		1265: {"WARN_DATA_TRUNCATED", ClientError},
+		1266: {"ER_WARN_USING_OTHER_HANDLER", ClientError},
+		1267: {"ER_CANT_AGGREGATE_2COLLATIONS", ClientError},
+		1268: {"ER_DROP_USER", ClientError},
+		1269: {"ER_REVOKE_GRANTS", ClientError},
+		1270: {"ER_CANT_AGGREGATE_3COLLATIONS", ClientError},
+		1271: {"ER_CANT_AGGREGATE_NCOLLATIONS", ClientError},
+		1272: {"ER_VARIABLE_IS_NOT_STRUCT", ClientError},
+		1273: {"ER_UNKNOWN_COLLATION", ClientError},
+		1274: {"ER_SLAVE_IGNORED_SSL_PARAMS", Server},
+		1275: {"ER_SERVER_IS_IN_SECURE_AUTH_MODE", ClientError},
+		1276: {"ER_WARN_FIELD_RESOLVED", ClientError},
+		1277: {"ER_BAD_SLAVE_UNTIL_COND", Server},
+		1278: {"ER_MISSING_SKIP_SLAVE", Server},
+		1279: {"ER_UNTIL_COND_IGNORED", ClientError},
+		1280: {"ER_WRONG_NAME_FOR_INDEX", Syntax},
+		1281: {"ER_WRONG_NAME_FOR_CATALOG", Syntax},
+		1282: {"ER_WARN_QC_RESIZE", ClientError},
+		1283: {"ER_BAD_FT_COLUMN", ClientError},
+		1284: {"ER_UNKNOWN_KEY_CACHE", ClientError},
+		1285: {"ER_WARN_HOSTNAME_WONT_WORK", ClientError},
+		1286: {"ER_UNKNOWN_STORAGE_ENGINE", Syntax},
+		1287: {"ER_WARN_DEPRECATED_SYNTAX", Syntax},
+		1288: {"ER_NON_UPDATABLE_TABLE", ClientError},
+		1289: {"ER_FEATURE_DISABLED", ClientError},
+		1290: {"ER_OPTION_PREVENTS_STATEMENT", Permission},
+		1291: {"ER_DUPLICATED_VALUE_IN_TYPE", ClientError},
+		1292: {"ER_TRUNCATED_WRONG_VALUE", ClientError},
+		1293: {"ER_TOO_MUCH_AUTO_TIMESTAMP_COLS", ClientError},
+		1294: {"ER_INVALID_ON_UPDATE", ClientError},
+		1295: {"ER_UNSUPPORTED_PS", ClientError},
+		1296: {"ER_GET_ERRMSG", ClientError},
+		1297: {"ER_GET_TEMPORARY_ERRMSG", ClientError},
+		1298: {"ER_UNKNOWN_TIME_ZONE", ClientError},
+		1299: {"ER_WARN_INVALID_TIMESTAMP", ClientError},
+		1300: {"ER_INVALID_CHARACTER_STRING", ClientError},
+		1301: {"ER_WARN_ALLOWED_PACKET_OVERFLOWED", ClientError},
+		1302: {"ER_CONFLICTING_DECLARATIONS", ClientError},
+		1303: {"ER_SP_NO_RECURSIVE_CREATE", ClientError},
+		1304: {"ER_SP_ALREADY_EXISTS", Syntax},
+		1305: {"ER_SP_DOES_NOT_EXIST", Syntax},
+		1306: {"ER_SP_DROP_FAILED", Server},
+		1307: {"ER_SP_STORE_FAILED", Server},
+		1308: {"ER_SP_LILABEL_MISMATCH", Syntax},
+		1309: {"ER_SP_LABEL_REDEFINE", Syntax},
+		1310: {"ER_SP_LABEL_MISMATCH", Syntax},
+		1311: {"ER_SP_UNINIT_VAR", ClientError},
+		1312: {"ER_SP_BADSELECT", ClientError},
+		1313: {"ER_SP_BADRETURN", Syntax},
+		1314: {"ER_SP_BADSTATEMENT", ClientError},
+		1315: {"ER_UPDATE_LOG_DEPRECATED_IGNORED", Syntax},
+		1316: {"ER_UPDATE_LOG_DEPRECATED_TRANSLATED", Syntax},
+		1317: {"ER_QUERY_INTERRUPTED", Server},
+		1318: {"ER_SP_WRONG_NO_OF_ARGS", Syntax},
+		1319: {"ER_SP_COND_MISMATCH", Syntax},
+		1320: {"ER_SP_NORETURN", Syntax},
+		1321: {"ER_SP_NORETURNEND", ClientError},
+		1322: {"ER_SP_BAD_CURSOR_QUERY", Syntax},
+		1323: {"ER_SP_BAD_CURSOR_SELECT", Syntax},
+		1324: {"ER_SP_CURSOR_MISMATCH", Syntax},
+		1325: {"ER_SP_CURSOR_ALREADY_OPEN", ClientError},
+		1326: {"ER_SP_CURSOR_NOT_OPEN", ClientError},
+		1327: {"ER_SP_UNDECLARED_VAR", Syntax},
+		1328: {"ER_SP_WRONG_NO_OF_FETCH_ARGS", ClientError},
+		1329: {"ER_SP_FETCH_NO_DATA", ClientError},
+		1330: {"ER_SP_DUP_PARAM", Syntax},
+		1331: {"ER_SP_DUP_VAR", Syntax},
+		1332: {"ER_SP_DUP_COND", Syntax},
+		1333: {"ER_SP_DUP_CURS", Syntax},
+		1334: {"ER_SP_CANT_ALTER", ClientError},
+		1335: {"ER_SP_SUBSELECT_NYI", ClientError},
+		1336: {"ER_STMT_NOT_ALLOWED_IN_SF_OR_TRG", ClientError},
+		1337: {"ER_SP_VARCOND_AFTER_CURSHNDLR", Syntax},
+		1338: {"ER_SP_CURSOR_AFTER_HANDLER", Syntax},
+		1339: {"ER_SP_CASE_NOT_FOUND", ClientError},
+		1340: {"ER_FPARSER_TOO_BIG_FILE", Server},
+		1341: {"ER_FPARSER_BAD_HEADER", ClientError},
+		1342: {"ER_FPARSER_EOF_IN_COMMENT", ClientError},
+		1343: {"ER_FPARSER_ERROR_IN_PARAMETER", ClientError},
+		1344: {"ER_FPARSER_EOF_IN_UNKNOWN_PARAMETER", ClientError},
+		1345: {"ER_VIEW_NO_EXPLAIN", ClientError},
+		1346: {"ER_FRM_UNKNOWN_TYPE", ClientError},
+		1347: {"ER_WRONG_OBJECT", ClientError},
+		1348: {"ER_NONUPDATEABLE_COLUMN", ClientError},
+		1349: {"ER_VIEW_SELECT_DERIVED", ClientError},
+		1350: {"ER_VIEW_SELECT_CLAUSE", ClientError},
+		1351: {"ER_VIEW_SELECT_VARIABLE", ClientError},
+		1352: {"ER_VIEW_SELECT_TMPTABLE", ClientError},
+		1353: {"ER_VIEW_WRONG_LIST", ClientError},
+		1354: {"ER_WARN_VIEW_MERGE", ClientError},
+		1355: {"ER_WARN_VIEW_WITHOUT_KEY", ClientError},
+		1356: {"ER_VIEW_INVALID", ClientError},
+		1357: {"ER_SP_NO_DROP_SP", ClientError},
+		1358: {"ER_SP_GOTO_IN_HNDLR", ClientError},
+		1359: {"ER_TRG_ALREADY_EXISTS", ClientError},
+		1360: {"ER_TRG_DOES_NOT_EXIST", ClientError},
+		1361: {"ER_TRG_ON_VIEW_OR_TEMP_TABLE", ClientError},
+		1362: {"ER_TRG_CANT_CHANGE_ROW", ClientError},
+		1363: {"ER_TRG_NO_SUCH_ROW_IN_TRG", ClientError},
+		1364: {"ER_NO_DEFAULT_FOR_FIELD", ClientError},
+		1365: {"ER_DIVISION_BY_ZERO", ClientError},
+		1366: {"ER_TRUNCATED_WRONG_VALUE_FOR_FIELD", ClientError},
+		1367: {"ER_ILLEGAL_VALUE_FOR_TYPE", ClientError},
+		1368: {"ER_VIEW_NONUPD_CHECK", ClientError},
+		1369: {"ER_VIEW_CHECK_FAILED", Server},
+		1370: {"ER_PROCACCESS_DENIED_ERROR", Permission},
+		1371: {"ER_RELAY_LOG_FAIL", Server},
+		1372: {"ER_PASSWD_LENGTH", ClientError},
+		1373: {"ER_UNKNOWN_TARGET_BINLOG", Server},
+		1374: {"ER_IO_ERR_LOG_INDEX_READ", ClientError},
+		1375: {"ER_BINLOG_PURGE_PROHIBITED", Server},
+		1376: {"ER_FSEEK_FAIL", Server},
+		1377: {"ER_BINLOG_PURGE_FATAL_ERR", Server},
+		1378: {"ER_LOG_IN_USE", ClientError},
+		1379: {"ER_LOG_PURGE_UNKNOWN_ERR", ClientError},
+		1380: {"ER_RELAY_LOG_INIT", Server},
+		1381: {"ER_NO_BINARY_LOGGING", ClientError},
+		1382: {"ER_RESERVED_SYNTAX", Syntax},
+		1383: {"ER_WSAS_FAILED", Server},
+		1384: {"ER_DIFF_GROUPS_PROC", ClientError},
+		1385: {"ER_NO_GROUP_FOR_PROC", ClientError},
+		1386: {"ER_ORDER_WITH_PROC", ClientError},
+		1387: {"ER_LOGGING_PROHIBIT_CHANGING_OF", ClientError},
+		1388: {"ER_NO_FILE_MAPPING", Server},
+		1389: {"ER_WRONG_MAGIC", ClientError},
+		1390: {"ER_PS_MANY_PARAM", ClientError},
+		1391: {"ER_KEY_PART_0", ClientError},
+		1392: {"ER_VIEW_CHECKSUM", ClientError},
+		1393: {"ER_VIEW_MULTIUPDATE", ClientError},
+		1394: {"ER_VIEW_NO_INSERT_FIELD_LIST", ClientError},
+		1395: {"ER_VIEW_DELETE_MERGE_VIEW", ClientError},
+		1396: {"ER_CANNOT_USER", ClientError},
+		1397: {"ER_XAER_NOTA", ClientError},
+		1398: {"ER_XAER_INVAL", ClientError},
+		1399: {"ER_XAER_RMFAIL", ClientError},
+		1400: {"ER_XAER_OUTSIDE", ClientError},
+		1401: {"ER_XAER_RMERR", ClientError},
+		1402: {"ER_XA_RBROLLBACK", ClientError},
+		1403: {"ER_NONEXISTING_PROC_GRANT", Permission},
+		1404: {"ER_PROC_AUTO_GRANT_FAIL", Permission},
+		1405: {"ER_PROC_AUTO_REVOKE_FAIL", Server},
+		1406: {"ER_DATA_TOO_LONG", ClientError},
+		1407: {"ER_SP_BAD_SQLSTATE", Syntax},
+		1408: {"ER_STARTUP", ClientError},
+		1409: {"ER_LOAD_FROM_FIXED_SIZE_ROWS_TO_VAR", ClientError},
+		1410: {"ER_CANT_CREATE_USER_WITH_GRANT", Permission},
+		1411: {"ER_WRONG_VALUE_FOR_TYPE", ClientError},
+		1412: {"ER_TABLE_DEF_CHANGED", ClientError},
+		1413: {"ER_SP_DUP_HANDLER", Syntax},
+		1414: {"ER_SP_NOT_VAR_ARG", Syntax},
+		1415: {"ER_SP_NO_RETSET", ClientError},
+		1416: {"ER_CANT_CREATE_GEOMETRY_OBJECT", ClientError},
+		1417: {"ER_FAILED_ROUTINE_BREAK_BINLOG", Server},
+		1418: {"ER_BINLOG_UNSAFE_ROUTINE", Server},
+		1419: {"ER_BINLOG_CREATE_ROUTINE_NEED_SUPER", Server},
+		1420: {"ER_EXEC_STMT_WITH_OPEN_CURSOR", ClientError},
+		1421: {"ER_STMT_HAS_NO_OPEN_CURSOR", ClientError},
+		1422: {"ER_COMMIT_NOT_ALLOWED_IN_SF_OR_TRG", ClientError},
+		1423: {"ER_NO_DEFAULT_FOR_VIEW_FIELD", ClientError},
+		1424: {"ER_SP_NO_RECURSION", ClientError},
+		1425: {"ER_TOO_BIG_SCALE", Syntax},
+		1426: {"ER_TOO_BIG_PRECISION", Syntax},
+		1427: {"ER_M_BIGGER_THAN_D", Syntax},
+		1428: {"ER_WRONG_LOCK_OF_SYSTEM_TABLE", Lock},
+		1429: {"ER_CONNECT_TO_FOREIGN_DATA_SOURCE", Constraint},
+		1430: {"ER_QUERY_ON_FOREIGN_DATA_SOURCE", Constraint},
+		1431: {"ER_FOREIGN_DATA_SOURCE_DOESNT_EXIST", Constraint},
+		1432: {"ER_FOREIGN_DATA_STRING_INVALID_CANT_CREATE", Constraint},
+		1433: {"ER_FOREIGN_DATA_STRING_INVALID", Constraint},
+		1434: {"ER_CANT_CREATE_FEDERATED_TABLE", ClientError},
+		1435: {"ER_TRG_IN_WRONG_SCHEMA", ClientError},
+		1436: {"ER_STACK_OVERRUN_NEED_MORE", Server},
+		1437: {"ER_TOO_LONG_BODY", Syntax},
+		1438: {"ER_WARN_CANT_DROP_DEFAULT_KEYCACHE", ClientError},
+		1439: {"ER_TOO_BIG_DISPLAYWIDTH", Syntax},
+		1440: {"ER_XAER_DUPID", ClientError},
+		1441: {"ER_DATETIME_FUNCTION_OVERFLOW", ClientError},
+		1442: {"ER_CANT_UPDATE_USED_TABLE_IN_SF_OR_TRG", ClientError},
+		1443: {"ER_VIEW_PREVENT_UPDATE", ClientError},
+		1444: {"ER_PS_NO_RECURSION", ClientError},
+		1445: {"ER_SP_CANT_SET_AUTOCOMMIT", ClientError},
+		1446: {"ER_MALFORMED_DEFINER", ClientError},
+		1447: {"ER_VIEW_FRM_NO_USER", ClientError},
+		1449: {"ER_NO_SUCH_USER", ClientError},
+		1450: {"ER_FORBID_SCHEMA_CHANGE", ClientError},
+		1451: {"ER_ROW_IS_REFERENCED_2", Constraint},
+		1452: {"ER_NO_REFERENCED_ROW_2", Constraint},
+		1453: {"ER_SP_BAD_VAR_SHADOW", Syntax},
+		1454: {"ER_TRG_NO_DEFINER", ClientError},
+		1455: {"ER_OLD_FILE_FORMAT", Server},
+		1456: {"ER_SP_RECURSION_LIMIT", ClientError},
+		1457: {"ER_SP_PROC_TABLE_CORRUPT", Server},
+		1458: {"ER_SP_WRONG_NAME", Syntax},
+		1459: {"ER_TABLE_NEEDS_UPGRADE", Server},
+		1460: {"ER_SP_NO_AGGREGATE", Syntax},
+		1461: {"ER_MAX_PREPARED_STMT_COUNT_REACHED", Syntax},
+		1462: {"ER_VIEW_RECURSIVE", ClientError},
+		1463: {"ER_NON_GROUPING_FIELD_USED", Syntax},
+		1464: {"ER_TABLE_CANT_HANDLE_SPKEYS", ClientError},
+		1465: {"ER_NO_TRIGGERS_ON_SYSTEM_SCHEMA", ClientError},
+		1466: {"ER_REMOVED_SPACES", ClientError},
+		1467: {"ER_AUTOINC_READ_FAILED", Server},
+		1468: {"ER_USERNAME", ClientError},
+		1469: {"ER_HOSTNAME", ClientError},
+		1470: {"ER_WRONG_STRING_LENGTH", ClientError},
+		1471: {"ER_NON_INSERTABLE_TABLE", ClientError},
+		1472: {"ER_ADMIN_WRONG_MRG_TABLE", ClientError},
+		1473: {"ER_TOO_HIGH_LEVEL_OF_NESTING_FOR_SELECT", ClientError},
+		1474: {"ER_NAME_BECOMES_EMPTY", ClientError},
+		1475: {"ER_AMBIGUOUS_FIELD_TERM", ClientError},
+		1476: {"ER_FOREIGN_SERVER_EXISTS", Constraint},
+		1477: {"ER_FOREIGN_SERVER_DOESNT_EXIST", Constraint},
+		1478: {"ER_ILLEGAL_HA_CREATE_OPTION", ClientError},
+		1479: {"ER_PARTITION_REQUIRES_VALUES_ERROR", ClientError},
+		1480: {"ER_PARTITION_WRONG_VALUES_ERROR", ClientError},
+		1481: {"ER_PARTITION_MAXVALUE_ERROR", ClientError},
+		1482: {"ER_PARTITION_SUBPARTITION_ERROR", ClientError},
+		1483: {"ER_PARTITION_SUBPART_MIX_ERROR", ClientError},
+		1484: {"ER_PARTITION_WRONG_NO_PART_ERROR", ClientError},
+		1485: {"ER_PARTITION_WRONG_NO_SUBPART_ERROR", ClientError},
+		1486: {"ER_WRONG_EXPR_IN_PARTITION_FUNC_ERROR", ClientError},
+		1487: {"ER_NOT_CONSTANT_EXPRESSION", ClientError},
+		1488: {"ER_FIELD_NOT_FOUND_PART_ERROR", ClientError},
+		1489: {"ER_LIST_OF_FIELDS_ONLY_IN_HASH_ERROR", ClientError},
+		1490: {"ER_INCONSISTENT_PARTITION_INFO_ERROR", ClientError},
+		1491: {"ER_PARTITION_FUNC_NOT_ALLOWED_ERROR", ClientError},
+		1492: {"ER_PARTITIONS_MUST_BE_DEFINED_ERROR", ClientError},
+		1493: {"ER_RANGE_NOT_INCREASING_ERROR", ClientError},
+		1494: {"ER_INCONSISTENT_TYPE_OF_FUNCTIONS_ERROR", ClientError},
+		1495: {"ER_MULTIPLE_DEF_CONST_IN_LIST_PART_ERROR", ClientError},
+		1496: {"ER_PARTITION_ENTRY_ERROR", ClientError},
+		1497: {"ER_MIX_HANDLER_ERROR", ClientError},
+		1498: {"ER_PARTITION_NOT_DEFINED_ERROR", ClientError},
+		1499: {"ER_TOO_MANY_PARTITIONS_ERROR", ClientError},
+		1500: {"ER_SUBPARTITION_ERROR", ClientError},
+		1501: {"ER_CANT_CREATE_HANDLER_FILE", Server},
+		1502: {"ER_BLOB_FIELD_IN_PART_FUNC_ERROR", ClientError},
+		1503: {"ER_UNIQUE_KEY_NEED_ALL_FIELDS_IN_PF", ClientError},
+		1504: {"ER_NO_PARTS_ERROR", ClientError},
+		1505: {"ER_PARTITION_MGMT_ON_NONPARTITIONED", ClientError},
+		1506: {"ER_FEATURE_NOT_SUPPORTED_WITH_PARTITIONING", ClientError},
+		1507: {"ER_PARTITION_DOES_NOT_EXIST", ClientError},
+		1508: {"ER_DROP_LAST_PARTITION", ClientError},
+		1509: {"ER_COALESCE_ONLY_ON_HASH_PARTITION", ClientError},
+		1510: {"ER_REORG_HASH_ONLY_ON_SAME_NO", ClientError},
+		1511: {"ER_REORG_NO_PARAM_ERROR", ClientError},
+		1512: {"ER_ONLY_ON_RANGE_LIST_PARTITION", ClientError},
+		1513: {"ER_ADD_PARTITION_SUBPART_ERROR", ClientError},
+		1514: {"ER_ADD_PARTITION_NO_NEW_PARTITION", ClientError},
+		1515: {"ER_COALESCE_PARTITION_NO_PARTITION", ClientError},
+		1516: {"ER_REORG_PARTITION_NOT_EXIST", ClientError},
+		1517: {"ER_SAME_NAME_PARTITION", ClientError},
+		1518: {"ER_NO_BINLOG_ERROR", Server},
+		1519: {"ER_CONSECUTIVE_REORG_PARTITIONS", ClientError},
+		1520: {"ER_REORG_OUTSIDE_RANGE", ClientError},
+		1521: {"ER_PARTITION_FUNCTION_FAILURE", ClientError},
+		1522: {"ER_PART_STATE_ERROR", ClientError},
+		1523: {"ER_LIMITED_PART_RANGE", ClientError},
+		1524: {"ER_PLUGIN_IS_NOT_LOADED", ClientError},
+		1525: {"ER_WRONG_VALUE", ClientError},
+		1526: {"ER_NO_PARTITION_FOR_GIVEN_VALUE", ClientError},
+		1527: {"ER_FILEGROUP_OPTION_ONLY_ONCE", ClientError},
+		1528: {"ER_CREATE_FILEGROUP_FAILED", Server},
+		1529: {"ER_DROP_FILEGROUP_FAILED", Server},
+		1530: {"ER_TABLESPACE_AUTO_EXTEND_ERROR", ClientError},
+		1531: {"ER_WRONG_SIZE_NUMBER", ClientError},
+		1532: {"ER_SIZE_OVERFLOW_ERROR", ClientError},
+		1533: {"ER_ALTER_FILEGROUP_FAILED", Server},
+		1534: {"ER_BINLOG_ROW_LOGGING_FAILED", Server},
+		1535: {"ER_BINLOG_ROW_WRONG_TABLE_DEF", Server},
+		1536: {"ER_BINLOG_ROW_RBR_TO_SBR", Server},
+		1537: {"ER_EVENT_ALREADY_EXISTS", ClientError},
+		1538: {"ER_EVENT_STORE_FAILED", Server},
+		1539: {"ER_EVENT_DOES_NOT_EXIST", ClientError},
+		1540: {"ER_EVENT_CANT_ALTER", ClientError},
+		1541: {"ER_EVENT_DROP_FAILED", Server},
+		1542: {"ER_EVENT_INTERVAL_NOT_POSITIVE_OR_TOO_BIG", ClientError},
+		1543: {"ER_EVENT_ENDS_BEFORE_STARTS", ClientError},
+		1544: {"ER_EVENT_EXEC_TIME_IN_THE_PAST", ClientError},
+		1545: {"ER_EVENT_OPEN_TABLE_FAILED", Server},
+		1546: {"ER_EVENT_NEITHER_M_EXPR_NOR_M_AT", ClientError},
+		1549: {"ER_EVENT_CANNOT_DELETE", ClientError},
+		1550: {"ER_EVENT_COMPILE_ERROR", ClientError},
+		1551: {"ER_EVENT_SAME_NAME", ClientError},
+		1552: {"ER_EVENT_DATA_TOO_LONG", ClientError},
+		1553: {"ER_DROP_INDEX_FK", Constraint},
+		1554: {"ER_WARN_DEPRECATED_SYNTAX_WITH_VER", Syntax},
+		1555: {"ER_CANT_WRITE_LOCK_LOG_TABLE", Lock},
+		1556: {"ER_CANT_LOCK_LOG_TABLE", Lock},
+		1558: {"ER_COL_COUNT_DOESNT_MATCH_PLEASE_UPDATE", ClientError},
+		1559: {"ER_TEMP_TABLE_PREVENTS_SWITCH_OUT_OF_RBR", ClientError},
+		1560: {"ER_STORED_FUNCTION_PREVENTS_SWITCH_BINLOG_FORMAT", Server},
+		1562: {"ER_PARTITION_NO_TEMPORARY", ClientError},
+		1563: {"ER_PARTITION_CONST_DOMAIN_ERROR", ClientError},
+		1564: {"ER_PARTITION_FUNCTION_IS_NOT_ALLOWED", ClientError},
+		1565: {"ER_DDL_LOG_ERROR", ClientError},
+		1566: {"ER_NULL_IN_VALUES_LESS_THAN", ClientError},
+		1567: {"ER_WRONG_PARTITION_NAME", ClientError},
+		1568: {"ER_CANT_CHANGE_TX_CHARACTERISTICS", ClientError},
+		1569: {"ER_DUP_ENTRY_AUTOINCREMENT_CASE", ClientError},
+		1570: {"ER_EVENT_MODIFY_QUEUE_ERROR", ClientError},
+		1571: {"ER_EVENT_SET_VAR_ERROR", ClientError},
+		1572: {"ER_PARTITION_MERGE_ERROR", ClientError},
+		1573: {"ER_CANT_ACTIVATE_LOG", ClientError},
+		1574: {"ER_RBR_NOT_AVAILABLE", ClientError},
+		1575: {"ER_BASE64_DECODE_ERROR", ClientError},
+		1576: {"ER_EVENT_RECURSION_FORBIDDEN", ClientError},
+		1577: {"ER_EVENTS_DB_ERROR", ClientError},
+		1578: {"ER_ONLY_INTEGERS_ALLOWED", ClientError},
+		1579: {"ER_UNSUPORTED_LOG_ENGINE", ClientError},
+		1580: {"ER_BAD_LOG_STATEMENT", ClientError},
+		1581: {"ER_CANT_RENAME_LOG_TABLE", ClientError},
+		1582: {"ER_WRONG_PARAMCOUNT_TO_NATIVE_FCT", Syntax},
+		1583: {"ER_WRONG_PARAMETERS_TO_NATIVE_FCT", Syntax},
+		1584: {"ER_WRONG_PARAMETERS_TO_STORED_FCT", Syntax},
+		1585: {"ER_NATIVE_FCT_NAME_COLLISION", ClientError},
+		1586: {"ER_DUP_ENTRY_WITH_KEY_NAME", Constraint},
+		1587: {"ER_BINLOG_PURGE_EMFILE", Server},
+		1588: {"ER_EVENT_CANNOT_CREATE_IN_THE_PAST", ClientError},
+		1589: {"ER_EVENT_CANNOT_ALTER_IN_THE_PAST", ClientError},
+		1590: {"ER_SLAVE_INCIDENT", Server},
+		1591: {"ER_NO_PARTITION_FOR_GIVEN_VALUE_SILENT", ClientError},
+		1592: {"ER_BINLOG_UNSAFE_STATEMENT", Server},
+		1593: {"ER_SLAVE_FATAL_ERROR", Server},
+		1594: {"ER_SLAVE_RELAY_LOG_READ_FAILURE", Server},
+		1595: {"ER_SLAVE_RELAY_LOG_WRITE_FAILURE", Server},
+		1596: {"ER_SLAVE_CREATE_EVENT_FAILURE", Server},
+		1597: {"ER_SLAVE_MASTER_COM_FAILURE", Server},
+		1598: {"ER_BINLOG_LOGGING_IMPOSSIBLE", Server},
+		1599: {"ER_VIEW_NO_CREATION_CTX", ClientError},
+		1600: {"ER_VIEW_INVALID_CREATION_CTX", ClientError},
+		1601: {"ER_SR_INVALID_CREATION_CTX", ClientError},
+		1602: {"ER_TRG_CORRUPTED_FILE", Server},
+		1603: {"ER_TRG_NO_CREATION_CTX", ClientError},
+		1604: {"ER_TRG_INVALID_CREATION_CTX", ClientError},
+		1605: {"ER_EVENT_INVALID_CREATION_CTX", ClientError},
+		1606: {"ER_TRG_CANT_OPEN_TABLE", ClientError},
+		1607: {"ER_CANT_CREATE_SROUTINE", ClientError},
+		1609: {"ER_NO_FORMAT_DESCRIPTION_EVENT_BEFORE_BINLOG_STATEMENT", Server},
+		1610: {"ER_SLAVE_CORRUPT_EVENT", Server},
+		1611: {"ER_LOAD_DATA_INVALID_COLUMN", ClientError},
+		1612: {"ER_LOG_PURGE_NO_FILE", Server},
+		1613: {"ER_XA_RBTIMEOUT", ClientError},
+		1614: {"ER_XA_RBDEADLOCK", ClientError},
+		1615: {"ER_NEED_REPREPARE", ClientError},
+		1616: {"ER_DELAYED_NOT_SUPPORTED", ClientError},
+		1617: {"WARN_NO_MASTER_INFO", Server},
+		1618: {"WARN_OPTION_IGNORED", ClientError},
+		1619: {"ER_PLUGIN_DELETE_BUILTIN", ClientError},
+		1620: {"WARN_PLUGIN_BUSY", ClientError},
+		1621: {"ER_VARIABLE_IS_READONLY", ClientError},
+		1622: {"ER_WARN_ENGINE_TRANSACTION_ROLLBACK", ClientError},
+		1623: {"ER_SLAVE_HEARTBEAT_FAILURE", Server},
+		1624: {"ER_SLAVE_HEARTBEAT_VALUE_OUT_OF_RANGE", Server},
+		1626: {"ER_CONFLICT_FN_PARSE_ERROR", Syntax},
+		1627: {"ER_EXCEPTIONS_WRITE_ERROR", ClientError},
+		1628: {"ER_TOO_LONG_TABLE_COMMENT", ClientError},
+		1629: {"ER_TOO_LONG_FIELD_COMMENT", ClientError},
+		1630: {"ER_FUNC_INEXISTENT_NAME_COLLISION", Syntax},
+		1631: {"ER_DATABASE_NAME", ClientError},
+		1632: {"ER_TABLE_NAME", ClientError},
+		1633: {"ER_PARTITION_NAME", ClientError},
+		1634: {"ER_SUBPARTITION_NAME", ClientError},
+		1635: {"ER_TEMPORARY_NAME", ClientError},
+		1636: {"ER_RENAMED_NAME", ClientError},
+		1637: {"ER_TOO_MANY_CONCURRENT_TRXS", ClientError},
+		1638: {"WARN_NON_ASCII_SEPARATOR_NOT_IMPLEMENTED", ClientError},
+		1639: {"ER_DEBUG_SYNC_TIMEOUT", Server},
+		1640: {"ER_DEBUG_SYNC_HIT_LIMIT", ClientError},
+		1641: {"ER_DUP_SIGNAL_SET", Syntax},
+		1642: {"ER_SIGNAL_WARN", ClientError},
+		1643: {"ER_SIGNAL_NOT_FOUND", ClientError},
+		1644: {"ER_SIGNAL_EXCEPTION", ClientError},
+		1645: {"ER_RESIGNAL_WITHOUT_ACTIVE_HANDLER", ClientError},
+		1646: {"ER_SIGNAL_BAD_CONDITION_TYPE", ClientError},
+		1647: {"WARN_COND_ITEM_TRUNCATED", ClientError},
+		1648: {"ER_COND_ITEM_TOO_LONG", ClientError},
+		1649: {"ER_UNKNOWN_LOCALE", ClientError},
+		1650: {"ER_SLAVE_IGNORE_SERVER_IDS", Server},
+		1651: {"ER_QUERY_CACHE_DISABLED", ClientError},
+		1652: {"ER_SAME_NAME_PARTITION_FIELD", ClientError},
+		1653: {"ER_PARTITION_COLUMN_LIST_ERROR", ClientError},
+		1654: {"ER_WRONG_TYPE_COLUMN_VALUE_ERROR", ClientError},
+		1655: {"ER_TOO_MANY_PARTITION_FUNC_FIELDS_ERROR", ClientError},
+		1656: {"ER_MAXVALUE_IN_VALUES_IN", ClientError},
+		1657: {"ER_TOO_MANY_VALUES_ERROR", ClientError},
+		1658: {"ER_ROW_SINGLE_PARTITION_FIELD_ERROR", ClientError},
+		1659: {"ER_FIELD_TYPE_NOT_ALLOWED_AS_PARTITION_FIELD", ClientError},
+		1660: {"ER_PARTITION_FIELDS_TOO_LONG", ClientError},
+		1661: {"ER_BINLOG_ROW_ENGINE_AND_STMT_ENGINE", Server},
+		1662: {"ER_BINLOG_ROW_MODE_AND_STMT_ENGINE", Server},
+		1663: {"ER_BINLOG_UNSAFE_AND_STMT_ENGINE", Server},
+		1664: {"ER_BINLOG_ROW_INJECTION_AND_STMT_ENGINE", Server},
+		1665: {"ER_BINLOG_STMT_MODE_AND_ROW_ENGINE", Server},
+		1666: {"ER_BINLOG_ROW_INJECTION_AND_STMT_MODE", Server},
+		1667: {"ER_BINLOG_MULTIPLE_ENGINES_AND_SELF_LOGGING_ENGINE", Server},
+		1668: {"ER_BINLOG_UNSAFE_LIMIT", Server},
+		1669: {"ER_BINLOG_UNSAFE_INSERT_DELAYED", Server},
+		1670: {"ER_BINLOG_UNSAFE_SYSTEM_TABLE", Server},
+		1671: {"ER_BINLOG_UNSAFE_AUTOINC_COLUMNS", Server},
+		1672: {"ER_BINLOG_UNSAFE_UDF", Server},
+		1673: {"ER_BINLOG_UNSAFE_SYSTEM_VARIABLE", Server},
+		1674: {"ER_BINLOG_UNSAFE_SYSTEM_FUNCTION", Server},
+		1675: {"ER_BINLOG_UNSAFE_NONTRANS_AFTER_TRANS", Server},
+		1676: {"ER_MESSAGE_AND_STATEMENT", ClientError},
+		1677: {"ER_SLAVE_CONVERSION_FAILED", Server},
+		1678: {"ER_SLAVE_CANT_CREATE_CONVERSION", Server},
+		1679: {"ER_INSIDE_TRANSACTION_PREVENTS_SWITCH_BINLOG_FORMAT", Server},
+		1680: {"ER_PATH_LENGTH", ClientError},
+		1681: {"ER_WARN_DEPRECATED_SYNTAX_NO_REPLACEMENT", Syntax},
+		1682: {"ER_WRONG_NATIVE_TABLE_STRUCTURE", ClientError},
+		1683: {"ER_WRONG_PERFSCHEMA_USAGE", ClientError},
+		1684: {"ER_WARN_I_S_SKIPPED_TABLE", ClientError},
+		1685: {"ER_INSIDE_TRANSACTION_PREVENTS_SWITCH_BINLOG_DIRECT", Server},
+		1686: {"ER_STORED_FUNCTION_PREVENTS_SWITCH_BINLOG_DIRECT", Server},
+		1687: {"ER_SPATIAL_MUST_HAVE_GEOM_COL", Syntax},
+		1688: {"ER_TOO_LONG_INDEX_COMMENT", ClientError},
+		1689: {"ER_LOCK_ABORTED", Lock},
+		1690: {"ER_DATA_OUT_OF_RANGE", ClientError},
+		1691: {"ER_WRONG_SPVAR_TYPE_IN_LIMIT", ClientError},
+		1692: {"ER_BINLOG_UNSAFE_MULTIPLE_ENGINES_AND_SELF_LOGGING_ENGINE", Server},
+		1693: {"ER_BINLOG_UNSAFE_MIXED_STATEMENT", Server},
+		1694: {"ER_INSIDE_TRANSACTION_PREVENTS_SWITCH_SQL_LOG_BIN", ClientError},
+		1695: {"ER_STORED_FUNCTION_PREVENTS_SWITCH_SQL_LOG_BIN", ClientError},
+		1696: {"ER_FAILED_READ_FROM_PAR_FILE", Server},
+		1697: {"ER_VALUES_IS_NOT_INT_TYPE_ERROR", ClientError},
+		1698: {"ER_ACCESS_DENIED_NO_PASSWORD_ERROR", Permission},
+		1699: {"ER_SET_PASSWORD_AUTH_PLUGIN", Permission},
+		1700: {"ER_GRANT_PLUGIN_USER_EXISTS", Permission},
+		1701: {"ER_TRUNCATE_ILLEGAL_FK", Constraint},
+		1702: {"ER_PLUGIN_IS_PERMANENT", ClientError},
+		1703: {"ER_SLAVE_HEARTBEAT_VALUE_OUT_OF_RANGE_MIN", Server},
+		1704: {"ER_SLAVE_HEARTBEAT_VALUE_OUT_OF_RANGE_MAX", Server},
+		1705: {"ER_STMT_CACHE_FULL", ClientError},
+		1706: {"ER_MULTI_UPDATE_KEY_CONFLICT", ClientError},
+		1707: {"ER_TABLE_NEEDS_REBUILD", ClientError},
+		1708: {"WARN_OPTION_BELOW_LIMIT", ClientError},
+		1709: {"ER_INDEX_COLUMN_TOO_LONG", ClientError},
+		1710: {"ER_ERROR_IN_TRIGGER_BODY", ClientError},
+		1711: {"ER_ERROR_IN_UNKNOWN_TRIGGER_BODY", ClientError},
+		1712: {"ER_INDEX_CORRUPT", Server},
+		1713: {"ER_UNDO_RECORD_TOO_BIG", ClientError},
+		1714: {"ER_BINLOG_UNSAFE_INSERT_IGNORE_SELECT", Server},
+		1715: {"ER_BINLOG_UNSAFE_INSERT_SELECT_UPDATE", Server},
+		1716: {"ER_BINLOG_UNSAFE_REPLACE_SELECT", Server},
+		1717: {"ER_BINLOG_UNSAFE_CREATE_IGNORE_SELECT", Server},
+		1718: {"ER_BINLOG_UNSAFE_CREATE_REPLACE_SELECT", Server},
+		1719: {"ER_BINLOG_UNSAFE_UPDATE_IGNORE", Server},
+		1722: {"ER_BINLOG_UNSAFE_WRITE_AUTOINC_SELECT", Server},
+		1723: {"ER_BINLOG_UNSAFE_CREATE_SELECT_AUTOINC", Server},
+		1724: {"ER_BINLOG_UNSAFE_INSERT_TWO_KEYS", Server},
+		1726: {"ER_VERS_NOT_ALLOWED", ClientError},
+		1727: {"ER_BINLOG_UNSAFE_AUTOINC_NOT_FIRST", Server},
+		1728: {"ER_CANNOT_LOAD_FROM_TABLE_V2", ClientError},
+		1729: {"ER_MASTER_DELAY_VALUE_OUT_OF_RANGE", Server},
+		1730: {"ER_ONLY_FD_AND_RBR_EVENTS_ALLOWED_IN_BINLOG_STATEMENT", Server},
+		1731: {"ER_PARTITION_EXCHANGE_DIFFERENT_OPTION", ClientError},
+		1732: {"ER_PARTITION_EXCHANGE_PART_TABLE", ClientError},
+		1733: {"ER_PARTITION_EXCHANGE_TEMP_TABLE", ClientError},
+		1734: {"ER_PARTITION_INSTEAD_OF_SUBPARTITION", ClientError},
+		1735: {"ER_UNKNOWN_PARTITION", ClientError},
+		1736: {"ER_TABLES_DIFFERENT_METADATA", ClientError},
+		1737: {"ER_ROW_DOES_NOT_MATCH_PARTITION", ClientError},
+		1738: {"ER_BINLOG_CACHE_SIZE_GREATER_THAN_MAX", Server},
+		1739: {"ER_WARN_INDEX_NOT_APPLICABLE", ClientError},
+		1740: {"ER_PARTITION_EXCHANGE_FOREIGN_KEY", Constraint},
+		1741: {"ER_NO_SUCH_KEY_VALUE", ClientError},
+		1742: {"ER_VALUE_TOO_LONG", ClientError},
+		1743: {"ER_NETWORK_READ_EVENT_CHECKSUM_FAILURE", ClientError},
+		1744: {"ER_BINLOG_READ_EVENT_CHECKSUM_FAILURE", Server},
+		1745: {"ER_BINLOG_STMT_CACHE_SIZE_GREATER_THAN_MAX", Server},
+		1746: {"ER_CANT_UPDATE_TABLE_IN_CREATE_TABLE_SELECT", ClientError},
+		1747: {"ER_PARTITION_CLAUSE_ON_NONPARTITIONED", ClientError},
+		1748: {"ER_ROW_DOES_NOT_MATCH_GIVEN_PARTITION_SET", ClientError},
+		1750: {"ER_CHANGE_RPL_INFO_REPOSITORY_FAILURE", ClientError},
+		1751: {"ER_WARNING_NOT_COMPLETE_ROLLBACK_WITH_CREATED_TEMP_TABLE", ClientError},
+		1752: {"ER_WARNING_NOT_COMPLETE_ROLLBACK_WITH_DROPPED_TEMP_TABLE", ClientError},
+		1753: {"ER_MTS_FEATURE_IS_NOT_SUPPORTED", ClientError},
+		1754: {"ER_MTS_UPDATED_DBS_GREATER_MAX", ClientError},
+		1755: {"ER_MTS_CANT_PARALLEL", ClientError},
+		1756: {"ER_MTS_INCONSISTENT_DATA", ClientError},
+		1757: {"ER_FULLTEXT_NOT_SUPPORTED_WITH_PARTITIONING", ClientError},
+		1758: {"ER_DA_INVALID_CONDITION_NUMBER", ClientError},
+		1759: {"ER_INSECURE_PLAIN_TEXT", ClientError},
+		1760: {"ER_INSECURE_CHANGE_MASTER", Server},
+		1761: {"ER_FOREIGN_DUPLICATE_KEY_WITH_CHILD_INFO", Constraint},
+		1762: {"ER_FOREIGN_DUPLICATE_KEY_WITHOUT_CHILD_INFO", Constraint},
+		1763: {"ER_SQLTHREAD_WITH_SECURE_SLAVE", Server},
+		1764: {"ER_TABLE_HAS_NO_FT", ClientError},
+		1765: {"ER_VARIABLE_NOT_SETTABLE_IN_SF_OR_TRIGGER", ClientError},
+		1766: {"ER_VARIABLE_NOT_SETTABLE_IN_TRANSACTION", ClientError},
+		1767: {"ER_GTID_NEXT_IS_NOT_IN_GTID_NEXT_LIST", ClientError},
+		1768: {"ER_CANT_CHANGE_GTID_NEXT_IN_TRANSACTION_WHEN_GTID_NEXT_LIST_IS_NULL", ClientError},
+		1769: {"ER_SET_STATEMENT_CANNOT_INVOKE_FUNCTION", ClientError},
+		1770: {"ER_GTID_NEXT_CANT_BE_AUTOMATIC_IF_GTID_NEXT_LIST_IS_NON_NULL", ClientError},
+		1771: {"ER_SKIPPING_LOGGED_TRANSACTION", ClientError},
+		1772: {"ER_MALFORMED_GTID_SET_SPECIFICATION", ClientError},
+		1773: {"ER_MALFORMED_GTID_SET_ENCODING", ClientError},
+		1774: {"ER_MALFORMED_GTID_SPECIFICATION", ClientError},
+		1775: {"ER_GNO_EXHAUSTED", ClientError},
+		1776: {"ER_BAD_SLAVE_AUTO_POSITION", Server},
+		1777: {"ER_AUTO_POSITION_REQUIRES_GTID_MODE_ON", ClientError},
+		1778: {"ER_CANT_DO_IMPLICIT_COMMIT_IN_TRX_WHEN_GTID_NEXT_IS_SET", ClientError},
+		1779: {"ER_GTID_MODE_2_OR_3_REQUIRES_ENFORCE_GTID_CONSISTENCY_ON", ClientError},
+		1780: {"ER_GTID_MODE_REQUIRES_BINLOG", Server},
+		1781: {"ER_CANT_SET_GTID_NEXT_TO_GTID_WHEN_GTID_MODE_IS_OFF", ClientError},
+		1782: {"ER_CANT_SET_GTID_NEXT_TO_ANONYMOUS_WHEN_GTID_MODE_IS_ON", ClientError},
+		1783: {"ER_CANT_SET_GTID_NEXT_LIST_TO_NON_NULL_WHEN_GTID_MODE_IS_OFF", ClientError},
+		1784: {"ER_FOUND_GTID_EVENT_WHEN_GTID_MODE_IS_OFF", ClientError},
+		1785: {"ER_GTID_UNSAFE_NON_TRANSACTIONAL_TABLE", ClientError},
+		1786: {"ER_GTID_UNSAFE_CREATE_SELECT", ClientError},
+		1787: {"ER_GTID_UNSAFE_CREATE_DROP_TEMPORARY_TABLE_IN_TRANSACTION", ClientError},
+		1788: {"ER_GTID_MODE_CAN_ONLY_CHANGE_ONE_STEP_AT_A_TIME", ClientError},
+		1789: {"ER_MASTER_HAS_PURGED_REQUIRED_GTIDS", Server},
+		1790: {"ER_CANT_SET_GTID_NEXT_WHEN_OWNING_GTID", ClientError},
+		1791: {"ER_UNKNOWN_EXPLAIN_FORMAT", ClientError},
+		1792: {"ER_CANT_EXECUTE_IN_READ_ONLY_TRANSACTION", ClientError},
+		1793: {"ER_TOO_LONG_TABLE_PARTITION_COMMENT", ClientError},
+		1794: {"ER_SLAVE_CONFIGURATION", Server},
+		1795: {"ER_INNODB_FT_LIMIT", Server},
+		1796: {"ER_NO_INDEX_ON_TEMPORARY", ClientError},
+		1797: {"ER_INNODB_FT_WRONG_DOCID_COLUMN", Server},
+		1798: {"ER_INNODB_FT_WRONG_DOCID_INDEX", Server},
+		1799: {"ER_INNODB_ONLINE_LOG_TOO_BIG", Server},
+		1800: {"ER_UNKNOWN_ALTER_ALGORITHM", ClientError},
+		1801: {"ER_UNKNOWN_ALTER_LOCK", Lock},
+		1802: {"ER_MTS_CHANGE_MASTER_CANT_RUN_WITH_GAPS", Server},
+		1803: {"ER_MTS_RECOVERY_FAILURE", ClientError},
+		1804: {"ER_MTS_RESET_WORKERS", ClientError},
+		1805: {"ER_COL_COUNT_DOESNT_MATCH_CORRUPTED_V2", ClientError},
+		1806: {"ER_SLAVE_SILENT_RETRY_TRANSACTION", Server},
+		1808: {"ER_TABLE_SCHEMA_MISMATCH", ClientError},
+		1809: {"ER_TABLE_IN_SYSTEM_TABLESPACE", ClientError},
+		1810: {"ER_IO_READ_ERROR", ClientError},
+		1811: {"ER_IO_WRITE_ERROR", ClientError},
+		1812: {"ER_TABLESPACE_MISSING", ClientError},
+		1813: {"ER_TABLESPACE_EXISTS", ClientError},
+		1814: {"ER_TABLESPACE_DISCARDED", ClientError},
+		1815: {"ER_INTERNAL_ERROR", ClientError},
+		1816: {"ER_INNODB_IMPORT_ERROR", Server},
+		1817: {"ER_INNODB_INDEX_CORRUPT", Server},
+		1818: {"ER_INVALID_YEAR_COLUMN_LENGTH", ClientError},
+		1819: {"ER_NOT_VALID_PASSWORD", Permission},
+		1820: {"ER_MUST_CHANGE_PASSWORD", Permission},
+		1821: {"ER_FK_NO_INDEX_CHILD", Constraint},
+		1822: {"ER_FK_NO_INDEX_PARENT", Constraint},
+		1823: {"ER_FK_FAIL_ADD_SYSTEM", Constraint},
+		1824: {"ER_FK_CANNOT_OPEN_PARENT", Constraint},
+		1825: {"ER_FK_INCORRECT_OPTION", Constraint},
+		1826: {"ER_DUP_CONSTRAINT_NAME", Constraint},
+		1827: {"ER_PASSWORD_FORMAT", Permission},
+		1828: {"ER_FK_COLUMN_CANNOT_DROP", Constraint},
+		1829: {"ER_FK_COLUMN_CANNOT_DROP_CHILD", Constraint},
+		1830: {"ER_FK_COLUMN_NOT_NULL", Constraint},
+		1831: {"ER_DUP_INDEX", ClientError},
+		1832: {"ER_FK_COLUMN_CANNOT_CHANGE", Constraint},
+		1833: {"ER_FK_COLUMN_CANNOT_CHANGE_CHILD", Constraint},
+		1834: {"ER_FK_CANNOT_DELETE_PARENT", Constraint},
+		1835: {"ER_MALFORMED_PACKET", ClientError},
+		1836: {"ER_READ_ONLY_MODE", ClientError},
+		1837: {"ER_GTID_NEXT_TYPE_UNDEFINED_GROUP", ClientError},
+		1838: {"ER_VARIABLE_NOT_SETTABLE_IN_SP", ClientError},
+		1839: {"ER_CANT_SET_GTID_PURGED_WHEN_GTID_MODE_IS_OFF", ClientError},
+		1840: {"ER_CANT_SET_GTID_PURGED_WHEN_GTID_EXECUTED_IS_NOT_EMPTY", ClientError},
+		1841: {"ER_CANT_SET_GTID_PURGED_WHEN_OWNED_GTIDS_IS_NOT_EMPTY", ClientError},
+		1842: {"ER_GTID_PURGED_WAS_CHANGED", ClientError},
+		1843: {"ER_GTID_EXECUTED_WAS_CHANGED", ClientError},
+		1844: {"ER_BINLOG_STMT_MODE_AND_NO_REPL_TABLES", Server},
+		1845: {"ER_ALTER_OPERATION_NOT_SUPPORTED", ClientError},
+		1846: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON", ClientError},
+		1847: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_COPY", ClientError},
+		1848: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_PARTITION", ClientError},
+		1849: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_FK_RENAME", Constraint},
+		1850: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_COLUMN_TYPE", ClientError},
+		1851: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_FK_CHECK", Constraint},
+		1852: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_IGNORE", ClientError},
+		1853: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_NOPK", ClientError},
+		1854: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_AUTOINC", ClientError},
+		1855: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_HIDDEN_FTS", ClientError},
+		1856: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_CHANGE_FTS", ClientError},
+		1857: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_FTS", ClientError},
+		1858: {"ER_SQL_SLAVE_SKIP_COUNTER_NOT_SETTABLE_IN_GTID_MODE", Server},
+		1859: {"ER_DUP_UNKNOWN_IN_INDEX", Constraint},
+		1860: {"ER_IDENT_CAUSES_TOO_LONG_PATH", ClientError},
+		1861: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_NOT_NULL", ClientError},
+		1862: {"ER_MUST_CHANGE_PASSWORD_LOGIN", Permission},
+		1863: {"ER_ROW_IN_WRONG_PARTITION", ClientError},
+		1864: {"ER_MTS_EVENT_BIGGER_PENDING_JOBS_SIZE_MAX", ClientError},
+		1865: {"ER_INNODB_NO_FT_USES_PARSER", Server},
+		1866: {"ER_BINLOG_LOGICAL_CORRUPTION", Server},
+		1867: {"ER_WARN_PURGE_LOG_IN_USE", ClientError},
+		1868: {"ER_WARN_PURGE_LOG_IS_ACTIVE", ClientError},
+		1869: {"ER_AUTO_INCREMENT_CONFLICT", ClientError},
+		1870: {"WARN_ON_BLOCKHOLE_IN_RBR", ClientError},
+		1871: {"ER_SLAVE_MI_INIT_REPOSITORY", Server},
+		1872: {"ER_SLAVE_RLI_INIT_REPOSITORY", Server},
+		1873: {"ER_ACCESS_DENIED_CHANGE_USER_ERROR", Permission},
+		1874: {"ER_INNODB_READ_ONLY", Server},
+		1875: {"ER_STOP_SLAVE_SQL_THREAD_TIMEOUT", Server},
+		1876: {"ER_STOP_SLAVE_IO_THREAD_TIMEOUT", Server},
+		1877: {"ER_TABLE_CORRUPT", Server},
+		1878: {"ER_TEMP_FILE_WRITE_FAILURE", Server},
+		1879: {"ER_INNODB_FT_AUX_NOT_HEX_ID", Server},
+		1880: {"ER_LAST_MYSQL_ERROR_MESSAGE", ClientError},
+		1901: {"ER_GENERATED_COLUMN_FUNCTION_IS_NOT_ALLOWED", ClientError},
+		1903: {"ER_PRIMARY_KEY_BASED_ON_GENERATED_COLUMN", ClientError},
+		1904: {"ER_KEY_BASED_ON_GENERATED_VIRTUAL_COLUMN", ClientError},
+		1905: {"ER_WRONG_FK_OPTION_FOR_GENERATED_COLUMN", Constraint},
+		1906: {"ER_WARNING_NON_DEFAULT_VALUE_FOR_GENERATED_COLUMN", ClientError},
+		1907: {"ER_UNSUPPORTED_ACTION_ON_GENERATED_COLUMN", ClientError},
+		1910: {"ER_UNSUPPORTED_ENGINE_FOR_GENERATED_COLUMNS", ClientError},
+		1911: {"ER_UNKNOWN_OPTION", ClientError},
+		1912: {"ER_BAD_OPTION_VALUE", ClientError},
+		1916: {"ER_DATA_OVERFLOW", ClientError},
+		1917: {"ER_DATA_TRUNCATED", ClientError},
+		1918: {"ER_BAD_DATA", ClientError},
+		1919: {"ER_DYN_COL_WRONG_FORMAT", ClientError},
+		1920: {"ER_DYN_COL_IMPLEMENTATION_LIMIT", ClientError},
+		1921: {"ER_DYN_COL_DATA", ClientError},
+		1922: {"ER_DYN_COL_WRONG_CHARSET", ClientError},
+		1923: {"ER_ILLEGAL_SUBQUERY_OPTIMIZER_SWITCHES", ClientError},
+		1924: {"ER_QUERY_CACHE_IS_DISABLED", ClientError},
+		1925: {"ER_QUERY_CACHE_IS_GLOBALY_DISABLED", ClientError},
+		1926: {"ER_VIEW_ORDERBY_IGNORED", ClientError},
+		1927: {"ER_CONNECTION_KILLED", Server},
+		1929: {"ER_INSIDE_TRANSACTION_PREVENTS_SWITCH_SKIP_REPLICATION", ClientError},
+		1930: {"ER_STORED_FUNCTION_PREVENTS_SWITCH_SKIP_REPLICATION", ClientError},
+		1931: {"ER_QUERY_RESULT_INCOMPLETE", ClientError},
+		1932: {"ER_NO_SUCH_TABLE_IN_ENGINE", ClientError},
+		1933: {"ER_TARGET_NOT_EXPLAINABLE", ClientError},
+		1934: {"ER_CONNECTION_ALREADY_EXISTS", ClientError},
+		1935: {"ER_MASTER_LOG_PREFIX", Server},
+		1936: {"ER_CANT_START_STOP_SLAVE", Server},
+		1937: {"ER_SLAVE_STARTED", Server},
+		1938: {"ER_SLAVE_STOPPED", Server},
+		1939: {"ER_SQL_DISCOVER_ERROR", ClientError},
+		1940: {"ER_FAILED_GTID_STATE_INIT", Server},
+		1941: {"ER_INCORRECT_GTID_STATE", ClientError},
+		1942: {"ER_CANNOT_UPDATE_GTID_STATE", ClientError},
+		1943: {"ER_DUPLICATE_GTID_DOMAIN", ClientError},
+		1944: {"ER_GTID_OPEN_TABLE_FAILED", Server},
+		1945: {"ER_GTID_POSITION_NOT_FOUND_IN_BINLOG", Server},
+		1946: {"ER_CANNOT_LOAD_SLAVE_GTID_STATE", Server},
+		1947: {"ER_MASTER_GTID_POS_CONFLICTS_WITH_BINLOG", Server},
+		1948: {"ER_MASTER_GTID_POS_MISSING_DOMAIN", Server},
+		1949: {"ER_UNTIL_REQUIRES_USING_GTID", ClientError},
+		1950: {"ER_GTID_STRICT_OUT_OF_ORDER", ClientError},
+		1951: {"ER_GTID_START_FROM_BINLOG_HOLE", Server},
+		1952: {"ER_SLAVE_UNEXPECTED_MASTER_SWITCH", Server},
+		1953: {"ER_INSIDE_TRANSACTION_PREVENTS_SWITCH_GTID_DOMAIN_ID_SEQ_NO", ClientError},
+		1954: {"ER_STORED_FUNCTION_PREVENTS_SWITCH_GTID_DOMAIN_ID_SEQ_NO", ClientError},
+		1955: {"ER_GTID_POSITION_NOT_FOUND_IN_BINLOG2", ClientError},
+		1956: {"ER_BINLOG_MUST_BE_EMPTY", Server},
+		1957: {"ER_NO_SUCH_QUERY", ClientError},
+		1958: {"ER_BAD_BASE64_DATA", ClientError},
+		1959: {"ER_INVALID_ROLE", ClientError},
+		1960: {"ER_INVALID_CURRENT_USER", ClientError},
+		1961: {"ER_CANNOT_GRANT_ROLE", Permission},
+		1962: {"ER_CANNOT_REVOKE_ROLE", ClientError},
+		1963: {"ER_CHANGE_SLAVE_PARALLEL_THREADS_ACTIVE", Server},
+		1964: {"ER_PRIOR_COMMIT_FAILED", Server},
+		1965: {"ER_IT_IS_A_VIEW", ClientError},
+		1966: {"ER_SLAVE_SKIP_NOT_IN_GTID", Server},
+		1967: {"ER_TABLE_DEFINITION_TOO_BIG", ClientError},
+		1968: {"ER_PLUGIN_INSTALLED", ClientError},
+		1969: {"ER_STATEMENT_TIMEOUT", Server},
+		1970: {"ER_SUBQUERIES_NOT_SUPPORTED", Syntax},
+		1971: {"ER_SET_STATEMENT_NOT_SUPPORTED", Syntax},
+		1973: {"ER_USER_CREATE_EXISTS", ClientError},
+		1974: {"ER_USER_DROP_EXISTS", ClientError},
+		1975: {"ER_ROLE_CREATE_EXISTS", ClientError},
+		1976: {"ER_ROLE_DROP_EXISTS", ClientError},
+		1977: {"ER_CANNOT_CONVERT_CHARACTER", ClientError},
+		1978: {"ER_INVALID_DEFAULT_VALUE_FOR_FIELD", ClientError},
+		1979: {"ER_KILL_QUERY_DENIED_ERROR", Permission},
+		1980: {"ER_NO_EIS_FOR_FIELD", ClientError},
+		1981: {"ER_WARN_AGGFUNC_DEPENDENCE", ClientError},
+		1982: {"WARN_INNODB_PARTITION_OPTION_IGNORED", Server},
+		3000: {"ER_FILE_CORRUPT", Server},
+		3001: {"ER_ERROR_ON_MASTER", Server},
+		3002: {"ER_INCONSISTENT_ERROR", ClientError},
+		3003: {"ER_STORAGE_ENGINE_NOT_LOADED", ClientError},
+		3004: {"ER_GET_STACKED_DA_WITHOUT_ACTIVE_HANDLER", ClientError},
+		3005: {"ER_WARN_LEGACY_SYNTAX_CONVERTED", Syntax},
+		3006: {"ER_BINLOG_UNSAFE_FULLTEXT_PLUGIN", Server},
+		3007: {"ER_CANNOT_DISCARD_TEMPORARY_TABLE", ClientError},
+		3008: {"ER_FK_DEPTH_EXCEEDED", Constraint},
+		3009: {"ER_COL_COUNT_DOESNT_MATCH_PLEASE_UPDATE_V2", ClientError},
+		3010: {"ER_WARN_TRIGGER_DOESNT_HAVE_CREATED", ClientError},
+		3011: {"ER_REFERENCED_TRG_DOES_NOT_EXIST_MYSQL", ClientError},
+		3012: {"ER_EXPLAIN_NOT_SUPPORTED", ClientError},
+		3013: {"ER_INVALID_FIELD_SIZE", ClientError},
+		3014: {"ER_MISSING_HA_CREATE_OPTION", ClientError},
+		3015: {"ER_ENGINE_OUT_OF_MEMORY", Server},
+		3016: {"ER_PASSWORD_EXPIRE_ANONYMOUS_USER", Permission},
+		3017: {"ER_SLAVE_SQL_THREAD_MUST_STOP", Server},
+		3018: {"ER_NO_FT_MATERIALIZED_SUBQUERY", ClientError},
+		3019: {"ER_INNODB_UNDO_LOG_FULL", Server},
+		3020: {"ER_INVALID_ARGUMENT_FOR_LOGARITHM", ClientError},
+		3021: {"ER_SLAVE_CHANNEL_IO_THREAD_MUST_STOP", Server},
+		3022: {"ER_WARN_OPEN_TEMP_TABLES_MUST_BE_ZERO", ClientError},
+		3023: {"ER_WARN_ONLY_MASTER_LOG_FILE_NO_POS", Server},
+		3025: {"ER_NON_RO_SELECT_DISABLE_TIMER", ClientError},
+		3026: {"ER_DUP_LIST_ENTRY", ClientError},
+		3027: {"ER_SQL_MODE_NO_EFFECT", ClientError},
+		3028: {"ER_AGGREGATE_ORDER_FOR_UNION", ClientError},
+		3029: {"ER_AGGREGATE_ORDER_NON_AGG_QUERY", ClientError},
+		3030: {"ER_SLAVE_WORKER_STOPPED_PREVIOUS_THD_ERROR", Server},
+		3031: {"ER_DONT_SUPPORT_SLAVE_PRESERVE_COMMIT_ORDER", Server},
+		3032: {"ER_SERVER_OFFLINE_MODE", ClientError},
+		3033: {"ER_GIS_DIFFERENT_SRIDS", ClientError},
+		3034: {"ER_GIS_UNSUPPORTED_ARGUMENT", ClientError},
+		3035: {"ER_GIS_UNKNOWN_ERROR", ClientError},
+		3036: {"ER_GIS_UNKNOWN_EXCEPTION", ClientError},
+		3037: {"ER_GIS_INVALID_DATA", ClientError},
+		3038: {"ER_BOOST_GEOMETRY_EMPTY_INPUT_EXCEPTION", ClientError},
+		3039: {"ER_BOOST_GEOMETRY_CENTROID_EXCEPTION", ClientError},
+		3040: {"ER_BOOST_GEOMETRY_OVERLAY_INVALID_INPUT_EXCEPTION", ClientError},
+		3041: {"ER_BOOST_GEOMETRY_TURN_INFO_EXCEPTION", ClientError},
+		3042: {"ER_BOOST_GEOMETRY_SELF_INTERSECTION_POINT_EXCEPTION", ClientError},
+		3043: {"ER_BOOST_GEOMETRY_UNKNOWN_EXCEPTION", ClientError},
+		3044: {"ER_STD_BAD_ALLOC_ERROR", ClientError},
+		3045: {"ER_STD_DOMAIN_ERROR", ClientError},
+		3046: {"ER_STD_LENGTH_ERROR", ClientError},
+		3047: {"ER_STD_INVALID_ARGUMENT", ClientError},
+		3048: {"ER_STD_OUT_OF_RANGE_ERROR", ClientError},
+		3049: {"ER_STD_OVERFLOW_ERROR", ClientError},
+		3050: {"ER_STD_RANGE_ERROR", ClientError},
+		3051: {"ER_STD_UNDERFLOW_ERROR", ClientError},
+		3052: {"ER_STD_LOGIC_ERROR", ClientError},
+		3053: {"ER_STD_RUNTIME_ERROR", ClientError},
+		3054: {"ER_STD_UNKNOWN_EXCEPTION", ClientError},
+		3055: {"ER_GIS_DATA_WRONG_ENDIANESS", ClientError},
+		3056: {"ER_CHANGE_MASTER_PASSWORD_LENGTH", Permission},
+		3057: {"ER_USER_LOCK_WRONG_NAME", Lock},
+		3058: {"ER_USER_LOCK_DEADLOCK", Lock},
+		3059: {"ER_REPLACE_INACCESSIBLE_ROWS", ClientError},
+		3060: {"ER_ALTER_OPERATION_NOT_SUPPORTED_REASON_GIS", ClientError},
+		3159: {"ER_SECURE_TRANSPORT_REQUIRED", Server},
+		4002: {"ER_WITH_COL_WRONG_LIST", ClientError},
+		4003: {"ER_TOO_MANY_DEFINITIONS_IN_WITH_CLAUSE", ClientError},
+		4004: {"ER_DUP_QUERY_NAME", ClientError},
+		4005: {"ER_RECURSIVE_WITHOUT_ANCHORS", ClientError},
+		4006: {"ER_UNACCEPTABLE_MUTUAL_RECURSION", ClientError},
+		4007: {"ER_REF_TO_RECURSIVE_WITH_TABLE_IN_DERIVED", ClientError},
+		4008: {"ER_NOT_STANDARD_COMPLIANT_RECURSIVE", ClientError},
+		4009: {"ER_WRONG_WINDOW_SPEC_NAME", ClientError},
+		4010: {"ER_DUP_WINDOW_NAME", ClientError},
+		4011: {"ER_PARTITION_LIST_IN_REFERENCING_WINDOW_SPEC", ClientError},
+		4012: {"ER_ORDER_LIST_IN_REFERENCING_WINDOW_SPEC", ClientError},
+		4013: {"ER_WINDOW_FRAME_IN_REFERENCED_WINDOW_SPEC", ClientError},
+		4014: {"ER_BAD_COMBINATION_OF_WINDOW_FRAME_BOUND_SPECS", ClientError},
+		4015: {"ER_WRONG_PLACEMENT_OF_WINDOW_FUNCTION", ClientError},
+		4016: {"ER_WINDOW_FUNCTION_IN_WINDOW_SPEC", ClientError},
+		4017: {"ER_NOT_ALLOWED_WINDOW_FRAME", ClientError},
+		4018: {"ER_NO_ORDER_LIST_IN_WINDOW_SPEC", ClientError},
+		4019: {"ER_RANGE_FRAME_NEEDS_SIMPLE_ORDERBY", ClientError},
+		4020: {"ER_WRONG_TYPE_FOR_ROWS_FRAME", ClientError},
+		4021: {"ER_WRONG_TYPE_FOR_RANGE_FRAME", ClientError},
+		4022: {"ER_FRAME_EXCLUSION_NOT_SUPPORTED", ClientError},
+		4023: {"ER_WINDOW_FUNCTION_DONT_HAVE_FRAME", ClientError},
+		4024: {"ER_INVALID_NTILE_ARGUMENT", ClientError},
+		4025: {"ER_CONSTRAINT_FAILED", Constraint},
+		4026: {"ER_EXPRESSION_IS_TOO_BIG", ClientError},
+		4027: {"ER_ERROR_EVALUATING_EXPRESSION", ClientError},
+		4028: {"ER_CALCULATING_DEFAULT_VALUE", ClientError},
+		4029: {"ER_EXPRESSION_REFERS_TO_UNINIT_FIELD", ClientError},
+		4030: {"ER_PARTITION_DEFAULT_ERROR", ClientError},
+		4031: {"ER_REFERENCED_TRG_DOES_NOT_EXIST", ClientError},
+		4032: {"ER_INVALID_DEFAULT_PARAM", ClientError},
+		4033: {"ER_BINLOG_NON_SUPPORTED_BULK", Server},
+		4034: {"ER_BINLOG_UNCOMPRESS_ERROR", Server},
+		4035: {"ER_JSON_BAD_CHR", ClientError},
+		4036: {"ER_JSON_NOT_JSON_CHR", ClientError},
+		4037: {"ER_JSON_EOS", ClientError},
+		4038: {"ER_JSON_SYNTAX", Syntax},
+		4039: {"ER_JSON_ESCAPING", ClientError},
+		4040: {"ER_JSON_DEPTH", ClientError},
+		4041: {"ER_JSON_PATH_EOS", ClientError},
+		4042: {"ER_JSON_PATH_SYNTAX", Syntax},
+		4043: {"ER_JSON_PATH_DEPTH", ClientError},
+		4044: {"ER_JSON_PATH_NO_WILDCARD", ClientError},
+		4045: {"ER_JSON_PATH_ARRAY", ClientError},
+		4046: {"ER_JSON_ONE_OR_ALL", ClientError},
+		4047: {"ER_UNSUPPORTED_COMPRESSED_TABLE", ClientError},
+		4048: {"ER_GEOJSON_INCORRECT", ClientError},
+		4049: {"ER_GEOJSON_TOO_FEW_POINTS", ClientError},
+		4050: {"ER_GEOJSON_NOT_CLOSED", ClientError},
+		4051: {"ER_JSON_PATH_EMPTY", ClientError},
+		4052: {"ER_SLAVE_SAME_ID", Server},
+		4053: {"ER_FLASHBACK_NOT_SUPPORTED", ClientError},
+		4054: {"ER_KEYS_OUT_OF_ORDER", ClientError},
+		4055: {"ER_OVERLAPPING_KEYS", ClientError},
+		4056: {"ER_REQUIRE_ROW_BINLOG_FORMAT", Server},
+		4057: {"ER_ISOLATION_MODE_NOT_SUPPORTED", ClientError},
+		4058: {"ER_ON_DUPLICATE_DISABLED", ClientError},
+		4059: {"ER_UPDATES_WITH_CONSISTENT_SNAPSHOT", ClientError},
+		4060: {"ER_ROLLBACK_ONLY", ClientError},
+		4061: {"ER_ROLLBACK_TO_SAVEPOINT", ClientError},
+		4062: {"ER_ISOLATION_LEVEL_WITH_CONSISTENT_SNAPSHOT", ClientError},
+		4063: {"ER_UNSUPPORTED_COLLATION", ClientError},
+		4064: {"ER_METADATA_INCONSISTENCY", ClientError},
+		4065: {"ER_CF_DIFFERENT", ClientError},
+		4066: {"ER_RDB_TTL_DURATION_FORMAT", ClientError},
+		4067: {"ER_RDB_STATUS_GENERAL", ClientError},
+		4068: {"ER_RDB_STATUS_MSG", ClientError},
+		4069: {"ER_RDB_TTL_UNSUPPORTED", ClientError},
+		4070: {"ER_RDB_TTL_COL_FORMAT", ClientError},
+		4071: {"ER_PER_INDEX_CF_DEPRECATED", ClientError},
+		4072: {"ER_KEY_CREATE_DURING_ALTER", Server},
+		4073: {"ER_SK_POPULATE_DURING_ALTER", Server},
+		4074: {"ER_SUM_FUNC_WITH_WINDOW_FUNC_AS_ARG", ClientError},
+		4075: {"ER_NET_OK_PACKET_TOO_LARGE", Server},
+		4076: {"ER_GEOJSON_EMPTY_COORDINATES", ClientError},
+		4077: {"ER_MYROCKS_CANT_NOPAD_COLLATION", ClientError},
+		4078: {"ER_ILLEGAL_PARAMETER_DATA_TYPES2_FOR_OPERATION", ClientError},
+		4079: {"ER_ILLEGAL_PARAMETER_DATA_TYPE_FOR_OPERATION", ClientError},
+		4080: {"ER_WRONG_PARAMCOUNT_TO_CURSOR", Syntax},
+		4081: {"ER_UNKNOWN_STRUCTURED_VARIABLE", ClientError},
+		4082: {"ER_ROW_VARIABLE_DOES_NOT_HAVE_FIELD", ClientError},
+		4083: {"ER_END_IDENTIFIER_DOES_NOT_MATCH", ClientError},
+		4084: {"ER_SEQUENCE_RUN_OUT", ClientError},
+		4085: {"ER_SEQUENCE_INVALID_DATA", ClientError},
+		4086: {"ER_SEQUENCE_INVALID_TABLE_STRUCTURE", ClientError},
+		4087: {"ER_SEQUENCE_ACCESS_ERROR", ClientError},
+		4088: {"ER_SEQUENCE_BINLOG_FORMAT", Server},
+		4089: {"ER_NOT_SEQUENCE", ClientError},
+		4090: {"ER_NOT_SEQUENCE2", ClientError},
+		4091: {"ER_UNKNOWN_SEQUENCES", ClientError},
+		4092: {"ER_UNKNOWN_VIEW", ClientError},
+		4093: {"ER_WRONG_INSERT_INTO_SEQUENCE", ClientError},
+		4094: {"ER_SP_STACK_TRACE", Server},
+		4095: {"ER_PACKAGE_ROUTINE_IN_SPEC_NOT_DEFINED_IN_BODY", ClientError},
+		4096: {"ER_PACKAGE_ROUTINE_FORWARD_DECLARATION_NOT_DEFINED", ClientError},
+		4097: {"ER_COMPRESSED_COLUMN_USED_AS_KEY", ClientError},
+		4098: {"ER_UNKNOWN_COMPRESSION_METHOD", ClientError},
+		4099: {"ER_WRONG_NUMBER_OF_VALUES_IN_TVC", ClientError},
+		4100: {"ER_FIELD_REFERENCE_IN_TVC", ClientError},
+		4101: {"ER_WRONG_TYPE_FOR_PERCENTILE_FUNC", ClientError},
+		4102: {"ER_ARGUMENT_NOT_CONSTANT", ClientError},
+		4103: {"ER_ARGUMENT_OUT_OF_RANGE", ClientError},
+		4104: {"ER_WRONG_TYPE_OF_ARGUMENT", ClientError},
+		4105: {"ER_NOT_AGGREGATE_FUNCTION", ClientError},
+		4106: {"ER_INVALID_AGGREGATE_FUNCTION", ClientError},
+		4107: {"ER_INVALID_VALUE_TO_LIMIT", ClientError},
+		4108: {"ER_INVISIBLE_NOT_NULL_WITHOUT_DEFAULT", ClientError},
+		4109: {"ER_UPDATE_INFO_WITH_SYSTEM_VERSIONING", ClientError},
+		4110: {"ER_VERS_FIELD_WRONG_TYPE", ClientError},
+		4111: {"ER_VERS_ENGINE_UNSUPPORTED", ClientError},
+		4112: {"ER_WRONG_VERSIONING_RANGE", ClientError},
+		4113: {"ER_PARTITION_WRONG_TYPE", ClientError},
+		4114: {"WARN_VERS_PART_FULL", ClientError},
+		4115: {"WARN_VERS_PARAMETERS", ClientError},
+		4116: {"ER_VERS_DROP_PARTITION_INTERVAL", ClientError},
+		4118: {"WARN_VERS_PART_NON_HISTORICAL", ClientError},
+		4119: {"ER_VERS_ALTER_NOT_ALLOWED", ClientError},
+		4120: {"ER_VERS_ALTER_ENGINE_PROHIBITED", ClientError},
+		4121: {"ER_VERS_RANGE_PROHIBITED", ClientError},
+		4122: {"ER_CONFLICTING_FOR_SYSTEM_TIME", ClientError},
+		4123: {"ER_VERS_TABLE_MUST_HAVE_COLUMNS", ClientError},
+		4124: {"ER_VERS_NOT_VERSIONED", ClientError},
+		4125: {"ER_MISSING", ClientError},
+		4126: {"ER_VERS_PERIOD_COLUMNS", ClientError},
+		4127: {"ER_PART_WRONG_VALUE", ClientError},
+		4128: {"ER_VERS_WRONG_PARTS", ClientError},
+		4129: {"ER_VERS_NO_TRX_ID", ClientError},
+		4130: {"ER_VERS_ALTER_SYSTEM_FIELD", ClientError},
+		4131: {"ER_DROP_VERSIONING_SYSTEM_TIME_PARTITION", ClientError},
+		4132: {"ER_VERS_DB_NOT_SUPPORTED", ClientError},
+		4133: {"ER_VERS_TRT_IS_DISABLED", ClientError},
+		4134: {"ER_VERS_DUPLICATE_ROW_START_END", ClientError},
+		4135: {"ER_VERS_ALREADY_VERSIONED", ClientError},
+		4137: {"ER_VERS_NOT_SUPPORTED", ClientError},
+		4138: {"ER_VERS_TRX_PART_HISTORIC_ROW_NOT_SUPPORTED", ClientError},
+		4139: {"ER_INDEX_FILE_FULL", Server},
+		4140: {"ER_UPDATED_COLUMN_ONLY_ONCE", ClientError},
+		4141: {"ER_EMPTY_ROW_IN_TVC", ClientError},
+		4142: {"ER_VERS_QUERY_IN_PARTITION", ClientError},
+		4143: {"ER_KEY_DOESNT_SUPPORT", ClientError},
+		4144: {"ER_ALTER_OPERATION_TABLE_OPTIONS_NEED_REBUILD", ClientError},
+		4145: {"ER_BACKUP_LOCK_IS_ACTIVE", Lock},
+		4146: {"ER_BACKUP_NOT_RUNNING", ClientError},
+		4147: {"ER_BACKUP_WRONG_STAGE", ClientError},
+		4148: {"ER_BACKUP_STAGE_FAILED", Server},
+		4149: {"ER_BACKUP_UNKNOWN_STAGE", ClientError},
+		4150: {"ER_USER_IS_BLOCKED", ClientError},
+		4151: {"ER_ACCOUNT_HAS_BEEN_LOCKED", Permission},
+		4152: {"ER_PERIOD_TEMPORARY_NOT_ALLOWED", ClientError},
+		4153: {"ER_PERIOD_TYPES_MISMATCH", ClientError},
+		4154: {"ER_MORE_THAN_ONE_PERIOD", ClientError},
+		4155: {"ER_PERIOD_FIELD_WRONG_ATTRIBUTES", ClientError},
+		4156: {"ER_PERIOD_NOT_FOUND", ClientError},
+		4157: {"ER_PERIOD_COLUMNS_UPDATED", ClientError},
+		4158: {"ER_PERIOD_CONSTRAINT_DROP", Constraint},
+		4159: {"ER_TOO_LONG_KEYPART", Syntax},
+		4160: {"ER_TOO_LONG_DATABASE_COMMENT", ClientError},
+		4161: {"ER_UNKNOWN_DATA_TYPE", ClientError},
+		4162: {"ER_UNKNOWN_OPERATOR", ClientError},
+		4163: {"ER_SP_DUP_DECL", ClientError},
+		4164: {"ER_PART_STARTS_BEYOND_INTERVAL", ClientError},
+		4165: {"ER_GALERA_REPLICATION_NOT_SUPPORTED", ClientError},
+		4166: {"ER_LOAD_INFILE_CAPABILITY_DISABLED", ClientError},
+		4167: {"ER_NO_SECURE_TRANSPORTS_CONFIGURED", ClientError},
+		4168: {"ER_SLAVE_IGNORED_SHARED_TABLE", Server},
+		4169: {"ER_NO_AUTOINCREMENT_WITH_UNIQUE", ClientError},
+		4170: {"ER_KEY_CONTAINS_PERIOD_FIELDS", ClientError},
+		4171: {"ER_KEY_CANT_HAVE_WITHOUT_OVERLAPS", ClientError},
+		4172: {"ER_NOT_ALLOWED_IN_THIS_CONTEXT", ClientError},
+		4173: {"ER_DATA_WAS_COMMITED_UNDER_ROLLBACK", ClientError},
+		4174: {"ER_PK_INDEX_CANT_BE_IGNORED", ClientError},
+		4175: {"ER_BINLOG_UNSAFE_SKIP_LOCKED", Lock},
+		4176: {"ER_JSON_TABLE_ERROR_ON_FIELD", ClientError},
+		4177: {"ER_JSON_TABLE_ALIAS_REQUIRED", ClientError},
+		4178: {"ER_JSON_TABLE_SCALAR_EXPECTED", ClientError},
+		4179: {"ER_JSON_TABLE_MULTIPLE_MATCHES", ClientError},
+		4180: {"ER_WITH_TIES_NEEDS_ORDER", ClientError},
+		4181: {"ER_REMOVED_ORPHAN_TRIGGER", ClientError},
+		4182: {"ER_STORAGE_ENGINE_DISABLED", ClientError},
+		4183: {"WARN_SFORMAT_ERROR", ClientError},
+		4184: {"ER_PARTITION_CONVERT_SUBPARTITIONED", ClientError},
+		4185: {"ER_PROVIDER_NOT_LOADED", ClientError},
+		4186: {"ER_JSON_HISTOGRAM_PARSE_FAILED", Syntax},
+		4187: {"ER_SF_OUT_INOUT_ARG_NOT_ALLOWED", ClientError},
+		4188: {"ER_INCONSISTENT_SLAVE_TEMP_TABLE", Server},
+		4189: {"ER_VERS_HIST_PART_FAILED", Server},
+		4190: {"WARN_OPTION_CHANGING", ClientError},
+		4191: {"ER_CM_OPTION_MISSING_REQUIREMENT", ClientError},
+		4192: {"ER_SLAVE_STATEMENT_TIMEOUT", Server},
+		4193: {"ER_JSON_INVALID_VALUE_FOR_KEYWORD", ClientError},
+		4194: {"ER_JSON_SCHEMA_KEYWORD_UNSUPPORTED", ClientError},
+		4195: {"ER_JSON_NO_VARIABLE_SCHEMA", ClientError},
+		4196: {"ER_SEQUENCE_TABLE_HAS_WRONG_NUMBER_OF_COLUMNS", ClientError},
+		4197: {"ER_SEQUENCE_TABLE_CANNOT_HAVE_ANY_KEYS", ClientError},
+		4198: {"ER_SEQUENCE_TABLE_CANNOT_HAVE_ANY_CONSTRAINTS", ClientError},
+		4199: {"ER_SEQUENCE_TABLE_ORDER_BY", ClientError},
+		4200: {"ER_VARIABLE_IGNORED", ClientError},
+		4201: {"ER_INCORRECT_COLUMN_NAME_COUNT", ClientError},
+		4202: {"WARN_SORTING_ON_TRUNCATED_LENGTH", ClientError},
+		4203: {"ER_VECTOR_BINARY_FORMAT_INVALID", ClientError},
+		4204: {"ER_VECTOR_FORMAT_INVALID", ClientError},
+		4205: {"ER_PSEUDO_THREAD_ID_OVERWRITE", Server},
+		4206: {"ER_VEC_DISTANCE_TYPE", ClientError},
+		4207: {"WARN_INDEX_HINTS_IGNORED", ClientError},
+		4208: {"ER_SIGNAL_SKIP_ROW_FROM_TRIGGER", ClientError},
+	}
+)
@@ -0,0 +1,339 @@
+// Package filter narrows down the decoded connections to the ones of
+// interest, and optionally to just the exchanges of interest within them.
+package filter
+
+import (
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+// Options say what to keep.  Everything set has to match.  The addresses
+// are checked against the connection, the rest against each exchange, and
+// a connection is kept when any of its exchanges match.
+type Options struct {
+	Clients []*net.IPNet
+	Servers []*net.IPNet
+	// Users and Databases are checked against the user logged in and the
+	// database in use at the time of the exchange.
+	Users     []string
+	Databases []string
+	// Commands are matched ignoring case and any COM_ or MYSQL_ prefix,
+	// so query matches COM_QUERY.
+	Commands   []string
+	SQL        *regexp.Regexp
+	ErrorCodes []uint16
+	Errors     bool
+	MinLatency time.Duration
+	// Since and Until bound when the command was sent.
+	Since time.Time
+	Until time.Time
+	// OnlyMatching trims the connections down to the matching exchanges
+	// rather than keeping everything from a connection with a match.
+	OnlyMatching bool
+}
+
+var errBadAddress = errors.New("not an ip address or cidr")
+
+// ParseNetworks parses a list of ip addresses and CIDR ranges.
+func ParseNetworks(specs []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(specs))
+	for _, s := range specs {
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, errors.Wrap(errBadAddress, s)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, errors.Wrap(errBadAddress, s)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// ParseTime parses an RFC 3339 time, or returns the zero time for an empty
+// string.
+func ParseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	return t, errors.Wrap(err, "filter-time")
+}
+
+// Active is true when there's something to filter on.
+func (o Options) Active() bool {
+	return len(o.Clients) > 0 || len(o.Servers) > 0 || o.exchangeCriteria()
+}
+
+func (o Options) exchangeCriteria() bool {
+	return len(o.Users) > 0 || len(o.Databases) > 0 || len(o.Commands) > 0 ||
+		o.SQL != nil || len(o.ErrorCodes) > 0 || o.Errors || o.MinLatency > 0 ||
+		!o.Since.IsZero() || !o.Until.IsZero()
+}
+
+// Apply passes on the connections that match, and anything that isn't a
+// connection.
+func Apply(o Options, completed chan interface{}) chan interface{} {
+	filtered := make(chan interface{})
+	go func() {
+		defer close(filtered)
+		for c := range completed {
+			if conn, ok := c.(structure.Connection); ok {
+				if conn, ok = o.Connection(conn); !ok {
+					continue
+				}
+				c = conn
+			}
+			filtered <- c
+		}
+	}()
+	return filtered
+}
+
+// SplitSessions passes on each logical session of a pooled connection as a
+// connection of its own, so they can be filtered and read separately.
+func SplitSessions(completed chan interface{}) chan interface{} {
+	split := make(chan interface{})
+	go func() {
+		defer close(split)
+		for c := range completed {
+			conn, ok := c.(structure.Connection)
+			if !ok {
+				split <- c
+				continue
+			}
+			for _, session := range conn.SplitSessions() {
+				split <- session
+			}
+		}
+	}()
+	return split
+}
+
+// Connection checks whether the connection matches, returning it trimmed
+// down to the matching exchanges with OnlyMatching.
+func (o Options) Connection(c structure.Connection) (structure.Connection, bool) {
+	if !matchIP(o.Clients, c.Address.IP.Src().Raw()) || !matchIP(o.Servers, c.Address.IP.Dst().Raw()) {
+		return c, false
+	}
+	if !o.exchangeCriteria() {
+		return c, true
+	}
+	matches := o.exchanges(c)
+	if len(matches) == 0 {
+		return c, false
+	}
+	if !o.OnlyMatching {
+		return c, true
+	}
+	keep := make([]bool, len(c.Items))
+	for _, e := range matches {
+		keep[e.Request] = true
+		for _, r := range e.Responses {
+			keep[r] = true
+		}
+	}
+	return trim(c, keep), true
+}
+
+// exchanges picks out the exchanges that match.
+func (o Options) exchanges(c structure.Connection) []structure.Exchange {
+	exchanges := make(map[int]structure.Exchange, len(c.Exchanges))
+	for _, e := range c.Exchanges {
+		exchanges[e.Request] = e
+	}
+	var matches []structure.Exchange
+	for i := range c.Items {
+		e, ok := exchanges[i]
+		if ok && o.exchange(c, e) {
+			matches = append(matches, e)
+		}
+	}
+	return matches
+}
+
+func (o Options) exchange(c structure.Connection, e structure.Exchange) bool {
+	// the session state from the decoder follows USE and the schema
+	// changes the server reports as well as the logins.
+	var s structure.SessionState
+	if session := c.Items[e.Request].Session; session != nil {
+		s = *session
+	}
+	switch {
+	case len(o.Users) > 0 && !contains(o.Users, s.User),
+		len(o.Databases) > 0 && !contains(o.Databases, s.Database),
+		len(o.Commands) > 0 && !o.command(e.Command),
+		o.MinLatency > 0 && e.TimeToLastByte < o.MinLatency,
+		!o.Since.IsZero() && e.RequestStart.Before(o.Since),
+		!o.Until.IsZero() && e.RequestStart.After(o.Until):
+		return false
+	}
+	if o.SQL != nil && !o.sql(c.Items[e.Request].Data) {
+		return false
+	}
+	if o.Errors || len(o.ErrorCodes) > 0 {
+		return o.errors(c, e)
+	}
+	return true
+}
+
+func (o Options) command(command string) bool {
+	for _, c := range o.Commands {
+		if normalise(c) == normalise(command) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalise(command string) string {
+	command = strings.ToUpper(command)
+	command = strings.TrimPrefix(command, "COM_")
+	command = strings.TrimPrefix(command, "MYSQL_")
+	return strings.TrimPrefix(command, "STMT_")
+}
+
+func (o Options) sql(data interface{}) bool {
+	switch r := unwrap(data).(type) {
+	case structure.Request:
+		return r.Query != "" && o.SQL.MatchString(r.Query)
+	case structure.ExecuteRequest:
+		return (r.Query != "" && o.SQL.MatchString(r.Query)) ||
+			(r.SQL != "" && o.SQL.MatchString(r.SQL))
+	}
+	return false
+}
+
+// errors checks whether any of the responses were errors, with one of the
+// codes wanted if any were given.
+func (o Options) errors(c structure.Connection, e structure.Exchange) bool {
+	for _, r := range e.Responses {
+		err, ok := unwrap(c.Items[r].Data).(structure.ErrorResponse)
+		if !ok {
+			continue
+		}
+		if len(o.ErrorCodes) == 0 {
+			return true
+		}
+		for _, code := range o.ErrorCodes {
+			if err.Code == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// trim keeps just the items marked, fixing up the indexes to match.
+func trim(c structure.Connection, keep []bool) structure.Connection {
+	index := make([]int, len(c.Items))
+	trimmed := structure.Connection{
+		Address:            c.Address,
+		Summary:            c.Summary,
+		RawRequestPackets:  c.RawRequestPackets,
+		RawResponsePackets: c.RawResponsePackets,
+	}
+	for i, t := range c.Items {
+		index[i] = -1
+		if !keep[i] {
+			continue
+		}
+		index[i] = len(trimmed.Items)
+		if t.ResponseTo != nil {
+			request := index[*t.ResponseTo]
+			t.ResponseTo = nil
+			if request >= 0 {
+				t.ResponseTo = &request
+			}
+		}
+		trimmed.Items = append(trimmed.Items, t)
+	}
+	for _, e := range c.Exchanges {
+		if index[e.Request] < 0 {
+			continue
+		}
+		e.Request = index[e.Request]
+		responses := make([]int, 0, len(e.Responses))
+		for _, r := range e.Responses {
+			responses = append(responses, index[r])
+		}
+		e.Responses = responses
+		trimmed.Exchanges = append(trimmed.Exchanges, e)
+	}
+	for _, t := range c.Transactions {
+		// the transaction is described as a whole, only the links to the
+		// requests dropped go.
+		requests := make([]int, 0, len(t.Requests))
+		for _, r := range t.Requests {
+			if index[r] >= 0 {
+				requests = append(requests, index[r])
+			}
+		}
+		if len(requests) == 0 {
+			continue
+		}
+		t.Requests = requests
+		trimmed.Transactions = append(trimmed.Transactions, t)
+	}
+	for _, s := range c.Sessions {
+		// the session starts with the first item kept from it.
+		first := -1
+		for i := s.FirstItem; i < len(index) && first < 0; i++ {
+			first = index[i]
+		}
+		if first < 0 {
+			continue
+		}
+		s.FirstItem = first
+		if n := len(trimmed.Sessions); n > 0 && trimmed.Sessions[n-1].FirstItem == first {
+			// nothing was kept from the earlier session.
+			trimmed.Sessions[n-1] = s
+			continue
+		}
+		trimmed.Sessions = append(trimmed.Sessions, s)
+	}
+	return trimmed
+}
+
+func matchIP(networks []*net.IPNet, ip net.IP) bool {
+	if len(networks) == 0 {
+		return true
+	}
+	for _, n := range networks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+func unwrap(data interface{}) interface{} {
+	if rawPacket, ok := data.(structure.WithRawPacket); ok {
+		return rawPacket.Transmission
+	}
+	return data
+}
@@ -0,0 +1,190 @@
+package filter_test
+
+import (
+	"net"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"github.com/colinnewell/pcap-cli/tcp"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/filter"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+var start = time.Date(2021, 4, 4, 17, 28, 50, 0, time.UTC)
+
+func connection() structure.Connection {
+	zero, two, four, six := 0, 2, 4, 6
+	site := &structure.SessionState{User: "site", Database: "demo"}
+	admin := &structure.SessionState{User: "admin", Database: "demo"}
+	return structure.Connection{
+		Address: tcp.ConnectionAddress{
+			IP:   gopacket.NewFlow(layers.EndpointIPv4, net.IP{10, 0, 0, 2}, net.IP{10, 0, 0, 1}),
+			Port: gopacket.NewFlow(layers.EndpointTCPPort, []byte{0xc3, 0x50}, []byte{0x0c, 0xea}),
+		},
+		Items: []structure.Transmission{
+			{Data: structure.LoginRequest{Type: "Login", Username: "site", Database: "demo"}, Session: site},
+			{Data: structure.OKResponse{Type: "OK"}, ResponseTo: &zero},
+			{Data: structure.Request{Type: "Query", Query: "SELECT * FROM peeps"}, Session: site},
+			{Data: structure.ErrorResponse{Type: "Error", Code: 1146}, ResponseTo: &two},
+			{
+				Data:    structure.ChangeUserRequest{Type: "MYSQL_CHANGE_USER", Username: "admin", Database: "demo"},
+				Session: admin,
+			},
+			{Data: structure.OKResponse{Type: "OK"}, ResponseTo: &four},
+			// the server reported the schema changing to shop.
+			{
+				Data:    structure.WithRawPacket{Transmission: structure.Request{Type: "Query", Query: "SELECT 1"}},
+				Session: &structure.SessionState{User: "admin", Database: "shop"},
+			},
+			{Data: structure.OKResponse{Type: "OK"}, ResponseTo: &six},
+		},
+		Sessions: []structure.Session{{FirstItem: 4, Reason: "MYSQL_CHANGE_USER", Username: "admin"}},
+		Exchanges: []structure.Exchange{
+			{Request: 0, Responses: []int{1}, Command: "Login", RequestStart: start},
+			{
+				Request: 2, Responses: []int{3}, Command: "Query",
+				RequestStart: start.Add(time.Second), TimeToLastByte: 20 * time.Millisecond,
+			},
+			{Request: 4, Responses: []int{5}, Command: "MYSQL_CHANGE_USER", RequestStart: start.Add(2 * time.Second)},
+			{Request: 6, Responses: []int{7}, Command: "Query", RequestStart: start.Add(3 * time.Second)},
+		},
+		Transactions: []structure.Transaction{
+			{Requests: []int{0, 2}, Outcome: "session ended"},
+			{Requests: []int{6}, Outcome: "open"},
+		},
+	}
+}
+
+func networks(t *testing.T, specs ...string) []*net.IPNet {
+	t.Helper()
+	n, err := filter.ParseNetworks(specs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestConnectionMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  filter.Options
+		expected bool
+	}{
+		{"client", filter.Options{Clients: networks(t, "10.0.0.0/24")}, true},
+		{"wrong client", filter.Options{Clients: networks(t, "10.0.1.0/24")}, false},
+		{"server", filter.Options{Servers: networks(t, "10.0.0.1")}, true},
+		{"wrong server", filter.Options{Servers: networks(t, "::1")}, false},
+		{"user", filter.Options{Users: []string{"admin"}}, true},
+		{"wrong user", filter.Options{Users: []string{"root"}}, false},
+		{"database", filter.Options{Databases: []string{"demo"}}, true},
+		{"database changed", filter.Options{Databases: []string{"shop"}, SQL: regexp.MustCompile("SELECT 1")}, true},
+		{"database changed after", filter.Options{Databases: []string{"shop"}, Errors: true}, false},
+		{"wrong database", filter.Options{Databases: []string{"test"}}, false},
+		{"command", filter.Options{Commands: []string{"com_change_user"}}, true},
+		{"missing command", filter.Options{Commands: []string{"COM_STMT_EXECUTE"}}, false},
+		{"sql", filter.Options{SQL: regexp.MustCompile("peeps")}, true},
+		{"errors", filter.Options{Errors: true}, true},
+		{"error code", filter.Options{ErrorCodes: []uint16{1146}}, true},
+		{"wrong error code", filter.Options{ErrorCodes: []uint16{1045}}, false},
+		{"latency", filter.Options{MinLatency: 10 * time.Millisecond}, true},
+		{"too slow", filter.Options{MinLatency: time.Second}, false},
+		{"since", filter.Options{Since: start.Add(3 * time.Second)}, true},
+		{"until", filter.Options{Until: start.Add(-time.Second)}, false},
+		{
+			"combined across exchanges",
+			filter.Options{Users: []string{"admin"}, Errors: true},
+			false,
+		},
+	}
+	for _, test := range tests {
+		if _, ok := test.options.Connection(connection()); ok != test.expected {
+			t.Errorf("%s: expected %v", test.name, test.expected)
+		}
+	}
+}
+
+func TestOnlyMatching(t *testing.T) {
+	o := filter.Options{Commands: []string{"query"}, OnlyMatching: true}
+	conn, ok := o.Connection(connection())
+	if !ok {
+		t.Fatal("Connection should match")
+	}
+	zero, two := 0, 2
+	c := connection()
+	if conn.Address.String() != c.Address.String() {
+		t.Errorf("Address changed to %s", conn.Address)
+	}
+	expected := structure.Connection{
+		Items: []structure.Transmission{
+			c.Items[2],
+			{Data: c.Items[3].Data, ResponseTo: &zero},
+			c.Items[6],
+			{Data: c.Items[7].Data, ResponseTo: &two},
+		},
+		Sessions: []structure.Session{{FirstItem: 2, Reason: "MYSQL_CHANGE_USER", Username: "admin"}},
+		Exchanges: []structure.Exchange{
+			{
+				Request: 0, Responses: []int{1}, Command: "Query",
+				RequestStart: start.Add(time.Second), TimeToLastByte: 20 * time.Millisecond,
+			},
+			{Request: 2, Responses: []int{3}, Command: "Query", RequestStart: start.Add(3 * time.Second)},
+		},
+		Transactions: []structure.Transaction{
+			{Requests: []int{0}, Outcome: "session ended"},
+			{Requests: []int{2}, Outcome: "open"},
+		},
+	}
+	if diff := cmp.Diff(conn, expected, cmpopts.IgnoreFields(structure.Connection{}, "Address")); diff != "" {
+		t.Fatalf("Connection doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestSplitSessions(t *testing.T) {
+	completed := make(chan interface{})
+	go func() {
+		defer close(completed)
+		completed <- connection()
+		completed <- "not a connection"
+	}()
+
+	var commands [][]string
+	for c := range filter.SplitSessions(completed) {
+		conn, ok := c.(structure.Connection)
+		if !ok {
+			commands = append(commands, []string{c.(string)})
+			continue
+		}
+		var u []string
+		for _, e := range conn.Exchanges {
+			u = append(u, e.Command)
+		}
+		commands = append(commands, u)
+	}
+	expected := [][]string{{"Login", "Query"}, {"MYSQL_CHANGE_USER", "Query"}, {"not a connection"}}
+	if diff := cmp.Diff(commands, expected); diff != "" {
+		t.Fatalf("Sessions don't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestParseNetworks(t *testing.T) {
+	n, err := filter.ParseNetworks([]string{"10.0.0.1", "192.168.0.0/16", "::1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, network := range n {
+		got = append(got, network.String())
+	}
+	if diff := cmp.Diff(got, []string{"10.0.0.1/32", "192.168.0.0/16", "::1/128"}); diff != "" {
+		t.Fatalf("Networks don't match (-got +expected):\n%s\n", diff)
+	}
+	if _, err := filter.ParseNetworks([]string{"10.0.0"}); err == nil {
+		t.Fatal("Expected an error")
+	}
+}
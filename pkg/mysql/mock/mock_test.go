@@ -0,0 +1,204 @@
+package mock_test
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/mock"
+)
+
+func startMock(t *testing.T, captures ...string) *sql.DB {
+	t.Helper()
+
+	recordings := mock.NewRecordings()
+	for _, c := range captures {
+		f, err := os.Open("../../../test/captures/" + c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = recordings.Load(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := mock.NewServer(recordings)
+	server.ErrorLog = log.New(io.Discard, "", 0)
+	go func() {
+		if err := server.Serve(l); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	config := mysql.NewConfig()
+	config.Net = "tcp"
+	config.Addr = l.Addr().String()
+	config.User = "test"
+	config.Passwd = "anything"
+	connector, err := mysql.NewConnector(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := sql.OpenDB(connector)
+	t.Cleanup(func() {
+		db.Close()
+		l.Close()
+	})
+	return db
+}
+
+func readRows(t *testing.T, rows *sql.Rows) [][]string {
+	t.Helper()
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var all [][]string
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			t.Fatal(err)
+		}
+		row := make([]string, len(values))
+		for i, v := range values {
+			row[i] = v.String
+		}
+		all = append(all, row)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return all
+}
+
+func TestResultSets(t *testing.T) {
+	db := startMock(t, "numeric-types.expected", "date-types.expected")
+
+	expected := map[string][][]string{
+		"SELECT * FROM demo.dates": {
+			{"1", "2013-03-04", "2021-09-25 17:21:23", "20:33:00", "2021", "1997"},
+		},
+		"SELECT * FROM demo.dbtypes": {
+			{
+				"1", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+				"3.46", "3.33", "4.44", "\x03",
+			},
+			{
+				"2", "127", "8388607", "32767", "2147483647", "9223372036854775807",
+				"255", "16777215", "65535", "4294967295", "18446744073709551615",
+				"3.46", "3.33", "4.44", "\x03",
+			},
+			{
+				"3", "-1", "-2", "-3", "-4", "-5", "6", "7", "8", "9", "10",
+				"3.46", "3.33", "4.44", "\x03",
+			},
+		},
+	}
+
+	for query, want := range expected {
+		// the captures have these as prepared statements, but they
+		// can be run as plain queries too.
+		rows, err := db.Query(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(readRows(t, rows), want); diff != "" {
+			t.Errorf("text %s (-got +expected):\n%s", query, diff)
+		}
+
+		stmt, err := db.Prepare(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows, err = stmt.Query()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(readRows(t, rows), want); diff != "" {
+			t.Errorf("binary %s (-got +expected):\n%s", query, diff)
+		}
+		stmt.Close()
+	}
+}
+
+func TestStatements(t *testing.T) {
+	db := startMock(t, "execute.expected")
+
+	// the execute is matched with its parameters filled in.
+	result, err := db.Exec("INSERT INTO peeps (name, age) VALUES ( ?, ? )", "person", 33)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 1 {
+		t.Errorf("LastInsertId = %d, expected 1", id)
+	}
+
+	// different literals match by fingerprint, and get the error the
+	// capture had.
+	_, err = db.Exec("INSERT INTO test VALUES (3, 'other')")
+	expectError(t, err, 1146)
+
+	_, err = db.Exec("DELETE FROM test")
+	expectError(t, err, 1105)
+
+	if _, err := db.Exec("USE demo"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Ping(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func expectError(t *testing.T, err error, number uint16) {
+	t.Helper()
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		t.Fatalf("expected a MySQL error, got %v", err)
+	}
+	if mysqlErr.Number != number {
+		t.Errorf("error %d, expected %d", mysqlErr.Number, number)
+	}
+}
+
+func TestExactOnly(t *testing.T) {
+	recordings := mock.NewRecordings()
+	recordings.Fingerprints = false
+	f, err := os.Open("../../../test/captures/execute.expected")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := recordings.Load(f); err != nil {
+		t.Fatal(err)
+	}
+	if r := recordings.Statement("INSERT INTO test VALUES (3, 'other')"); r != nil {
+		t.Errorf("unexpected match %#v", r)
+	}
+	if r := recordings.Statement("INSERT INTO test VALUES ( 2, 'TEST' )"); r == nil {
+		t.Error("expected an exact match")
+	}
+}
@@ -0,0 +1,214 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/sqltext"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/transcript"
+)
+
+// Recordings are the responses from the captures, looked up by the SQL they
+// answered.
+type Recordings struct {
+	// Fingerprints allows a query that wasn't captured to be answered with
+	// the responses to one that only differs in its literals.
+	Fingerprints bool
+	// Greeting is the first greeting seen in the captures.
+	Greeting *structure.Greeting
+
+	mu                    sync.Mutex
+	statements            map[string]*recording
+	statementFingerprints map[string]*recording
+	prepares              map[string]*recording
+	prepareFingerprints   map[string]*recording
+}
+
+// recording holds every set of responses seen to a piece of SQL.  They are
+// handed out in turn, going back to the start when they run out.
+type recording struct {
+	replies [][]interface{}
+	next    int
+}
+
+// connection is the part of a connection from the pcap2mysql-log json output
+// that is needed.  The items are kept raw as their type isn't known until
+// they are looked at.
+type connection struct {
+	Items     []struct{ Data json.RawMessage }
+	Exchanges []structure.Exchange
+}
+
+func NewRecordings() *Recordings {
+	return &Recordings{
+		Fingerprints:          true,
+		statements:            make(map[string]*recording),
+		statementFingerprints: make(map[string]*recording),
+		prepares:              make(map[string]*recording),
+		prepareFingerprints:   make(map[string]*recording),
+	}
+}
+
+// Load reads the json output of pcap2mysql-log and adds the responses in it.
+func (r *Recordings) Load(in io.Reader) error {
+	return transcript.Read(in, r.add)
+}
+
+func (r *Recordings) add(c connection) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.Greeting == nil {
+		if err := r.findGreeting(c); err != nil {
+			return err
+		}
+	}
+
+	for _, e := range c.Exchanges {
+		if e.Request < 0 || e.Request >= len(c.Items) {
+			continue
+		}
+		var request transcript.Data
+		if err := decode(c.Items[e.Request].Data, &request); err != nil {
+			return errors.Wrap(err, "mock-decode-request")
+		}
+		var replies []interface{}
+		for _, i := range e.Responses {
+			if i < 0 || i >= len(c.Items) {
+				continue
+			}
+			reply, err := decodeReply(c.Items[i].Data)
+			if err != nil {
+				return errors.Wrap(err, "mock-decode-response")
+			}
+			if reply != nil {
+				replies = append(replies, reply)
+			}
+		}
+		if len(replies) == 0 {
+			continue
+		}
+
+		switch request.Type {
+		case "Query":
+			record(r.statements, r.statementFingerprints, request.Query, replies)
+		case "Execute":
+			// without the prepare there's no telling what was run.
+			if request.SQL != "" {
+				record(r.statements, r.statementFingerprints, request.SQL, replies)
+			}
+		case "Prepare":
+			record(r.prepares, r.prepareFingerprints, request.Query, replies)
+		}
+	}
+	return nil
+}
+
+func (r *Recordings) findGreeting(c connection) error {
+	for _, item := range c.Items {
+		var t struct{ Type string }
+		if err := decode(item.Data, &t); err != nil {
+			return errors.Wrap(err, "mock-decode-item")
+		}
+		if t.Type != "Greeting" {
+			continue
+		}
+		var g structure.Greeting
+		if err := decode(item.Data, &g); err != nil {
+			return errors.Wrap(err, "mock-decode-greeting")
+		}
+		r.Greeting = &g
+		return nil
+	}
+	return nil
+}
+
+func record(exact, fingerprints map[string]*recording, sql string, replies []interface{}) {
+	add(exact, sql, replies)
+	add(fingerprints, sqltext.Fingerprint(sql), replies)
+}
+
+func add(recordings map[string]*recording, key string, replies []interface{}) {
+	rec, ok := recordings[key]
+	if !ok {
+		rec = &recording{}
+		recordings[key] = rec
+	}
+	rec.replies = append(rec.replies, replies)
+}
+
+// Statement returns the next set of responses recorded for a query, or
+// execute of a prepared statement, or nil if there are none.
+func (r *Recordings) Statement(sql string) []interface{} {
+	return r.find(r.statements, r.statementFingerprints, sql)
+}
+
+// Prepare returns the next set of responses recorded for preparing a
+// statement, or nil if there are none.
+func (r *Recordings) Prepare(sql string) []interface{} {
+	return r.find(r.prepares, r.prepareFingerprints, sql)
+}
+
+func (r *Recordings) find(exact, fingerprints map[string]*recording, sql string) []interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec, ok := exact[sql]
+	if !ok && r.Fingerprints {
+		rec, ok = fingerprints[sqltext.Fingerprint(sql)]
+	}
+	if !ok {
+		return nil
+	}
+	replies := rec.replies[rec.next]
+	rec.next = (rec.next + 1) % len(rec.replies)
+	return replies
+}
+
+// decodeReply turns a response from the json back into the structure it
+// was written from.  Responses the mock can't send back are ignored.
+func decodeReply(raw json.RawMessage) (interface{}, error) {
+	var item struct{ Type string }
+	if err := decode(raw, &item); err != nil {
+		return nil, err
+	}
+	var reply interface{}
+	switch item.Type {
+	case "OK":
+		reply = &structure.OKResponse{}
+	case "Error":
+		reply = &structure.ErrorResponse{}
+	case "SQL results":
+		reply = &structure.ResultSetResponse{}
+	case "PREPARE_OK":
+		reply = &structure.PrepareOKResponse{}
+	case "EOF":
+		reply = &structure.Response{}
+	default:
+		return nil, nil //nolint:nilnil
+	}
+	if err := decode(raw, reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// decode unmarshals an item, unwrapping it if the raw data was included,
+// and leaving numbers as json.Number so that large integers survive.
+func decode(raw json.RawMessage, v interface{}) error {
+	var wrapped struct{ Transmission json.RawMessage }
+	if err := json.Unmarshal(raw, &wrapped); err != nil {
+		return err
+	}
+	if len(wrapped.Transmission) > 0 {
+		raw = wrapped.Transmission
+	}
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	return d.Decode(v)
+}
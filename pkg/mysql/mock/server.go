@@ -0,0 +1,392 @@
+package mock
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/decoding"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/encoder"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/packet"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/sqltext"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+const (
+	defaultVersion = "5.7.0-pcap2mysql-mock"
+	// utf8mb4_general_ci
+	defaultCollation = 45
+	authPlugin       = "mysql_native_password"
+	// scramble is sent for the password hashing.  Any password is
+	// accepted so there's no point in it being random.
+	scramble = "pcap2mysql-log-mock!"
+
+	maxPayload = 0xffffff
+
+	comQuit             = 0x01
+	comInitDB           = 0x02
+	comQuery            = 0x03
+	comPing             = 0x0e
+	comStmtPrepare      = 0x16
+	comStmtExecute      = 0x17
+	comStmtSendLongData = 0x18
+	comStmtClose        = 0x19
+	comStmtReset        = 0x1a
+	comSetOption        = 0x1b
+	comResetConnection  = 0x1f
+
+	errUnknownCommand   = 1047
+	errUnknown          = 1105
+	errUnknownStatement = 1243
+	stateGeneral        = "HY000"
+	stateConnection     = "08S01"
+)
+
+// serverCapabilities are those offered in the greeting.  Deliberately no
+// SSL, compression or deprecated EOF to keep things simple.
+const serverCapabilities = structure.CCAP_CLIENT_MYSQL |
+	structure.CCAP_FOUND_ROWS |
+	structure.CCAP_CONNECT_WITH_DB |
+	structure.CCAP_CLIENT_PROTOCOL_41 |
+	structure.CCAP_TRANSACTIONS |
+	structure.CCAP_SECURE_CONNECTION |
+	structure.CCAP_MULTI_STATEMENTS |
+	structure.CCAP_MULTI_RESULTS |
+	structure.CCAP_PS_MULTI_RESULTS |
+	structure.CCAP_PLUGIN_AUTH
+
+// Server answers MySQL connections with the responses recorded in a
+// capture.  Any login is accepted.
+type Server struct {
+	recordings *Recordings
+	// ErrorLog is where problems with connections are logged, the
+	// standard logger is used if it's nil.
+	ErrorLog    *log.Logger
+	connections atomic.Uint32
+}
+
+func NewServer(recordings *Recordings) *Server {
+	return &Server{recordings: recordings}
+}
+
+// Serve accepts connections and answers them until the listener is closed.
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		c, err := l.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "mock-accept")
+		}
+		go s.handle(c)
+	}
+}
+
+func (s *Server) handle(c net.Conn) {
+	defer c.Close()
+	ss := &session{
+		recordings: s.recordings,
+		id:         s.connections.Add(1),
+		reader:     bufio.NewReader(c),
+		encoder:    encoder.New(c, serverCapabilities),
+		statements: make(map[uint32]statement),
+	}
+	ss.decoder = &decoding.RequestDecoder{Emit: ss}
+	if err := ss.run(); err != nil && !errors.Is(err, io.EOF) {
+		s.logf("connection from %s: %s", c.RemoteAddr(), err)
+	}
+}
+
+func (s *Server) logf(format string, v ...interface{}) {
+	if s.ErrorLog != nil {
+		s.ErrorLog.Printf(format, v...)
+		return
+	}
+	log.Printf(format, v...)
+}
+
+type statement struct {
+	query  string
+	params uint16
+}
+
+// session is a connection to the mock.  The requests are read with the
+// same decoder used for the captures, so it plays the part of the
+// connection builder for it.
+type session struct {
+	recordings    *Recordings
+	id            uint32
+	reader        *bufio.Reader
+	encoder       *encoder.Encoder
+	decoder       *decoding.RequestDecoder
+	greeted       bool
+	previous      string
+	request       interface{}
+	capabilities  structure.ClientCapabilities
+	statements    map[uint32]statement
+	lastStatement uint32
+}
+
+func (s *session) run() error {
+	if err := s.greet(); err != nil {
+		return err
+	}
+	for {
+		p, err := readPacket(s.reader)
+		if err != nil {
+			return err
+		}
+		s.encoder.SetSequence(p[packet.PacketNo] + 1)
+		s.encoder.Status = structure.SERVER_STATUS_AUTOCOMMIT
+		if len(p) == packet.HeaderLen {
+			return errors.Wrap(io.ErrUnexpectedEOF, "mock-empty-packet")
+		}
+		if s.greeted {
+			if err := s.login(p); err != nil {
+				return err
+			}
+			continue
+		}
+		if p[packet.HeaderLen] == comQuit {
+			return nil
+		}
+		if err := s.command(p); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *session) greet() error {
+	g := structure.Greeting{
+		Capabilities: serverCapabilities,
+		Collation:    defaultCollation,
+		Version:      defaultVersion,
+	}
+	if captured := s.recordings.Greeting; captured != nil {
+		g.Version = captured.Version
+		g.Collation = captured.Collation
+	}
+	s.encoder.SetSequence(0)
+	s.greeted = true
+	return s.encoder.WriteGreeting(g, encoder.Handshake{
+		ConnectionID: s.id,
+		Status:       structure.SERVER_STATUS_AUTOCOMMIT,
+		AuthData:     []byte(scramble),
+		AuthPlugin:   authPlugin,
+	})
+}
+
+func (s *session) login(p []byte) error {
+	if _, err := s.decoder.Write(p); err != nil {
+		return errors.Wrap(err, "mock-login")
+	}
+	s.greeted = false
+	if login, ok := s.request.(structure.LoginRequest); ok {
+		s.capabilities = login.ClientCapabilities & serverCapabilities
+		s.encoder.Capabilities = s.capabilities
+	}
+	return s.encoder.WriteOK(structure.OKResponse{
+		ServerStatus: structure.SERVER_STATUS_AUTOCOMMIT,
+	})
+}
+
+func (s *session) command(p []byte) error {
+	switch p[packet.HeaderLen] {
+	case comQuery:
+		if _, err := s.decoder.Write(p); err != nil {
+			return errors.Wrap(err, "mock-query")
+		}
+		query := s.request.(structure.Request).Query
+		replies := s.recordings.Statement(query)
+		// changing database is allowed, the same as with COM_INIT_DB.
+		if replies == nil && strings.HasPrefix(sqltext.Fingerprint(query), "use ") {
+			replies = []interface{}{&structure.OKResponse{ServerStatus: s.encoder.Status}}
+		}
+		return s.reply(replies, false, query)
+	case comStmtPrepare:
+		if _, err := s.decoder.Write(p); err != nil {
+			return errors.Wrap(err, "mock-prepare")
+		}
+		return s.prepare(s.request.(structure.Request).Query)
+	case comStmtExecute:
+		if _, err := s.decoder.Write(p); err != nil {
+			return errors.Wrap(err, "mock-execute")
+		}
+		execute := s.request.(structure.ExecuteRequest)
+		if _, ok := s.statements[execute.StatementID]; !ok {
+			return s.encoder.WriteError(structure.ErrorResponse{
+				Code:    errUnknownStatement,
+				State:   stateGeneral,
+				Message: "Unknown prepared statement handler given to mysqld_stmt_execute",
+			})
+		}
+		return s.reply(s.recordings.Statement(execute.SQL), true, execute.SQL)
+	case comStmtClose:
+		//nolint:mnd
+		if len(p) >= packet.HeaderLen+5 {
+			delete(s.statements, binary.LittleEndian.Uint32(p[packet.HeaderLen+1:]))
+		}
+		return nil
+	case comStmtSendLongData:
+		// no response is expected, and the data isn't used.
+		return nil
+	case comSetOption:
+		return s.encoder.WriteEOF(s.encoder.Status)
+	case comInitDB, comPing, comStmtReset, comResetConnection:
+		return s.encoder.WriteOK(structure.OKResponse{ServerStatus: s.encoder.Status})
+	}
+	return s.encoder.WriteError(structure.ErrorResponse{
+		Code:    errUnknownCommand,
+		State:   stateConnection,
+		Message: "Unknown command",
+	})
+}
+
+// prepare replies to a prepare with the recorded response, or when there
+// isn't one makes one up, so that the executes can still be answered.
+func (s *session) prepare(query string) error {
+	replies := s.recordings.Prepare(query)
+	if len(replies) == 0 {
+		replies = []interface{}{&structure.PrepareOKResponse{
+			NumParams: uint16(sqltext.Placeholders(query)), //nolint:gosec
+		}}
+	}
+	for i, r := range replies {
+		ok, isOK := r.(*structure.PrepareOKResponse)
+		if !isOK {
+			continue
+		}
+		s.lastStatement++
+		prepared := *ok
+		prepared.StatementID = s.lastStatement
+		s.statements[prepared.StatementID] = statement{query: query, params: prepared.NumParams}
+		// the recording is shared, so swap the response in a copy.
+		replies = append([]interface{}(nil), replies...)
+		replies[i] = &prepared
+		break
+	}
+	return s.reply(replies, false, query)
+}
+
+// reply writes the responses recorded for a request, or an error if there
+// are none.
+func (s *session) reply(replies []interface{}, binary bool, sql string) error {
+	if len(replies) == 0 {
+		return s.encoder.WriteError(structure.ErrorResponse{
+			Code:    errUnknown,
+			State:   stateGeneral,
+			Message: "No response recorded for: " + sql,
+		})
+	}
+	for i, r := range replies {
+		s.encoder.Status = structure.SERVER_STATUS_AUTOCOMMIT
+		if i < len(replies)-1 {
+			s.encoder.Status |= structure.SERVER_MORE_RESULTS_EXISTS
+		}
+		var err error
+		switch r := r.(type) {
+		case *structure.OKResponse:
+			err = s.encoder.WriteOK(*r)
+		case *structure.ErrorResponse:
+			err = s.encoder.WriteError(*r)
+		case *structure.ResultSetResponse:
+			err = s.encoder.WriteResultSet(*r, binary)
+		case *structure.PrepareOKResponse:
+			err = s.encoder.WritePrepareOK(*r)
+		case *structure.Response:
+			err = s.encoder.WriteEOF(s.encoder.Status)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readPacket reads a packet, joining it up with the ones that follow if it
+// was too long to fit in one.
+func readPacket(r io.Reader) ([]byte, error) {
+	var p []byte
+	for {
+		header := make([]byte, packet.HeaderLen)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return nil, err
+		}
+		length := int(header[0]) | int(header[1])<<8 | int(header[2])<<16 //nolint:mnd
+		if p == nil {
+			p = header
+		}
+		start := len(p)
+		p = append(p, make([]byte, length)...)
+		if _, err := io.ReadFull(r, p[start:]); err != nil {
+			return nil, errors.Wrap(err, "mock-read-packet")
+		}
+		if length < maxPayload {
+			return p, nil
+		}
+	}
+}
+
+// Transmission is called by the decoder with each request.
+func (s *session) Transmission(typeName string, t interface{}) {
+	s.request = t
+	s.previous = typeName
+}
+
+func (s *session) ConnectionBuilder() decoding.ConnectionBuilder {
+	return s
+}
+
+func (s *session) AddToConnection(bool, []time.Time, string, interface{}) {}
+
+func (s *session) Compressed() bool {
+	return false
+}
+
+func (s *session) JustSeenGreeting() bool {
+	return s.greeted
+}
+
+func (s *session) PreviousRequestType() string {
+	return s.previous
+}
+
+func (s *session) ParamsForQuery(query uint32) uint16 {
+	return s.statements[query].params
+}
+
+func (s *session) StatementQuery(statementID uint32) string {
+	return s.statements[statementID].query
+}
+
+func (s *session) QueryAttributes() bool {
+	return s.capabilities&structure.CCAP_CLIENT_QUERY_ATTRIBUTES != 0
+}
+
+func (s *session) OptionalMetadata() bool {
+	return false
+}
+
+func (s *session) DeprecateEOF() bool {
+	return s.capabilities&structure.CCAP_CLIENT_DEPRECATE_EOF != 0
+}
+
+func (s *session) CachedColumns() []structure.ColumnInfo {
+	return nil
+}
+
+func (s *session) Capabilities() structure.ClientCapabilities {
+	return s.capabilities
+}
+
+func (s *session) Authenticating() bool {
+	return false
+}
@@ -0,0 +1,115 @@
+// Package ndjson writes the connections out as newline delimited json, so
+// that each can be read as soon as it's complete rather than after the
+// whole capture has been processed.
+package ndjson
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+
+	"github.com/colinnewell/pcap-cli/tcp"
+	"github.com/pkg/errors"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/schema"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+// Item is a single transmission from a connection, along with the address
+// of the connection and where it was in the Items.  Requests that got a
+// response also have the exchange.
+type Item struct {
+	Address tcp.ConnectionAddress
+	Index   int
+	structure.Transmission
+	Exchange *structure.Exchange `json:"Exchange,omitempty"`
+}
+
+// Connections writes each connection to stdout as a line of json as soon
+// as it's complete.
+func Connections(completed chan interface{}) {
+	output(completed, WriteConnection)
+}
+
+// Items writes each transmission to stdout as a line of json.  When the
+// decoder's items haven't been assembled into connections they're written
+// as each exchange completes, otherwise a connection at a time.
+func Items(completed chan interface{}) {
+	output(completed, WriteItems)
+}
+
+func output(completed chan interface{}, write func(io.Writer, interface{}) error) {
+	for c := range completed {
+		if err := write(os.Stdout, c); err != nil {
+			log.Fatal(err)
+		}
+		if items, ok := c.(structure.ConnectionItems); ok && items.Release != nil {
+			items.Release()
+		}
+	}
+}
+
+// WriteConnection writes the connection as a single line of json.
+func WriteConnection(w io.Writer, c interface{}) error {
+	return errors.Wrap(json.NewEncoder(w).Encode(c), "ndjson-connection")
+}
+
+// WriteItems writes each of the transmissions in the connection, or the
+// items handed on before it closed, as a line of json.  Anything else is
+// written as it is.
+func WriteItems(w io.Writer, c interface{}) error {
+	switch v := c.(type) {
+	case structure.Connection:
+		return writeItems(w, v.Address, 0, v.Items, v.Exchanges)
+	case structure.ConnectionItems:
+		return writeItems(w, v.Address, v.First, v.Items, v.Exchanges)
+	case schema.Connection:
+		return writeItemLines(w, v.Address, v.Items, v.Exchanges)
+	case schema.ConnectionItems:
+		return writeItemLines(w, v.Address, v.Items, v.Exchanges)
+	}
+	return WriteConnection(w, c)
+}
+
+func writeItems(
+	w io.Writer, address tcp.ConnectionAddress, first int,
+	items []structure.Transmission, exchanges []structure.Exchange,
+) error {
+	byRequest := make(map[int]*structure.Exchange, len(exchanges))
+	for i := range exchanges {
+		byRequest[exchanges[i].Request] = &exchanges[i]
+	}
+	e := json.NewEncoder(w)
+	for i, t := range items {
+		if err := e.Encode(Item{
+			Address:      address,
+			Index:        first + i,
+			Transmission: t,
+			Exchange:     byRequest[first+i],
+		}); err != nil {
+			return errors.Wrap(err, "ndjson-item")
+		}
+	}
+	return nil
+}
+
+// writeItemLines is writeItems for version 2 of the output.
+func writeItemLines(w io.Writer, address string, items []schema.Item, exchanges []schema.Exchange) error {
+	byRequest := make(map[int]*schema.Exchange, len(exchanges))
+	for i := range exchanges {
+		byRequest[exchanges[i].Request] = &exchanges[i]
+	}
+	e := json.NewEncoder(w)
+	for _, item := range items {
+		if err := e.Encode(schema.ItemLine{
+			SchemaVersion: schema.Version,
+			Address:       address,
+			Item:          item,
+			Exchange:      byRequest[item.Index],
+		}); err != nil {
+			return errors.Wrap(err, "ndjson-item")
+		}
+	}
+	return nil
+}
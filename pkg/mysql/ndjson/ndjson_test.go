@@ -0,0 +1,154 @@
+package ndjson_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"github.com/colinnewell/pcap-cli/tcp"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/ndjson"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/schema"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+func connection() structure.Connection {
+	seen := time.Date(2021, 4, 4, 17, 28, 50, 538141000, time.UTC)
+	zero := 0
+	return structure.Connection{
+		Address: tcp.ConnectionAddress{
+			IP:   gopacket.NewFlow(layers.EndpointIPv4, net.IP{10, 0, 0, 2}, net.IP{10, 0, 0, 1}),
+			Port: gopacket.NewFlow(layers.EndpointTCPPort, []byte{0xc3, 0x50}, []byte{0x0c, 0xea}),
+		},
+		Items: []structure.Transmission{
+			{Data: structure.Request{Type: "Query", Query: "SELECT 1"}, Seen: []time.Time{seen}},
+			{Data: structure.OKResponse{Type: "OK"}, Seen: []time.Time{seen}, ResponseTo: &zero},
+		},
+		Exchanges: []structure.Exchange{
+			{Request: 0, Responses: []int{1}, Command: "Query", RequestStart: seen, RequestEnd: seen},
+		},
+	}
+}
+
+func TestWriteConnection(t *testing.T) {
+	var sb strings.Builder
+	if err := ndjson.WriteConnection(&sb, connection()); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected one line, got %q", sb.String())
+	}
+	if !strings.HasPrefix(lines[0], `{"Address":"10.0.0.2:50000 - 10.0.0.1:3306","Items":[`) {
+		t.Errorf("unexpected connection %s", lines[0])
+	}
+}
+
+func TestWriteItems(t *testing.T) {
+	var sb strings.Builder
+	if err := ndjson.WriteItems(&sb, connection()); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"Address":"10.0.0.2:50000 - 10.0.0.1:3306","Index":0,` +
+		`"Data":{"Type":"Query","Query":"SELECT 1"},"Seen":["2021-04-04T17:28:50.538141Z"],` +
+		`"Exchange":{"Request":0,"Responses":[1],"Command":"Query",` +
+		`"RequestStart":"2021-04-04T17:28:50.538141Z","RequestEnd":"2021-04-04T17:28:50.538141Z",` +
+		`"Rows":0,"RequestBytes":0,"ResponseBytes":0}}
+{"Address":"10.0.0.2:50000 - 10.0.0.1:3306","Index":1,` +
+		`"Data":{"AffectedRows":0,"LastInsertID":0,"ServerStatus":"0: ","WarningCount":0,"Type":"OK","Info":""},` +
+		`"Seen":["2021-04-04T17:28:50.538141Z"],"ResponseTo":0}
+`
+	if diff := cmp.Diff(sb.String(), expected); diff != "" {
+		t.Errorf("Items don't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestWriteItemsVersion2(t *testing.T) {
+	conn, err := schema.FromConnection(connection())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := ndjson.WriteItems(&sb, conn); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"schema_version":2,"address":"10.0.0.2:50000 - 10.0.0.1:3306",` +
+		`"item":{"index":0,"kind":"query","direction":"request","seen":["2021-04-04T17:28:50.538141Z"],` +
+		`"data":{"query":"SELECT 1"}},` +
+		`"exchange":{"request":0,"responses":[1],"command":"Query",` +
+		`"request_start":"2021-04-04T17:28:50.538141Z","request_end":"2021-04-04T17:28:50.538141Z",` +
+		`"time_to_first_byte_ns":0,"time_to_last_byte_ns":0,"rows":0,"request_bytes":0,"response_bytes":0}}
+{"schema_version":2,"address":"10.0.0.2:50000 - 10.0.0.1:3306",` +
+		`"item":{"index":1,"kind":"ok","direction":"response","seen":["2021-04-04T17:28:50.538141Z"],` +
+		`"response_to":0,"data":{"affected_rows":0,"last_insert_id":0,"status":{"value":0,"names":[]},"warnings":0}}}
+`
+	if diff := cmp.Diff(sb.String(), expected); diff != "" {
+		t.Errorf("Items don't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+// handedOn is the connection's exchange as the decoder hands it on before
+// the connection closes, as if it were the second exchange.
+func handedOn() structure.ConnectionItems {
+	conn := connection()
+	two := 2
+	conn.Items[1].ResponseTo = &two
+	conn.Exchanges[0].Request, conn.Exchanges[0].Responses = 2, []int{3}
+	return structure.ConnectionItems{
+		Address:   conn.Address,
+		First:     2,
+		Items:     conn.Items,
+		Exchanges: conn.Exchanges,
+	}
+}
+
+func TestWriteConnectionItems(t *testing.T) {
+	var sb strings.Builder
+	if err := ndjson.WriteItems(&sb, handedOn()); err != nil {
+		t.Fatal(err)
+	}
+	// the connection that follows has already had its items written.
+	if err := ndjson.WriteItems(&sb, structure.Connection{Address: handedOn().Address}); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"Address":"10.0.0.2:50000 - 10.0.0.1:3306","Index":2,` +
+		`"Data":{"Type":"Query","Query":"SELECT 1"},"Seen":["2021-04-04T17:28:50.538141Z"],` +
+		`"Exchange":{"Request":2,"Responses":[3],"Command":"Query",` +
+		`"RequestStart":"2021-04-04T17:28:50.538141Z","RequestEnd":"2021-04-04T17:28:50.538141Z",` +
+		`"Rows":0,"RequestBytes":0,"ResponseBytes":0}}
+{"Address":"10.0.0.2:50000 - 10.0.0.1:3306","Index":3,` +
+		`"Data":{"AffectedRows":0,"LastInsertID":0,"ServerStatus":"0: ","WarningCount":0,"Type":"OK","Info":""},` +
+		`"Seen":["2021-04-04T17:28:50.538141Z"],"ResponseTo":2}
+`
+	if diff := cmp.Diff(sb.String(), expected); diff != "" {
+		t.Errorf("Items don't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestWriteConnectionItemsVersion2(t *testing.T) {
+	items, err := schema.FromConnectionItems(handedOn())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := ndjson.WriteItems(&sb, items); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"schema_version":2,"address":"10.0.0.2:50000 - 10.0.0.1:3306",` +
+		`"item":{"index":2,"kind":"query","direction":"request","seen":["2021-04-04T17:28:50.538141Z"],` +
+		`"data":{"query":"SELECT 1"}},` +
+		`"exchange":{"request":2,"responses":[3],"command":"Query",` +
+		`"request_start":"2021-04-04T17:28:50.538141Z","request_end":"2021-04-04T17:28:50.538141Z",` +
+		`"time_to_first_byte_ns":0,"time_to_last_byte_ns":0,"rows":0,"request_bytes":0,"response_bytes":0}}
+{"schema_version":2,"address":"10.0.0.2:50000 - 10.0.0.1:3306",` +
+		`"item":{"index":3,"kind":"ok","direction":"response","seen":["2021-04-04T17:28:50.538141Z"],` +
+		`"response_to":2,"data":{"affected_rows":0,"last_insert_id":0,"status":{"value":0,"names":[]},"warnings":0}}}
+`
+	if diff := cmp.Diff(sb.String(), expected); diff != "" {
+		t.Errorf("Items don't match (-got +expected):\n%s\n", diff)
+	}
+}
@@ -14,7 +14,7 @@ func decompressPacket(data []byte) ([]byte, int, error) {
 	}
 
 	compLength := mySQLPacketLength(data[:3])
-	unCompLength := mySQLPacketLength(data[4:6])
+	unCompLength := mySQLPacketLength(data[4:7])
 
 	if len(data) < compressedHeaderLen+int(compLength) {
 		return []byte(nil), 0, ErrIncompletePacket
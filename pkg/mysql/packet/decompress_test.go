@@ -2,6 +2,7 @@ package packet_test
 
 import (
 	"bytes"
+	"compress/zlib"
 	"io"
 	"testing"
 
@@ -98,3 +99,79 @@ func TestMultiplePacketsDecompress(t *testing.T) {
 		t.Fatalf("Decompressed version doesn't match (-got +expected):\n%s\n", diff)
 	}
 }
+
+func TestLargeUncompressedLength(t *testing.T) {
+	// a single 64KiB packet, so the uncompressed length needs all 3 bytes
+	// of its header field (0x00, 0x00, 0x01).
+	expected := append([]byte{0xfc, 0xff, 0x00, 0x00}, bytes.Repeat([]byte("a"), 0xfffc)...)
+
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(expected); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	length := compressed.Len()
+	input := append(
+		[]byte{byte(length), byte(length >> 8), byte(length >> 16), 0x00, 0x00, 0x00, 0x01},
+		compressed.Bytes()...,
+	)
+
+	var b bytes.Buffer
+	d := packet.NewSplitter(&b)
+	d.CompressionDetected()
+	if _, err := d.Write(input); err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(b.Bytes(), expected); diff != "" {
+		t.Fatalf("Decompressed version doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestCompressedAcrossWrites(t *testing.T) {
+	compressed := []byte{
+		0x22, 0x00, 0x00, 0x00, 0x32, 0x00, 0x00, 0x78, 0x9c, 0xd3, 0x63, 0x60, 0x60, 0x60, 0x2e, 0x4e, // "...2..x..c```.N
+		0xcd, 0x49, 0x4d, 0x2e, 0x51, 0x50, 0x32, 0x30, 0x34, 0x32, 0x36, 0x31, 0x35, 0x33, 0xb7, 0xb0, // .IM.QP20426153..
+		0xc4, 0xcd, 0x52, 0x02, 0x00, 0x0c, 0xd1, 0x0a, 0x6c, // ..R.....l
+	}
+	// a compressed packet split over several writes, followed by an
+	// uncompressed one and the start of another in the same write.
+	input := append(append(append([]byte(nil), compressed...),
+		0x09, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00,
+		0x05, 0x00, 0x00, 0x01, 0xfe, 0x00, 0x00, 0x02, 0x00,
+	), compressed[:10]...)
+
+	expected := []byte{
+		0x2e, 0x00, 0x00, 0x00, 0x03, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x20, 0x22, 0x30, 0x31, 0x32, // .....select "012
+		0x33, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39, 0x30, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37, 0x38, // 3456789012345678
+		0x39, 0x30, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39, 0x30, 0x31, 0x32, 0x33, 0x34, // 9012345678901234
+		0x35, 0x22, // 5"
+		0x05, 0x00, 0x00, 0x01, 0xfe, 0x00, 0x00, 0x02, 0x00, // .........
+	}
+
+	var b bytes.Buffer
+	d := packet.NewSplitter(&b)
+	d.CompressionDetected()
+	for _, chunk := range [][]byte{input[:2], input[2:20], input[20:]} {
+		n, err := d.Write(chunk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != len(chunk) {
+			t.Fatalf("wrote %d of %d bytes", n, len(chunk))
+		}
+	}
+
+	if diff := cmp.Diff(b.Bytes(), expected); diff != "" {
+		t.Fatalf("Decompressed version doesn't match (-got +expected):\n%s\n", diff)
+	}
+	if !d.IncompletePacket() {
+		t.Error("expected the partial compressed packet to be left over")
+	}
+	if diff := cmp.Diff(d.Bytes(), compressed[:10]); diff != "" {
+		t.Errorf("Left over data doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
@@ -1,7 +1,10 @@
 package packet
 
 import (
+	"encoding/json"
 	"time"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/spill"
 )
 
 // TimesSeen interface for something that gathers the times that data was seen.
@@ -15,8 +18,30 @@ type TimesSeen interface {
 
 // Packet MySQL packet along with when it was seen.
 type Packet struct {
-	Seen []time.Time
-	Data []byte
+	Seen    []time.Time
+	Data    []byte
+	spilled *spill.Chunk
+}
+
+// Load reads the data back in if it was spilled to disk.
+func (p *Packet) Load() error {
+	if p.spilled == nil || p.Data != nil {
+		return nil
+	}
+	data, err := p.spilled.Bytes()
+	if err != nil {
+		return err
+	}
+	p.Data = data
+	return nil
+}
+
+func (p Packet) MarshalJSON() ([]byte, error) {
+	type plain Packet
+	if err := p.Load(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(plain(p))
 }
 
 func (p Packet) FirstSeen() time.Time {
@@ -29,15 +54,23 @@ func (p Packet) FirstSeen() time.Time {
 // Buffer buffers MySQL packets along with their times seen so they can
 // be played back in order.
 type Buffer struct {
-	Times   TimesSeen
-	Packets []Packet
-	pos     int
+	Times    TimesSeen
+	Packets  []Packet
+	pos      int
+	lastSeen []time.Time
+	spill    *spill.Store
 }
 
 func (b *Buffer) SetTimes(t TimesSeen) {
 	b.Times = t
 }
 
+// SetSpill sets the store to spill packets to once the memory budget has
+// been used up.
+func (b *Buffer) SetSpill(s *spill.Store) {
+	b.spill = s
+}
+
 // Write buffers up the packets and stores when they were seen.
 func (b *Buffer) Write(p []byte) (n int, err error) {
 	data := make([]byte, len(p))
@@ -45,12 +78,18 @@ func (b *Buffer) Write(p []byte) (n int, err error) {
 	packet := Packet{Data: data, Seen: b.Times.Seen()}
 	b.Times.Reset()
 	if len(packet.Seen) == 0 {
-		lastPacket := len(b.Packets) - 1
-		if lastPacket >= 0 {
-			// assume it must have come in at
-			// the same time as the previous
-			// packet.
-			packet.Seen = b.Packets[lastPacket].Seen
+		// assume it must have come in at
+		// the same time as the previous
+		// packet.
+		packet.Seen = b.lastSeen
+	}
+	b.lastSeen = packet.Seen
+	if !b.spill.Reserve(len(data)) {
+		// if we can't write it out we'll just have to
+		// hang onto it.
+		if chunk, err := b.spill.Write(data); err == nil {
+			packet.Data = nil
+			packet.spilled = chunk
 		}
 	}
 	b.Packets = append(b.Packets, packet)
@@ -69,6 +108,21 @@ func (b *Buffer) Next() {
 	b.pos++
 }
 
+// Discard drops the packets that have already been read to free up the
+// memory they're holding.
+func (b *Buffer) Discard() {
+	if b.pos == 0 {
+		return
+	}
+	for _, p := range b.Packets[:b.pos] {
+		if p.spilled == nil {
+			b.spill.Release(len(p.Data))
+		}
+	}
+	b.Packets = append([]Packet(nil), b.Packets[b.pos:]...)
+	b.pos = 0
+}
+
 func (*Buffer) Reset() {
 	// FIXME: this is awkward.  This is wanted for the times seen thing
 }
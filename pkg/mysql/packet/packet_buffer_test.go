@@ -0,0 +1,46 @@
+package packet_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/packet"
+)
+
+type times struct {
+	seen []time.Time
+}
+
+func (t *times) Reset() {
+	t.seen = nil
+}
+
+func (t *times) Seen() []time.Time {
+	return t.seen
+}
+
+func TestBufferDiscard(t *testing.T) {
+	seen := []time.Time{time.Date(2021, 10, 23, 9, 52, 9, 0, time.UTC)}
+	tm := &times{seen: seen}
+	b := packet.Buffer{Times: tm}
+
+	if _, err := b.Write([]byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	b.Next()
+	b.Discard()
+	if b.CurrentPacket() != nil || len(b.Packets) != 0 {
+		t.Fatal("Packet read should have been discarded")
+	}
+
+	// no times recorded for this one so it should use the last ones.
+	if _, err := b.Write([]byte{2}); err != nil {
+		t.Fatal(err)
+	}
+	p := b.CurrentPacket()
+	if diff := cmp.Diff([]interface{}{p.Data, p.Seen}, []interface{}{[]byte{2}, seen}); diff != "" {
+		t.Fatalf("Packet doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
@@ -13,6 +13,7 @@ import (
 // IncompletePacket() function once done writing.
 type Splitter struct {
 	buf              bytes.Buffer
+	compressedBuf    bytes.Buffer
 	writer           io.Writer
 	incompletePacket bool
 	compressed       bool
@@ -29,15 +30,27 @@ func (c *Splitter) CompressionDetected() {
 }
 
 func (c *Splitter) Write(p []byte) (int, error) {
-	var compressedRead int
+	read := len(p)
 	if c.compressed {
-		unwrapped, n, err := decompressPacket(p)
-		if err != nil && errors.Is(err, ErrIncompletePacket) {
-			c.incompletePacket = true
-			return 0, nil
+		// the compressed packets needn't line up with the writes, so
+		// unwrap all the complete ones and hang onto the rest.
+		c.compressedBuf.Write(p)
+		for c.compressedBuf.Len() > 0 {
+			unwrapped, n, err := decompressPacket(c.compressedBuf.Bytes())
+			if errors.Is(err, ErrIncompletePacket) {
+				break
+			}
+			if n == 0 {
+				c.compressedBuf.Reset()
+				return 0, errors.Wrap(err, "decompress")
+			}
+			c.compressedBuf.Next(n)
+			c.buf.Write(unwrapped)
+		}
+		if c.buf.Len() == 0 {
+			c.incompletePacket = c.compressedBuf.Len() > 0
+			return read, nil
 		}
-		compressedRead = n
-		c.buf.Write(unwrapped)
 	} else {
 		c.buf.Write(p)
 	}
@@ -45,16 +58,17 @@ func (c *Splitter) Write(p []byte) (int, error) {
 	if n > 0 {
 		// suck up the data
 		c.buf.Next(n)
-		// if it was compressed we potentially
-		// read a different number of bytes so use
-		// the uncompressed block size for reporting.
-		n = compressedRead
+	}
+	if c.compressed {
+		// everything written has been taken, either unwrapped or held
+		// onto until the rest of the compressed packet arrives.
+		n = read
 	}
 	if err != nil && errors.Is(err, ErrIncompletePacket) {
 		c.incompletePacket = true
 		err = nil
 	} else {
-		c.incompletePacket = false
+		c.incompletePacket = c.compressedBuf.Len() > 0
 	}
 	return n, err
 }
@@ -63,6 +77,8 @@ func (c *Splitter) IncompletePacket() bool {
 	return c.incompletePacket
 }
 
+// Bytes returns the data left over, still compressed if it couldn't be
+// unwrapped.
 func (c *Splitter) Bytes() []byte {
-	return c.buf.Bytes()
+	return append(append([]byte(nil), c.buf.Bytes()...), c.compressedBuf.Bytes()...)
 }
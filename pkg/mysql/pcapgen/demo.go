@@ -0,0 +1,118 @@
+package pcapgen
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/tcpassembly"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/encoder"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+// The demo server is the one the test captures talk to, with its peeps
+// table in the demo database.
+//
+//nolint:gochecknoglobals
+var (
+	// DemoStart is when the test captures start.
+	DemoStart = time.Date(2021, 9, 25, 17, 21, 23, 0, time.UTC)
+
+	// DemoHandshake is the rest of the demo server's greeting.
+	DemoHandshake = encoder.Handshake{
+		ConnectionID: 9,
+		Status:       structure.SERVER_STATUS_AUTOCOMMIT,
+		AuthData:     []byte("pcap2mysql-log-test!"),
+		AuthPlugin:   "mysql_native_password",
+	}
+)
+
+// DemoColumn is a column of the peeps table.
+func DemoColumn(name string, fieldType structure.FieldType, detail structure.FieldDetail) structure.ColumnInfo {
+	return structure.ColumnInfo{
+		Catalog:     "def",
+		Schema:      "demo",
+		TableAlias:  "peeps",
+		Table:       "peeps",
+		ColumnAlias: name,
+		Column:      name,
+		TypeInfo: structure.TypeInfo{
+			LengthOfFixedFields: 12,
+			CharacterSetNumber:  45,
+			MaxColumnSize:       255,
+			FieldTypes:          fieldType,
+			FieldDetail:         detail,
+		},
+	}
+}
+
+// DemoGreeting is the demo server's greeting, offering compression if
+// asked.
+func DemoGreeting(compress bool) structure.Greeting {
+	g := structure.Greeting{
+		Capabilities: structure.CCAP_CLIENT_MYSQL |
+			structure.CCAP_CONNECT_WITH_DB |
+			structure.CCAP_CLIENT_PROTOCOL_41 |
+			structure.CCAP_SECURE_CONNECTION |
+			structure.CCAP_PLUGIN_AUTH,
+		Collation: 45,
+		Version:   "8.0.36",
+	}
+	if compress {
+		g.Capabilities |= structure.CCAP_COMPRESS
+	}
+	return g
+}
+
+// DemoLogin is root logging in to the demo database.
+func DemoLogin(caps structure.ClientCapabilities) structure.LoginRequest {
+	return structure.LoginRequest{
+		ClientCapabilities: caps,
+		Collation:          45,
+		MaxPacketSize:      16777216,
+		Username:           "root",
+		AuthData:           []byte("0123456789abcdefghij"),
+		Database:           "demo",
+		AuthPlugin:         "mysql_native_password",
+	}
+}
+
+// Reassemble puts the streams in the packets back together the way
+// pcap2mysql-log does, keyed by their flows, to check what the other end
+// of a capture would see.
+func Reassemble(packets []gopacket.Packet) map[string]string {
+	s := make(streams)
+	assembler := tcpassembly.NewAssembler(tcpassembly.NewStreamPool(s))
+	for _, p := range packets {
+		tcp, _ := p.TransportLayer().(*layers.TCP)
+		assembler.AssembleWithTimestamp(p.NetworkLayer().NetworkFlow(), tcp, p.Metadata().Timestamp)
+	}
+	assembler.FlushAll()
+	data := make(map[string]string, len(s))
+	for k, v := range s {
+		data[k] = v.String()
+	}
+	return data
+}
+
+type streams map[string]*bytes.Buffer
+
+type stream struct {
+	data *bytes.Buffer
+}
+
+func (s stream) Reassembled(rs []tcpassembly.Reassembly) {
+	for _, r := range rs {
+		s.data.Write(r.Bytes)
+	}
+}
+
+func (s stream) ReassemblyComplete() {}
+
+func (s streams) New(network, transport gopacket.Flow) tcpassembly.Stream {
+	b := &bytes.Buffer{}
+	s[network.String()+" "+transport.String()] = b
+	return stream{data: b}
+}
@@ -0,0 +1,458 @@
+// Package pcapgen builds pcap files of MySQL conversations so that test
+// captures can be made without a server.  The conversations are written
+// with the structures the decoder produces, and the TCP segments can be
+// arranged to produce the awkward cases real servers rarely do.
+package pcapgen
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+	"github.com/pkg/errors"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/encoder"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+const (
+	// DefaultMSS is the most data sent in a segment unless the
+	// connection says otherwise.
+	DefaultMSS = 1460
+
+	snapLength = 65536
+	window     = 65535
+	ttl        = 64
+	// isnStep spaces out the initial sequence numbers of the connections.
+	isnStep = 0x10000000
+)
+
+var errUnexpectedType = errors.New("unexpected type")
+
+// Capture holds the packets of the connections as they are made up.
+// Packets are given the time on the capture's clock, which moves on by
+// Gap with each one.
+type Capture struct {
+	// Gap is the time between one packet and the next.
+	Gap time.Duration
+
+	now         time.Time
+	frames      []frame
+	connections uint32
+	ipID        uint16
+}
+
+type frame struct {
+	seen time.Time
+	data []byte
+}
+
+type endpoint struct {
+	mac  net.HardwareAddr
+	ip   net.IP
+	port uint16
+}
+
+// New creates a capture starting at the time given.
+func New(start time.Time) *Capture {
+	return &Capture{Gap: time.Millisecond, now: start}
+}
+
+// Wait moves the clock on, as if nothing happened for that long.
+func (c *Capture) Wait(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// Write writes the packets out as a pcap file.
+func (c *Capture) Write(w io.Writer) error {
+	pw := pcapgo.NewWriter(w)
+	if err := pw.WriteFileHeader(snapLength, layers.LinkTypeEthernet); err != nil {
+		return errors.Wrap(err, "pcap-header")
+	}
+	for _, f := range c.frames {
+		info := gopacket.CaptureInfo{
+			Timestamp:     f.seen,
+			CaptureLength: len(f.data),
+			Length:        len(f.data),
+		}
+		if err := pw.WritePacket(info, f.data); err != nil {
+			return errors.Wrap(err, "pcap-packet")
+		}
+	}
+	return nil
+}
+
+// Connect opens a TCP connection from the client to the server, with the
+// three way handshake.  Addresses are host:port, and the server needs the
+// lower port as that's how pcap2mysql-log tells the sides apart.
+func (c *Capture) Connect(client, server string) (*Connection, error) {
+	from, err := parseEndpoint(client)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseEndpoint(server)
+	if err != nil {
+		return nil, err
+	}
+	c.connections++
+	isn := c.connections * isnStep
+	conn := &Connection{
+		MSS:       DefaultMSS,
+		capture:   c,
+		client:    from,
+		server:    to,
+		clientSeq: isn,
+		serverSeq: isn + isnStep/2, //nolint:mnd
+	}
+	conn.requests = encoder.New(&conn.buffer, 0)
+	conn.responses = encoder.New(&conn.buffer, 0)
+
+	if err := c.segment(from, to, layers.TCP{SYN: true, Seq: conn.clientSeq}, nil); err != nil {
+		return nil, err
+	}
+	conn.clientSeq++
+	if err := c.segment(to, from, layers.TCP{
+		SYN: true, ACK: true, Seq: conn.serverSeq, Ack: conn.clientSeq,
+	}, nil); err != nil {
+		return nil, err
+	}
+	conn.serverSeq++
+	if err := c.segment(from, to, layers.TCP{
+		ACK: true, Seq: conn.clientSeq, Ack: conn.serverSeq,
+	}, nil); err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+func parseEndpoint(address string) (endpoint, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return endpoint{}, errors.Wrap(err, "endpoint")
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return endpoint{}, errors.Errorf("endpoint: bad ip address %q", host)
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return endpoint{}, errors.Wrap(err, "endpoint")
+	}
+	// a locally administered mac address made from the ip address, so
+	// each host has its own.
+	mac := net.HardwareAddr{0x02, 0x00, 0, 0, 0, 0}
+	copy(mac[2:], ip[len(ip)-4:])
+	return endpoint{mac: mac, ip: ip, port: uint16(p)}, nil
+}
+
+// segment frames a TCP segment up and adds it to the capture.
+func (c *Capture) segment(from, to endpoint, tcp layers.TCP, payload []byte) error {
+	tcp.SrcPort = layers.TCPPort(from.port)
+	tcp.DstPort = layers.TCPPort(to.port)
+	tcp.Window = window
+	eth := layers.Ethernet{SrcMAC: from.mac, DstMAC: to.mac}
+	var network gopacket.SerializableLayer
+	if ip4 := from.ip.To4(); ip4 != nil {
+		eth.EthernetType = layers.EthernetTypeIPv4
+		c.ipID++
+		ip := &layers.IPv4{
+			Version:  4, //nolint:mnd
+			Id:       c.ipID,
+			Flags:    layers.IPv4DontFragment,
+			TTL:      ttl,
+			Protocol: layers.IPProtocolTCP,
+			SrcIP:    ip4,
+			DstIP:    to.ip.To4(),
+		}
+		if err := tcp.SetNetworkLayerForChecksum(ip); err != nil {
+			return errors.Wrap(err, "segment")
+		}
+		network = ip
+	} else {
+		eth.EthernetType = layers.EthernetTypeIPv6
+		ip := &layers.IPv6{
+			Version:    6, //nolint:mnd
+			NextHeader: layers.IPProtocolTCP,
+			HopLimit:   ttl,
+			SrcIP:      from.ip,
+			DstIP:      to.ip,
+		}
+		if err := tcp.SetNetworkLayerForChecksum(ip); err != nil {
+			return errors.Wrap(err, "segment")
+		}
+		network = ip
+	}
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	if err := gopacket.SerializeLayers(
+		buf, opts, &eth, network, &tcp, gopacket.Payload(payload),
+	); err != nil {
+		return errors.Wrap(err, "segment")
+	}
+	c.frames = append(c.frames, frame{seen: c.now, data: buf.Bytes()})
+	c.now = c.now.Add(c.Gap)
+	return nil
+}
+
+// Connection is a MySQL connection in the capture.  The client and server
+// take turns, each request followed by its responses, and the packets
+// are numbered as MySQL would.  Compression is switched on when the login
+// asks for it.
+type Connection struct {
+	// MSS is the most data sent in one segment.
+	MSS int
+
+	capture            *Capture
+	client             endpoint
+	server             endpoint
+	clientSeq          uint32
+	serverSeq          uint32
+	buffer             bytes.Buffer
+	requests           *encoder.Encoder
+	responses          *encoder.Encoder
+	awaitingResponse   bool
+	binary             bool
+	compressResponses  bool
+	compressAfterReply bool
+}
+
+// Option changes how the data for a single call is sent.
+type Option func(*send)
+
+type send struct {
+	cuts       []int
+	outOfOrder bool
+	retransmit bool
+}
+
+// SplitAt cuts the data into segments at the offsets given, as well as
+// wherever the MSS requires.  SplitAt(2) splits the first packet header.
+func SplitAt(offsets ...int) Option {
+	return func(s *send) {
+		s.cuts = append(s.cuts, offsets...)
+	}
+}
+
+// OutOfOrder captures the segments in reverse order.
+func OutOfOrder() Option {
+	return func(s *send) {
+		s.outOfOrder = true
+	}
+}
+
+// Retransmit sends the first segment again after the rest, as happens
+// when an acknowledgement goes missing.
+func Retransmit() Option {
+	return func(s *send) {
+		s.retransmit = true
+	}
+}
+
+// Greeting sends the server's initial handshake.
+func (c *Connection) Greeting(g structure.Greeting, h encoder.Handshake, opts ...Option) error {
+	c.responses.SetSequence(0)
+	if err := c.responses.WriteGreeting(g, h); err != nil {
+		return err
+	}
+	return c.sendResponse(opts)
+}
+
+// Login sends the client's reply to the greeting.  The capabilities in it
+// are used for the rest of the connection.
+func (c *Connection) Login(l structure.LoginRequest, opts ...Option) error {
+	c.requests.Capabilities = l.ClientCapabilities
+	c.responses.Capabilities = l.ClientCapabilities
+	c.requests.SetSequence(c.responses.Sequence())
+	if err := c.requests.WriteLogin(l); err != nil {
+		return err
+	}
+	if err := c.sendRequest(opts); err != nil {
+		return err
+	}
+	// the client compresses from here on, but the server's reply to the
+	// login isn't compressed.
+	if l.ClientCapabilities&structure.CCAP_COMPRESS != 0 {
+		c.requests.Compress = true
+		c.compressAfterReply = true
+	}
+	return nil
+}
+
+// Request sends a command, either a structure.Request or a
+// structure.ExecuteRequest.
+func (c *Connection) Request(r interface{}, opts ...Option) error {
+	var err error
+	c.binary = false
+	switch r := r.(type) {
+	case structure.Request:
+		err = c.requests.WriteRequest(r)
+	case structure.ExecuteRequest:
+		c.binary = true
+		err = c.requests.WriteExecute(r)
+	default:
+		return errors.Wrapf(errUnexpectedType, "request %T", r)
+	}
+	if err != nil {
+		return err
+	}
+	return c.sendRequest(opts)
+}
+
+// Respond sends the server's responses to the last request.  It can be
+// called more than once to send them in separate writes, the numbering
+// carries on.  Result sets to an execute are sent as binary rows.
+func (c *Connection) Respond(responses []interface{}, opts ...Option) error {
+	if c.awaitingResponse {
+		c.responses.SetSequence(c.requests.Sequence())
+		c.responses.Compress = c.compressResponses
+		c.awaitingResponse = false
+	}
+	for _, r := range responses {
+		var err error
+		switch r := r.(type) {
+		case structure.OKResponse:
+			err = c.responses.WriteOK(r)
+		case structure.ErrorResponse:
+			err = c.responses.WriteError(r)
+		case structure.PrepareOKResponse:
+			err = c.responses.WritePrepareOK(r)
+		case structure.ResultSetResponse:
+			err = c.responses.WriteResultSet(r, c.binary)
+		default:
+			return errors.Wrapf(errUnexpectedType, "response %T", r)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := c.sendResponse(opts); err != nil {
+		return err
+	}
+	if c.compressAfterReply {
+		c.compressResponses = true
+		c.compressAfterReply = false
+	}
+	return nil
+}
+
+// ClientData sends bytes from the client as they are, for things the
+// encoder can't produce.
+func (c *Connection) ClientData(data []byte, opts ...Option) error {
+	return c.send(true, data, opts)
+}
+
+// ServerData sends bytes from the server as they are.
+func (c *Connection) ServerData(data []byte, opts ...Option) error {
+	return c.send(false, data, opts)
+}
+
+// Close shuts the connection down from the client end.
+func (c *Connection) Close() error {
+	if err := c.capture.segment(c.client, c.server, layers.TCP{
+		FIN: true, ACK: true, Seq: c.clientSeq, Ack: c.serverSeq,
+	}, nil); err != nil {
+		return err
+	}
+	c.clientSeq++
+	if err := c.capture.segment(c.server, c.client, layers.TCP{
+		FIN: true, ACK: true, Seq: c.serverSeq, Ack: c.clientSeq,
+	}, nil); err != nil {
+		return err
+	}
+	c.serverSeq++
+	return c.capture.segment(c.client, c.server, layers.TCP{
+		ACK: true, Seq: c.clientSeq, Ack: c.serverSeq,
+	}, nil)
+}
+
+// Reset aborts the connection from the client end.
+func (c *Connection) Reset() error {
+	return c.capture.segment(c.client, c.server, layers.TCP{RST: true, Seq: c.clientSeq}, nil)
+}
+
+func (c *Connection) sendRequest(opts []Option) error {
+	c.awaitingResponse = true
+	return c.sendBuffer(true, opts)
+}
+
+func (c *Connection) sendResponse(opts []Option) error {
+	return c.sendBuffer(false, opts)
+}
+
+func (c *Connection) sendBuffer(client bool, opts []Option) error {
+	data := append([]byte(nil), c.buffer.Bytes()...)
+	c.buffer.Reset()
+	return c.send(client, data, opts)
+}
+
+type segment struct {
+	seq  uint32
+	data []byte
+}
+
+// send puts the data in segments and has the other side acknowledge them.
+func (c *Connection) send(client bool, data []byte, opts []Option) error {
+	var s send
+	for _, o := range opts {
+		o(&s)
+	}
+	from, to, seq, ack := c.client, c.server, &c.clientSeq, c.serverSeq
+	if !client {
+		from, to, seq, ack = c.server, c.client, &c.serverSeq, c.clientSeq
+	}
+
+	segments := c.segments(*seq, data, s.cuts)
+	ordered := segments
+	if s.outOfOrder {
+		ordered = make([]segment, len(segments))
+		for i, seg := range segments {
+			ordered[len(segments)-1-i] = seg
+		}
+	}
+	if s.retransmit && len(segments) > 0 {
+		ordered = append(ordered, segments[0])
+	}
+	for _, seg := range ordered {
+		if err := c.capture.segment(from, to, layers.TCP{
+			ACK: true, PSH: true, Seq: seg.seq, Ack: ack,
+		}, seg.data); err != nil {
+			return err
+		}
+	}
+	*seq += uint32(len(data)) //nolint:gosec
+	return c.capture.segment(to, from, layers.TCP{ACK: true, Seq: ack, Ack: *seq}, nil)
+}
+
+// segments cuts the data up at the offsets given and to fit the MSS.
+func (c *Connection) segments(seq uint32, data []byte, cuts []int) []segment {
+	var segments []segment
+	start := 0
+	next := func(end int) {
+		for start < end {
+			size := end - start
+			if c.MSS > 0 && size > c.MSS {
+				size = c.MSS
+			}
+			segments = append(segments, segment{
+				seq:  seq + uint32(start), //nolint:gosec
+				data: data[start : start+size],
+			})
+			start += size
+		}
+	}
+	cuts = append([]int(nil), cuts...)
+	sort.Ints(cuts)
+	for _, cut := range cuts {
+		if cut > start && cut < len(data) {
+			next(cut)
+		}
+	}
+	next(len(data))
+	return segments
+}
@@ -0,0 +1,281 @@
+package pcapgen_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/pcapgen"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+const edgeCasesCapture = "../../../test/captures/edge-cases.pcap"
+
+var update = flag.Bool("update", false, "Regenerate "+edgeCasesCapture)
+
+var columns = []structure.ColumnInfo{
+	pcapgen.DemoColumn("id", structure.LONG, structure.DETAIL_NOT_NULL|structure.DETAIL_PRIMARY_KEY),
+	pcapgen.DemoColumn("name", structure.VAR_STRING, 0),
+}
+
+// conversations builds the same connections with or without the edge
+// cases, which shouldn't change what comes out of the other end.
+func conversations(t *testing.T, edge bool) *pcapgen.Capture {
+	t.Helper()
+
+	opts := func(o ...pcapgen.Option) []pcapgen.Option {
+		if edge {
+			return o
+		}
+		return nil
+	}
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	ok := structure.OKResponse{ServerStatus: structure.SERVER_STATUS_AUTOCOMMIT}
+	id, name := "1", "Jo"
+
+	capture := pcapgen.New(pcapgen.DemoStart)
+	c, err := capture.Connect("10.0.0.2:50000", "10.0.0.1:3306")
+	must(err)
+	if edge {
+		c.MSS = 20
+	}
+	caps := pcapgen.DemoGreeting(false).Capabilities
+	must(c.Greeting(pcapgen.DemoGreeting(false), pcapgen.DemoHandshake, opts(pcapgen.SplitAt(2))...))
+	must(c.Login(pcapgen.DemoLogin(caps), opts(pcapgen.SplitAt(1, 3))...))
+	must(c.Respond([]interface{}{ok}))
+	must(c.Request(structure.Request{Type: "Query", Query: "SELECT * FROM peeps"},
+		opts(pcapgen.OutOfOrder())...))
+	must(c.Respond([]interface{}{structure.ResultSetResponse{
+		Columns: columns,
+		Results: [][]interface{}{{&id, &name}},
+	}}, opts(pcapgen.OutOfOrder(), pcapgen.Retransmit())...))
+	must(c.Request(structure.Request{Type: "Prepare", Query: "SELECT * FROM peeps WHERE id = ?"}))
+	param := pcapgen.DemoColumn("?", structure.LONGLONG, structure.DETAIL_BINARY_COLLATION)
+	must(c.Respond([]interface{}{structure.PrepareOKResponse{
+		StatementID: 1,
+		NumColumns:  uint16(len(columns)),
+		NumParams:   1,
+		Columns:     columns,
+		Params:      []structure.ColumnInfo{param},
+	}}, opts(pcapgen.Retransmit())...))
+	must(c.Request(structure.ExecuteRequest{
+		StatementID:    1,
+		IterationCount: 1,
+		Params:         []interface{}{int64(1)},
+	}, opts(pcapgen.Retransmit())...))
+	must(c.Respond([]interface{}{structure.ResultSetResponse{
+		Columns: columns,
+		Results: [][]interface{}{{int32(1), "Jo"}},
+	}}, opts(pcapgen.SplitAt(4, 5))...))
+	must(c.Request(structure.Request{Type: "QUIT"}))
+	must(c.Close())
+
+	capture.Wait(time.Second)
+	c, err = capture.Connect("10.0.0.3:50001", "10.0.0.1:3306")
+	must(err)
+	if edge {
+		c.MSS = 30
+	}
+	caps = pcapgen.DemoGreeting(true).Capabilities
+	long := strings.Repeat("compress me ", 20)
+	must(c.Greeting(pcapgen.DemoGreeting(true), pcapgen.DemoHandshake))
+	must(c.Login(pcapgen.DemoLogin(caps)))
+	must(c.Respond([]interface{}{ok}))
+	must(c.Request(structure.Request{Type: "Query", Query: "SELECT '" + long + "'"},
+		opts(pcapgen.SplitAt(3, 6))...))
+	must(c.Respond([]interface{}{structure.ResultSetResponse{
+		Columns: columns[1:],
+		Results: [][]interface{}{{&long}},
+	}}, opts(pcapgen.SplitAt(2, 7), pcapgen.OutOfOrder())...))
+	must(c.Request(structure.Request{Type: "QUIT"}))
+	must(c.Close())
+	return capture
+}
+
+type packetInfo struct {
+	seen    time.Time
+	network gopacket.Flow
+	tcp     *layers.TCP
+}
+
+func readCapture(t *testing.T, capture *pcapgen.Capture) []packetInfo {
+	t.Helper()
+
+	var packets []packetInfo
+	for _, p := range readPackets(t, capture) {
+		tcp, ok := p.TransportLayer().(*layers.TCP)
+		if !ok {
+			t.Fatalf("expected tcp, got %s", p)
+		}
+		packets = append(packets, packetInfo{
+			seen:    p.Metadata().Timestamp,
+			network: p.NetworkLayer().NetworkFlow(),
+			tcp:     tcp,
+		})
+	}
+	return packets
+}
+
+func readPackets(t *testing.T, capture *pcapgen.Capture) []gopacket.Packet {
+	t.Helper()
+
+	var b bytes.Buffer
+	if err := capture.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	r, err := pcapgo.NewReader(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var packets []gopacket.Packet
+	for p := range gopacket.NewPacketSource(r, r.LinkType()).Packets() {
+		if err := p.ErrorLayer(); err != nil {
+			t.Fatal(err.Error())
+		}
+		packets = append(packets, p)
+	}
+	return packets
+}
+
+// reassemble puts the streams in the capture back together.
+func reassemble(t *testing.T, capture *pcapgen.Capture) map[string]string {
+	t.Helper()
+
+	return pcapgen.Reassemble(readPackets(t, capture))
+}
+
+func TestEdgeCasesReassemble(t *testing.T) {
+	plain := reassemble(t, conversations(t, false))
+	edge := reassemble(t, conversations(t, true))
+	if len(plain) != 4 {
+		t.Fatalf("expected 4 streams, got %d", len(plain))
+	}
+	if diff := cmp.Diff(edge, plain); diff != "" {
+		t.Errorf("Streams don't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestEdgeCasesSegments(t *testing.T) {
+	plain := readCapture(t, conversations(t, false))
+	edge := readCapture(t, conversations(t, true))
+	if len(edge) <= len(plain) {
+		t.Errorf("expected more segments with the edge cases, %d vs %d", len(edge), len(plain))
+	}
+
+	var outOfOrder, retransmits int
+	next := make(map[string]uint32)
+	for _, p := range edge {
+		if len(p.tcp.Payload) == 0 {
+			continue
+		}
+		key := p.network.Src().String() + ":" + p.tcp.SrcPort.String()
+		expected, seen := next[key]
+		switch {
+		case !seen || p.tcp.Seq == expected:
+		case p.tcp.Seq > expected:
+			outOfOrder++
+		default:
+			retransmits++
+		}
+		if end := p.tcp.Seq + uint32(len(p.tcp.Payload)); !seen || end > expected {
+			next[key] = end
+		}
+	}
+	if outOfOrder == 0 {
+		t.Error("expected segments out of order")
+	}
+	if retransmits == 0 {
+		t.Error("expected retransmitted segments")
+	}
+}
+
+func TestConnect(t *testing.T) {
+	capture := pcapgen.New(pcapgen.DemoStart)
+	capture.Gap = time.Microsecond
+	c, err := capture.Connect("[2001:db8::2]:50000", "[2001:db8::1]:3306")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ServerData([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	type summary struct {
+		Seen          time.Time
+		From          string
+		SYN, ACK, FIN bool
+		Payload       string
+	}
+	var got []summary
+	for _, p := range readCapture(t, capture) {
+		got = append(got, summary{
+			Seen:    p.seen,
+			From:    p.network.Src().String() + ":" + p.tcp.SrcPort.String(),
+			SYN:     p.tcp.SYN,
+			ACK:     p.tcp.ACK,
+			FIN:     p.tcp.FIN,
+			Payload: string(p.tcp.Payload),
+		})
+	}
+	client, server := "2001:db8::2:50000", "2001:db8::1:3306(mysql)"
+	at := func(n int) time.Time {
+		return pcapgen.DemoStart.Add(time.Duration(n) * time.Microsecond)
+	}
+	expected := []summary{
+		{Seen: at(0), From: client, SYN: true},
+		{Seen: at(1), From: server, SYN: true, ACK: true},
+		{Seen: at(2), From: client, ACK: true},
+		{Seen: at(3), From: server, ACK: true, Payload: "hello"},
+		{Seen: at(4), From: client, ACK: true},
+		{Seen: at(5), From: client, ACK: true, FIN: true},
+		{Seen: at(6), From: server, ACK: true, FIN: true},
+		{Seen: at(7), From: client, ACK: true},
+	}
+	if diff := cmp.Diff(got, expected); diff != "" {
+		t.Errorf("Packets don't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestConnectBadAddress(t *testing.T) {
+	if _, err := pcapgen.New(pcapgen.DemoStart).Connect("10.0.0.2", "10.0.0.1:3306"); err == nil {
+		t.Error("expected an error for an address without a port")
+	}
+}
+
+// TestEdgeCasesCapture keeps test/captures/edge-cases.pcap up to date, that
+// is checked end to end with the other captures.  Run with -update to
+// write it out again after changing the conversations.
+func TestEdgeCasesCapture(t *testing.T) {
+	var b bytes.Buffer
+	if err := conversations(t, true).Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	if *update {
+		if err := os.WriteFile(edgeCasesCapture, b.Bytes(), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(edgeCasesCapture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b.Bytes(), expected) {
+		t.Errorf("%s is out of date, run the tests with -update", edgeCasesCapture)
+	}
+}
@@ -0,0 +1,117 @@
+package redact
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/sqltext"
+)
+
+// The fitted redactions keep the length of what they replace, for rewriting
+// captures where the data has to take up the same space.  Hashes are made
+// from the keyed hash so the same value still comes out the same way, but
+// short values will clash.  Numbers and dates stay numbers and dates, with
+// their digits replaced.  Drop can't keep the length.
+
+// FitSQL redacts the literals in the query keeping each the length it was.
+// Drop replaces them with ? as SQL does.
+func (o Options) FitSQL(query string) string {
+	if o.Literals == Keep || query == "" {
+		return query
+	}
+	return sqltext.ReplaceLiterals(query, o.fitLiteral)
+}
+
+func (o Options) fitLiteral(literal string) string {
+	switch o.Literals {
+	case Mask:
+		return MaskLiteral(literal)
+	case Drop:
+		return "?"
+	case Keep:
+		return literal
+	}
+	switch {
+	case literal[0] == '\'' || literal[0] == '"':
+		end := len(literal)
+		if end > 1 && literal[end-1] == literal[0] {
+			end--
+		}
+		return literal[:1] + o.fit(sqltext.Unquote(literal), end-1, false) + literal[end:]
+	case len(literal) > 1 && literal[1] == '\'':
+		end := len(literal)
+		if end > 2 && literal[end-1] == '\'' {
+			end--
+		}
+		digits := hexDigits
+		if literal[0] == 'b' || literal[0] == 'B' {
+			digits = "01"
+		}
+		return literal[:2] + o.digits(literal, literal[2:end], digits) + literal[end:]
+	}
+	return o.fit(literal, len(literal), true)
+}
+
+// FitValue redacts a value sent as text keeping its length.  Drop gives nil.
+func (o Options) FitValue(action Action, v []byte) []byte {
+	switch action {
+	case Keep:
+		return v
+	case Mask:
+		if numeric(string(v)) {
+			return []byte(o.digits("", string(v), "0"))
+		}
+		return []byte(strings.Repeat("*", len(v)))
+	case Hash:
+		return []byte(o.fit(string(v), len(v), numeric(string(v))))
+	}
+	return nil
+}
+
+const hexDigits = "0123456789abcdef"
+
+// fit hashes s into n characters, digits for numbers and hex for anything
+// else.
+func (o Options) fit(s string, n int, number bool) string {
+	if number && n == len(s) {
+		return o.digits(s, s, "0123456789")
+	}
+	h := hex.EncodeToString(o.Digest(s))
+	return strings.Repeat(h, n/len(h)+1)[:n]
+}
+
+// digits replaces the digits in template with ones picked by the hash of s,
+// leaving any punctuation.  With the hex alphabet the hex digits are
+// replaced.
+func (o Options) digits(s, template, alphabet string) string {
+	digest := o.Digest(s)
+	out := []byte(template)
+	n := 0
+	for i, c := range out {
+		if !isDigit(c) && (len(alphabet) != len(hexDigits) || !strings.ContainsRune("abcdefABCDEF", rune(c))) {
+			continue
+		}
+		out[i] = alphabet[int(digest[n%len(digest)])%len(alphabet)]
+		n++
+	}
+	return string(out)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// numeric is true for text that looks like a number or a date.
+func numeric(s string) bool {
+	digit := false
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			digit = true
+		case strings.ContainsRune("+-.:eE ", c):
+		default:
+			return false
+		}
+	}
+	return digit
+}
@@ -0,0 +1,427 @@
+// Package redact removes customer data from the decoded connections so that
+// transcripts can be shared.  Values can be hashed, so the same value
+// always comes out the same way and joins across rows are still visible,
+// masked, or dropped altogether.
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/sqltext"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+// Action says what to do with a value.
+type Action int
+
+const (
+	// Keep leaves the value alone.
+	Keep Action = iota
+	// Hash replaces the value with a keyed hash of it.
+	Hash
+	// Mask replaces each character with *, or each digit with 0 in SQL.
+	Mask
+	// Drop replaces the value with null, or ? in SQL.
+	Drop
+)
+
+var errUnknownAction = errors.New("unknown redaction")
+
+// ParseAction converts the name of an action.
+func ParseAction(s string) (Action, error) {
+	switch s {
+	case "keep":
+		return Keep, nil
+	case "hash":
+		return Hash, nil
+	case "mask":
+		return Mask, nil
+	case "drop":
+		return Drop, nil
+	}
+	return Keep, errors.Wrap(errUnknownAction, s)
+}
+
+// Rule is the action for the result set columns matching the pattern.  The
+// pattern is schema.table.column, and each part can use the wildcards
+// path.Match does, which don't match across the dots.
+type Rule struct {
+	Pattern string
+	Action  Action
+}
+
+var errBadRule = errors.New("rules look like schema.table.column=action")
+
+// ParseRule parses a rule in the form schema.table.column=action.
+func ParseRule(s string) (Rule, error) {
+	i := strings.LastIndex(s, "=")
+	if i < 0 || strings.Count(s[:i], ".") != 2 {
+		return Rule{}, errors.Wrap(errBadRule, s)
+	}
+	if _, err := path.Match(parts(s[:i]), ""); err != nil {
+		return Rule{}, errors.Wrap(errBadRule, s)
+	}
+	action, err := ParseAction(s[i+1:])
+	return Rule{Pattern: s[:i], Action: action}, err
+}
+
+// Options say what to redact.
+type Options struct {
+	// Literals is for the literals in SQL and the quoted parts of error
+	// messages.
+	Literals Action
+	// Values is for the values in result sets, execute parameters, query
+	// attributes and session state changes.  Rules override it for result set columns, the
+	// first matching rule wins.
+	Values Action
+	Rules  []Rule
+	// Raw drops the raw packet data and the authentication data.
+	Raw bool
+	// Key is used for the hashes, so they can't be reversed by hashing
+	// likely values without it.
+	Key []byte
+}
+
+// Active is true when there's something to redact.
+func (o Options) Active() bool {
+	return o.Literals != Keep || o.Values != Keep || len(o.Rules) > 0 || o.Raw
+}
+
+// Apply passes on what comes through completed with the connections
+// redacted.
+func Apply(o Options, completed chan interface{}) chan interface{} {
+	redacted := make(chan interface{})
+	go func() {
+		defer close(redacted)
+		for c := range completed {
+			if conn, ok := c.(structure.Connection); ok {
+				var err error
+				if c, err = o.Connection(conn); err != nil {
+					log.Fatal(err)
+				}
+			}
+			redacted <- c
+		}
+	}()
+	return redacted
+}
+
+// Connection redacts a connection.  Spilled rows are redacted into new
+// spilled rows.
+func (o Options) Connection(c structure.Connection) (structure.Connection, error) {
+	items := make([]structure.Transmission, len(c.Items))
+	for i, t := range c.Items {
+		var err error
+		if t.Data, err = o.transmission(t.Data); err != nil {
+			return c, err
+		}
+		items[i] = t
+	}
+	c.Items = items
+	if o.Raw {
+		c.RawRequestPackets = nil
+		c.RawResponsePackets = nil
+	}
+	return c, nil
+}
+
+//nolint:gocognit
+func (o Options) transmission(data interface{}) (interface{}, error) {
+	if raw, ok := data.(structure.WithRawPacket); ok {
+		t, err := o.transmission(raw.Transmission)
+		if o.Raw {
+			return t, err
+		}
+		raw.Transmission = t
+		return raw, err
+	}
+	switch v := data.(type) {
+	case structure.Request:
+		v.Query = o.SQL(v.Query)
+		v.Attributes = o.attributes(v.Attributes)
+		return v, nil
+	case structure.ExecuteRequest:
+		v.Query = o.SQL(v.Query)
+		params := make([]interface{}, len(v.Params))
+		for i, p := range v.Params {
+			params[i] = o.Value(o.Values, p)
+		}
+		v.Params = params
+		v.Attributes = o.attributes(v.Attributes)
+		if v.SQL != "" {
+			v.SQL = sqltext.Inline(v.Query, v.Params)
+		}
+		return v, nil
+	case structure.ErrorResponse:
+		v.Message = o.Message(v.Message)
+		return v, nil
+	case structure.OKResponse:
+		v.StateChanges = o.stateChanges(v.StateChanges)
+		return v, nil
+	case structure.ResultSetResponse:
+		actions := o.ColumnActions(v.Columns)
+		results := make([][]interface{}, len(v.Results))
+		for i, row := range v.Results {
+			results[i] = o.row(actions, row)
+		}
+		v.Results = results
+		if v.SpilledResults.Len() > 0 {
+			spilled, err := v.SpilledResults.Map(func(row []interface{}) []interface{} {
+				return o.row(actions, row)
+			})
+			if err != nil {
+				return v, errors.Wrap(err, "redact-spilled-results")
+			}
+			v.SpilledResults = spilled
+		}
+		return v, nil
+	}
+	if !o.Raw {
+		return data, nil
+	}
+	switch v := data.(type) {
+	case structure.LoginRequest:
+		v.AuthData = nil
+		return v, nil
+	case structure.ChangeUserRequest:
+		v.AuthData = nil
+		return v, nil
+	case structure.AuthResponse:
+		v.Data = nil
+		return v, nil
+	case structure.AuthSwitchResponse:
+		v.Data = nil
+		return v, nil
+	case structure.AuthMoreDataResponse:
+		v.Data = nil
+		return v, nil
+	case structure.DecodeError:
+		v.Packet = nil
+		return v, nil
+	}
+	return data, nil
+}
+
+func (o Options) attributes(attributes map[string]interface{}) map[string]interface{} {
+	if attributes == nil {
+		return nil
+	}
+	redacted := make(map[string]interface{}, len(attributes))
+	for k, v := range attributes {
+		redacted[k] = o.Value(o.Values, v)
+	}
+	return redacted
+}
+
+func (o Options) stateChanges(changes []structure.StateChange) []structure.StateChange {
+	if o.Values == Keep || changes == nil {
+		return changes
+	}
+	redacted := make([]structure.StateChange, len(changes))
+	for i, c := range changes {
+		// dropped values come back as nil, which leaves them empty.
+		c.Value, _ = o.Value(o.Values, c.Value).(string)
+		redacted[i] = c
+	}
+	return redacted
+}
+
+// ColumnActions works out the action for each column from the rules.
+func (o Options) ColumnActions(columns []structure.ColumnInfo) []Action {
+	actions := make([]Action, len(columns))
+	for i, c := range columns {
+		actions[i] = o.Values
+		// the original names when there are some, derived columns
+		// only have the aliases.
+		table, column := c.Table, c.Column
+		if table == "" {
+			table = c.TableAlias
+		}
+		if column == "" {
+			column = c.ColumnAlias
+		}
+		name := c.Schema + "/" + table + "/" + column
+		for _, r := range o.Rules {
+			if ok, _ := path.Match(parts(r.Pattern), name); ok {
+				actions[i] = r.Action
+				break
+			}
+		}
+	}
+	return actions
+}
+
+// parts separates the parts of a pattern with / so path.Match treats them
+// separately.
+func parts(pattern string) string {
+	return strings.ReplaceAll(pattern, ".", "/")
+}
+
+func (o Options) row(actions []Action, row []interface{}) []interface{} {
+	redacted := make([]interface{}, len(row))
+	for i, v := range row {
+		action := o.Values
+		if i < len(actions) {
+			action = actions[i]
+		}
+		redacted[i] = o.Value(action, v)
+	}
+	return redacted
+}
+
+// Value redacts a single value.  Nulls stay null.
+func (o Options) Value(action Action, v interface{}) interface{} {
+	if action == Keep || v == nil {
+		return v
+	}
+	if s, ok := v.(*string); ok {
+		if s == nil {
+			return v
+		}
+		v = *s
+	}
+	switch action {
+	case Hash:
+		return o.hash(text(v))
+	case Mask:
+		return strings.Repeat("*", len(text(v)))
+	}
+	return nil
+}
+
+// SQL redacts the literals in the query.
+func (o Options) SQL(query string) string {
+	if o.Literals == Keep || query == "" {
+		return query
+	}
+	return sqltext.ReplaceLiterals(query, o.literal)
+}
+
+// Message redacts the quoted parts of an error message, which is where the
+// server puts the values from the query, as in "Duplicate entry 'x' for key
+// 'y'".  They're treated as literals.  The messages aren't escaped, so a
+// quote only opens a part at the start of a word and closes it at the end
+// of one, which keeps the quotes in the SQL quoted after "near" together.
+func (o Options) Message(message string) string {
+	if o.Literals == Keep {
+		return message
+	}
+	var sb strings.Builder
+	for i := 0; i < len(message); i++ {
+		c := message[i]
+		if c != '\'' || (i > 0 && isWordChar(message[i-1])) {
+			sb.WriteByte(c)
+			continue
+		}
+		end := closingQuote(message, i)
+		if end < 0 {
+			sb.WriteString(message[i:])
+			break
+		}
+		sb.WriteString(o.literal(message[i : end+1]))
+		i = end
+	}
+	return sb.String()
+}
+
+// closingQuote finds the quote closing the one at i, the first followed by
+// something other than a word or another quote, or -1 when there isn't one.
+func closingQuote(message string, i int) int {
+	for j := i + 1; j < len(message); j++ {
+		if message[j] == '\'' && (j+1 == len(message) || (message[j+1] != '\'' && !isWordChar(message[j+1]))) {
+			return j
+		}
+	}
+	return -1
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c >= 0x80 || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+func (o Options) literal(literal string) string {
+	switch o.Literals {
+	case Hash:
+		return sqltext.Quote(o.hash(sqltext.Unquote(literal)))
+	case Mask:
+		return MaskLiteral(literal)
+	case Keep:
+		return literal
+	}
+	return "?"
+}
+
+// MaskLiteral masks a literal keeping its length and form, so strings stay
+// strings and numbers numbers.
+func MaskLiteral(literal string) string {
+	switch {
+	case literal[0] == '\'' || literal[0] == '"':
+		return maskQuoted(literal, 1, "*")
+	case len(literal) > 1 && literal[1] == '\'':
+		// x'..' and b'..'
+		return maskQuoted(literal, 2, "0")
+	}
+	return strings.Repeat("0", len(literal))
+}
+
+// maskQuoted replaces everything after the prefix up to the closing quote.
+func maskQuoted(literal string, prefix int, mask string) string {
+	end := len(literal)
+	if end > prefix && literal[end-1] == literal[prefix-1] {
+		end--
+	}
+	return literal[:prefix] + strings.Repeat(mask, end-prefix) + literal[end:]
+}
+
+func (o Options) hash(s string) string {
+	//nolint:mnd
+	return hex.EncodeToString(o.Digest(s)[:8])
+}
+
+// Digest is the keyed hash the hashes are made from.
+func (o Options) Digest(s string) []byte {
+	h := hmac.New(sha256.New, o.Key)
+	_, _ = h.Write([]byte(s))
+	return h.Sum(nil)
+}
+
+// text gives the value as the text it would be sent as, so that the same
+// value hashes the same way whichever protocol it was sent with.
+func text(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case struct{ Text string }:
+		return v.Text
+	case struct{ Base64 []byte }:
+		return string(v.Base64)
+	case json.Number:
+		return v.String()
+	case map[string]interface{}:
+		// the Text and Base64 values read back from spilled rows.
+		if t, ok := v["Text"].(string); ok {
+			return t
+		}
+		if b, ok := v["Base64"].(string); ok {
+			if data, err := base64.StdEncoding.DecodeString(b); err == nil {
+				return string(data)
+			}
+		}
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,250 @@
+package redact_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/redact"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/spill"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+func column(schema, table, name string) structure.ColumnInfo {
+	return structure.ColumnInfo{Schema: schema, Table: table, TableAlias: table, Column: name, ColumnAlias: name}
+}
+
+func TestConnection(t *testing.T) {
+	s := spill.New(1, t.TempDir())
+	defer s.Close()
+	spilled := &spill.Rows{}
+	if err := spilled.Add(s, []interface{}{"2", "bob@example.com", "secret"}); err != nil {
+		t.Fatal(err)
+	}
+	email := "bob@example.com"
+	c := structure.Connection{
+		Items: []structure.Transmission{
+			{Data: structure.LoginRequest{Type: "Login", Username: "site", AuthData: []byte{1, 2}}},
+			{Data: structure.WithRawPacket{
+				RawData:      []byte{3, 4},
+				Transmission: structure.Request{Type: "Query", Query: "SELECT * FROM users WHERE email = 'bob@example.com'"},
+			}},
+			{Data: structure.ResultSetResponse{
+				Type: "SQL results",
+				Columns: []structure.ColumnInfo{
+					column("demo", "users", "id"),
+					column("demo", "users", "email"),
+					column("demo", "users", "password"),
+				},
+				Results:        [][]interface{}{{int64(1), &email, nil}},
+				SpilledResults: spilled,
+			}},
+			{Data: structure.ExecuteRequest{
+				Type:   "Execute",
+				Params: []interface{}{"bob@example.com", int32(2)},
+				Query:  "UPDATE users SET n = 1 WHERE email = ? AND id = ?",
+				SQL:    "UPDATE users SET n = 1 WHERE email = 'bob@example.com' AND id = 2",
+			}},
+		},
+	}
+	o := redact.Options{
+		Literals: redact.Hash,
+		Values:   redact.Hash,
+		Rules: []redact.Rule{
+			{Pattern: "demo.*.id", Action: redact.Keep},
+			{Pattern: "*.users.password", Action: redact.Drop},
+		},
+		Raw: true,
+	}
+	redacted, err := o.Connection(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(redacted.Items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	hash := o.Value(redact.Hash, "bob@example.com")
+	expected := []map[string]interface{}{
+		{"Data": map[string]interface{}{
+			"Type": "Login", "ClientCapabilities": "0: ", "Collation": float64(0),
+			"ExtendedCapabilities": float64(0), "MaxPacketSize": float64(0), "Username": "site",
+		}, "Seen": nil},
+		{"Data": map[string]interface{}{
+			"Type": "Query", "Query": "SELECT * FROM users WHERE email = '" + hash.(string) + "'",
+		}, "Seen": nil},
+		{"Data": map[string]interface{}{
+			"Type":    "SQL results",
+			"Columns": got[2]["Data"].(map[string]interface{})["Columns"],
+			"Results": []interface{}{
+				[]interface{}{float64(1), hash, nil},
+				[]interface{}{"2", hash, nil},
+			},
+		}, "Seen": nil},
+		{"Data": map[string]interface{}{
+			"Type": "Execute", "StatementID": float64(0), "Flags": float64(0), "IterationCount": float64(0),
+			"NullMap": nil, "Params": []interface{}{hash, o.Value(redact.Hash, "2")},
+			"Query": "UPDATE users SET n = '" + o.Value(redact.Hash, "1").(string) + "' WHERE email = ? AND id = ?",
+			"SQL": "UPDATE users SET n = '" + o.Value(redact.Hash, "1").(string) + "' WHERE email = '" +
+				hash.(string) + "' AND id = '" + o.Value(redact.Hash, "2").(string) + "'",
+		}, "Seen": nil},
+	}
+	if diff := cmp.Diff(got, expected); diff != "" {
+		t.Fatalf("Redacted items don't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestValue(t *testing.T) {
+	o := redact.Options{Key: []byte("key")}
+	text := "1"
+	tests := []struct {
+		action   redact.Action
+		value    interface{}
+		expected interface{}
+	}{
+		{redact.Keep, "secret", "secret"},
+		{redact.Mask, "secret", "******"},
+		{redact.Mask, int64(1234), "****"},
+		{redact.Drop, "secret", nil},
+		{redact.Hash, nil, nil},
+		{redact.Hash, int32(1), o.Value(redact.Hash, &text)},
+		{redact.Hash, struct{ Text string }{Text: "1"}, o.Value(redact.Hash, json.Number("1"))},
+	}
+	for _, test := range tests {
+		if diff := cmp.Diff(o.Value(test.action, test.value), test.expected); diff != "" {
+			t.Errorf("Value %v doesn't match (-got +expected):\n%s\n", test.value, diff)
+		}
+	}
+	if o.Value(redact.Hash, "1") == (redact.Options{}).Value(redact.Hash, "1") {
+		t.Error("The key should change the hash")
+	}
+}
+
+func TestMaskSQL(t *testing.T) {
+	o := redact.Options{Literals: redact.Mask}
+	masked := o.SQL("SELECT * FROM t WHERE a = 'it''s' AND b = 12.5 AND c = x'0f' AND `d1` = ?")
+	expected := "SELECT * FROM t WHERE a = '*****' AND b = 0000 AND c = x'00' AND `d1` = ?"
+	if diff := cmp.Diff(masked, expected); diff != "" {
+		t.Fatalf("Masked SQL doesn't match (-got +expected):\n%s\n", diff)
+	}
+	o.Literals = redact.Drop
+	if diff := cmp.Diff(o.SQL("SELECT 'a', 1"), "SELECT ?, ?"); diff != "" {
+		t.Fatalf("Dropped SQL doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestResponses(t *testing.T) {
+	c := structure.Connection{
+		Items: []structure.Transmission{
+			{Data: structure.ErrorResponse{
+				Type: "Error", Code: 1062, State: "23000", Name: "ER_DUP_ENTRY",
+				Message: "Duplicate entry 'bob@example.com' for key 'users.email'",
+			}},
+			{Data: structure.ErrorResponse{
+				Type: "Error", Code: 1064, State: "42000", Name: "ER_PARSE_ERROR",
+				Message: "You have an error in your SQL syntax; check the manual that corresponds to your " +
+					"MySQL server version for the right syntax to use near 'WHERE email = 'bob'' at line 1",
+			}},
+			{Data: structure.ErrorResponse{
+				Type: "Error", Code: 1049, State: "42000", Name: "ER_BAD_DB_ERROR",
+				Message: "Can't find file: 'x",
+			}},
+			{Data: structure.OKResponse{
+				Type: "OK",
+				StateChanges: []structure.StateChange{
+					{Type: structure.StateSystemVariable, Name: "time_zone", Value: "Europe/London"},
+				},
+			}},
+		},
+	}
+	o := redact.Options{Literals: redact.Mask, Values: redact.Drop}
+	redacted, err := o.Connection(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []interface{}
+	for _, i := range redacted.Items {
+		got = append(got, i.Data)
+	}
+	expected := []interface{}{
+		structure.ErrorResponse{
+			Type: "Error", Code: 1062, State: "23000", Name: "ER_DUP_ENTRY",
+			Message: "Duplicate entry '***************' for key '***********'",
+		},
+		structure.ErrorResponse{
+			Type: "Error", Code: 1064, State: "42000", Name: "ER_PARSE_ERROR",
+			Message: "You have an error in your SQL syntax; check the manual that corresponds to your " +
+				"MySQL server version for the right syntax to use near '*******************' at line 1",
+		},
+		structure.ErrorResponse{
+			Type: "Error", Code: 1049, State: "42000", Name: "ER_BAD_DB_ERROR",
+			Message: "Can't find file: 'x",
+		},
+		structure.OKResponse{
+			Type: "OK",
+			StateChanges: []structure.StateChange{
+				{Type: structure.StateSystemVariable, Name: "time_zone"},
+			},
+		},
+	}
+	if diff := cmp.Diff(got, expected); diff != "" {
+		t.Fatalf("Redacted responses don't match (-got +expected):\n%s\n", diff)
+	}
+	o = redact.Options{Literals: redact.Hash}
+	message := o.Message("Duplicate entry 'bob@example.com' for key 'users.email'")
+	if !strings.Contains(message, o.SQL("'bob@example.com'")) {
+		t.Errorf("The entry should hash the way the literal does: %s", message)
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	r, err := redact.ParseRule("demo.users.email=mask")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(r, redact.Rule{Pattern: "demo.users.email", Action: redact.Mask}); diff != "" {
+		t.Fatalf("Rule doesn't match (-got +expected):\n%s\n", diff)
+	}
+	for _, bad := range []string{"users.email=mask", "demo.users.email=scramble", "demo.[.x=hash"} {
+		if _, err := redact.ParseRule(bad); err == nil {
+			t.Errorf("Expected an error for %s", bad)
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	o := redact.Options{Literals: redact.Hash, Values: redact.Hash, Key: []byte("key")}
+	query := "SELECT * FROM t WHERE a = 'it''s' AND b = 12.5 AND c = x'0f' AND d = ?"
+	fitted := o.FitSQL(query)
+	if len(fitted) != len(query) || fitted == query {
+		t.Errorf("Expected the literals hashed to the same length, got %q", fitted)
+	}
+	if fitted != o.FitSQL(query) {
+		t.Error("Expected the same hash each time")
+	}
+	for _, v := range []string{"1234", "-12.5", "2021-09-25 17:21:23", "secret"} {
+		for _, action := range []redact.Action{redact.Hash, redact.Mask} {
+			f := string(o.FitValue(action, []byte(v)))
+			if len(f) != len(v) || f == v {
+				t.Errorf("Expected %q redacted to the same length, got %q", v, f)
+			}
+			if strings.IndexFunc(v, unicode.IsDigit) == 0 && strings.IndexFunc(f, unicode.IsDigit) != 0 {
+				t.Errorf("Expected %q to still look like a number, got %q", v, f)
+			}
+		}
+	}
+	if o.FitValue(redact.Drop, []byte("secret")) != nil {
+		t.Error("Expected dropped values to be nil")
+	}
+	o.Literals = redact.Mask
+	if diff := cmp.Diff(o.FitSQL("SELECT 'ab', 12"), "SELECT '**', 00"); diff != "" {
+		t.Errorf("Masked SQL doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
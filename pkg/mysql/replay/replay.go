@@ -0,0 +1,318 @@
+package replay
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/transcript"
+)
+
+// Timing says how the requests are spaced out when they are replayed.
+type Timing int
+
+const (
+	// Original sends each request the same time after the start of the
+	// replay as it was sent after the start of the capture.
+	Original Timing = iota
+	// Scaled is the original timing sped up, or slowed down, by the speed.
+	Scaled
+	// Fast sends each request as soon as the previous one on the connection
+	// has been answered.
+	Fast
+)
+
+var errUnknownTiming = errors.New("unknown timing")
+
+// ParseTiming converts the name of a timing mode.
+func ParseTiming(s string) (Timing, error) {
+	switch s {
+	case "original":
+		return Original, nil
+	case "scaled":
+		return Scaled, nil
+	case "fast":
+		return Fast, nil
+	}
+	return Original, errors.Wrap(errUnknownTiming, s)
+}
+
+// Options control the replay.
+type Options struct {
+	Timing Timing
+	// Speed is how many times faster than the capture to go with Scaled
+	// timing.
+	Speed float64
+}
+
+func (o Options) delay(offset time.Duration) time.Duration {
+	switch o.Timing {
+	case Fast:
+		return 0
+	case Scaled:
+		return time.Duration(float64(offset) / o.Speed)
+	}
+	return offset
+}
+
+// Result is the outcome of replaying a request, along with what happened
+// in the capture.
+type Result struct {
+	Connection      string
+	Request         int
+	Command         string
+	SQL             string        `json:"SQL,omitempty"`
+	CapturedLatency time.Duration `json:"CapturedLatency,omitempty"`
+	Latency         time.Duration
+	CapturedRows    int
+	Rows            int
+	CapturedError   string `json:"CapturedError,omitempty"`
+	Error           string `json:"Error,omitempty"`
+}
+
+// Mismatch is true when the replay didn't turn out the way the capture did.
+func (r Result) Mismatch() bool {
+	return (r.CapturedError == "") != (r.Error == "") || r.CapturedRows != r.Rows
+}
+
+var errNotPrepared = errors.New("statement not prepared in the capture")
+
+// Replayer runs the requests from a transcript against a server.
+type Replayer struct {
+	db      *sql.DB
+	options Options
+}
+
+// New creates a replayer that sends the requests to db.
+func New(db *sql.DB, options Options) *Replayer {
+	return &Replayer{db: db, options: options}
+}
+
+// Run replays the connections at the same time, each on a connection of
+// its own so that the session state, like the current database and the
+// prepared statements, carries over between the requests as it did in the
+// capture.  The results are in the order of the connections.
+func (r *Replayer) Run(ctx context.Context, conns []transcript.Connection) []Result {
+	first := captureStart(conns)
+	start := time.Now()
+	results := make([][]Result, len(conns))
+	var wg sync.WaitGroup
+	for i, c := range conns {
+		wg.Add(1)
+		go func(i int, c transcript.Connection) {
+			defer wg.Done()
+			results[i] = r.connection(ctx, c, first, start)
+		}(i, c)
+	}
+	wg.Wait()
+
+	var all []Result
+	for _, rs := range results {
+		all = append(all, rs...)
+	}
+	return all
+}
+
+func captureStart(conns []transcript.Connection) time.Time {
+	var first time.Time
+	for _, c := range conns {
+		for _, e := range c.Exchanges {
+			if first.IsZero() || e.RequestStart.Before(first) {
+				first = e.RequestStart
+			}
+		}
+	}
+	return first
+}
+
+type session struct {
+	conn       *sql.Conn
+	statements map[uint32]*sql.Stmt
+}
+
+func (r *Replayer) connection(
+	ctx context.Context, c transcript.Connection, first, start time.Time,
+) []Result {
+	var results []Result
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return []Result{{Connection: c.Address, Request: -1, Command: "Connect", Error: err.Error()}}
+	}
+	s := session{conn: conn, statements: make(map[uint32]*sql.Stmt)}
+	defer s.close()
+
+	for _, e := range c.Exchanges {
+		if e.Request >= len(c.Items) {
+			continue
+		}
+		request := c.Items[e.Request].Data.Unwrap()
+		if request.Type == "QUIT" {
+			break
+		}
+		query, ok := statement(request)
+		if !ok {
+			continue
+		}
+		if err := r.wait(ctx, start, e.RequestStart.Sub(first)); err != nil {
+			break
+		}
+		result := Result{
+			Connection:   c.Address,
+			Request:      e.Request,
+			Command:      e.Command,
+			SQL:          query,
+			CapturedRows: e.Rows,
+		}
+		if e.LastResponse != nil {
+			result.CapturedLatency = e.LastResponse.Sub(e.RequestStart)
+		}
+		for _, i := range e.Responses {
+			if response := c.Items[i].Data.Unwrap(); response.Type == "Error" {
+				result.CapturedError = response.Message
+			}
+		}
+		began := time.Now()
+		rows, err := s.run(ctx, request, preparedID(c, e))
+		result.Latency = time.Since(began)
+		result.Rows = rows
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// statement works out the SQL for the requests that can be replayed.
+func statement(request transcript.Data) (string, bool) {
+	switch request.Type {
+	case "Query", "Prepare":
+		return request.Query, true
+	case "Execute":
+		if request.SQL != "" {
+			return request.SQL, true
+		}
+		return request.Query, true
+	case "Login":
+		if request.Database == "" {
+			return "", false
+		}
+		return "USE " + quoteIdentifier(request.Database), true
+	case "MYSQL_INIT_DB":
+		return "USE " + quoteIdentifier(request.Schema), true
+	}
+	return "", false
+}
+
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// preparedID finds the statement id the server gave a Prepare.
+func preparedID(c transcript.Connection, e structure.Exchange) uint32 {
+	for _, i := range e.Responses {
+		if response := c.Items[i].Data.Unwrap(); response.Type == "PREPARE_OK" {
+			return response.StatementID
+		}
+	}
+	return 0
+}
+
+func (r *Replayer) wait(ctx context.Context, start time.Time, offset time.Duration) error {
+	d := time.Until(start.Add(r.options.delay(offset)))
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// run sends the request, returning the number of rows that came back.
+func (s *session) run(ctx context.Context, request transcript.Data, preparedID uint32) (int, error) {
+	switch request.Type {
+	case "Prepare":
+		stmt, err := s.conn.PrepareContext(ctx, request.Query)
+		if err != nil {
+			return 0, err
+		}
+		s.statements[preparedID] = stmt
+		return 0, nil
+	case "Execute":
+		stmt, ok := s.statements[request.StatementID]
+		if !ok {
+			return 0, errNotPrepared
+		}
+		params := make([]interface{}, len(request.Params))
+		for i, p := range request.Params {
+			params[i] = param(p)
+		}
+		return countRows(stmt.QueryContext(ctx, params...))
+	}
+	query, _ := statement(request)
+	return countRows(s.conn.QueryContext(ctx, query))
+}
+
+func (s *session) close() {
+	for _, stmt := range s.statements {
+		stmt.Close()
+	}
+	s.conn.Close()
+}
+
+func countRows(rows *sql.Rows, err error) (int, error) {
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	n := 0
+	for {
+		for rows.Next() {
+			n++
+		}
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+	return n, rows.Err()
+}
+
+// param turns a parameter from the json back into a value to send.
+func param(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			return u
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return string(v)
+	case map[string]interface{}:
+		if text, ok := v["Text"].(string); ok {
+			return text
+		}
+		if encoded, ok := v["Base64"].(string); ok {
+			if data, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+				return data
+			}
+		}
+	}
+	return v
+}
@@ -0,0 +1,250 @@
+package replay_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/replay"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/transcript"
+)
+
+// fakeDriver stands in for a server, logging the statements it's sent.
+// Queries against peeps return two rows and the missing table fails.
+type fakeDriver struct {
+	mu  sync.Mutex
+	log []string
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+type fakeRows struct {
+	left int
+}
+
+var errNoTable = errors.New("Table 'demo.missing' doesn't exist")
+
+func (d *fakeDriver) Open(_ string) (driver.Conn, error) {
+	return &fakeConn{driver: d}, nil
+}
+
+func (d *fakeDriver) Connect(_ context.Context) (driver.Conn, error) {
+	return d.Open("")
+}
+
+func (d *fakeDriver) Driver() driver.Driver {
+	return d
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	if strings.Contains(query, "missing") {
+		return nil, errNoTable
+	}
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+// CheckNamedValue accepts the arguments as they are, as the mysql driver
+// does for the likes of large unsigned numbers.
+func (c *fakeConn) CheckNamedValue(_ *driver.NamedValue) error {
+	return nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(_ []driver.Value) (driver.Result, error) {
+	return nil, driver.ErrSkip
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.conn.driver.mu.Lock()
+	s.conn.driver.log = append(s.conn.driver.log, fmt.Sprint(s.query, args))
+	s.conn.driver.mu.Unlock()
+	if strings.Contains(s.query, "peeps") {
+		return &fakeRows{left: 2}, nil
+	}
+	return &fakeRows{}, nil
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"id"}
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.left == 0 {
+		return io.EOF
+	}
+	r.left--
+	dest[0] = int64(r.left)
+	return nil
+}
+
+const connections = `[
+{
+  "Address": "10.0.0.1:40000 - 10.0.0.9:3306",
+  "Items": [
+    {"Data": {"Type": "Login", "Username": "site", "Database": "demo"}},
+    {"Data": {"Type": "OK"}, "ResponseTo": 0},
+    {"Data": {"Type": "Query", "Query": "SELECT * FROM peeps"}},
+    {"Data": {"Type": "SQL results"}, "ResponseTo": 2},
+    {"Data": {"Type": "Prepare", "Query": "SELECT * FROM peeps WHERE id = ?"}},
+    {"Data": {"Type": "PREPARE_OK", "StatementID": 7}, "ResponseTo": 4},
+    {"Data": {"Type": "Execute", "StatementID": 7, "Params": [18446744073709551615],
+              "Query": "SELECT * FROM peeps WHERE id = ?",
+              "SQL": "SELECT * FROM peeps WHERE id = 18446744073709551615"}},
+    {"Data": {"Type": "SQL results"}, "ResponseTo": 6},
+    {"Data": {"Type": "Query", "Query": "INSERT INTO test VALUES (1)"}},
+    {"Data": {"Type": "Error", "Code": 1146, "Message": "Table 'demo.test' doesn't exist"}, "ResponseTo": 8},
+    {"Data": {"Type": "MYSQL_PING"}},
+    {"Data": {"Type": "OK"}, "ResponseTo": 10},
+    {"Data": {"Type": "Query", "Query": "SELECT * FROM missing"}},
+    {"Data": {"Type": "OK"}, "ResponseTo": 12},
+    {"Data": {"Type": "QUIT"}},
+    {"Data": {"Type": "Query", "Query": "SELECT 1"}}
+  ],
+  "Exchanges": [
+    {"Request": 0, "Responses": [1], "Command": "Login", "RequestStart": "2021-04-04T17:28:50Z"},
+    {"Request": 2, "Responses": [3], "Command": "Query", "Rows": 2, "RequestStart": "2021-04-04T17:28:50.001Z",
+     "LastResponse": "2021-04-04T17:28:50.003Z"},
+    {"Request": 4, "Responses": [5], "Command": "Prepare", "RequestStart": "2021-04-04T17:28:50.004Z"},
+    {"Request": 6, "Responses": [7], "Command": "Execute", "Rows": 1, "RequestStart": "2021-04-04T17:28:50.005Z"},
+    {"Request": 8, "Responses": [9], "Command": "Query", "RequestStart": "2021-04-04T17:28:50.006Z"},
+    {"Request": 10, "Responses": [11], "Command": "MYSQL_PING", "RequestStart": "2021-04-04T17:28:50.007Z"},
+    {"Request": 12, "Responses": [13], "Command": "Query", "RequestStart": "2021-04-04T17:28:50.008Z"},
+    {"Request": 14, "Command": "QUIT", "RequestStart": "2021-04-04T17:28:50.009Z"},
+    {"Request": 15, "Command": "Query", "RequestStart": "2021-04-04T17:28:50.010Z"}
+  ]
+}
+]`
+
+func TestReplay(t *testing.T) {
+	var conns []transcript.Connection
+	err := transcript.Read(strings.NewReader(connections), func(c transcript.Connection) error {
+		conns = append(conns, c)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake := &fakeDriver{}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	options := replay.Options{Timing: replay.Fast}
+	results := replay.New(db, options).Run(context.Background(), conns)
+
+	address := "10.0.0.1:40000 - 10.0.0.9:3306"
+	expected := []replay.Result{
+		{Connection: address, Request: 0, Command: "Login", SQL: "USE `demo`"},
+		{
+			Connection: address, Request: 2, Command: "Query", SQL: "SELECT * FROM peeps",
+			CapturedLatency: 2 * time.Millisecond, CapturedRows: 2, Rows: 2,
+		},
+		{Connection: address, Request: 4, Command: "Prepare", SQL: "SELECT * FROM peeps WHERE id = ?"},
+		{
+			Connection: address, Request: 6, Command: "Execute",
+			SQL:          "SELECT * FROM peeps WHERE id = 18446744073709551615",
+			CapturedRows: 1, Rows: 2,
+		},
+		{
+			Connection: address, Request: 8, Command: "Query", SQL: "INSERT INTO test VALUES (1)",
+			CapturedError: "Table 'demo.test' doesn't exist",
+		},
+		{
+			Connection: address, Request: 12, Command: "Query", SQL: "SELECT * FROM missing",
+			Error: "Table 'demo.missing' doesn't exist",
+		},
+	}
+	if diff := cmp.Diff(results, expected, cmpopts.IgnoreFields(replay.Result{}, "Latency")); diff != "" {
+		t.Fatalf("Results don't match (-got +expected):\n%s\n", diff)
+	}
+
+	expectedLog := []string{
+		"USE `demo`[]",
+		"SELECT * FROM peeps[]",
+		"SELECT * FROM peeps WHERE id = ?[18446744073709551615]",
+		"INSERT INTO test VALUES (1)[]",
+	}
+	if diff := cmp.Diff(fake.log, expectedLog); diff != "" {
+		t.Fatalf("Statements don't match (-got +expected):\n%s\n", diff)
+	}
+
+	expectedSummary := replay.Summary{
+		Requests:        6,
+		Errors:          1,
+		CapturedErrors:  1,
+		NewErrors:       1,
+		FixedErrors:     1,
+		RowMismatches:   1,
+		CapturedLatency: 2 * time.Millisecond,
+	}
+	summary := replay.Summarise(results)
+	if diff := cmp.Diff(summary, expectedSummary, cmpopts.IgnoreFields(replay.Summary{}, "Latency")); diff != "" {
+		t.Fatalf("Summary doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestScaledTiming(t *testing.T) {
+	start := time.Date(2021, 4, 4, 17, 28, 50, 0, time.UTC)
+	conns := []transcript.Connection{
+		{
+			Items: []transcript.Item{
+				{Data: transcript.Data{Type: "Query", Query: "SELECT 1"}},
+				{Data: transcript.Data{Type: "Query", Query: "SELECT 2"}},
+			},
+			Exchanges: []structure.Exchange{
+				{Request: 0, Command: "Query", RequestStart: start},
+				{Request: 1, Command: "Query", RequestStart: start.Add(200 * time.Millisecond)},
+			},
+		},
+	}
+
+	db := sql.OpenDB(&fakeDriver{})
+	defer db.Close()
+
+	began := time.Now()
+	options := replay.Options{Timing: replay.Scaled, Speed: 10}
+	results := replay.New(db, options).Run(context.Background(), conns)
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if taken := time.Since(began); taken < 20*time.Millisecond || taken > 150*time.Millisecond {
+		t.Fatalf("Expected the replay to take about 20ms, took %s", taken)
+	}
+}
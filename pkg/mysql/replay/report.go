@@ -0,0 +1,116 @@
+package replay
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// slowdowns is how many of the requests that slowed down the most are
+// listed in the text report.
+const slowdowns = 10
+
+// Summary totals up the results of a replay.
+type Summary struct {
+	Requests        int
+	Errors          int
+	CapturedErrors  int
+	NewErrors       int
+	FixedErrors     int
+	RowMismatches   int
+	CapturedLatency time.Duration
+	Latency         time.Duration
+}
+
+// Summarise totals up the results.
+func Summarise(results []Result) Summary {
+	var s Summary
+	for _, r := range results {
+		s.Requests++
+		s.CapturedLatency += r.CapturedLatency
+		s.Latency += r.Latency
+		if r.Error != "" {
+			s.Errors++
+		}
+		if r.CapturedError != "" {
+			s.CapturedErrors++
+		}
+		switch {
+		case r.Error != "" && r.CapturedError == "":
+			s.NewErrors++
+		case r.Error == "" && r.CapturedError != "":
+			s.FixedErrors++
+		}
+		if r.Rows != r.CapturedRows {
+			s.RowMismatches++
+		}
+	}
+	return s
+}
+
+// WriteText writes a report for people to read.  It lists the requests that
+// didn't turn out the way they did in the capture, the ones that slowed down
+// the most, then the totals.
+func WriteText(w io.Writer, results []Result) error {
+	var sb strings.Builder
+	for _, r := range results {
+		if r.Mismatch() {
+			writeResult(&sb, r)
+		}
+	}
+
+	slower := make([]Result, 0, len(results))
+	for _, r := range results {
+		if r.Error == "" && r.CapturedError == "" && r.Latency > r.CapturedLatency {
+			slower = append(slower, r)
+		}
+	}
+	sort.SliceStable(slower, func(i, j int) bool {
+		return slower[i].Latency-slower[i].CapturedLatency >
+			slower[j].Latency-slower[j].CapturedLatency
+	})
+	if len(slower) > slowdowns {
+		slower = slower[:slowdowns]
+	}
+	if len(slower) > 0 {
+		sb.WriteString("Slowed down the most:\n\n")
+		for _, r := range slower {
+			writeResult(&sb, r)
+		}
+	}
+
+	s := Summarise(results)
+	fmt.Fprintf(&sb, "Requests: %d  Errors: %d (captured %d)  New errors: %d  Fixed errors: %d  Row mismatches: %d\n",
+		s.Requests, s.Errors, s.CapturedErrors, s.NewErrors, s.FixedErrors, s.RowMismatches)
+	fmt.Fprintf(&sb, "Latency: captured %s  replayed %s  difference %s\n",
+		s.CapturedLatency, s.Latency, signed(s.Latency-s.CapturedLatency))
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeResult(sb *strings.Builder, r Result) {
+	fmt.Fprintf(sb, "Connection: %s request %d %s\n", r.Connection, r.Request, r.Command)
+	if r.SQL != "" {
+		fmt.Fprintf(sb, "%s\n", r.SQL)
+	}
+	fmt.Fprintf(sb, "  captured: %s, %d rows, %s\n", outcome(r.CapturedError), r.CapturedRows, r.CapturedLatency)
+	fmt.Fprintf(sb, "  replayed: %s, %d rows, %s (%s)\n\n",
+		outcome(r.Error), r.Rows, r.Latency, signed(r.Latency-r.CapturedLatency))
+}
+
+func outcome(err string) string {
+	if err == "" {
+		return "ok"
+	}
+	return "error " + err
+}
+
+func signed(d time.Duration) string {
+	if d > 0 {
+		return "+" + d.String()
+	}
+	return d.String()
+}
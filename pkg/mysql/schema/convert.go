@@ -0,0 +1,425 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/packet"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+// FromConnection converts a connection from the decoder into the version 2
+// form.  Anything spilled to disk is read back in.
+func FromConnection(c structure.Connection) (Connection, error) {
+	conn := Connection{
+		SchemaVersion: Version,
+		Address:       c.Address.String(),
+		Client:        net.JoinHostPort(c.Address.IP.Src().String(), c.Address.Port.Src().String()),
+		Server:        net.JoinHostPort(c.Address.IP.Dst().String(), c.Address.Port.Dst().String()),
+		Items:         make([]Item, 0, len(c.Items)),
+		Exchanges:     make([]Exchange, 0, len(c.Exchanges)),
+		Summary:       FromSummary(c.Summary),
+	}
+	for i, t := range c.Items {
+		item, err := FromTransmission(i, t)
+		if err != nil {
+			return Connection{}, err
+		}
+		conn.Items = append(conn.Items, item)
+	}
+	for _, e := range c.Exchanges {
+		conn.Exchanges = append(conn.Exchanges, FromExchange(e))
+	}
+	for _, s := range c.Sessions {
+		conn.Sessions = append(conn.Sessions, Session{
+			FirstItem: s.FirstItem,
+			Reason:    s.Reason,
+			Username:  s.Username,
+			Database:  s.Database,
+		})
+	}
+	for _, t := range c.Transactions {
+		conn.Transactions = append(conn.Transactions, Transaction{
+			Requests:     t.Requests,
+			Statements:   t.Statements,
+			Start:        t.Start,
+			End:          t.End,
+			DurationNs:   t.Duration.Nanoseconds(),
+			IdleNs:       t.Idle.Nanoseconds(),
+			Outcome:      t.Outcome,
+			RowsAffected: t.RowsAffected,
+		})
+	}
+	var err error
+	if conn.RawRequestPackets, err = packets(c.RawRequestPackets); err != nil {
+		return Connection{}, err
+	}
+	if conn.RawResponsePackets, err = packets(c.RawResponsePackets); err != nil {
+		return Connection{}, err
+	}
+	return conn, nil
+}
+
+// FromConnectionItems converts the items handed on before the connection
+// closed.
+func FromConnectionItems(c structure.ConnectionItems) (ConnectionItems, error) {
+	conn := ConnectionItems{
+		Address:   c.Address.String(),
+		Items:     make([]Item, 0, len(c.Items)),
+		Exchanges: make([]Exchange, 0, len(c.Exchanges)),
+	}
+	for i, t := range c.Items {
+		item, err := FromTransmission(c.First+i, t)
+		if err != nil {
+			return ConnectionItems{}, err
+		}
+		conn.Items = append(conn.Items, item)
+	}
+	for _, e := range c.Exchanges {
+		conn.Exchanges = append(conn.Exchanges, FromExchange(e))
+	}
+	return conn, nil
+}
+
+// FromSummary converts a connection summary.
+func FromSummary(s structure.ConnectionSummary) Summary {
+	commands := s.Commands
+	if commands == nil {
+		commands = map[string]int{}
+	}
+	return Summary{
+		Start:         s.Start,
+		End:           s.End,
+		Ending:        s.Ending,
+		ServerVersion: s.ServerVersion,
+		User:          s.User,
+		Database:      s.Database,
+		Capabilities:  CapabilityFlags(s.Capabilities),
+		Compressed:    s.Compressed,
+		TLS:           s.TLS,
+		Commands:      commands,
+		Errors:        s.Errors,
+		Rows:          s.Rows,
+		RequestBytes:  s.RequestBytes,
+		ResponseBytes: s.ResponseBytes,
+		ServerTimeNs:  s.ServerTime.Nanoseconds(),
+		IdleTimeNs:    s.IdleTime.Nanoseconds(),
+	}
+}
+
+// FromExchange converts an exchange.
+func FromExchange(e structure.Exchange) Exchange {
+	responses := e.Responses
+	if responses == nil {
+		responses = []int{}
+	}
+	return Exchange{
+		Request:           e.Request,
+		Responses:         responses,
+		Command:           e.Command,
+		RequestStart:      e.RequestStart,
+		RequestEnd:        e.RequestEnd,
+		FirstResponse:     e.FirstResponse,
+		LastResponse:      e.LastResponse,
+		TimeToFirstByteNs: e.TimeToFirstByte.Nanoseconds(),
+		TimeToLastByteNs:  e.TimeToLastByte.Nanoseconds(),
+		Rows:              e.Rows,
+		RequestBytes:      e.RequestBytes,
+		ResponseBytes:     e.ResponseBytes,
+	}
+}
+
+// FromTransmission converts the item at index i in a connection.
+func FromTransmission(i int, t structure.Transmission) (Item, error) {
+	item := Item{
+		Index:      i,
+		Seen:       seen(t.Seen),
+		ResponseTo: t.ResponseTo,
+	}
+	if t.Session != nil {
+		item.Session = &SessionState{
+			User:           t.Session.User,
+			Database:       t.Session.Database,
+			CharacterSet:   t.Session.CharacterSet,
+			Autocommit:     t.Session.Autocommit,
+			SQLMode:        t.Session.SQLMode,
+			IsolationLevel: t.Session.IsolationLevel,
+		}
+	}
+	d := t.Data
+	if raw, ok := d.(structure.WithRawPacket); ok {
+		item.RawData = raw.RawData
+		d = raw.Transmission
+	}
+	var err error
+	item.Kind, item.Direction, item.Data, err = convert(d)
+	if err != nil {
+		return Item{}, err
+	}
+	if item.Direction == "" {
+		// only decode errors and things we don't recognise get
+		// here, decode errors know which side they came from.
+		item.Direction = request
+		if e, ok := d.(structure.DecodeError); ok {
+			item.Direction = strings.ToLower(e.Direction)
+		} else if t.ResponseTo != nil {
+			item.Direction = response
+		}
+	}
+	return item, nil
+}
+
+func convert(d interface{}) (kind, direction string, data interface{}, err error) {
+	switch v := d.(type) {
+	case structure.Greeting:
+		return "greeting", response, Greeting{
+			Protocol:     v.Protocol,
+			Version:      v.Version,
+			Capabilities: CapabilityFlags(v.Capabilities),
+			Collation:    v.Collation,
+		}, nil
+	case structure.LoginRequest:
+		return "login", request, Login{
+			Capabilities:         CapabilityFlags(v.ClientCapabilities),
+			ExtendedCapabilities: flags(uint64(v.ExtendedCapabilities), extendedCapabilityNames),
+			Collation:            v.Collation,
+			MaxPacketSize:        v.MaxPacketSize,
+			Username:             v.Username,
+			Database:             v.Database,
+			AuthPlugin:           v.AuthPlugin,
+			Attributes:           v.Attributes,
+		}, nil
+	case structure.SSLRequest:
+		return "ssl_request", request, SSLRequest{
+			Capabilities:         CapabilityFlags(v.ClientCapabilities),
+			ExtendedCapabilities: flags(uint64(v.ExtendedCapabilities), extendedCapabilityNames),
+			Collation:            v.Collation,
+			MaxPacketSize:        v.MaxPacketSize,
+		}, nil
+	case structure.ChangeUserRequest:
+		return "change_user", request, ChangeUser{
+			Username:   v.Username,
+			Database:   v.Database,
+			Collation:  v.Collation,
+			AuthPlugin: v.AuthPlugin,
+			Attributes: v.Attributes,
+		}, nil
+	case structure.AuthResponse:
+		return "auth_response", request, AuthData{Data: v.Data}, nil
+	case structure.Request:
+		switch v.Type {
+		case "Query":
+			return "query", request, Query{Query: v.Query, Attributes: v.Attributes}, nil
+		case "Prepare":
+			return "prepare", request, Query{Query: v.Query, Attributes: v.Attributes}, nil
+		}
+		return "command", request, Command{Name: v.Type}, nil
+	case structure.ExecuteRequest:
+		params := v.Params
+		if params == nil {
+			params = []interface{}{}
+		}
+		return "execute", request, Execute{
+			StatementID:    v.StatementID,
+			Flags:          v.Flags,
+			IterationCount: v.IterationCount,
+			Params:         params,
+			Attributes:     v.Attributes,
+			Query:          v.Query,
+			SQL:            v.SQL,
+		}, nil
+	case structure.InitDBRequest:
+		return "init_db", request, InitDB{Schema: v.Schema}, nil
+	case structure.FieldListRequest:
+		return "field_list", request, FieldList{Table: v.Table, Wildcard: v.Wildcard}, nil
+	case structure.ProcessKillRequest:
+		return "process_kill", request, ProcessKill{ConnectionID: v.ConnectionID}, nil
+	case structure.SetOptionRequest:
+		return "set_option", request, SetOption{
+			Option: Enum{Value: uint64(v.Option), Name: v.Option.String()},
+		}, nil
+	case structure.RefreshRequest:
+		return "refresh", request, Refresh{Flags: flags(uint64(v.Flags), refreshNames)}, nil
+	case structure.ShutdownRequest:
+		return "shutdown", request, Shutdown{
+			Level: Enum{Value: uint64(v.Level), Name: v.Level.String()},
+		}, nil
+	case structure.OKResponse:
+		return "ok", response, OK{
+			AffectedRows: v.AffectedRows,
+			LastInsertID: v.LastInsertID,
+			Status:       StatusFlags(v.ServerStatus),
+			Warnings:     v.WarningCount,
+			Info:         v.Info,
+			StateChanges: stateChanges(v.StateChanges),
+		}, nil
+	case structure.ErrorResponse:
+		return "error", response, Error{
+			Code:     v.Code,
+			State:    v.State,
+			Message:  v.Message,
+			Name:     v.Name,
+			Category: v.Category,
+		}, nil
+	case structure.Response:
+		switch v.Type {
+		case "EOF":
+			return "eof", response, Empty{}, nil
+		case "In file":
+			return "local_infile", response, Empty{}, nil
+		}
+	case structure.ResultSetResponse:
+		rows, err := resultRows(v)
+		if err != nil {
+			return "", "", nil, err
+		}
+		return "result_set", response, ResultSet{
+			Columns:       columns(v.Columns),
+			Rows:          rows,
+			CachedColumns: v.CachedColumns,
+		}, nil
+	case structure.PrepareOKResponse:
+		return "prepare_ok", response, PrepareOK{
+			StatementID: v.StatementID,
+			NumColumns:  v.NumColumns,
+			NumParams:   v.NumParams,
+			Warnings:    v.Warnings,
+			Columns:     columns(v.Columns),
+			Params:      columns(v.Params),
+		}, nil
+	case structure.AuthSwitchResponse:
+		return "auth_switch", response, AuthSwitch{AuthPlugin: v.AuthPlugin, Data: v.Data}, nil
+	case structure.AuthMoreDataResponse:
+		return "auth_more_data", response, AuthData{Data: v.Data}, nil
+	case structure.StatisticsResponse:
+		return "statistics", response, Statistics{Status: v.Status}, nil
+	case structure.FieldListResponse:
+		return "field_list_response", response, FieldListResponse{Columns: columns(v.Columns)}, nil
+	case structure.DecodeError:
+		e := DecodeError{
+			Error:               v.DecodeErrorString,
+			DecoderState:        v.DecoderState,
+			CompressionOn:       v.CompressionOn,
+			JustSeenGreeting:    v.JustSeenGreeting,
+			PreviousRequestType: v.PreviousRequestType,
+		}
+		if v.Packet != nil {
+			p := *v.Packet
+			if err := p.Load(); err != nil {
+				return "", "", nil, errors.Wrap(err, "schema-decode-error")
+			}
+			e.Packet = &Packet{Seen: seen(p.Seen), Data: p.Data}
+		}
+		return "decode_error", "", e, nil
+	}
+	return "unknown", "", Unknown{Type: fmt.Sprintf("%T", d), Value: d}, nil
+}
+
+func stateChanges(changes []structure.StateChange) []StateChange {
+	var converted []StateChange
+	for _, c := range changes {
+		converted = append(converted, StateChange{Type: c.Type, Name: c.Name, Value: c.Value})
+	}
+	return converted
+}
+
+func columns(cols []structure.ColumnInfo) []Column {
+	converted := make([]Column, 0, len(cols))
+	for _, c := range cols {
+		converted = append(converted, Column{
+			Catalog:      c.Catalog,
+			Schema:       c.Schema,
+			Table:        c.TableAlias,
+			OrgTable:     c.Table,
+			Name:         c.ColumnAlias,
+			OrgName:      c.Column,
+			CharacterSet: c.TypeInfo.CharacterSetNumber,
+			Length:       c.TypeInfo.MaxColumnSize,
+			Type:         fieldType(c.TypeInfo.FieldTypes),
+			Flags:        ColumnFlags(c.TypeInfo.FieldDetail),
+			Decimals:     c.TypeInfo.Decimals,
+			Default:      c.Default,
+		})
+	}
+	return converted
+}
+
+// resultRows gathers up the rows, including any that were spilled to disk.
+// The spilled rows are kept as json so numbers are read back as
+// json.Number to be written out just as they were.
+func resultRows(r structure.ResultSetResponse) ([][]interface{}, error) {
+	rows := make([][]interface{}, 0, len(r.Results)+r.SpilledResults.Len())
+	rows = append(rows, r.Results...)
+	spilled, err := r.SpilledResults.JSON()
+	if err != nil {
+		return nil, errors.Wrap(err, "schema-spilled-rows")
+	}
+	for _, raw := range spilled {
+		var row []interface{}
+		d := json.NewDecoder(bytes.NewReader(raw))
+		d.UseNumber()
+		if err := d.Decode(&row); err != nil {
+			return nil, errors.Wrap(err, "schema-spilled-rows")
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func packets(b *packet.Buffer) ([]Packet, error) {
+	if b == nil {
+		return nil, nil
+	}
+	converted := make([]Packet, 0, len(b.Packets))
+	for _, p := range b.Packets {
+		if err := p.Load(); err != nil {
+			return nil, errors.Wrap(err, "schema-raw-packets")
+		}
+		converted = append(converted, Packet{Seen: seen(p.Seen), Data: p.Data})
+	}
+	return converted, nil
+}
+
+// seen makes sure the times are written as an array rather than null.
+func seen(t []time.Time) []time.Time {
+	if t == nil {
+		return []time.Time{}
+	}
+	return t
+}
+
+// Convert passes on what comes through completed, with the connections
+// converted to the version 2 form.
+func Convert(completed chan interface{}) chan interface{} {
+	converted := make(chan interface{})
+	go func() {
+		defer close(converted)
+		for c := range completed {
+			var err error
+			var release func()
+			switch v := c.(type) {
+			case structure.Connection:
+				c, err = FromConnection(v)
+			case structure.ConnectionItems:
+				c, err = FromConnectionItems(v)
+				release = v.Release
+			}
+			if err != nil {
+				log.Fatal(err)
+			}
+			converted <- c
+			if release != nil {
+				// the converted rows have been passed on.
+				release()
+			}
+		}
+	}()
+	return converted
+}
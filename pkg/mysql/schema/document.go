@@ -0,0 +1,143 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Document generates the JSON Schema describing the output.  Each line of
+// the ndjson format is a connection, and each line of ndjson-items an item
+// line.  The json format is an array of connections.
+func Document() ([]byte, error) {
+	g := generator{defs: map[string]interface{}{}}
+	doc := map[string]interface{}{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"title":       "pcap2mysql-log output, version 2",
+		"description": "A connection, or a single item from one with --format ndjson-items.",
+		"oneOf": []interface{}{
+			g.schema(reflect.TypeOf(Connection{})),
+			g.schema(reflect.TypeOf(ItemLine{})),
+		},
+		"$defs": g.defs,
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+type generator struct {
+	defs map[string]interface{}
+}
+
+//nolint:gochecknoglobals
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	itemType = reflect.TypeOf(Item{})
+)
+
+func (g *generator) schema(t reflect.Type) map[string]interface{} {
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		// encoding/json writes a nil slice as null.
+		return map[string]interface{}{"type": []string{"string", "null"}, "contentEncoding": "base64"}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schema(t.Elem())
+	case reflect.Struct:
+		name := snakeCase(t.Name())
+		if _, ok := g.defs[name]; !ok {
+			// placeholder first, in case the type refers to itself.
+			g.defs[name] = nil
+			g.defs[name] = g.object(t)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + name}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Interface:
+		return map[string]interface{}{}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	}
+	return map[string]interface{}{}
+}
+
+// object describes a struct from its json tags.  Fields that aren't
+// omitempty are always present so are required.
+func (g *generator) object(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("json"), ",")
+		if tag[0] == "-" || tag[0] == "" {
+			continue
+		}
+		p := g.schema(f.Type)
+		if d := f.Tag.Get("description"); d != "" {
+			p["description"] = d
+		}
+		if e := f.Tag.Get("enum"); e != "" {
+			p["enum"] = strings.Split(e, ",")
+		}
+		properties[tag[0]] = p
+		if len(tag) == 1 {
+			required = append(required, tag[0])
+		}
+	}
+	o := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+	if t == itemType {
+		o["oneOf"] = g.itemKinds()
+	}
+	return o
+}
+
+// itemKinds ties the form of an item's data to its kind.
+func (g *generator) itemKinds() []interface{} {
+	options := make([]interface{}, 0, len(kinds))
+	for _, k := range kinds {
+		options = append(options, map[string]interface{}{
+			"properties": map[string]interface{}{
+				"kind": map[string]interface{}{"const": k.kind},
+				"data": g.schema(reflect.TypeOf(k.data)),
+			},
+		})
+	}
+	return options
+}
+
+// snakeCase turns a Go type name into the name used for its definition,
+// ItemLine becomes item_line, PrepareOK prepare_ok.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			previousLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if previousLower || (nextLower && unicode.IsUpper(runes[i-1])) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
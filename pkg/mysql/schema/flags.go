@@ -0,0 +1,128 @@
+package schema
+
+import (
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+// The names of the bits, lowest first, as MySQL and MariaDB name them.
+// Bits that aren't named are left out of the names but kept in the value.
+//
+//nolint:gochecknoglobals
+var (
+	capabilityNames = []string{
+		"CLIENT_LONG_PASSWORD",
+		"CLIENT_FOUND_ROWS",
+		"CLIENT_LONG_FLAG",
+		"CLIENT_CONNECT_WITH_DB",
+		"CLIENT_NO_SCHEMA",
+		"CLIENT_COMPRESS",
+		"CLIENT_ODBC",
+		"CLIENT_LOCAL_FILES",
+		"CLIENT_IGNORE_SPACE",
+		"CLIENT_PROTOCOL_41",
+		"CLIENT_INTERACTIVE",
+		"CLIENT_SSL",
+		"CLIENT_IGNORE_SIGPIPE",
+		"CLIENT_TRANSACTIONS",
+		"CLIENT_RESERVED",
+		"CLIENT_SECURE_CONNECTION",
+		"CLIENT_MULTI_STATEMENTS",
+		"CLIENT_MULTI_RESULTS",
+		"CLIENT_PS_MULTI_RESULTS",
+		"CLIENT_PLUGIN_AUTH",
+		"CLIENT_CONNECT_ATTRS",
+		"CLIENT_PLUGIN_AUTH_LENENC_CLIENT_DATA",
+		"CLIENT_CAN_HANDLE_EXPIRED_PASSWORDS",
+		"CLIENT_SESSION_TRACK",
+		"CLIENT_DEPRECATE_EOF",
+		"CLIENT_OPTIONAL_RESULTSET_METADATA",
+		"CLIENT_ZSTD_COMPRESSION_ALGORITHM",
+		"CLIENT_QUERY_ATTRIBUTES",
+		"MULTI_FACTOR_AUTHENTICATION",
+		"CLIENT_CAPABILITY_EXTENSION",
+		"CLIENT_SSL_VERIFY_SERVER_CERT",
+		"CLIENT_REMEMBER_OPTIONS",
+	}
+	extendedCapabilityNames = []string{
+		"MARIADB_CLIENT_PROGRESS",
+		"MARIADB_CLIENT_COM_MULTI",
+		"MARIADB_CLIENT_STMT_BULK_OPERATIONS",
+		"MARIADB_CLIENT_EXTENDED_TYPE_INFO",
+		"MARIADB_CLIENT_CACHE_METADATA",
+	}
+	statusNames = []string{
+		"SERVER_STATUS_IN_TRANS",
+		"SERVER_STATUS_AUTOCOMMIT",
+		"",
+		"SERVER_MORE_RESULTS_EXISTS",
+		"SERVER_QUERY_NO_GOOD_INDEX_USED",
+		"SERVER_QUERY_NO_INDEX_USED",
+		"SERVER_STATUS_CURSOR_EXISTS",
+		"SERVER_STATUS_LAST_ROW_SENT",
+		"SERVER_STATUS_DB_DROPPED",
+		"SERVER_STATUS_NO_BACKSLASH_ESCAPES",
+		"SERVER_STATUS_METADATA_CHANGED",
+		"SERVER_QUERY_WAS_SLOW",
+		"SERVER_PS_OUT_PARAMS",
+		"SERVER_STATUS_IN_TRANS_READONLY",
+		"SERVER_SESSION_STATE_CHANGED",
+	}
+	columnFlagNames = []string{
+		"NOT_NULL_FLAG",
+		"PRI_KEY_FLAG",
+		"UNIQUE_KEY_FLAG",
+		"MULTIPLE_KEY_FLAG",
+		"BLOB_FLAG",
+		"UNSIGNED_FLAG",
+		"ZEROFILL_FLAG",
+		"BINARY_FLAG",
+		"ENUM_FLAG",
+		"AUTO_INCREMENT_FLAG",
+		"TIMESTAMP_FLAG",
+		"SET_FLAG",
+		"NO_DEFAULT_VALUE_FLAG",
+		"ON_UPDATE_NOW_FLAG",
+		"PART_KEY_FLAG",
+		"NUM_FLAG",
+	}
+	refreshNames = []string{
+		"REFRESH_GRANT",
+		"REFRESH_LOG",
+		"REFRESH_TABLES",
+		"REFRESH_HOSTS",
+		"REFRESH_STATUS",
+		"REFRESH_THREADS",
+		"REFRESH_SLAVE",
+		"REFRESH_MASTER",
+	}
+)
+
+// flags picks out the names of the bits set in value.
+func flags(value uint64, names []string) Flags {
+	f := Flags{Value: value, Names: []string{}}
+	for i, name := range names {
+		if value&(1<<uint(i)) != 0 && name != "" {
+			f.Names = append(f.Names, name)
+		}
+	}
+	return f
+}
+
+// CapabilityFlags names the client capabilities.
+func CapabilityFlags(c structure.ClientCapabilities) Flags {
+	return flags(uint64(c), capabilityNames)
+}
+
+// StatusFlags names the server status flags.
+func StatusFlags(s structure.StatusFlags) Flags {
+	return flags(uint64(s), statusNames)
+}
+
+// ColumnFlags names the column definition flags.
+func ColumnFlags(d structure.FieldDetail) Flags {
+	return flags(uint64(d), columnFlagNames)
+}
+
+func fieldType(t structure.FieldType) Enum {
+	return Enum{Value: uint64(t), Name: t.String()}
+}
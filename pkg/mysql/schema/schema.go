@@ -0,0 +1,392 @@
+// Package schema defines version 2 of the json output.  Version 1 is the
+// decoder's structures marshalled as they are, so it changes whenever they
+// do.  These types are only changed along with the Version, and the JSON
+// Schema document for them is generated from them with Document.
+//
+// Each item has a kind saying what it is, and the data for that kind.
+// Flags are given as a number along with the names of the bits set, and
+// the field names are all lower case with underscores.
+package schema
+
+import (
+	"time"
+)
+
+// Version is the version of the output these types produce.
+const Version = 2
+
+// Connection is a TCP connection from a client to the server, and
+// everything sent over it.
+type Connection struct {
+	SchemaVersion      int           `json:"schema_version" description:"Version of the output schema, 2"`
+	Address            string        `json:"address" description:"The client and server addresses, client first"`
+	Client             string        `json:"client" description:"Address and port of the client"`
+	Server             string        `json:"server" description:"Address and port of the server"`
+	Items              []Item        `json:"items" description:"Requests and responses in order"`
+	Exchanges          []Exchange    `json:"exchanges" description:"Each command along with the responses to it"`
+	Sessions           []Session     `json:"sessions,omitempty" description:"When the connection was reused"`
+	Transactions       []Transaction `json:"transactions,omitempty" description:"Transactions seen, in order"`
+	Summary            Summary       `json:"summary" description:"Figures for the whole connection"`
+	RawRequestPackets  []Packet      `json:"raw_request_packets,omitempty" description:"With --intermediate-data"`
+	RawResponsePackets []Packet      `json:"raw_response_packets,omitempty" description:"With --intermediate-data"`
+}
+
+// Summary gives the figures for a whole connection.
+type Summary struct {
+	Start         time.Time      `json:"start" description:"When the first packet was seen"`
+	End           time.Time      `json:"end" description:"When the last packet was seen"`
+	Ending        string         `json:"ending,omitempty" enum:"QUIT,FIN,RST,capture end"`
+	ServerVersion string         `json:"server_version,omitempty"`
+	User          string         `json:"user,omitempty" description:"From the login"`
+	Database      string         `json:"database,omitempty" description:"From the login"`
+	Capabilities  Flags          `json:"capabilities" description:"Those both sides have"`
+	Compressed    bool           `json:"compressed"`
+	TLS           bool           `json:"tls"`
+	Commands      map[string]int `json:"commands" description:"How many of each command were sent"`
+	Errors        int            `json:"errors"`
+	Rows          int            `json:"rows" description:"Rows returned"`
+	RequestBytes  int            `json:"request_bytes"`
+	ResponseBytes int            `json:"response_bytes"`
+	ServerTimeNs  int64          `json:"server_time_ns" description:"Time the server spent answering commands"`
+	IdleTimeNs    int64          `json:"idle_time_ns" description:"The rest of the time the connection was open"`
+}
+
+// SummaryLine is the summary of a connection on its own, as written when
+// only the summaries are wanted.
+type SummaryLine struct {
+	SchemaVersion int     `json:"schema_version" description:"Version of the output schema, 2"`
+	Address       string  `json:"address" description:"The connection summarised"`
+	Summary       Summary `json:"summary"`
+}
+
+// Item is a request or response.  The data depends on the kind.
+type Item struct {
+	Index      int           `json:"index" description:"Position in the connection's items"`
+	Kind       string        `json:"kind" description:"What the item is, deciding the form of the data"`
+	Direction  string        `json:"direction" enum:"request,response" description:"Request if the client sent it"`
+	Seen       []time.Time   `json:"seen" description:"When the packets making up the item were captured"`
+	ResponseTo *int          `json:"response_to,omitempty" description:"Index of the request this responds to"`
+	Data       interface{}   `json:"data"`
+	RawData    []byte        `json:"raw_data,omitempty" description:"Packets the item was decoded from, with --raw-data"`
+	Session    *SessionState `json:"session,omitempty" description:"The session state a request was sent in"`
+}
+
+// SessionState is the state of the session as far as it could be seen,
+// anything not seen is left out.
+type SessionState struct {
+	User           string `json:"user,omitempty"`
+	Database       string `json:"database,omitempty"`
+	CharacterSet   string `json:"character_set,omitempty"`
+	Autocommit     *bool  `json:"autocommit,omitempty"`
+	SQLMode        string `json:"sql_mode,omitempty"`
+	IsolationLevel string `json:"isolation_level,omitempty"`
+}
+
+// ItemLine is an item on its own, as written a line at a time by the
+// ndjson-items format.
+type ItemLine struct {
+	SchemaVersion int       `json:"schema_version" description:"Version of the output schema, 2"`
+	Address       string    `json:"address" description:"The connection the item belongs to"`
+	Item          Item      `json:"item"`
+	Exchange      *Exchange `json:"exchange,omitempty" description:"The exchange the item is the request of"`
+}
+
+// ConnectionItems are the items of a connection converted as the decoder
+// hands them on, before the connection has closed.
+type ConnectionItems struct {
+	Address   string
+	Items     []Item
+	Exchanges []Exchange
+}
+
+// Exchange groups a command with the responses to it.
+type Exchange struct {
+	Request           int        `json:"request" description:"Index of the command in the items"`
+	Responses         []int      `json:"responses" description:"Indexes of the responses in the items"`
+	Command           string     `json:"command"`
+	RequestStart      time.Time  `json:"request_start" description:"When the first packet of the command was seen"`
+	RequestEnd        time.Time  `json:"request_end" description:"When the last packet of the command was seen"`
+	FirstResponse     *time.Time `json:"first_response,omitempty"`
+	LastResponse      *time.Time `json:"last_response,omitempty"`
+	TimeToFirstByteNs int64      `json:"time_to_first_byte_ns" description:"From the end of the command"`
+	TimeToLastByteNs  int64      `json:"time_to_last_byte_ns" description:"From the end of the command"`
+	Rows              int        `json:"rows" description:"Rows returned"`
+	RequestBytes      int        `json:"request_bytes" description:"Size of the command's packets, after decompression"`
+	ResponseBytes     int        `json:"response_bytes" description:"Size of the response packets, after decompression"`
+}
+
+// Session is a logical session within a connection, started by a login,
+// COM_CHANGE_USER or COM_RESET_CONNECTION.
+type Session struct {
+	FirstItem int    `json:"first_item" description:"Index of the item the session starts with"`
+	Reason    string `json:"reason"`
+	Username  string `json:"username,omitempty"`
+	Database  string `json:"database,omitempty"`
+}
+
+// Transaction is a run of commands within a transaction, worked out from
+// the statements and the server status.
+type Transaction struct {
+	Requests     []int     `json:"requests" description:"Indexes of the commands in the items"`
+	Statements   []string  `json:"statements,omitempty" description:"The SQL run, in order"`
+	Start        time.Time `json:"start" description:"When the first command started"`
+	End          time.Time `json:"end" description:"When the response to the last command ended"`
+	DurationNs   int64     `json:"duration_ns"`
+	IdleNs       int64     `json:"idle_ns" description:"Time spent waiting for the client between commands"`
+	Outcome      string    `json:"outcome" enum:"COMMIT,ROLLBACK,implicit commit,deadlock,session ended,open"`
+	RowsAffected uint64    `json:"rows_affected"`
+}
+
+// Packet is a MySQL packet as it was captured.
+type Packet struct {
+	Seen []time.Time `json:"seen"`
+	Data []byte      `json:"data"`
+}
+
+// Flags are a set of bits, with the names of those that are known.
+type Flags struct {
+	Value uint64   `json:"value"`
+	Names []string `json:"names" description:"Names of the bits set, unknown bits are only in the value"`
+}
+
+// Enum is a value from a list, along with its name.
+type Enum struct {
+	Value uint64 `json:"value"`
+	Name  string `json:"name"`
+}
+
+// Column describes a column in a result set, or a parameter of a prepared
+// statement.
+type Column struct {
+	Catalog      string  `json:"catalog"`
+	Schema       string  `json:"schema"`
+	Table        string  `json:"table" description:"Table name as used in the query, which may be an alias"`
+	OrgTable     string  `json:"org_table" description:"Name of the table in the database"`
+	Name         string  `json:"name" description:"Column name as used in the query, which may be an alias"`
+	OrgName      string  `json:"org_name" description:"Name of the column in the table"`
+	CharacterSet uint16  `json:"character_set"`
+	Length       uint32  `json:"length" description:"Maximum length of the column"`
+	Type         Enum    `json:"type"`
+	Flags        Flags   `json:"flags"`
+	Decimals     byte    `json:"decimals"`
+	Default      *string `json:"default,omitempty" description:"Only sent in response to COM_FIELD_LIST"`
+}
+
+// Greeting is the initial handshake from the server.
+type Greeting struct {
+	Protocol     byte   `json:"protocol"`
+	Version      string `json:"version"`
+	Capabilities Flags  `json:"capabilities"`
+	Collation    byte   `json:"collation"`
+}
+
+// Login is the client's reply to the greeting.
+type Login struct {
+	Capabilities         Flags             `json:"capabilities"`
+	ExtendedCapabilities Flags             `json:"extended_capabilities" description:"MariaDB capabilities"`
+	Collation            byte              `json:"collation"`
+	MaxPacketSize        uint32            `json:"max_packet_size"`
+	Username             string            `json:"username"`
+	Database             string            `json:"database,omitempty"`
+	AuthPlugin           string            `json:"auth_plugin,omitempty"`
+	Attributes           map[string]string `json:"attributes,omitempty"`
+}
+
+// SSLRequest is the start of a login when the client wants TLS.  The rest
+// of the login is encrypted.
+type SSLRequest struct {
+	Capabilities         Flags  `json:"capabilities"`
+	ExtendedCapabilities Flags  `json:"extended_capabilities" description:"MariaDB capabilities"`
+	Collation            byte   `json:"collation"`
+	MaxPacketSize        uint32 `json:"max_packet_size"`
+}
+
+// ChangeUser is a COM_CHANGE_USER.
+type ChangeUser struct {
+	Username   string            `json:"username"`
+	Database   string            `json:"database,omitempty"`
+	Collation  uint16            `json:"collation,omitempty"`
+	AuthPlugin string            `json:"auth_plugin,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// AuthData is the data passed back and forth while authenticating, for the
+// auth_response and auth_more_data kinds.
+type AuthData struct {
+	Data []byte `json:"data"`
+}
+
+// AuthSwitch is the server asking for a different authentication method.
+type AuthSwitch struct {
+	AuthPlugin string `json:"auth_plugin"`
+	Data       []byte `json:"data"`
+}
+
+// Query is a COM_QUERY or COM_STMT_PREPARE.
+type Query struct {
+	Query      string                 `json:"query"`
+	Attributes map[string]interface{} `json:"attributes,omitempty" description:"Query attributes"`
+}
+
+// Execute is a COM_STMT_EXECUTE.
+type Execute struct {
+	StatementID    uint32                 `json:"statement_id"`
+	Flags          uint8                  `json:"flags"`
+	IterationCount uint32                 `json:"iteration_count"`
+	Params         []interface{}          `json:"params"`
+	Attributes     map[string]interface{} `json:"attributes,omitempty" description:"Query attributes"`
+	Query          string                 `json:"query,omitempty" description:"The SQL prepared, if captured"`
+	SQL            string                 `json:"sql,omitempty" description:"The SQL with the parameters filled in"`
+}
+
+// Command is a command with no arguments, like COM_PING or COM_QUIT.
+type Command struct {
+	Name string `json:"name"`
+}
+
+// InitDB is a COM_INIT_DB.
+type InitDB struct {
+	Schema string `json:"schema"`
+}
+
+// FieldList is a COM_FIELD_LIST.
+type FieldList struct {
+	Table    string `json:"table"`
+	Wildcard string `json:"wildcard,omitempty"`
+}
+
+// ProcessKill is a COM_PROCESS_KILL.
+type ProcessKill struct {
+	ConnectionID uint32 `json:"connection_id"`
+}
+
+// SetOption is a COM_SET_OPTION.
+type SetOption struct {
+	Option Enum `json:"option"`
+}
+
+// Refresh is a COM_REFRESH.
+type Refresh struct {
+	Flags Flags `json:"flags"`
+}
+
+// Shutdown is a COM_SHUTDOWN.
+type Shutdown struct {
+	Level Enum `json:"level"`
+}
+
+// OK is an OK packet.
+type OK struct {
+	AffectedRows uint64        `json:"affected_rows"`
+	LastInsertID uint64        `json:"last_insert_id"`
+	Status       Flags         `json:"status"`
+	Warnings     uint16        `json:"warnings"`
+	Info         string        `json:"info,omitempty"`
+	StateChanges []StateChange `json:"state_changes,omitempty" description:"With session tracking"`
+}
+
+// StateChange is a change to the session state reported in an OK.
+type StateChange struct {
+	Type  string `json:"type"`
+	Name  string `json:"name,omitempty" description:"The variable, for system variables"`
+	Value string `json:"value"`
+}
+
+// Error is an error packet.
+type Error struct {
+	Code     uint16 `json:"code"`
+	State    string `json:"state,omitempty" description:"The SQLSTATE"`
+	Message  string `json:"message"`
+	Name     string `json:"name,omitempty" description:"The symbolic name, like ER_DUP_ENTRY"`
+	Category string `json:"category,omitempty" enum:"client error,constraint,lock,permission,syntax,server"`
+}
+
+// ResultSet is the columns and rows returned by a query or execute.
+// Values are strings or null for a query, for an execute they're typed.
+type ResultSet struct {
+	Columns       []Column        `json:"columns"`
+	Rows          [][]interface{} `json:"rows"`
+	CachedColumns bool            `json:"cached_columns,omitempty" description:"Columns from an earlier result"`
+}
+
+// PrepareOK is the response to a prepare.
+type PrepareOK struct {
+	StatementID uint32   `json:"statement_id"`
+	NumColumns  uint16   `json:"num_columns"`
+	NumParams   uint16   `json:"num_params"`
+	Warnings    int16    `json:"warnings"`
+	Columns     []Column `json:"columns"`
+	Params      []Column `json:"params"`
+}
+
+// Statistics is the response to COM_STATISTICS.
+type Statistics struct {
+	Status string `json:"status"`
+}
+
+// FieldListResponse is the response to COM_FIELD_LIST.
+type FieldListResponse struct {
+	Columns []Column `json:"columns"`
+}
+
+// Empty is the data for the kinds that have none, like eof.
+type Empty struct{}
+
+// DecodeError is data that couldn't be decoded.
+type DecodeError struct {
+	Error               string  `json:"error"`
+	DecoderState        string  `json:"decoder_state,omitempty"`
+	CompressionOn       bool    `json:"compression_on"`
+	JustSeenGreeting    bool    `json:"just_seen_greeting,omitempty"`
+	PreviousRequestType string  `json:"previous_request_type,omitempty"`
+	Packet              *Packet `json:"packet,omitempty" description:"The data that couldn't be decoded"`
+}
+
+// Unknown is anything else, with the type the decoder gave it.  It
+// shouldn't happen, it's there so nothing is lost.
+type Unknown struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// kinds maps the kinds to the type of their data, in the order they are
+// listed in the JSON Schema.
+//
+//nolint:gochecknoglobals
+var kinds = []struct {
+	kind string
+	data interface{}
+}{
+	{"greeting", Greeting{}},
+	{"login", Login{}},
+	{"ssl_request", SSLRequest{}},
+	{"change_user", ChangeUser{}},
+	{"auth_response", AuthData{}},
+	{"query", Query{}},
+	{"prepare", Query{}},
+	{"execute", Execute{}},
+	{"command", Command{}},
+	{"init_db", InitDB{}},
+	{"field_list", FieldList{}},
+	{"process_kill", ProcessKill{}},
+	{"set_option", SetOption{}},
+	{"refresh", Refresh{}},
+	{"shutdown", Shutdown{}},
+	{"ok", OK{}},
+	{"error", Error{}},
+	{"eof", Empty{}},
+	{"local_infile", Empty{}},
+	{"result_set", ResultSet{}},
+	{"prepare_ok", PrepareOK{}},
+	{"auth_switch", AuthSwitch{}},
+	{"auth_more_data", AuthData{}},
+	{"statistics", Statistics{}},
+	{"field_list_response", FieldListResponse{}},
+	{"decode_error", DecodeError{}},
+	{"unknown", Unknown{}},
+}
+
+const (
+	request  = "request"
+	response = "response"
+)
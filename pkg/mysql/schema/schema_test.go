@@ -0,0 +1,228 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"flag"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"github.com/colinnewell/pcap-cli/tcp"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/schema"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+const schemaDocument = "../../../docs/output-v2.schema.json"
+
+var update = flag.Bool("update", false, "Regenerate "+schemaDocument)
+
+var seen = time.Date(2021, 4, 4, 17, 28, 50, 538141000, time.UTC)
+
+func connection() structure.Connection {
+	zero, two := 0, 2
+	return structure.Connection{
+		Address: tcp.ConnectionAddress{
+			IP:   gopacket.NewFlow(layers.EndpointIPv4, net.IP{10, 0, 0, 2}, net.IP{10, 0, 0, 1}),
+			Port: gopacket.NewFlow(layers.EndpointTCPPort, []byte{0xc3, 0x50}, []byte{0x0c, 0xea}),
+		},
+		Items: []structure.Transmission{
+			{
+				Data: structure.WithRawPacket{
+					RawData:      []byte{1, 2, 3},
+					Transmission: structure.Request{Type: "Query", Query: "SELECT id FROM peeps"},
+				},
+				Seen: []time.Time{seen},
+			},
+			{
+				Data: structure.ResultSetResponse{
+					Type: "SQL results",
+					Columns: []structure.ColumnInfo{{
+						Catalog:     "def",
+						Schema:      "demo",
+						TableAlias:  "p",
+						Table:       "peeps",
+						ColumnAlias: "id",
+						Column:      "person_id",
+						TypeInfo: structure.TypeInfo{
+							CharacterSetNumber: 63,
+							MaxColumnSize:      11,
+							FieldTypes:         structure.LONG,
+							FieldDetail:        structure.DETAIL_NOT_NULL | structure.DETAIL_PRIMARY_KEY,
+						},
+					}},
+					Results: [][]interface{}{{"1"}},
+				},
+				Seen:       []time.Time{seen},
+				ResponseTo: &zero,
+			},
+			{Data: structure.Request{Type: "PING"}},
+			{
+				Data: structure.OKResponse{
+					Type:         "OK",
+					ServerStatus: structure.SERVER_STATUS_AUTOCOMMIT | 4,
+				},
+				Seen:       []time.Time{seen},
+				ResponseTo: &two,
+			},
+			{Data: structure.DecodeError{DecodeErrorString: "bad", Direction: "Response"}},
+		},
+		Exchanges: []structure.Exchange{
+			{Request: 0, Responses: []int{1}, Command: "Query", TimeToFirstByte: time.Millisecond, Rows: 1},
+			{Request: 2, Command: "PING"},
+		},
+		Summary: structure.ConnectionSummary{
+			Start:         seen,
+			End:           seen.Add(time.Second),
+			Ending:        structure.EndingQuit,
+			Capabilities:  structure.CCAP_CLIENT_PROTOCOL_41,
+			Commands:      map[string]int{"Query": 1, "PING": 1},
+			Rows:          1,
+			RequestBytes:  29,
+			ResponseBytes: 64,
+			ServerTime:    time.Millisecond,
+			IdleTime:      999 * time.Millisecond,
+		},
+	}
+}
+
+func TestFromConnection(t *testing.T) {
+	conn, err := schema.FromConnection(connection())
+	if err != nil {
+		t.Fatal(err)
+	}
+	zero, two := 0, 2
+	expected := schema.Connection{
+		SchemaVersion: 2,
+		Address:       "10.0.0.2:50000 - 10.0.0.1:3306",
+		Client:        "10.0.0.2:50000",
+		Server:        "10.0.0.1:3306",
+		Items: []schema.Item{
+			{
+				Index:     0,
+				Kind:      "query",
+				Direction: "request",
+				Seen:      []time.Time{seen},
+				Data:      schema.Query{Query: "SELECT id FROM peeps"},
+				RawData:   []byte{1, 2, 3},
+			},
+			{
+				Index:      1,
+				Kind:       "result_set",
+				Direction:  "response",
+				Seen:       []time.Time{seen},
+				ResponseTo: &zero,
+				Data: schema.ResultSet{
+					Columns: []schema.Column{{
+						Catalog:      "def",
+						Schema:       "demo",
+						Table:        "p",
+						OrgTable:     "peeps",
+						Name:         "id",
+						OrgName:      "person_id",
+						CharacterSet: 63,
+						Length:       11,
+						Type:         schema.Enum{Value: 3, Name: "MYSQL_TYPE_LONG"},
+						Flags:        schema.Flags{Value: 3, Names: []string{"NOT_NULL_FLAG", "PRI_KEY_FLAG"}},
+					}},
+					Rows: [][]interface{}{{"1"}},
+				},
+			},
+			{
+				Index:     2,
+				Kind:      "command",
+				Direction: "request",
+				Seen:      []time.Time{},
+				Data:      schema.Command{Name: "PING"},
+			},
+			{
+				Index:      3,
+				Kind:       "ok",
+				Direction:  "response",
+				Seen:       []time.Time{seen},
+				ResponseTo: &two,
+				Data: schema.OK{
+					Status: schema.Flags{Value: 6, Names: []string{"SERVER_STATUS_AUTOCOMMIT"}},
+				},
+			},
+			{
+				Index:     4,
+				Kind:      "decode_error",
+				Direction: "response",
+				Seen:      []time.Time{},
+				Data:      schema.DecodeError{Error: "bad"},
+			},
+		},
+		Exchanges: []schema.Exchange{
+			{Request: 0, Responses: []int{1}, Command: "Query", TimeToFirstByteNs: 1000000, Rows: 1},
+			{Request: 2, Responses: []int{}, Command: "PING"},
+		},
+		Summary: schema.Summary{
+			Start:         seen,
+			End:           seen.Add(time.Second),
+			Ending:        "QUIT",
+			Capabilities:  schema.Flags{Value: 512, Names: []string{"CLIENT_PROTOCOL_41"}},
+			Commands:      map[string]int{"Query": 1, "PING": 1},
+			Rows:          1,
+			RequestBytes:  29,
+			ResponseBytes: 64,
+			ServerTimeNs:  1000000,
+			IdleTimeNs:    999000000,
+		},
+	}
+	if diff := cmp.Diff(conn, expected); diff != "" {
+		t.Fatalf("Connection doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestCapabilityFlags(t *testing.T) {
+	f := schema.CapabilityFlags(structure.CCAP_CLIENT_MYSQL | 1<<13 | 1<<15)
+	expected := schema.Flags{
+		Value: 0xa001,
+		Names: []string{"CLIENT_LONG_PASSWORD", "CLIENT_TRANSACTIONS", "CLIENT_SECURE_CONNECTION"},
+	}
+	if diff := cmp.Diff(f, expected); diff != "" {
+		t.Fatalf("Flags don't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestItemJSON(t *testing.T) {
+	conn, err := schema.FromConnection(connection())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(conn.Items[3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"index":3,"kind":"ok","direction":"response","seen":["2021-04-04T17:28:50.538141Z"],` +
+		`"response_to":2,"data":{"affected_rows":0,"last_insert_id":0,` +
+		`"status":{"value":6,"names":["SERVER_STATUS_AUTOCOMMIT"]},"warnings":0}}`
+	if diff := cmp.Diff(string(b), expected); diff != "" {
+		t.Fatalf("Json doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestDocumentUpToDate(t *testing.T) {
+	doc, err := schema.Document()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc = append(doc, '\n')
+	if *update {
+		if err := os.WriteFile(schemaDocument, doc, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(schemaDocument)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(doc), string(expected)); diff != "" {
+		t.Fatalf("%s is out of date, run go test with -update (-got +expected):\n%s\n", schemaDocument, diff)
+	}
+}
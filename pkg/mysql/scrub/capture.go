@@ -0,0 +1,281 @@
+package scrub
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+	"github.com/pkg/errors"
+)
+
+// capture is a whole capture file read into memory so it can be written
+// back out with the same packets in the same order.
+type capture struct {
+	frames   []*frame
+	linkType layers.LinkType
+	snaplen  uint32
+	nanos    bool
+	// ng is set for pcapng files, for their interfaces.
+	ng *pcapgo.NgReader
+}
+
+// frame is a packet from the capture.  For TCP over IP it also records
+// where the headers and payload are so they can be rewritten.
+type frame struct {
+	info gopacket.CaptureInfo
+	data []byte
+
+	tcp     bool
+	ipv4    bool
+	ip      int
+	header  int
+	payload int
+	size    int
+
+	flows            [2]gopacket.Flow
+	seq, ack         uint32
+	syn, fin, hasAck bool
+
+	// rel is where the payload starts in the stream.
+	rel     int64
+	changed bool
+}
+
+const ngMagic = 0x0A0D0D0A
+
+func readCapture(r io.Reader) (*capture, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(4)
+	if err != nil {
+		return nil, errors.Wrap(err, "read-magic")
+	}
+	var source gopacket.PacketDataSource
+	c := &capture{}
+	if binary.LittleEndian.Uint32(magic) == ngMagic {
+		ng, err := pcapgo.NewNgReader(br, pcapgo.DefaultNgReaderOptions)
+		if err != nil {
+			return nil, errors.Wrap(err, "read-pcapng")
+		}
+		c.ng, c.linkType, source = ng, ng.LinkType(), ng
+	} else {
+		p, err := pcapgo.NewReader(br)
+		if err != nil {
+			return nil, errors.Wrap(err, "read-pcap")
+		}
+		c.linkType, c.snaplen, source = p.LinkType(), p.Snaplen(), p
+		c.nanos = p.Resolution() == gopacket.TimestampResolutionNanosecond
+	}
+	for {
+		data, info, err := source.ReadPacketData()
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return c, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "read-packet")
+		}
+		f := &frame{info: info, data: data}
+		f.decode(c.linkType)
+		c.frames = append(c.frames, f)
+	}
+}
+
+func (c *capture) write(w io.Writer) error {
+	if c.ng != nil {
+		return c.writeNg(w)
+	}
+	pw := pcapgo.NewWriter(w)
+	if c.nanos {
+		pw = pcapgo.NewWriterNanos(w)
+	}
+	if err := pw.WriteFileHeader(c.snaplen, c.linkType); err != nil {
+		return errors.Wrap(err, "write-header")
+	}
+	for _, f := range c.frames {
+		if err := pw.WritePacket(f.info, f.data); err != nil {
+			return errors.Wrap(err, "write-packet")
+		}
+	}
+	return nil
+}
+
+func (c *capture) writeNg(w io.Writer) error {
+	interfaces := make([]pcapgo.NgInterface, c.ng.NInterfaces())
+	for i := range interfaces {
+		intf, err := c.ng.Interface(i)
+		if err != nil {
+			return errors.Wrap(err, "read-interface")
+		}
+		// the times read have the offset added already.
+		intf.TimestampOffset = 0
+		interfaces[i] = intf
+	}
+	if len(interfaces) == 0 {
+		interfaces = append(interfaces, pcapgo.DefaultNgInterface)
+	}
+	options := pcapgo.NgWriterOptions{SectionInfo: c.ng.SectionInfo()}
+	nw, err := pcapgo.NewNgWriterInterface(w, interfaces[0], options)
+	if err != nil {
+		return errors.Wrap(err, "write-pcapng")
+	}
+	for _, intf := range interfaces[1:] {
+		if _, err := nw.AddInterface(intf); err != nil {
+			return errors.Wrap(err, "write-interface")
+		}
+	}
+	for _, f := range c.frames {
+		if err := nw.WritePacket(f.info, f.data); err != nil {
+			return errors.Wrap(err, "write-packet")
+		}
+	}
+	return errors.Wrap(nw.Flush(), "write-flush")
+}
+
+// decode picks out the TCP headers.  Fragments and anything else that isn't
+// TCP over IP are left as they are.
+func (f *frame) decode(linkType layers.LinkType) {
+	p := gopacket.NewPacket(f.data, linkType, gopacket.DecodeOptions{NoCopy: true, Lazy: true})
+	t, ok := p.TransportLayer().(*layers.TCP)
+	if !ok || p.NetworkLayer() == nil {
+		return
+	}
+	switch ip := p.NetworkLayer().(type) {
+	case *layers.IPv4:
+		if ip.Flags&layers.IPv4MoreFragments != 0 || ip.FragOffset != 0 {
+			return
+		}
+		f.ipv4 = true
+	case *layers.IPv6:
+	default:
+		return
+	}
+	f.tcp = true
+	f.ip = offset(f.data, p.NetworkLayer().LayerContents())
+	f.header = offset(f.data, t.Contents)
+	f.payload = f.header + len(t.Contents)
+	f.size = len(t.Payload)
+	f.flows = [2]gopacket.Flow{p.NetworkLayer().NetworkFlow(), t.TransportFlow()}
+	f.seq, f.ack = t.Seq, t.Ack
+	f.syn, f.fin, f.hasAck = t.SYN, t.FIN, t.ACK
+}
+
+// offset gives where part starts in data, the layers being slices of the
+// data when decoded without copying.
+func offset(data, part []byte) int {
+	return cap(data) - cap(part)
+}
+
+// sackEdges gives the offsets of the edges of the selective
+// acknowledgements in the TCP options.
+func (f *frame) sackEdges() []int {
+	const (
+		end  = 0
+		nop  = 1
+		sack = 5
+	)
+	var edges []int
+	options := f.data[f.header+tcpFixedHeader : f.payload]
+	for i := 0; i < len(options); {
+		switch options[i] {
+		case end:
+			return edges
+		case nop:
+			i++
+			continue
+		}
+		if i+1 >= len(options) || options[i+1] < 2 {
+			return edges
+		}
+		length := int(options[i+1])
+		if options[i] == sack {
+			for e := i + 2; e+4 <= i+length && e+4 <= len(options); e += 4 {
+				edges = append(edges, f.header+tcpFixedHeader+e)
+			}
+		}
+		i += length
+	}
+	return edges
+}
+
+const (
+	tcpFixedHeader = 20
+	ipv6Header     = 40
+)
+
+// setPayload puts a new payload in the frame, fixing up the lengths.
+func (f *frame) setPayload(payload []byte) {
+	delta := len(payload) - f.size
+	data := make([]byte, 0, len(f.data)+delta)
+	data = append(data, f.data[:f.payload]...)
+	data = append(data, payload...)
+	data = append(data, f.data[f.payload+f.size:]...)
+	f.data = data
+	f.size = len(payload)
+	f.info.CaptureLength += delta
+	f.info.Length += delta
+	if f.ipv4 {
+		length := binary.BigEndian.Uint16(f.data[f.ip+2:])
+		//nolint:gosec // ip lengths are 16 bit.
+		binary.BigEndian.PutUint16(f.data[f.ip+2:], uint16(int(length)+delta))
+	} else {
+		length := binary.BigEndian.Uint16(f.data[f.ip+4:])
+		//nolint:gosec // ip lengths are 16 bit.
+		binary.BigEndian.PutUint16(f.data[f.ip+4:], uint16(int(length)+delta))
+	}
+	f.changed = true
+}
+
+func (f *frame) setSeq(seq uint32) {
+	if seq != f.seq {
+		binary.BigEndian.PutUint32(f.data[f.header+4:], seq)
+		f.changed = true
+	}
+}
+
+func (f *frame) setAck(ack uint32) {
+	if ack != f.ack {
+		binary.BigEndian.PutUint32(f.data[f.header+8:], ack)
+		f.changed = true
+	}
+}
+
+// checksum recalculates the checksums of a changed frame.  They can't be
+// when the packet wasn't captured in full.
+func (f *frame) checksum() {
+	if !f.changed || f.info.CaptureLength < f.info.Length {
+		return
+	}
+	var src, dst []byte
+	if f.ipv4 {
+		header := f.data[f.ip : f.ip+int(f.data[f.ip]&0x0f)*4]
+		header[10], header[11] = 0, 0
+		binary.BigEndian.PutUint16(header[10:], fold(sum(header)))
+		src, dst = header[12:16], header[16:20]
+	} else {
+		src, dst = f.data[f.ip+8:f.ip+24], f.data[f.ip+24:f.ip+ipv6Header]
+	}
+	segment := f.data[f.header : f.payload+f.size]
+	segment[16], segment[17] = 0, 0
+	total := sum(src) + sum(dst) + uint32(layers.IPProtocolTCP) + uint32(len(segment)) + sum(segment)
+	binary.BigEndian.PutUint16(segment[16:], fold(total))
+}
+
+func sum(data []byte) uint32 {
+	var total uint32
+	for i := 0; i+1 < len(data); i += 2 {
+		total += uint32(binary.BigEndian.Uint16(data[i:]))
+	}
+	if len(data)%2 == 1 {
+		total += uint32(data[len(data)-1]) << 8
+	}
+	return total
+}
+
+func fold(total uint32) uint16 {
+	for total > 0xffff {
+		total = total>>16 + total&0xffff
+	}
+	return ^uint16(total)
+}
@@ -0,0 +1,496 @@
+package scrub
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/decoding/bitmap"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/redact"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+const (
+	comQuery        = 0x03
+	comChangeUser   = 0x11
+	comStmtPrepare  = 0x16
+	comStmtExecute  = 0x17
+	comSendLongData = 0x18
+
+	loginHeaderLen      = 32
+	paramCountAvailable = 0x08
+	unsignedParam       = 128
+	nullValue           = 0xfb
+)
+
+var errMalformed = errors.New("malformed packet")
+
+// paramType is the type sent for a statement parameter or query attribute.
+type paramType struct {
+	fieldType structure.FieldType
+	unsigned  bool
+}
+
+// rewriter works through the items of a connection, keeping track of the
+// state the decoder did, and works out the new packets.
+type rewriter struct {
+	o            redact.Options
+	capabilities structure.ClientCapabilities
+	params       map[uint32]int
+	types        map[uint32][]paramType
+	previous     string
+	infile       bool
+}
+
+func newRewriter(o redact.Options) *rewriter {
+	r := &rewriter{o: o}
+	r.reset()
+	return r
+}
+
+// reset forgets what doesn't survive COM_CHANGE_USER and
+// COM_RESET_CONNECTION.
+func (r *rewriter) reset() {
+	r.params = make(map[uint32]int)
+	r.types = make(map[uint32][]paramType)
+}
+
+// request says whether an item came from the client.  Only the decode
+// errors record it.
+func request(item interface{}) bool {
+	switch v := item.(type) {
+	case structure.LoginRequest, structure.SSLRequest, structure.ChangeUserRequest, structure.AuthResponse,
+		structure.Request, structure.ExecuteRequest, structure.InitDBRequest,
+		structure.FieldListRequest, structure.ProcessKillRequest, structure.SetOptionRequest,
+		structure.RefreshRequest, structure.ShutdownRequest:
+		return true
+	case structure.DecodeError:
+		return v.Direction == "Request"
+	}
+	return false
+}
+
+// item rewrites the packets of an item, returning the new payloads by
+// packet, nil where they're unchanged.
+func (r *rewriter) item(item interface{}, ps []mysqlPacket) ([][]byte, error) {
+	payloads := make([][]byte, len(ps))
+	if len(ps) == 0 {
+		return payloads, nil
+	}
+	if request(item) && r.infile {
+		// the contents of a LOAD DATA LOCAL file, which the decoder
+		// takes for commands.
+		for i, p := range ps {
+			payloads[i] = r.o.FitValue(r.o.Values, p.payload)
+		}
+		return payloads, nil
+	}
+	first := ps[0].payload
+	var err error
+	switch v := item.(type) {
+	case structure.LoginRequest:
+		r.capabilities = v.ClientCapabilities
+		r.reset()
+		payloads[0] = r.auth(first, loginHeaderLen, v.Username, v.AuthData, r.capabilities)
+	case structure.ChangeUserRequest:
+		r.reset()
+		// the length is never lenenc here.
+		payloads[0] = r.auth(first, 1, v.Username, v.AuthData,
+			r.capabilities&^structure.CCAP_PLUGIN_AUTH_LENENC_CLIENT_DATA)
+	case structure.AuthResponse:
+		payloads[0] = make([]byte, len(first))
+	case structure.Request:
+		payloads[0], err = r.request(v, first)
+	case structure.ExecuteRequest:
+		payloads[0], err = r.execute(first)
+	case structure.PrepareOKResponse:
+		r.params[v.StatementID] = int(v.NumParams)
+	case structure.ResultSetResponse:
+		err = r.results(v, ps, payloads)
+	}
+	if !request(item) {
+		response, ok := item.(structure.Response)
+		r.infile = ok && response.Type == "In file"
+	} else if _, ok := item.(structure.DecodeError); !ok {
+		r.previous = typeName(item)
+	}
+	return payloads, err
+}
+
+func typeName(item interface{}) string {
+	switch v := item.(type) {
+	case structure.Request:
+		return v.Type
+	case structure.ExecuteRequest:
+		return v.Type
+	}
+	return ""
+}
+
+// auth blanks the authentication data, which follows the user name.
+func (r *rewriter) auth(
+	p []byte, start int, username string, data []byte, capabilities structure.ClientCapabilities,
+) []byte {
+	i := start + len(username) + 1
+	blank := byte(0)
+	switch {
+	case capabilities&structure.CCAP_PLUGIN_AUTH_LENENC_CLIENT_DATA != 0:
+		_, width := lenenc(p[min(i, len(p)):])
+		i += width
+	case capabilities&structure.CCAP_SECURE_CONNECTION != 0:
+		i++
+	default:
+		// terminated by a nul, so that can't be used.
+		blank = '*'
+	}
+	if len(data) == 0 || i+len(data) > len(p) || !bytes.Equal(p[i:i+len(data)], data) {
+		return nil
+	}
+	out := append([]byte{}, p...)
+	for j := i; j < i+len(data); j++ {
+		out[j] = blank
+	}
+	return out
+}
+
+func (r *rewriter) request(v structure.Request, p []byte) ([]byte, error) {
+	switch v.Type {
+	case "MYSQL_RESET_CONNECTION":
+		r.reset()
+	case "MYSQL_STMT_SEND_LONG_DATA":
+		// statement and parameter, then the data.
+		const header = 7
+		if p[0] != comSendLongData || len(p) < header {
+			return nil, nil
+		}
+		return append(append([]byte{}, p[:header]...), r.o.FitValue(r.o.Values, p[header:])...), nil
+	case "Prepare", "Query":
+		if p[0] != comQuery && p[0] != comStmtPrepare {
+			return nil, nil
+		}
+		// the query comes last, after any attributes.
+		query := len(p) - len(v.Query)
+		if query < 1 || string(p[query:]) != v.Query {
+			return nil, errors.Wrap(errMalformed, "query")
+		}
+		out := append([]byte{}, p[:query]...)
+		if query > 1 {
+			count, width := lenenc(p[1:])
+			_, setWidth := lenenc(p[1+width:])
+			start := 1 + width + setWidth
+			if count > 0 {
+				//nolint:gosec // the count was checked by the decoder.
+				params, n, _, err := r.parameters(p[start:query], int(count), true, nil)
+				if err != nil || start+n != query {
+					return nil, errors.Wrap(errMalformed, "query attributes")
+				}
+				out = append(out[:start], params...)
+			}
+		}
+		return append(out, r.o.FitSQL(v.Query)...), nil
+	}
+	return nil, nil
+}
+
+func (r *rewriter) execute(p []byte) ([]byte, error) {
+	const header = 10
+	if len(p) < header || p[0] != comStmtExecute {
+		return nil, errors.Wrap(errMalformed, "execute")
+	}
+	statement := binary.LittleEndian.Uint32(p[1:])
+	count := r.params[statement]
+	total := count
+	i := header
+	attributes := r.capabilities&structure.CCAP_CLIENT_QUERY_ATTRIBUTES != 0 && p[5]&paramCountAvailable != 0
+	if attributes {
+		n, width := lenenc(p[i:])
+		if width == 0 {
+			return nil, errors.Wrap(errMalformed, "execute")
+		}
+		//nolint:gosec // the count was checked by the decoder.
+		total, i = int(n), i+width
+	}
+	if len(p)-i <= 1 || total == 0 {
+		return nil, nil
+	}
+	params, n, types, err := r.parameters(p[i:], total, attributes, r.types[statement])
+	if err != nil {
+		return nil, err
+	}
+	r.types[statement] = types
+	out := append(append([]byte{}, p[:i]...), params...)
+	return append(out, p[i+n:]...), nil
+}
+
+// parameters rewrites the values of statement parameters or query
+// attributes.  It returns the new bytes for them, how many of the original
+// they replace, and the types.  The types are only sent when they change,
+// so the last ones sent for the statement have to be used otherwise.  When
+// they aren't known the values can't be picked out, so they're blanked.
+func (r *rewriter) parameters(
+	p []byte, count int, names bool, types []paramType,
+) ([]byte, int, []paramType, error) {
+	size := (count + bitmap.ExecuteParams) / 8
+	if len(p) < size+1 {
+		return nil, 0, types, errors.Wrap(errMalformed, "parameters")
+	}
+	nulls := bitmap.New(append([]byte{}, p[:size]...), count, bitmap.ExecuteParams)
+	i := size + 1
+	if p[size] == 1 {
+		types = make([]paramType, count)
+		for n := range types {
+			if i+2 > len(p) {
+				return nil, 0, types, errors.Wrap(errMalformed, "parameter types")
+			}
+			types[n] = paramType{structure.FieldType(p[i]), p[i+1]&unsignedParam != 0}
+			i += 2
+			if names {
+				length, width := lenenc(p[i:])
+				//nolint:gosec // bounds checked below.
+				i += width + int(length)
+				if width == 0 || i > len(p) {
+					return nil, 0, types, errors.Wrap(errMalformed, "parameter names")
+				}
+			}
+		}
+	}
+	header := p[size:i]
+	if len(types) != count {
+		values := p[i:]
+		if r.o.Values != redact.Keep {
+			values = make([]byte, len(values))
+		}
+		return append(append(nulls.Data, header...), values...), len(p), types, nil
+	}
+	var values []byte
+	for n, t := range types {
+		if nulls.IsNull(n) {
+			continue
+		}
+		value, width, err := r.binary(r.o.Values, p[i:], t)
+		if err != nil {
+			return nil, 0, types, err
+		}
+		if value == nil {
+			nulls.SetNull(n)
+		}
+		values = append(values, value...)
+		i += width
+	}
+	out := append(append(nulls.Data, header...), values...)
+	return out, i, types, nil
+}
+
+// results rewrites the rows of a result set.  They follow the column
+// count, the column definitions unless they were cached, and an EOF unless
+// those are deprecated.  They're in the binary protocol for statements.
+func (r *rewriter) results(v structure.ResultSetResponse, ps []mysqlPacket, payloads [][]byte) error {
+	actions := r.o.ColumnActions(v.Columns)
+	start := 1
+	if !v.CachedColumns {
+		start += len(v.Columns)
+	}
+	if r.capabilities&structure.CCAP_CLIENT_DEPRECATE_EOF == 0 {
+		start++
+	}
+	for i := start; i < len(ps); i++ {
+		row := ps[i].payload
+		if len(row) > 0 && row[0] == byte(structure.MySQLEOF) {
+			break
+		}
+		var err error
+		if r.previous == "Execute" {
+			payloads[i], err = r.binaryRow(v.Columns, actions, row)
+		} else {
+			payloads[i], err = r.textRow(actions, row)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *rewriter) textRow(actions []redact.Action, row []byte) ([]byte, error) {
+	out := make([]byte, 0, len(row))
+	i := 0
+	for _, action := range actions {
+		if i < len(row) && row[i] == nullValue {
+			out = append(out, nullValue)
+			i++
+			continue
+		}
+		length, width := lenenc(row[i:])
+		//nolint:gosec // bounds checked below.
+		end := i + width + int(length)
+		if width == 0 || end > len(row) {
+			return nil, errors.Wrap(errMalformed, "text row")
+		}
+		value := r.o.FitValue(action, row[i+width:end])
+		switch {
+		case value == nil:
+			out = append(out, nullValue)
+		case len(value) == end-i-width:
+			out = append(out, row[i:i+width]...)
+			out = append(out, value...)
+		default:
+			out = append(out, encodeLength(len(value))...)
+			out = append(out, value...)
+		}
+		i = end
+	}
+	return append(out, row[i:]...), nil
+}
+
+func (r *rewriter) binaryRow(columns []structure.ColumnInfo, actions []redact.Action, row []byte) ([]byte, error) {
+	size := (len(columns) + bitmap.ResultSetRow) / 8
+	if len(row) < size+1 || row[0] != 0 {
+		return nil, errors.Wrap(errMalformed, "binary row")
+	}
+	nulls := bitmap.New(append([]byte{}, row[1:1+size]...), len(columns), bitmap.ResultSetRow)
+	var values []byte
+	i := 1 + size
+	for n, c := range columns {
+		if nulls.IsNull(n) {
+			continue
+		}
+		t := paramType{c.TypeInfo.FieldTypes, c.TypeInfo.FieldDetail&structure.DETAIL_UNSIGNED != 0}
+		value, width, err := r.binary(actions[n], row[i:], t)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			nulls.SetNull(n)
+		}
+		values = append(values, value...)
+		i += width
+	}
+	out := append([]byte{0}, nulls.Data...)
+	out = append(out, values...)
+	return append(out, row[i:]...), nil
+}
+
+// binary rewrites a value in the binary protocol, returning the new value,
+// nil to make it null, and the width of the original.  Numbers are hashed
+// through their text so they come out as they do in the text protocol as
+// far as they fit, and dates are blanked.
+func (r *rewriter) binary(action redact.Action, p []byte, t paramType) ([]byte, int, error) {
+	width := 0
+	switch t.fieldType {
+	case structure.TINY:
+		width = 1
+	case structure.SHORT, structure.YEAR:
+		width = 2
+	case structure.INT24, structure.LONG, structure.FLOAT:
+		width = 4
+	case structure.LONGLONG, structure.DOUBLE:
+		width = 8
+	case structure.NULL:
+		return []byte{}, 0, nil
+	case structure.DATE, structure.DATETIME, structure.TIMESTAMP, structure.TIME:
+		if len(p) == 0 || len(p) < 1+int(p[0]) {
+			return nil, 0, errors.Wrap(errMalformed, "date")
+		}
+		width = 1 + int(p[0])
+		if action == redact.Keep {
+			return p[:width], width, nil
+		}
+		if action == redact.Drop {
+			return nil, width, nil
+		}
+		value := make([]byte, width)
+		value[0] = p[0]
+		return value, width, nil
+	default:
+		length, w := lenenc(p)
+		//nolint:gosec // bounds checked below.
+		width = w + int(length)
+		if w == 0 || width > len(p) {
+			return nil, 0, errors.Wrap(errMalformed, "binary value")
+		}
+		value := r.o.FitValue(action, p[w:width])
+		if value == nil {
+			return nil, width, nil
+		}
+		return append(encodeLength(len(value)), value...), width, nil
+	}
+	if len(p) < width {
+		return nil, 0, errors.Wrap(errMalformed, "binary value")
+	}
+	switch action {
+	case redact.Keep:
+		return p[:width], width, nil
+	case redact.Drop:
+		return nil, width, nil
+	case redact.Mask:
+		return make([]byte, width), width, nil
+	}
+	return r.number(p[:width], t), width, nil
+}
+
+// number hashes a binary number through its text.
+func (r *rewriter) number(p []byte, t paramType) []byte {
+	out := make([]byte, len(p))
+	switch t.fieldType {
+	case structure.FLOAT:
+		f := math.Float32frombits(binary.LittleEndian.Uint32(p))
+		text := r.o.FitValue(redact.Hash, []byte(strconv.FormatFloat(float64(f), 'g', -1, 32)))
+		if v, err := strconv.ParseFloat(string(text), 32); err == nil && !math.IsInf(v, 0) {
+			binary.LittleEndian.PutUint32(out, math.Float32bits(float32(v)))
+		}
+		return out
+	case structure.DOUBLE:
+		f := math.Float64frombits(binary.LittleEndian.Uint64(p))
+		text := r.o.FitValue(redact.Hash, []byte(strconv.FormatFloat(f, 'g', -1, 64)))
+		if v, err := strconv.ParseFloat(string(text), 64); err == nil && !math.IsInf(v, 0) {
+			binary.LittleEndian.PutUint64(out, math.Float64bits(v))
+		}
+		return out
+	}
+	var raw [8]byte
+	copy(raw[:], p)
+	n := binary.LittleEndian.Uint64(raw[:])
+	text := strconv.FormatUint(n, 10)
+	if !t.unsigned {
+		// sign extend.
+		shift := 64 - 8*len(p)
+		//nolint:gosec // reinterpreting the bits.
+		text = strconv.FormatInt(int64(n<<shift)>>shift, 10)
+	}
+	hashed := string(r.o.FitValue(redact.Hash, []byte(text)))
+	if v, err := strconv.ParseInt(hashed, 10, 64); err == nil {
+		//nolint:gosec // reinterpreting the bits.
+		n = uint64(v)
+	} else if v, err := strconv.ParseUint(hashed, 10, 64); err == nil {
+		n = v
+	} else {
+		n = binary.LittleEndian.Uint64(r.o.Digest(text))
+	}
+	binary.LittleEndian.PutUint64(raw[:], n)
+	copy(out, raw[:])
+	return out
+}
+
+func encodeLength(n int) []byte {
+	const (
+		twoBytes   = 0xfc
+		threeBytes = 0xfd
+		eightBytes = 0xfe
+	)
+	switch {
+	case n < nullValue:
+		return []byte{byte(n)}
+	case n < 1<<16:
+		return []byte{twoBytes, byte(n), byte(n >> 8)}
+	case n < 1<<24:
+		return append([]byte{threeBytes}, putUint24(n)...)
+	}
+	out := make([]byte, 9)
+	out[0] = eightBytes
+	//nolint:gosec // lengths aren't negative.
+	binary.LittleEndian.PutUint64(out[1:], uint64(n))
+	return out
+}
@@ -0,0 +1,295 @@
+// Package scrub rewrites the MySQL traffic in a capture to take the customer
+// data out of it, so the capture can be shared and still opened with the
+// usual tools.  The streams are decoded the way pcap2mysql-log decodes them,
+// and the values, literals and authentication data are rewritten in the
+// packets.  The redactions keep the length of what they replace where they
+// can, and where they can't the packets are rebuilt and the TCP sequence
+// numbers adjusted to suit.  Everything else, the timings included, is
+// left as it was.
+package scrub
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/colinnewell/pcap-cli/tcp"
+	"github.com/pkg/errors"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/decoding"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/redact"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+// Options say what to scrub.
+type Options struct {
+	// Redact says what to do with the literals and values.  Hashes and
+	// masks are fitted to the length of what they replace.  The
+	// authentication data is always blanked.
+	Redact redact.Options
+	// ServerPorts limits the scrubbing to connections to these ports, the
+	// rest are left as they are.
+	ServerPorts []int32
+	Verbose     bool
+}
+
+// Result says what was done to a capture.
+type Result struct {
+	Connections int
+	// Skipped is the number of connections left alone because they didn't
+	// look like MySQL.
+	Skipped int
+	// Frames is the number of frames changed.
+	Frames int
+	// Unscrubbed are the parts of connections that had to be left as
+	// they were.
+	Unscrubbed []Unscrubbed
+}
+
+// Unscrubbed is data left as it was because it couldn't be decoded, or
+// was encrypted.
+type Unscrubbed struct {
+	Connection string
+	Direction  string
+	Bytes      int
+	Reason     string
+}
+
+func (u Unscrubbed) String() string {
+	return fmt.Sprintf("%s %s: %d bytes left as they were, %s", u.Connection, u.Direction, u.Bytes, u.Reason)
+}
+
+// Scrub reads a pcap or pcapng capture and writes it out again scrubbed.
+// The whole capture is held in memory while it's worked on.
+func Scrub(o Options, r io.Reader, w io.Writer) (Result, error) {
+	var result Result
+	c, err := readCapture(r)
+	if err != nil {
+		return result, err
+	}
+	intermediate, raw, budget, dir := false, true, 0, ""
+	readers := decoding.New(&intermediate, &raw, &o.Verbose, &budget, &dir)
+	defer readers.Close()
+	for _, conn := range connections(c.frames) {
+		if !o.serverPort(conn) {
+			continue
+		}
+		unscrubbed, mysql, err := o.connection(readers, conn)
+		if err != nil {
+			return result, err
+		}
+		if !mysql {
+			if conn.requests.seen+conn.response.seen > 0 {
+				result.Skipped++
+			}
+			continue
+		}
+		result.Connections++
+		result.Unscrubbed = append(result.Unscrubbed, unscrubbed...)
+		result.Frames += conn.rewrite()
+	}
+	return result, c.write(w)
+}
+
+func (o Options) serverPort(c *connection) bool {
+	if len(o.ServerPorts) == 0 {
+		return true
+	}
+	_, server := c.flows[1].Endpoints()
+	port := binary.BigEndian.Uint16(server.Raw())
+	for _, p := range o.ServerPorts {
+		if int32(port) == p {
+			return true
+		}
+	}
+	return false
+}
+
+// seen is when the data being passed to the decoder arrived.
+type seen struct {
+	times []time.Time
+}
+
+func (s *seen) Seen() []time.Time { return s.times }
+
+func (s *seen) Reset() {}
+
+// connection decodes the connection and works out the edits to it.  The
+// data is passed to the decoder a frame at a time, in the order it was
+// captured, as the decoder relies on that to spot where compression starts.
+// Without the server ports to go on only connections with a greeting or
+// login are taken to be MySQL.
+func (o Options) connection(
+	readers *decoding.MySQLConnectionReaders, c *connection,
+) ([]Unscrubbed, bool, error) {
+	address := tcp.ConnectionAddress{IP: c.flows[0], Port: c.flows[1]}
+	completed := make(chan interface{})
+	defer close(completed)
+	connections := decoding.Assemble(completed)
+	builder := readers.ConnectionBuilder(address, completed)
+	var times seen
+	requests, responses := builder.RequestWriter(&times), builder.ResponseWriter(&times)
+	for _, f := range c.frames {
+		d, _ := c.direction(f)
+		data := d.add(f)
+		if len(data) == 0 {
+			continue
+		}
+		times.times = []time.Time{f.info.Timestamp}
+		w := requests
+		if d == &c.response {
+			w = responses
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, false, errors.Wrap(err, "scrub-decode")
+		}
+	}
+	_ = requests.Close()
+	_ = responses.Close()
+	decoded, _ := (<-connections).(structure.Connection)
+	if len(o.ServerPorts) == 0 && !handshake(decoded) {
+		return nil, false, nil
+	}
+
+	if encrypted(decoded) {
+		return []Unscrubbed{{
+			Connection: address.String(),
+			Direction:  "both",
+			Bytes:      int(c.requests.seen + c.response.seen),
+			Reason:     "encrypted",
+		}}, true, nil
+	}
+	return o.rewrite(address.String(), c, decoded), true, nil
+}
+
+func handshake(c structure.Connection) bool {
+	for _, t := range c.Items {
+		item := t.Data
+		if r, ok := item.(structure.WithRawPacket); ok {
+			item = r.Transmission
+		}
+		switch item.(type) {
+		case structure.Greeting, structure.LoginRequest, structure.SSLRequest:
+			return true
+		}
+	}
+	return false
+}
+
+// entry is a decoded item along with which side it came from and where its
+// raw data is.
+type entry struct {
+	item       interface{}
+	side       int
+	start, end int
+}
+
+const (
+	requestSide = iota
+	responseSide
+)
+
+// rewrite works out the edits to the connection from the decoded items.
+// It stops at the first sign of the items not lining up with the data
+// sent, leaving the rest as it was.
+func (o Options) rewrite(address string, c *connection, decoded structure.Connection) []Unscrubbed {
+	var entries []entry
+	var raw [2][]byte
+	compressed := false
+	for _, t := range decoded.Items {
+		item := t.Data
+		var data []byte
+		if r, ok := item.(structure.WithRawPacket); ok {
+			item, data = r.Transmission, r.RawData
+		}
+		side := responseSide
+		if request(item) {
+			side = requestSide
+		}
+		if login, ok := item.(structure.LoginRequest); ok {
+			compressed = login.ClientCapabilities&structure.CCAP_COMPRESS != 0
+		}
+		entries = append(entries, entry{item, side, len(raw[side]), len(raw[side]) + len(data)})
+		raw[side] = append(raw[side], data...)
+	}
+
+	directions := [2]*direction{&c.requests, &c.response}
+	var pieces [2][]piece
+	var matched, undecoded [2]int
+	for side, d := range directions {
+		pieces[side] = unwrap(d.data, raw[side], compressed)
+		if n := len(pieces[side]); n > 0 {
+			matched[side] = pieces[side][n-1].raw[1]
+		}
+	}
+
+	rw := newRewriter(o.Redact)
+	var rawEdits [2]edits
+	for _, e := range entries {
+		if e.end > matched[e.side] {
+			continue
+		}
+		if _, ok := e.item.(structure.DecodeError); ok {
+			undecoded[e.side] += e.end - e.start
+			continue
+		}
+		data := raw[e.side][e.start:e.end]
+		ps := packets(data)
+		payloads, err := rw.item(e.item, ps)
+		if err != nil {
+			if o.Verbose {
+				log.Printf("%s: %s\n", address, err)
+			}
+			undecoded[e.side] += e.end - e.start
+			continue
+		}
+		for i, p := range ps {
+			if payloads[i] == nil {
+				continue
+			}
+			if packet := p.encode(payloads[i]); !bytes.Equal(packet, data[p.start:p.end]) {
+				rawEdits[e.side] = append(rawEdits[e.side], edit{
+					start: int64(e.start + p.start), end: int64(e.start + p.end), data: packet,
+				})
+			}
+		}
+	}
+
+	var unscrubbed []Unscrubbed
+	for side, d := range directions {
+		d.edits = wireEdits(pieces[side], raw[side], rawEdits[side])
+		if len(d.edits) > 0 {
+			d.rewritten = d.edits.apply(d.data)
+		}
+		wire := 0
+		if n := len(pieces[side]); n > 0 {
+			wire = pieces[side][n-1].wire[1]
+		}
+		if left := int(d.seen) - wire + undecoded[side]; left > 0 {
+			unscrubbed = append(unscrubbed, Unscrubbed{
+				Connection: address,
+				Direction:  [2]string{"requests", "responses"}[side],
+				Bytes:      left,
+				Reason:     "couldn't be decoded",
+			})
+		}
+	}
+	return unscrubbed
+}
+
+// encrypted spots the SSL request that starts TLS.
+func encrypted(c structure.Connection) bool {
+	for _, t := range c.Items {
+		item := t.Data
+		if r, ok := item.(structure.WithRawPacket); ok {
+			item = r.Transmission
+		}
+		if _, ok := item.(structure.SSLRequest); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,315 @@
+package scrub_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/pcapgen"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/redact"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/scrub"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+var columns = []structure.ColumnInfo{
+	pcapgen.DemoColumn("id", structure.LONG, 0),
+	pcapgen.DemoColumn("name", structure.VAR_STRING, 0),
+}
+
+// conversation is what's said in the capture, so the same capture can be
+// built with the data as it should be after scrubbing.
+type conversation struct {
+	auth         []byte
+	query, long  string
+	id, name     *string
+	param        interface{}
+	binaryID     interface{}
+	binaryName   interface{}
+	segmentation bool
+}
+
+func original() conversation {
+	id, name := "1", "Jo"
+	return conversation{
+		auth:       []byte("0123456789abcdefghij"),
+		query:      "SELECT * FROM peeps WHERE name = 'Jo' AND id > 0",
+		long:       "SELECT '" + strings.Repeat("compress me ", 20) + "'",
+		id:         &id,
+		name:       &name,
+		param:      int64(1),
+		binaryID:   int32(1),
+		binaryName: "Jo",
+	}
+}
+
+func (c conversation) capture(t *testing.T) *pcapgen.Capture {
+	t.Helper()
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	ok := structure.OKResponse{ServerStatus: structure.SERVER_STATUS_AUTOCOMMIT}
+	login := func(compress bool) structure.LoginRequest {
+		l := pcapgen.DemoLogin(pcapgen.DemoGreeting(compress).Capabilities)
+		l.AuthData = c.auth
+		return l
+	}
+	var split []pcapgen.Option
+	if c.segmentation {
+		split = []pcapgen.Option{pcapgen.SplitAt(10, 20)}
+	}
+
+	capture := pcapgen.New(pcapgen.DemoStart)
+	conn, err := capture.Connect("10.0.0.2:50000", "10.0.0.1:3306")
+	must(err)
+	must(conn.Greeting(pcapgen.DemoGreeting(false), pcapgen.DemoHandshake))
+	must(conn.Login(login(false)))
+	must(conn.Respond([]interface{}{ok}))
+	must(conn.Request(structure.Request{Type: "Query", Query: c.query}, split...))
+	must(conn.Respond([]interface{}{structure.ResultSetResponse{
+		Columns: columns,
+		Results: [][]interface{}{{c.id, c.name}},
+	}}, split...))
+	must(conn.Request(structure.Request{Type: "Prepare", Query: "SELECT * FROM peeps WHERE id = ?"}))
+	must(conn.Respond([]interface{}{structure.PrepareOKResponse{
+		StatementID: 1,
+		NumColumns:  uint16(len(columns)),
+		NumParams:   1,
+		Columns:     columns,
+		Params:      []structure.ColumnInfo{pcapgen.DemoColumn("?", structure.LONGLONG, 0)},
+	}}))
+	must(conn.Request(structure.ExecuteRequest{
+		StatementID:    1,
+		IterationCount: 1,
+		Params:         []interface{}{c.param},
+	}))
+	must(conn.Respond([]interface{}{structure.ResultSetResponse{
+		Columns: columns,
+		Results: [][]interface{}{{c.binaryID, c.binaryName}},
+	}}))
+	must(conn.Request(structure.Request{Type: "QUIT"}))
+	must(conn.Close())
+
+	capture.Wait(time.Second)
+	conn, err = capture.Connect("10.0.0.3:50001", "10.0.0.1:3306")
+	must(err)
+	must(conn.Greeting(pcapgen.DemoGreeting(true), pcapgen.DemoHandshake))
+	must(conn.Login(login(true)))
+	must(conn.Respond([]interface{}{ok}))
+	must(conn.Request(structure.Request{Type: "Query", Query: c.long}))
+	must(conn.Respond([]interface{}{ok}))
+	must(conn.Request(structure.Request{Type: "QUIT"}))
+	must(conn.Close())
+	return capture
+}
+
+func scrubbed(t *testing.T, o scrub.Options, capture *pcapgen.Capture) (scrub.Result, []gopacket.Packet) {
+	t.Helper()
+
+	var in, out bytes.Buffer
+	if err := capture.Write(&in); err != nil {
+		t.Fatal(err)
+	}
+	result, err := scrub.Scrub(o, &in, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result, packets(t, &out)
+}
+
+func written(t *testing.T, capture *pcapgen.Capture) []gopacket.Packet {
+	t.Helper()
+
+	var b bytes.Buffer
+	if err := capture.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	return packets(t, &b)
+}
+
+func packets(t *testing.T, b *bytes.Buffer) []gopacket.Packet {
+	t.Helper()
+
+	r, err := pcapgo.NewReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var all []gopacket.Packet
+	for p := range gopacket.NewPacketSource(r, r.LinkType()).Packets() {
+		if err := p.ErrorLayer(); err != nil {
+			t.Fatal(err.Error())
+		}
+		all = append(all, p)
+	}
+	return all
+}
+
+// checksums recalculates the checksums to check the scrubbed ones.
+func checksums(t *testing.T, ps []gopacket.Packet) {
+	t.Helper()
+
+	for i, p := range ps {
+		ip, _ := p.NetworkLayer().(*layers.IPv4)
+		tcp, _ := p.TransportLayer().(*layers.TCP)
+		if err := tcp.SetNetworkLayerForChecksum(ip); err != nil {
+			t.Fatal(err)
+		}
+		scrubbed := [2]uint16{ip.Checksum, tcp.Checksum}
+		b := gopacket.NewSerializeBuffer()
+		opts := gopacket.SerializeOptions{ComputeChecksums: true}
+		if err := gopacket.SerializeLayers(b, opts, ip, tcp, gopacket.Payload(tcp.Payload)); err != nil {
+			t.Fatal(err)
+		}
+		if scrubbed != [2]uint16{ip.Checksum, tcp.Checksum} {
+			t.Errorf("Frame %d has the wrong checksums", i)
+		}
+	}
+}
+
+func timestamps(ps []gopacket.Packet) []time.Time {
+	ts := make([]time.Time, 0, len(ps))
+	for _, p := range ps {
+		ts = append(ts, p.Metadata().Timestamp)
+	}
+	return ts
+}
+
+func TestScrubMask(t *testing.T) {
+	o := redact.Options{Literals: redact.Mask, Values: redact.Mask}
+	for _, segmentation := range []bool{false, true} {
+		c := original()
+		c.segmentation = segmentation
+		capture := c.capture(t)
+		result, got := scrubbed(t, scrub.Options{Redact: o}, capture)
+		changed := 0
+		for i, p := range written(t, capture) {
+			if !bytes.Equal(p.Data(), got[i].Data()) {
+				changed++
+			}
+		}
+		if diff := cmp.Diff(result, scrub.Result{Connections: 2, Frames: changed}); diff != "" {
+			t.Errorf("Result doesn't match (-got +expected):\n%s\n", diff)
+		}
+
+		expected := original()
+		id, name := "0", "**"
+		expected.auth = make([]byte, len(expected.auth))
+		expected.query = o.FitSQL(expected.query)
+		expected.long = o.FitSQL(expected.long)
+		expected.id, expected.name = &id, &name
+		expected.param, expected.binaryID, expected.binaryName = int64(0), int32(0), name
+		expected.segmentation = segmentation
+		want := written(t, expected.capture(t))
+		if diff := cmp.Diff(pcapgen.Reassemble(got), pcapgen.Reassemble(want)); diff != "" {
+			t.Errorf("Scrubbed streams don't match (-got +expected):\n%s\n", diff)
+		}
+		if diff := cmp.Diff(timestamps(got), timestamps(want)); diff != "" {
+			t.Errorf("Timestamps don't match (-got +expected):\n%s\n", diff)
+		}
+		checksums(t, got)
+	}
+}
+
+func TestScrubHash(t *testing.T) {
+	o := redact.Options{
+		Literals: redact.Hash,
+		Values:   redact.Hash,
+		Key:      []byte("key"),
+		Rules:    []redact.Rule{{Pattern: "*.*.id", Action: redact.Keep}},
+	}
+	c := original()
+	c.param = "Jo"
+	capture := c.capture(t)
+	result, got := scrubbed(t, scrub.Options{Redact: o}, capture)
+	if len(result.Unscrubbed) > 0 {
+		t.Errorf("Expected everything scrubbed, got %v", result.Unscrubbed)
+	}
+
+	expected := c
+	name := string(o.FitValue(redact.Hash, []byte(*c.name)))
+	expected.auth = make([]byte, len(c.auth))
+	expected.query = o.FitSQL(c.query)
+	expected.long = o.FitSQL(c.long)
+	expected.name, expected.param, expected.binaryName = &name, name, name
+	want := written(t, expected.capture(t))
+	if diff := cmp.Diff(pcapgen.Reassemble(got), pcapgen.Reassemble(want)); diff != "" {
+		t.Errorf("Scrubbed streams don't match (-got +expected):\n%s\n", diff)
+	}
+	// the lengths are kept, so apart from the compressed connection the
+	// frames should be exactly the ones sent with the data hashed.
+	for i := range got {
+		if tcp, _ := got[i].TransportLayer().(*layers.TCP); tcp.DstPort == 50001 || tcp.SrcPort == 50001 {
+			continue
+		}
+		if diff := cmp.Diff(got[i].Data(), want[i].Data()); diff != "" {
+			t.Errorf("Frame %d doesn't match (-got +expected):\n%s\n", i, diff)
+		}
+	}
+	checksums(t, got)
+}
+
+func TestScrubDrop(t *testing.T) {
+	o := redact.Options{Literals: redact.Drop, Values: redact.Drop}
+	c := original()
+	c.segmentation = true
+	capture := c.capture(t)
+	result, got := scrubbed(t, scrub.Options{Redact: o}, capture)
+	if len(result.Unscrubbed) > 0 {
+		t.Errorf("Expected everything scrubbed, got %v", result.Unscrubbed)
+	}
+
+	// the packets shrink so the TCP sequence numbers have to follow for
+	// the streams to go back together.
+	expected := c
+	expected.auth = make([]byte, len(c.auth))
+	expected.query = o.FitSQL(c.query)
+	expected.long = o.FitSQL(c.long)
+	expected.id, expected.name = nil, nil
+	expected.param, expected.binaryID, expected.binaryName = nil, nil, nil
+	want := pcapgen.Reassemble(written(t, expected.capture(t)))
+	streams := pcapgen.Reassemble(got)
+	for _, responses := range []string{"10.0.0.1->10.0.0.2 3306->50000", "10.0.0.1->10.0.0.3 3306->50001"} {
+		if diff := cmp.Diff(streams[responses], want[responses]); diff != "" {
+			t.Errorf("Scrubbed responses don't match (-got +expected):\n%s\n", diff)
+		}
+	}
+	// the requests are put together a little differently by the encoder,
+	// with a NULL parameter type and small packets left uncompressed.
+	requests := streams["10.0.0.2->10.0.0.1 50000->3306"] + streams["10.0.0.3->10.0.0.1 50001->3306"]
+	for _, secret := range []string{"'Jo'", "compress me", string(c.auth)} {
+		if strings.Contains(requests, secret) {
+			t.Errorf("Expected %q to be scrubbed", secret)
+		}
+	}
+	var b bytes.Buffer
+	w := pcapgo.NewWriter(&b)
+	if err := w.WriteFileHeader(65536, layers.LinkTypeEthernet); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range got {
+		if err := w.WritePacket(p.Metadata().CaptureInfo, p.Data()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	again, err := scrub.Scrub(scrub.Options{}, &b, &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(again, scrub.Result{Connections: 2}); diff != "" {
+		t.Errorf("Scrubbed capture doesn't decode (-got +expected):\n%s\n", diff)
+	}
+
+	if diff := cmp.Diff(timestamps(got), timestamps(written(t, capture))); diff != "" {
+		t.Errorf("Timestamps don't match (-got +expected):\n%s\n", diff)
+	}
+	checksums(t, got)
+}
@@ -0,0 +1,229 @@
+package scrub
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"sort"
+
+	"github.com/google/gopacket"
+)
+
+// connection is a TCP connection in the capture.  The flows are the way
+// requests go, from the client to the server.
+type connection struct {
+	flows              [2]gopacket.Flow
+	frames             []*frame
+	requests, response direction
+}
+
+// direction is the data going one way in a connection.
+type direction struct {
+	known bool
+	base  uint32
+	// data is the stream put back together as far as it goes without a
+	// gap.
+	data    []byte
+	pending segments
+	// seen is how far the data seen goes, past any gaps.
+	seen int64
+	// edits are the changes to data, and rewritten the result.
+	edits     edits
+	rewritten []byte
+}
+
+// connections groups the TCP frames by connection, in the order they were
+// first seen.  The server is assumed to be on the lower port, as the
+// decoder does, and a SYN from the client starts a new connection when the
+// ports are reused.
+func connections(frames []*frame) []*connection {
+	var all []*connection
+	open := make(map[[2]gopacket.Flow]*connection)
+	for _, f := range frames {
+		if !f.tcp {
+			continue
+		}
+		flows := f.flows
+		if response(f) {
+			flows = [2]gopacket.Flow{flows[0].Reverse(), flows[1].Reverse()}
+		}
+		c, ok := open[flows]
+		if ok && f.syn && !f.hasAck && !response(f) && c.requests.known && c.requests.base != f.seq+1 {
+			ok = false
+		}
+		if !ok {
+			c = &connection{flows: flows}
+			open[flows] = c
+			all = append(all, c)
+		}
+		c.frames = append(c.frames, f)
+	}
+	return all
+}
+
+func response(f *frame) bool {
+	src, dst := f.flows[1].Endpoints()
+	return src.LessThan(dst)
+}
+
+func (c *connection) direction(f *frame) (this, other *direction) {
+	if response(f) {
+		return &c.response, &c.requests
+	}
+	return &c.requests, &c.response
+}
+
+// add adds the frame's payload to the stream, returning any data that's
+// now contiguous.  The stream starts after the SYN, or with the first data
+// seen when the capture starts part way through.
+func (d *direction) add(f *frame) []byte {
+	if f.syn {
+		d.known, d.base = true, f.seq+1
+		return nil
+	}
+	if f.size == 0 {
+		return nil
+	}
+	if !d.known {
+		d.known, d.base = true, f.seq
+	}
+	f.rel = int64(int32(f.seq - d.base))
+	d.seen = max(d.seen, f.rel+int64(f.size))
+	if f.rel+int64(f.size) <= int64(len(d.data)) {
+		return nil
+	}
+	heap.Push(&d.pending, segment{rel: f.rel, data: f.data[f.payload : f.payload+f.size]})
+	start := len(d.data)
+	for d.pending.Len() > 0 && d.pending[0].rel <= int64(len(d.data)) {
+		s, _ := heap.Pop(&d.pending).(segment)
+		if end := s.rel + int64(len(s.data)); end > int64(len(d.data)) {
+			d.data = append(d.data, s.data[int64(len(d.data))-s.rel:]...)
+		}
+	}
+	return d.data[start:]
+}
+
+// relative gives the offset of a sequence number in the stream.
+func (d *direction) relative(seq uint32) int64 {
+	return int64(int32(seq - d.base))
+}
+
+// offset maps a sequence number to where it is after the rewrite.
+func (d *direction) offset(seq uint32) uint32 {
+	if !d.known || len(d.edits) == 0 {
+		return seq
+	}
+	rel := d.relative(seq)
+	if rel < 0 {
+		return seq
+	}
+	//nolint:gosec // sequence numbers wrap.
+	return d.base + uint32(d.edits.offset(rel))
+}
+
+// rewrite puts the rewritten data in the frames going this way, along with
+// the new sequence and acknowledgement numbers.
+func (c *connection) rewrite() int {
+	changed := 0
+	for _, f := range c.frames {
+		this, other := c.direction(f)
+		if f.size > 0 && this.known && len(this.edits) > 0 {
+			this.payload(f)
+		}
+		if !f.syn {
+			f.setSeq(this.offset(f.seq))
+		}
+		if f.hasAck {
+			f.setAck(other.offset(f.ack))
+		}
+		for _, e := range f.sackEdges() {
+			binary.BigEndian.PutUint32(f.data[e:], other.offset(binary.BigEndian.Uint32(f.data[e:])))
+		}
+		f.checksum()
+		if f.changed {
+			changed++
+		}
+	}
+	return changed
+}
+
+// payload works out the new payload for a frame.  Anything past the end of
+// the stream, after a gap, is left as it was.
+func (d *direction) payload(f *frame) {
+	start, end := f.rel, f.rel+int64(f.size)
+	if start < 0 || start >= int64(len(d.data)) {
+		return
+	}
+	original := f.data[f.payload : f.payload+f.size]
+	payload := d.rewritten[d.edits.offset(start):d.edits.offset(min(end, int64(len(d.data))))]
+	if end > int64(len(d.data)) {
+		payload = append(append([]byte{}, payload...), original[int64(len(d.data))-start:]...)
+	}
+	if string(payload) != string(original) {
+		f.setPayload(payload)
+	}
+}
+
+// edit replaces the bytes from start to end of a stream.
+type edit struct {
+	start, end int64
+	data       []byte
+	// shift is how much the edits before this one moved it.
+	shift int64
+}
+
+// edits are in order and don't overlap.
+type edits []edit
+
+func (es edits) apply(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	last := int64(0)
+	for i := range es {
+		e := &es[i]
+		out = append(out, data[last:e.start]...)
+		e.shift = int64(len(out)) - e.start
+		out = append(out, e.data...)
+		last = e.end
+	}
+	return append(out, data[last:]...)
+}
+
+// offset maps an offset in the original to the rewritten data.  Offsets
+// inside an edit that changed length stay the same distance into it as far
+// as they can, and the end of an edit maps to the end of its replacement.
+// apply works out the shifts so has to be called first.
+func (es edits) offset(x int64) int64 {
+	i := sort.Search(len(es), func(i int) bool { return es[i].end > x })
+	if i < len(es) && x >= es[i].start {
+		e := es[i]
+		return e.start + e.shift + min(x-e.start, int64(len(e.data)))
+	}
+	if i == 0 {
+		return x
+	}
+	e := es[i-1]
+	return x + e.shift + int64(len(e.data)) - (e.end - e.start)
+}
+
+type segment struct {
+	rel  int64
+	data []byte
+}
+
+type segments []segment
+
+func (s segments) Len() int           { return len(s) }
+func (s segments) Less(i, j int) bool { return s[i].rel < s[j].rel }
+func (s segments) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func (s *segments) Push(x interface{}) {
+	if seg, ok := x.(segment); ok {
+		*s = append(*s, seg)
+	}
+}
+
+func (s *segments) Pop() interface{} {
+	old := *s
+	seg := old[len(old)-1]
+	*s = old[:len(old)-1]
+	return seg
+}
@@ -0,0 +1,231 @@
+package scrub
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+)
+
+const (
+	headerLen           = 4
+	compressedHeaderLen = 7
+	maxPayload          = 0xffffff
+)
+
+// mysqlPacket is a MySQL packet in the raw data, with packets split
+// because they went over 16MB joined back up.
+type mysqlPacket struct {
+	start, end int
+	seq        byte
+	payload    []byte
+}
+
+func packets(raw []byte) []mysqlPacket {
+	var ps []mysqlPacket
+	for i := 0; i+headerLen <= len(raw); {
+		p := mysqlPacket{start: i, seq: raw[i+3]}
+		for {
+			n := uint24(raw[i:])
+			end := min(i+headerLen+n, len(raw))
+			if p.payload == nil && n < maxPayload {
+				p.payload = raw[i+headerLen : end : end]
+			} else {
+				p.payload = append(append([]byte{}, p.payload...), raw[i+headerLen:end]...)
+			}
+			i = end
+			if n < maxPayload || i+headerLen > len(raw) {
+				break
+			}
+		}
+		p.end = i
+		ps = append(ps, p)
+	}
+	return ps
+}
+
+// encode gives the packet with a new payload, split up again if it's too
+// long for one.
+func (p mysqlPacket) encode(payload []byte) []byte {
+	out := make([]byte, 0, len(payload)+headerLen)
+	seq := p.seq
+	for {
+		n := min(len(payload), maxPayload)
+		out = append(out, putUint24(n)...)
+		out = append(out, seq)
+		out = append(out, payload[:n]...)
+		payload = payload[n:]
+		seq++
+		if n < maxPayload {
+			return out
+		}
+	}
+}
+
+func uint24(b []byte) int {
+	return int(b[0]) | int(b[1])<<8 | int(b[2])<<16
+}
+
+func putUint24(n int) []byte {
+	//nolint:gosec // packet lengths are 24 bit.
+	return []byte{byte(n), byte(n >> 8), byte(n >> 16)}
+}
+
+// lenenc reads a length encoded integer, returning it and its width, or a
+// width of 0 if there isn't one there.
+func lenenc(b []byte) (uint64, int) {
+	if len(b) == 0 {
+		return 0, 0
+	}
+	width := 1
+	switch b[0] {
+	case 0xfb:
+		return 0, 1
+	case 0xfc:
+		width = 3
+	case 0xfd:
+		width = 4
+	case 0xfe:
+		width = 9
+	default:
+		return uint64(b[0]), 1
+	}
+	if len(b) < width {
+		return 0, 0
+	}
+	var n uint64
+	for i := width - 1; i > 0; i-- {
+		n = n<<8 | uint64(b[i])
+	}
+	return n, width
+}
+
+// piece is a part of the stream sent and the part of the raw data the
+// decoder saw it as.  They're the same until compression starts.
+type piece struct {
+	wire, raw  [2]int
+	compressed bool
+	stored     bool
+	seq        byte
+}
+
+// unwrap lines up the stream sent with the raw data, returning the pieces
+// as far as they agree.  Once compression is on the data is in compressed
+// packets, the raw data being what they decompress to.
+func unwrap(wire, raw []byte, compressed bool) []piece {
+	var pieces []piece
+	w, r := 0, 0
+	plain := true
+	for w+headerLen <= len(wire) && r < len(raw) {
+		if plain {
+			end := w
+			for end+headerLen <= len(wire) {
+				n := uint24(wire[end:])
+				end += headerLen + n
+				if n < maxPayload {
+					break
+				}
+			}
+			n := end - w
+			if end <= len(wire) && n <= len(raw)-r && bytes.Equal(wire[w:end], raw[r:r+n]) {
+				pieces = append(pieces, piece{wire: [2]int{w, end}, raw: [2]int{r, r + n}})
+				w, r = end, r+n
+				continue
+			}
+			if !compressed {
+				break
+			}
+			plain = false
+		}
+		p, content, ok := envelope(wire, w)
+		if !ok || len(content) > len(raw)-r || !bytes.Equal(content, raw[r:r+len(content)]) {
+			break
+		}
+		p.raw = [2]int{r, r + len(content)}
+		pieces = append(pieces, p)
+		w, r = p.wire[1], r+len(content)
+	}
+	return pieces
+}
+
+// envelope reads the compressed packet at w.
+func envelope(wire []byte, w int) (piece, []byte, bool) {
+	if w+compressedHeaderLen > len(wire) {
+		return piece{}, nil, false
+	}
+	end := w + compressedHeaderLen + uint24(wire[w:])
+	uncompressed := uint24(wire[w+4:])
+	p := piece{wire: [2]int{w, end}, compressed: true, stored: uncompressed == 0, seq: wire[w+3]}
+	if end > len(wire) {
+		return p, nil, false
+	}
+	body := wire[w+compressedHeaderLen : end]
+	if p.stored {
+		return p, body, true
+	}
+	z, err := zlib.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return p, nil, false
+	}
+	content, err := io.ReadAll(z)
+	if err != nil || len(content) != uncompressed {
+		return p, nil, false
+	}
+	return p, content, true
+}
+
+// wrap puts new content in a compressed packet the way the original was.
+// An uncompressed length of 0 means it isn't compressed, so empty packets
+// can't be.
+func (p piece) wrap(content []byte) []byte {
+	body := content
+	uncompressed := 0
+	if !p.stored && len(content) > 0 {
+		var b bytes.Buffer
+		z := zlib.NewWriter(&b)
+		_, _ = z.Write(content)
+		_ = z.Close()
+		body, uncompressed = b.Bytes(), len(content)
+	}
+	out := make([]byte, 0, len(body)+compressedHeaderLen)
+	out = append(out, putUint24(len(body))...)
+	out = append(out, p.seq)
+	out = append(out, putUint24(uncompressed)...)
+	return append(out, body...)
+}
+
+// wireEdits turns the edits to the raw data into edits to the stream sent.
+// Compressed packets with changes are compressed again.
+func wireEdits(pieces []piece, raw []byte, rawEdits edits) edits {
+	if len(rawEdits) == 0 {
+		return nil
+	}
+	rewritten := rawEdits.apply(raw)
+	var out edits
+	i := 0
+	for _, p := range pieces {
+		start, end := int64(p.raw[0]), int64(p.raw[1])
+		for i < len(rawEdits) && rawEdits[i].end <= start {
+			i++
+		}
+		if i == len(rawEdits) {
+			break
+		}
+		if rawEdits[i].start >= end {
+			continue
+		}
+		if !p.compressed {
+			// the edits are whole packets so line up with these.
+			for ; i < len(rawEdits) && rawEdits[i].end <= end; i++ {
+				e := rawEdits[i]
+				shift := int64(p.wire[0]) - start
+				out = append(out, edit{start: e.start + shift, end: e.end + shift, data: e.data})
+			}
+			continue
+		}
+		content := rewritten[rawEdits.offset(start):rawEdits.offset(end)]
+		if !bytes.Equal(content, raw[start:end]) {
+			out = append(out, edit{start: int64(p.wire[0]), end: int64(p.wire[1]), data: p.wrap(content)})
+		}
+	}
+	return out
+}
@@ -0,0 +1,152 @@
+package slowlog
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/colinnewell/pcap-cli/tcp"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+// Entry is a query as it would appear in the MySQL slow query log.
+type Entry struct {
+	Time      time.Time
+	User      string
+	Host      string
+	Database  string
+	QueryTime time.Duration
+	RowsSent  int
+	SQL       string
+}
+
+// WriteTo writes the entry in the slow log format.  The lock time and rows
+// examined can't be seen on the wire, the lock time is only filled in as
+// some tools insist on it.
+func (e Entry) WriteTo(w io.Writer) (int64, error) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Time: %s\n", e.Time.UTC().Format("2006-01-02T15:04:05.000000Z"))
+	fmt.Fprintf(&sb, "# User@Host: %s[%s] @  [%s]\n", e.User, e.User, e.Host)
+	fmt.Fprintf(&sb, "# Query_time: %.6f  Lock_time: 0.000000 Rows_sent: %d\n",
+		e.QueryTime.Seconds(), e.RowsSent)
+	if e.Database != "" {
+		fmt.Fprintf(&sb, "use %s;\n", e.Database)
+	}
+	fmt.Fprintf(&sb, "SET timestamp=%d;\n", e.Time.Unix())
+	sql := strings.TrimRightFunc(e.SQL, func(r rune) bool {
+		return r == ';' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+	fmt.Fprintf(&sb, "%s;\n", sql)
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+// Entries finds the queries in a connection, along with who ran them and
+// how long they took.  Executes are shown with the SQL from the Prepare and
+// the parameters filled in.
+func Entries(c structure.Connection) []Entry {
+	return entries(c.Address, 0, c.Items, c.Exchanges)
+}
+
+// ItemEntries is Entries for the items the decoder hands on before the
+// connection has closed.  The user and database come from the session
+// state the decoder keeps with each command.
+func ItemEntries(c structure.ConnectionItems) []Entry {
+	return entries(c.Address, c.First, c.Items, c.Exchanges)
+}
+
+// entries finds the queries in items, which start at index first within
+// the connection.
+func entries(
+	address tcp.ConnectionAddress, first int, items []structure.Transmission, exchanges []structure.Exchange,
+) []Entry {
+	byRequest := make(map[int]structure.Exchange)
+	for _, e := range exchanges {
+		byRequest[e.Request] = e
+	}
+
+	var found []Entry
+	var user, database string
+	host := address.IP.Src().String()
+	for i, t := range items {
+		e, ok := byRequest[first+i]
+		if !ok {
+			continue
+		}
+		var sql string
+		switch v := unwrap(t.Data).(type) {
+		case structure.LoginRequest:
+			user, database = v.Username, v.Database
+		case structure.ChangeUserRequest:
+			user, database = v.Username, v.Database
+		case structure.InitDBRequest:
+			database = v.Schema
+		case structure.Request:
+			if v.Type == "Query" {
+				sql = v.Query
+			}
+		case structure.ExecuteRequest:
+			sql = v.SQL
+		}
+		if sql == "" {
+			continue
+		}
+		entry := Entry{
+			Time:     e.RequestStart,
+			User:     user,
+			Host:     host,
+			Database: database,
+			RowsSent: e.Rows,
+			SQL:      sql,
+		}
+		if t.Session != nil {
+			// the session state from the decoder follows USE and the
+			// schema changes the server reports as well.
+			entry.User, entry.Database = t.Session.User, t.Session.Database
+		}
+		if e.LastResponse != nil {
+			entry.QueryTime = e.LastResponse.Sub(e.RequestStart)
+		}
+		found = append(found, entry)
+	}
+	return found
+}
+
+func unwrap(data interface{}) interface{} {
+	if rawPacket, ok := data.(structure.WithRawPacket); ok {
+		return rawPacket.Transmission
+	}
+	return data
+}
+
+// Output writes the queries from all the connections to stdout as a slow
+// log.  The entries are sorted by time as a real slow log would be, so
+// they're gathered from the items as they're decoded rather than holding on
+// to whole connections.
+func Output(completed chan interface{}) {
+	var entries []Entry
+	for c := range completed {
+		switch v := c.(type) {
+		case structure.Connection:
+			entries = append(entries, Entries(v)...)
+		case structure.ConnectionItems:
+			entries = append(entries, ItemEntries(v)...)
+			if v.Release != nil {
+				v.Release()
+			}
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	for _, e := range entries {
+		if _, err := e.WriteTo(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
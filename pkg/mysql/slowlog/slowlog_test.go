@@ -0,0 +1,93 @@
+package slowlog_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"github.com/colinnewell/pcap-cli/tcp"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/slowlog"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+func TestSlowLog(t *testing.T) {
+	start := time.Date(2021, 4, 4, 17, 28, 50, 538141000, time.UTC)
+	finish := start.Add(1500 * time.Microsecond)
+	zero, two, four := 0, 2, 4
+	c := structure.Connection{
+		Address: tcp.ConnectionAddress{
+			IP: gopacket.NewFlow(layers.EndpointIPv4, net.IP{10, 0, 0, 2}, net.IP{10, 0, 0, 1}),
+		},
+		Items: []structure.Transmission{
+			{Data: structure.LoginRequest{Type: "Login", Username: "site", Database: "demo"}},
+			{Data: structure.OKResponse{Type: "OK"}, ResponseTo: &zero},
+			{Data: structure.Request{Type: "Prepare", Query: "SELECT * FROM peeps WHERE name = ?"}},
+			{Data: structure.PrepareOKResponse{Type: "PREPARE_OK", StatementID: 1}, ResponseTo: &two},
+			{Data: structure.ExecuteRequest{Type: "Execute", StatementID: 1, Params: []interface{}{"it's"},
+				Query: "SELECT * FROM peeps WHERE name = ?", SQL: "SELECT * FROM peeps WHERE name = 'it\\'s'"}},
+			{Data: structure.OKResponse{Type: "OK"}, ResponseTo: &four},
+			{
+				Data:    structure.Request{Type: "Query", Query: "SELECT 1"},
+				Session: &structure.SessionState{User: "site", Database: "archive"},
+			},
+		},
+		Exchanges: []structure.Exchange{
+			{Request: 0, Responses: []int{1}, Command: "Login"},
+			{Request: 2, Responses: []int{3}, Command: "Prepare"},
+			{Request: 4, Command: "Execute", RequestStart: start, LastResponse: &finish, Rows: 2},
+			{Request: 6, Command: "Query", RequestStart: finish},
+		},
+	}
+
+	var sb strings.Builder
+	for _, e := range slowlog.Entries(c) {
+		if _, err := e.WriteTo(&sb); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := `# Time: 2021-04-04T17:28:50.538141Z
+# User@Host: site[site] @  [10.0.0.2]
+# Query_time: 0.001500  Lock_time: 0.000000 Rows_sent: 2
+use demo;
+SET timestamp=1617557330;
+SELECT * FROM peeps WHERE name = 'it\'s';
+# Time: 2021-04-04T17:28:50.539641Z
+# User@Host: site[site] @  [10.0.0.2]
+# Query_time: 0.000000  Lock_time: 0.000000 Rows_sent: 0
+use archive;
+SET timestamp=1617557330;
+SELECT 1;
+`
+	if diff := cmp.Diff(sb.String(), expected); diff != "" {
+		t.Fatalf("Slow log doesn't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestItemEntries(t *testing.T) {
+	start := time.Date(2021, 4, 4, 17, 28, 50, 538141000, time.UTC)
+	c := structure.ConnectionItems{
+		Address: tcp.ConnectionAddress{
+			IP: gopacket.NewFlow(layers.EndpointIPv4, net.IP{10, 0, 0, 2}, net.IP{10, 0, 0, 1}),
+		},
+		// the login was handed on earlier.
+		First: 2,
+		Items: []structure.Transmission{
+			{
+				Data:    structure.Request{Type: "Query", Query: "SELECT 1"},
+				Session: &structure.SessionState{User: "site", Database: "demo"},
+			},
+		},
+		Exchanges: []structure.Exchange{{Request: 2, Command: "Query", RequestStart: start}},
+	}
+	expected := []slowlog.Entry{
+		{Time: start, User: "site", Host: "10.0.0.2", Database: "demo", SQL: "SELECT 1"},
+	}
+	if diff := cmp.Diff(slowlog.ItemEntries(c), expected); diff != "" {
+		t.Fatalf("Entries don't match (-got +expected):\n%s\n", diff)
+	}
+}
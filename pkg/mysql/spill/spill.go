@@ -0,0 +1,193 @@
+package spill
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Store keeps track of a memory budget shared between all the connections
+// being decoded.  Once the budget has been used up data is written out to a
+// temporary file instead, and read back when it's needed.
+type Store struct {
+	mu     sync.Mutex
+	budget int64
+	used   int64
+	dir    string
+	file   *os.File
+	size   int64
+}
+
+// New creates a store with a budget in bytes.  A budget of 0 means there
+// is no limit and nothing will be spilled.  The temporary file is created
+// in dir, or the default temporary directory if that's empty.
+func New(budget int64, dir string) *Store {
+	return &Store{budget: budget, dir: dir}
+}
+
+// Reserve claims n bytes of the budget.  It returns false if there isn't
+// room, in which case the data should be spilled instead.
+func (s *Store) Reserve(n int) bool {
+	if s == nil || s.budget == 0 {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.used+int64(n) > s.budget {
+		return false
+	}
+	s.used += int64(n)
+	return true
+}
+
+// Release hands back bytes claimed with Reserve.
+func (s *Store) Release(n int) {
+	if s == nil || s.budget == 0 {
+		return
+	}
+	s.mu.Lock()
+	s.used -= int64(n)
+	s.mu.Unlock()
+}
+
+// Write puts the data in the spill file.
+func (s *Store) Write(data []byte) (*Chunk, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		f, err := os.CreateTemp(s.dir, "pcap2mysql-spill-")
+		if err != nil {
+			return nil, errors.Wrap(err, "spill-create")
+		}
+		s.file = f
+	}
+	if _, err := s.file.WriteAt(data, s.size); err != nil {
+		return nil, errors.Wrap(err, "spill-write")
+	}
+	c := &Chunk{store: s, offset: s.size, length: len(data)}
+	s.size += int64(len(data))
+	return c, nil
+}
+
+// Close removes the spill file.
+func (s *Store) Close() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	name := s.file.Name()
+	err := s.file.Close()
+	s.file = nil
+	if rmErr := os.Remove(name); err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+// Chunk is a piece of data that has been spilled.
+type Chunk struct {
+	store  *Store
+	offset int64
+	length int
+}
+
+// Bytes reads the data back from the spill file.
+func (c *Chunk) Bytes() ([]byte, error) {
+	data := make([]byte, c.length)
+	if _, err := c.store.file.ReadAt(data, c.offset); err != nil {
+		return nil, errors.Wrap(err, "spill-read")
+	}
+	return data, nil
+}
+
+// Rows holds result set rows that have been spilled.  They are stored as
+// JSON as that's the form they will be needed in for the output.
+type Rows struct {
+	chunks []*Chunk
+}
+
+// Add spills a row.
+func (r *Rows) Add(s *Store, row []interface{}) error {
+	data, err := json.Marshal(row)
+	if err != nil {
+		return errors.Wrap(err, "spill-row")
+	}
+	c, err := s.Write(data)
+	if err != nil {
+		return err
+	}
+	r.chunks = append(r.chunks, c)
+	return nil
+}
+
+// Len returns the number of rows spilled.
+func (r *Rows) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.chunks)
+}
+
+// JSON reads the rows back.
+func (r *Rows) JSON() ([]json.RawMessage, error) {
+	if r == nil {
+		return nil, nil
+	}
+	rows := make([]json.RawMessage, 0, len(r.chunks))
+	err := r.Each(func(row json.RawMessage) error {
+		rows = append(rows, row)
+		return nil
+	})
+	return rows, err
+}
+
+// Each reads the rows back one at a time, passing each to f, so they
+// don't all have to be held in memory at once.
+func (r *Rows) Each(f func(row json.RawMessage) error) error {
+	if r == nil {
+		return nil
+	}
+	for _, c := range r.chunks {
+		data, err := c.Bytes()
+		if err != nil {
+			return err
+		}
+		if err := f(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Map reads back each of the rows, passes it through f, and spills the
+// results to the same store.  The rows themselves are left alone.
+func (r *Rows) Map(f func(row []interface{}) []interface{}) (*Rows, error) {
+	if r == nil {
+		return nil, nil
+	}
+	mapped := &Rows{chunks: make([]*Chunk, 0, len(r.chunks))}
+	for _, c := range r.chunks {
+		data, err := c.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		var row []interface{}
+		d := json.NewDecoder(bytes.NewReader(data))
+		// keep the numbers just as they were.
+		d.UseNumber()
+		if err := d.Decode(&row); err != nil {
+			return nil, errors.Wrap(err, "spill-map")
+		}
+		if err := mapped.Add(c.store, f(row)); err != nil {
+			return nil, err
+		}
+	}
+	return mapped, nil
+}
@@ -0,0 +1,91 @@
+package spill_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/spill"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+func TestBudget(t *testing.T) {
+	s := spill.New(10, t.TempDir())
+	if !s.Reserve(6) {
+		t.Fatal("Should have room for 6 bytes")
+	}
+	if s.Reserve(6) {
+		t.Fatal("Shouldn't have room for another 6 bytes")
+	}
+	s.Release(6)
+	if !s.Reserve(10) {
+		t.Fatal("Should have room once released")
+	}
+	var unlimited *spill.Store
+	if !unlimited.Reserve(1 << 30) {
+		t.Fatal("No store means no limit")
+	}
+}
+
+func TestSpilledResults(t *testing.T) {
+	dir := t.TempDir()
+	s := spill.New(1, dir)
+	rows := &spill.Rows{}
+	if err := rows.Add(s, []interface{}{"2", nil}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rows.Add(s, []interface{}{"3", "three"}); err != nil {
+		t.Fatal(err)
+	}
+
+	r := structure.ResultSetResponse{
+		Type:           "SQL results",
+		Results:        [][]interface{}{{"1", "one"}},
+		SpilledResults: rows,
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"Type":"SQL results","Columns":null,"Results":[["1","one"],["2",null],["3","three"]]}`
+	if diff := cmp.Diff(string(data), expected); diff != "" {
+		t.Fatalf("JSON doesn't match (-got +expected):\n%s\n", diff)
+	}
+
+	r.Results = nil
+	data, err = json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `{"Type":"SQL results","Columns":null,"Results":[["2",null],["3","three"]]}`
+	if diff := cmp.Diff(string(data), expected); diff != "" {
+		t.Fatalf("JSON with only spilled rows doesn't match (-got +expected):\n%s\n", diff)
+	}
+
+	mapped, err := rows.Map(func(row []interface{}) []interface{} {
+		return append(row, "extra")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := mapped.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(got[1]), `["3","three","extra"]`); diff != "" {
+		t.Fatalf("Mapped row doesn't match (-got +expected):\n%s\n", diff)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Fatal("Spill file should have been removed")
+	}
+}
@@ -0,0 +1,119 @@
+package sqltext
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+)
+
+//nolint:gochecknoglobals
+var (
+	inList     = regexp.MustCompile(`\bin ?\(\?(?: ?, ?\?)*\)`)
+	valuesList = regexp.MustCompile(`\bvalues ?\(\?(?: ?, ?\?)*\)(?: ?, ?\(\?(?: ?, ?\?)*\))*`)
+)
+
+// Fingerprint normalises a query so that queries that only differ by the
+// values used in them come out the same.  Comments are stripped, the
+// whitespace collapsed, everything is lower cased, literals are replaced with
+// ? and lists of them in IN and VALUES are collapsed to (?+).  Prepared
+// statements already have their placeholders so they fingerprint the same
+// way as the equivalent plain query.
+//
+//nolint:gocognit
+func Fingerprint(query string) string {
+	var sb strings.Builder
+	space := false
+	write := func(s string) {
+		if space && sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		space = false
+		sb.WriteString(s)
+	}
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"':
+			i = quoteEnd(query, i)
+			write("?")
+		case c == '`':
+			end := quoteEnd(query, i)
+			write(strings.ToLower(query[i:min(end+1, len(query))]))
+			i = end
+		case c == '#' || (c == '-' && strings.HasPrefix(query[i:], "-- ")):
+			i = lineEnd(query, i)
+			space = true
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += end + 3
+			}
+			space = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = true
+		case (c == 'x' || c == 'X' || c == 'b' || c == 'B') &&
+			i+1 < len(query) && query[i+1] == '\'' && !afterWord(query, i):
+			i = quoteEnd(query, i+1)
+			write("?")
+		case isDigit(c) && !afterWord(query, i):
+			i = numberEnd(query, i)
+			write("?")
+		default:
+			write(strings.ToLower(string(c)))
+		}
+	}
+	fingerprint := strings.TrimRight(sb.String(), "; ")
+	fingerprint = strings.NewReplacer("( ", "(", " )", ")", " ,", ",", ", ", ",").Replace(fingerprint)
+	fingerprint = inList.ReplaceAllString(fingerprint, "in(?+)")
+	return valuesList.ReplaceAllString(fingerprint, "values(?+)")
+}
+
+// ID gives a short identifier for a fingerprint.
+func ID(fingerprint string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(fingerprint))
+	return fmt.Sprintf("%016X", h.Sum64())
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordChar(c byte) bool {
+	return isDigit(c) || c == '_' || c == '$' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+// afterWord is true if the character at i is part of an identifier.
+func afterWord(query string, i int) bool {
+	return i > 0 && isWordChar(query[i-1])
+}
+
+// numberEnd returns the index of the last character of the number
+// starting at i, including hex and exponents.
+func numberEnd(query string, i int) int {
+	j := i + 1
+	if query[i] == '0' && j < len(query) && (query[j] == 'x' || query[j] == 'X') {
+		j++
+		for j < len(query) && isWordChar(query[j]) {
+			j++
+		}
+		return j - 1
+	}
+	for j < len(query) {
+		c := query[j]
+		switch {
+		case isDigit(c) || c == '.':
+		case (c == 'e' || c == 'E') && j+1 < len(query) &&
+			(isDigit(query[j+1]) || query[j+1] == '-' || query[j+1] == '+'):
+			j++
+		default:
+			return j - 1
+		}
+		j++
+	}
+	return j - 1
+}
@@ -0,0 +1,45 @@
+package sqltext_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/sqltext"
+)
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{
+			query:    "SELECT id, password FROM users WHERE username = 'bob'  AND age > 21.5e3;",
+			expected: "select id,password from users where username = ? and age > ?",
+		},
+		{
+			query:    "select * from `T1` /* hint */ where id in (1, 2, 3) -- trailing\n and x = 0xFF",
+			expected: "select * from `t1` where id in(?+) and x = ?",
+		},
+		{
+			query:    "INSERT INTO t2 (a, b) VALUES (1, 'a'), (2, \"b\")",
+			expected: "insert into t2 (a,b) values(?+)",
+		},
+		{
+			query:    "SELECT * FROM peeps WHERE id = ? AND name = ?",
+			expected: "select * from peeps where id = ? and name = ?",
+		},
+		{
+			query:    "SELECT col1, x'ff' FROM tab2 WHERE id IN (?,?)",
+			expected: "select col1,? from tab2 where id in(?+)",
+		},
+	}
+	for _, test := range tests {
+		if diff := cmp.Diff(sqltext.Fingerprint(test.query), test.expected); diff != "" {
+			t.Errorf("Fingerprint doesn't match (-got +expected):\n%s\n", diff)
+		}
+	}
+	if sqltext.ID(tests[0].expected) == sqltext.ID(tests[1].expected) {
+		t.Error("Different fingerprints should have different ids")
+	}
+}
@@ -0,0 +1,242 @@
+package sqltext
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Inline substitutes the parameters into the placeholders of a prepared
+// statement so that it can be run by hand.  Placeholders inside strings and
+// comments are left alone, as are any there aren't parameters for.
+func Inline(query string, params []interface{}) string {
+	var sb strings.Builder
+	n := 0
+	scan(query, func(text string, placeholder bool) {
+		if placeholder && n < len(params) {
+			sb.WriteString(Literal(params[n]))
+			n++
+			return
+		}
+		sb.WriteString(text)
+	})
+	return sb.String()
+}
+
+// Placeholders counts the placeholders in a prepared statement.
+func Placeholders(query string) int {
+	n := 0
+	scan(query, func(_ string, placeholder bool) {
+		if placeholder {
+			n++
+		}
+	})
+	return n
+}
+
+// scan walks through the query calling emit with each piece of text,
+// flagging the placeholders.
+func scan(query string, emit func(text string, placeholder bool)) {
+	start := 0
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			i = quoteEnd(query, i)
+		case c == '#' || (c == '-' && strings.HasPrefix(query[i:], "-- ")):
+			i = lineEnd(query, i)
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += end + 3
+			}
+		case c == '?':
+			emit(query[start:i], false)
+			emit("?", true)
+			start = i + 1
+		}
+	}
+	if start < len(query) {
+		emit(query[start:], false)
+	}
+}
+
+// quoteEnd returns the index of the quote closing the one at i.
+func quoteEnd(query string, i int) int {
+	q := query[i]
+	for j := i + 1; j < len(query); j++ {
+		switch query[j] {
+		case '\\':
+			if q != '`' {
+				j++
+			}
+		case q:
+			// a doubled quote is part of the literal.
+			if j+1 >= len(query) || query[j+1] != q {
+				return j
+			}
+			j++
+		}
+	}
+	return len(query)
+}
+
+func lineEnd(query string, i int) int {
+	end := strings.IndexByte(query[i:], '\n')
+	if end < 0 {
+		return len(query)
+	}
+	return i + end
+}
+
+// Literal renders a value as a MySQL literal.
+func Literal(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return Quote(v)
+	case []byte:
+		return hexLiteral(v)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case struct{ Text string }:
+		return Quote(v.Text)
+	case struct{ Base64 []byte }:
+		return hexLiteral(v.Base64)
+	case json.Marshaler:
+		// the dates know how to present themselves for the json.
+		if data, err := v.MarshalJSON(); err == nil {
+			var s string
+			if err := json.Unmarshal(data, &s); err == nil {
+				return Quote(s)
+			}
+			return string(data)
+		}
+	case fmt.Stringer:
+		return Quote(v.String())
+	}
+	return Quote(fmt.Sprint(v))
+}
+
+func hexLiteral(b []byte) string {
+	return "X'" + hex.EncodeToString(b) + "'"
+}
+
+// Quote quotes a string, escaping it the way the mysql client does.
+func Quote(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('\'')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case 0:
+			sb.WriteString(`\0`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\\', '\'', '"':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case '\x1a':
+			sb.WriteString(`\Z`)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte('\'')
+	return sb.String()
+}
+
+// ReplaceLiterals calls replace with each string, number, hex and bit
+// literal in the query and puts what it returns in its place.  Everything
+// else, comments included, is left as it is.
+func ReplaceLiterals(query string, replace func(literal string) string) string {
+	var sb strings.Builder
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"':
+			end := min(quoteEnd(query, i)+1, len(query))
+			sb.WriteString(replace(query[i:end]))
+			i = end - 1
+		case c == '`':
+			end := min(quoteEnd(query, i)+1, len(query))
+			sb.WriteString(query[i:end])
+			i = end - 1
+		case c == '#' || (c == '-' && strings.HasPrefix(query[i:], "-- ")):
+			end := lineEnd(query, i)
+			sb.WriteString(query[i:end])
+			i = end - 1
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := len(query)
+			if e := strings.Index(query[i+2:], "*/"); e >= 0 {
+				end = i + e + 4
+			}
+			sb.WriteString(query[i:end])
+			i = end - 1
+		case (c == 'x' || c == 'X' || c == 'b' || c == 'B') &&
+			i+1 < len(query) && query[i+1] == '\'' && !afterWord(query, i):
+			end := min(quoteEnd(query, i+1)+1, len(query))
+			sb.WriteString(replace(query[i:end]))
+			i = end - 1
+		case isDigit(c) && !afterWord(query, i):
+			end := numberEnd(query, i) + 1
+			sb.WriteString(replace(query[i:end]))
+			i = end - 1
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// Unquote gives the value of a quoted string literal, undoing the escapes
+// Quote adds along with doubled quotes.  Anything else is returned as is.
+func Unquote(literal string) string {
+	if len(literal) < 2 || (literal[0] != '\'' && literal[0] != '"') || literal[len(literal)-1] != literal[0] {
+		return literal
+	}
+	q := literal[0]
+	inner := literal[1 : len(literal)-1]
+	var sb strings.Builder
+	for i := 0; i < len(inner); i++ {
+		c := inner[i]
+		switch {
+		case c == '\\' && i+1 < len(inner):
+			i++
+			switch inner[i] {
+			case '0':
+				sb.WriteByte(0)
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'Z':
+				sb.WriteByte('\x1a')
+			default:
+				sb.WriteByte(inner[i])
+			}
+		case c == q && i+1 < len(inner) && inner[i+1] == q:
+			sb.WriteByte(q)
+			i++
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
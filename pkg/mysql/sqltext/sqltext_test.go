@@ -0,0 +1,70 @@
+package sqltext_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/sqltext"
+)
+
+func TestInline(t *testing.T) {
+	tests := []struct {
+		query    string
+		params   []interface{}
+		expected string
+	}{
+		{
+			query:    "SELECT * FROM peeps WHERE id = ? AND name = ?",
+			params:   []interface{}{int32(1), "O'Brien"},
+			expected: `SELECT * FROM peeps WHERE id = 1 AND name = 'O\'Brien'`,
+		},
+		{
+			query:    "SELECT '?', `a?`, \"it\\\"s?\" /* ? */ FROM t WHERE x = ? -- ?\nAND y = ?",
+			params:   []interface{}{nil, 1.5},
+			expected: "SELECT '?', `a?`, \"it\\\"s?\" /* ? */ FROM t WHERE x = NULL -- ?\nAND y = 1.5",
+		},
+		{
+			query:    "INSERT INTO t VALUES (?, ?, ?)",
+			params:   []interface{}{struct{ Base64 []byte }{Base64: []byte{0, 1}}, "a\nb"},
+			expected: `INSERT INTO t VALUES (X'0001', 'a\nb', ?)`,
+		},
+	}
+	for _, test := range tests {
+		if diff := cmp.Diff(sqltext.Inline(test.query, test.params), test.expected); diff != "" {
+			t.Errorf("Inlined SQL doesn't match (-got +expected):\n%s\n", diff)
+		}
+	}
+}
+
+func TestReplaceLiterals(t *testing.T) {
+	var literals []string
+	replaced := sqltext.ReplaceLiterals(
+		"SELECT `a1`, x'00ff', col2 FROM t /* 'c' */ WHERE name = 'O\\'Brien' AND n > 21.5e3 -- 5\nAND id = ?",
+		func(literal string) string {
+			literals = append(literals, literal)
+			return "L"
+		},
+	)
+	expected := "SELECT `a1`, L, col2 FROM t /* 'c' */ WHERE name = L AND n > L -- 5\nAND id = ?"
+	if diff := cmp.Diff(replaced, expected); diff != "" {
+		t.Errorf("Replaced SQL doesn't match (-got +expected):\n%s\n", diff)
+	}
+	if diff := cmp.Diff(literals, []string{"x'00ff'", `'O\'Brien'`, "21.5e3"}); diff != "" {
+		t.Errorf("Literals don't match (-got +expected):\n%s\n", diff)
+	}
+}
+
+func TestUnquote(t *testing.T) {
+	for literal, expected := range map[string]string{
+		`'O\'Brien'`:              "O'Brien",
+		`'it''s'`:                 "it's",
+		`"a\nb"`:                  "a\nb",
+		"21.5":                    "21.5",
+		sqltext.Quote("x\x00\"y"): "x\x00\"y",
+	} {
+		if diff := cmp.Diff(sqltext.Unquote(literal), expected); diff != "" {
+			t.Errorf("Unquoted %s doesn't match (-got +expected):\n%s\n", literal, diff)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package structure
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSplitSessionsLinks(t *testing.T) {
+	zero, two := 0, 2
+	c := Connection{
+		Items: []Transmission{
+			{Data: Request{Type: "Query"}},
+			{Data: OKResponse{Type: "OK"}, ResponseTo: &zero},
+			{Data: Request{Type: "MYSQL_RESET_CONNECTION"}},
+			{Data: OKResponse{Type: "OK"}, ResponseTo: &two},
+		},
+		Sessions: []Session{{FirstItem: 2, Reason: "MYSQL_RESET_CONNECTION"}},
+		Exchanges: []Exchange{
+			{Request: 0, Responses: []int{1}, Command: "Query"},
+			{Request: 2, Responses: []int{3}, Command: "MYSQL_RESET_CONNECTION"},
+		},
+		Transactions: []Transaction{{Requests: []int{0}, Outcome: TransactionSessionEnded}},
+	}
+
+	var links [][]*int
+	for _, conn := range c.SplitSessions() {
+		var l []*int
+		for _, item := range conn.Items {
+			l = append(l, item.ResponseTo)
+		}
+		links = append(links, l)
+	}
+	expected := [][]*int{{nil, &zero}, {nil, &zero}}
+	if diff := cmp.Diff(links, expected); diff != "" {
+		t.Fatalf("Links don't match (-got +expected):\n%s\n", diff)
+	}
+	var exchanges [][]Exchange
+	for _, conn := range c.SplitSessions() {
+		exchanges = append(exchanges, conn.Exchanges)
+	}
+	expectedExchanges := [][]Exchange{
+		{{Request: 0, Responses: []int{1}, Command: "Query"}},
+		{{Request: 0, Responses: []int{1}, Command: "MYSQL_RESET_CONNECTION"}},
+	}
+	if diff := cmp.Diff(exchanges, expectedExchanges); diff != "" {
+		t.Fatalf("Exchanges don't match (-got +expected):\n%s\n", diff)
+	}
+	var transactions [][]Transaction
+	for _, conn := range c.SplitSessions() {
+		transactions = append(transactions, conn.Transactions)
+	}
+	expectedTransactions := [][]Transaction{{{Requests: []int{0}, Outcome: "session ended"}}, nil}
+	if diff := cmp.Diff(transactions, expectedTransactions); diff != "" {
+		t.Fatalf("Transactions don't match (-got +expected):\n%s\n", diff)
+	}
+	if *c.Items[3].ResponseTo != 2 || c.Exchanges[1].Request != 2 {
+		t.Fatal("Original connection modified")
+	}
+}
@@ -1,6 +1,7 @@
 package structure
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -9,15 +10,37 @@ import (
 	"github.com/colinnewell/pcap-cli/tcp"
 	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/decoding/bitmap"
 	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/packet"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/spill"
 )
 
 type Connection struct {
-	Address            tcp.ConnectionAddress
-	Items              []Transmission
+	Address tcp.ConnectionAddress
+	Items   []Transmission
+	// Sessions is only filled in when the connection was reused with a
+	// COM_CHANGE_USER or COM_RESET_CONNECTION.
+	Sessions           []Session     `json:"Sessions,omitempty"`
+	Exchanges          []Exchange    `json:"Exchanges,omitempty"`
+	Transactions       []Transaction `json:"Transactions,omitempty"`
+	Summary            ConnectionSummary
 	RawRequestPackets  *packet.Buffer `json:"RawRequestPackets,omitempty"`
 	RawResponsePackets *packet.Buffer `json:"RawResponsePackets,omitempty"`
 }
 
+// ConnectionItems are the items of a connection handed on by the decoder as
+// soon as the exchanges they make up are complete, rather than holding on
+// to them until the connection closes.  The Connection follows once it has
+// closed, with everything but the Items and Exchanges.
+type ConnectionItems struct {
+	Address tcp.ConnectionAddress
+	// First is the index of the first of the Items within the connection.
+	First     int
+	Items     []Transmission
+	Exchanges []Exchange
+	// Release hands back the part of the memory budget the rows in the
+	// Items are holding.  Whatever drops the Items calls it, if it's set.
+	Release func() `json:"-"`
+}
+
 func (c Connection) FirstSeen() time.Time {
 	if len(c.Items) > 0 && len(c.Items[0].Seen) > 0 {
 		return c.Items[0].Seen[0]
@@ -25,9 +48,226 @@ func (c Connection) FirstSeen() time.Time {
 	return time.Time{}
 }
 
+// ConnectionSummary gives the figures for a whole TCP connection, enough to
+// pick out the interesting ones from many.
+type ConnectionSummary struct {
+	Start time.Time
+	End   time.Time
+	// Ending is how the connection finished.  The decoder only sees a
+	// QUIT, the TCP flags are read from the capture by the summary
+	// package when the summaries are output on their own.
+	Ending        string `json:"Ending,omitempty"`
+	ServerVersion string `json:"ServerVersion,omitempty"`
+	// User and Database are from the login.
+	User     string `json:"User,omitempty"`
+	Database string `json:"Database,omitempty"`
+	// Capabilities are those both sides have, or the client's if the
+	// greeting wasn't seen.
+	Capabilities ClientCapabilities
+	Compressed   bool
+	TLS          bool
+	// Commands counts the commands of each type.
+	Commands      map[string]int `json:"Commands,omitempty"`
+	Errors        int
+	Rows          int
+	RequestBytes  int
+	ResponseBytes int
+	// ServerTime is the time the server spent answering commands and
+	// IdleTime the rest of the time the connection was open, in
+	// nanoseconds.
+	ServerTime time.Duration
+	IdleTime   time.Duration
+}
+
+// How a connection ended.
+const (
+	EndingQuit       = "QUIT"
+	EndingFIN        = "FIN"
+	EndingRST        = "RST"
+	EndingCaptureEnd = "capture end"
+)
+
+// SplitSessions breaks a pooled connection up into a connection per logical
+// session.  Connections without session boundaries are returned as is.
+// Each keeps the Summary of the whole connection.
+func (c Connection) SplitSessions() []Connection {
+	if len(c.Sessions) == 0 {
+		return []Connection{c}
+	}
+	conns := make([]Connection, 0, len(c.Sessions)+1)
+	if c.Sessions[0].FirstItem > 0 {
+		// anything before the first boundary we know about.
+		conns = append(conns, Connection{
+			Address:      c.Address,
+			Items:        rebaseItems(c.Items[:c.Sessions[0].FirstItem], 0),
+			Exchanges:    rebaseExchanges(c.Exchanges, 0, c.Sessions[0].FirstItem),
+			Transactions: rebaseTransactions(c.Transactions, 0, c.Sessions[0].FirstItem),
+			Summary:      c.Summary,
+		})
+	}
+	for i, s := range c.Sessions {
+		end := len(c.Items)
+		if i+1 < len(c.Sessions) {
+			end = c.Sessions[i+1].FirstItem
+		}
+		conns = append(conns, Connection{
+			Address:      c.Address,
+			Items:        rebaseItems(c.Items[s.FirstItem:end], s.FirstItem),
+			Exchanges:    rebaseExchanges(c.Exchanges, s.FirstItem, end),
+			Transactions: rebaseTransactions(c.Transactions, s.FirstItem, end),
+			Sessions:     []Session{{Reason: s.Reason, Username: s.Username, Database: s.Database}},
+			Summary:      c.Summary,
+		})
+	}
+	return conns
+}
+
+// rebaseItems adjusts the ResponseTo links for items that have been cut out
+// of a connection starting at offset.
+func rebaseItems(items []Transmission, offset int) []Transmission {
+	rebased := make([]Transmission, len(items))
+	for i, t := range items {
+		if t.ResponseTo != nil {
+			request := *t.ResponseTo - offset
+			t.ResponseTo = nil
+			if request >= 0 {
+				t.ResponseTo = &request
+			}
+		}
+		rebased[i] = t
+	}
+	return rebased
+}
+
+// rebaseExchanges picks out the exchanges for the commands between start
+// and end and adjusts their indexes to match.
+func rebaseExchanges(exchanges []Exchange, start, end int) []Exchange {
+	var rebased []Exchange
+	for _, e := range exchanges {
+		if e.Request < start || e.Request >= end {
+			continue
+		}
+		e.Request -= start
+		responses := make([]int, 0, len(e.Responses))
+		for _, r := range e.Responses {
+			responses = append(responses, r-start)
+		}
+		if len(responses) > 0 {
+			e.Responses = responses
+		} else {
+			e.Responses = nil
+		}
+		rebased = append(rebased, e)
+	}
+	return rebased
+}
+
+// rebaseTransactions picks out the transactions started between start and
+// end.  They can't span sessions as changing user or resetting the
+// connection ends them.
+func rebaseTransactions(transactions []Transaction, start, end int) []Transaction {
+	var rebased []Transaction
+	for _, t := range transactions {
+		if len(t.Requests) == 0 || t.Requests[0] < start || t.Requests[0] >= end {
+			continue
+		}
+		requests := make([]int, len(t.Requests))
+		for i, r := range t.Requests {
+			requests[i] = r - start
+		}
+		t.Requests = requests
+		rebased = append(rebased, t)
+	}
+	return rebased
+}
+
+// Exchange groups a command with the responses to it, and records how long
+// the server took to answer.
+type Exchange struct {
+	// Request is the index into the connections Items of the command,
+	// and Responses those of the responses to it.
+	Request   int
+	Responses []int `json:"Responses,omitempty"`
+	Command   string
+	// RequestStart and RequestEnd are when the first and last packets of
+	// the command were seen.
+	RequestStart  time.Time
+	RequestEnd    time.Time
+	FirstResponse *time.Time `json:"FirstResponse,omitempty"`
+	LastResponse  *time.Time `json:"LastResponse,omitempty"`
+	// TimeToFirstByte and TimeToLastByte are measured from the end of the
+	// command, in nanoseconds.
+	TimeToFirstByte time.Duration `json:"TimeToFirstByte,omitempty"`
+	TimeToLastByte  time.Duration `json:"TimeToLastByte,omitempty"`
+	Rows            int
+	// RequestBytes and ResponseBytes count the MySQL packets, after
+	// decompression.
+	RequestBytes  int
+	ResponseBytes int
+}
+
+// Transaction is a run of commands the server ran as one transaction, from
+// the one that started it to the one that ended it.
+type Transaction struct {
+	// Requests are the indexes into the connections Items of the commands
+	// in the transaction, and Statements the SQL of the queries and
+	// executes among them.
+	Requests   []int
+	Statements []string `json:"Statements,omitempty"`
+	// Start is when the first command started and End when the response
+	// to the last one finished.
+	Start time.Time
+	End   time.Time
+	// Duration and Idle are in nanoseconds.  Idle is the time between
+	// commands, when the server was waiting on the client with the
+	// transaction open.
+	Duration     time.Duration
+	Idle         time.Duration
+	Outcome      string
+	RowsAffected uint64
+}
+
+// The outcomes of a transaction.  The server rolls back a transaction left
+// open when the session ends, and on a deadlock.
+const (
+	TransactionCommit         = "COMMIT"
+	TransactionRollback       = "ROLLBACK"
+	TransactionImplicitCommit = "implicit commit"
+	TransactionDeadlock       = "deadlock"
+	TransactionSessionEnded   = "session ended"
+	TransactionOpen           = "open"
+)
+
+// Session marks the start of a logical session within a connection.
+type Session struct {
+	// FirstItem is the index into the connections Items where the
+	// session starts.
+	FirstItem int
+	Reason    string
+	Username  string `json:"Username,omitempty"`
+	Database  string `json:"Database,omitempty"`
+}
+
 type Transmission struct {
 	Data interface{}
 	Seen []time.Time
+	// ResponseTo is the index into the connections Items of the request
+	// this is a response to.
+	ResponseTo *int `json:"ResponseTo,omitempty"`
+	// Session is the state of the session a request was sent in.  It's
+	// shared between the requests until something changes it.
+	Session *SessionState `json:"Session,omitempty"`
+}
+
+// SessionState is the session state that affects how a query is run, as
+// far as it can be seen in the capture.  Anything not seen is left empty.
+type SessionState struct {
+	User           string `json:"User,omitempty"`
+	Database       string `json:"Database,omitempty"`
+	CharacterSet   string `json:"CharacterSet,omitempty"`
+	Autocommit     *bool  `json:"Autocommit,omitempty"`
+	SQLMode        string `json:"SQLMode,omitempty"`
+	IsolationLevel string `json:"IsolationLevel,omitempty"`
 }
 
 type DecodeError struct {
@@ -44,6 +284,9 @@ type DecodeError struct {
 type Request struct {
 	Type  string `json:"Type"`
 	Query string `json:"Query,omitempty"`
+	// Attributes are the query attributes sent along with the query when
+	// CLIENT_QUERY_ATTRIBUTES has been negotiated.
+	Attributes map[string]interface{} `json:"Attributes,omitempty"`
 }
 
 type ExecuteRequest struct {
@@ -52,8 +295,14 @@ type ExecuteRequest struct {
 	Flags          uint8
 	IterationCount uint32
 	// FIXME: ought to think about how to express this in the output.
-	NullMap *bitmap.NullBitMap
-	Params  []interface{}
+	NullMap    *bitmap.NullBitMap
+	Params     []interface{}
+	Attributes map[string]interface{} `json:"Attributes,omitempty"`
+	// Query is the SQL the statement was prepared with, and SQL is that
+	// with the parameters filled in, ready to paste into a mysql shell.
+	// They are only known if the Prepare was in the capture.
+	Query string `json:"Query,omitempty"`
+	SQL   string `json:"SQL,omitempty"`
 }
 
 type LoginRequest struct {
@@ -63,6 +312,72 @@ type LoginRequest struct {
 	ExtendedCapabilities uint32
 	MaxPacketSize        uint32
 	Username             string
+	Database             string            `json:"Database,omitempty"`
+	AuthPlugin           string            `json:"AuthPlugin,omitempty"`
+	Attributes           map[string]string `json:"Attributes,omitempty"`
+	// AuthData is the scrambled password.  It's left out of the JSON as
+	// along with the greeting it's enough to attack the password offline.
+	AuthData []byte `json:"-"`
+}
+
+// SSLRequest is the start of the login when the client wants TLS.  It's
+// the first part of the LoginRequest, and the rest of the login follows
+// once TLS is running, so can't be seen.
+type SSLRequest struct {
+	Type                 string
+	ClientCapabilities   ClientCapabilities
+	Collation            byte
+	ExtendedCapabilities uint32
+	MaxPacketSize        uint32
+}
+
+type ChangeUserRequest struct {
+	Type       string
+	Username   string
+	Database   string            `json:"Database,omitempty"`
+	Collation  uint16            `json:"Collation,omitempty"`
+	AuthPlugin string            `json:"AuthPlugin,omitempty"`
+	Attributes map[string]string `json:"Attributes,omitempty"`
+	// AuthData is left out of the JSON, as with the LoginRequest.
+	AuthData []byte `json:"-"`
+}
+
+// AuthResponse is the client's half of the authentication exchange after a
+// Login or COM_CHANGE_USER.
+type AuthResponse struct {
+	Type string
+	Data []byte
+}
+
+type InitDBRequest struct {
+	Type   string
+	Schema string
+}
+
+type FieldListRequest struct {
+	Type     string
+	Table    string
+	Wildcard string `json:"Wildcard,omitempty"`
+}
+
+type ProcessKillRequest struct {
+	Type         string
+	ConnectionID uint32
+}
+
+type SetOptionRequest struct {
+	Type   string
+	Option SetOption
+}
+
+type RefreshRequest struct {
+	Type  string
+	Flags RefreshFlags
+}
+
+type ShutdownRequest struct {
+	Type  string
+	Level ShutdownLevel
 }
 
 type Response struct {
@@ -73,6 +388,173 @@ type ResultSetResponse struct {
 	Type    string          `json:"Type"`
 	Columns []ColumnInfo    `json:"Columns"`
 	Results [][]interface{} `json:"Results"`
+	// CachedColumns is set when the server didn't send the column
+	// definitions and they were filled in from earlier ones.
+	CachedColumns bool `json:"CachedColumns,omitempty"`
+	// SpilledResults are the rows that followed Results but were written
+	// to disk to stay within the memory budget.
+	SpilledResults *spill.Rows `json:"-"`
+}
+
+// MarshalJSON writes out the results, reading back any that were spilled
+// to disk.
+func (r ResultSetResponse) MarshalJSON() ([]byte, error) {
+	type plain ResultSetResponse
+	if r.SpilledResults.Len() == 0 {
+		return json.Marshal(plain(r))
+	}
+	return json.Marshal(struct {
+		plain
+		Results resultRows `json:"Results"`
+	}{plain(r), resultRows{r.Results, r.SpilledResults}})
+}
+
+// resultRows writes out the rows held in memory followed by those spilled
+// to disk, which are read back a row at a time.
+type resultRows struct {
+	results [][]interface{}
+	spilled *spill.Rows
+}
+
+func (r resultRows) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('[')
+	for i, row := range r.results {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		data, err := json.Marshal(row)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(data)
+	}
+	first := len(r.results) == 0
+	err := r.spilled.Each(func(row json.RawMessage) error {
+		if !first {
+			b.WriteByte(',')
+		}
+		first = false
+		b.Write(row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	b.WriteByte(']')
+	return b.Bytes(), nil
+}
+
+type AuthSwitchResponse struct {
+	Type       string
+	AuthPlugin string
+	Data       []byte
+}
+
+type AuthMoreDataResponse struct {
+	Type string
+	Data []byte
+}
+
+type StatisticsResponse struct {
+	Type   string
+	Status string
+}
+
+type FieldListResponse struct {
+	Type    string
+	Columns []ColumnInfo
+}
+
+type SetOption uint16
+
+const (
+	MultiStatementsOn  SetOption = 0
+	MultiStatementsOff SetOption = 1
+)
+
+func (o SetOption) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.String())
+}
+
+func (o SetOption) String() string {
+	switch o {
+	case MultiStatementsOn:
+		return "MYSQL_OPTION_MULTI_STATEMENTS_ON"
+	case MultiStatementsOff:
+		return "MYSQL_OPTION_MULTI_STATEMENTS_OFF"
+	}
+	return fmt.Sprintf("UNRECOGNISED: %d", uint16(o))
+}
+
+type RefreshFlags byte
+
+func (f RefreshFlags) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("%d: %s", byte(f), f.String()))
+}
+
+func (f RefreshFlags) String() string {
+	var b strings.Builder
+	for _, flag := range []string{
+		"REFRESH_GRANT",
+		"REFRESH_LOG",
+		"REFRESH_TABLES",
+		"REFRESH_HOSTS",
+		"REFRESH_STATUS",
+		"REFRESH_THREADS",
+		"REFRESH_SLAVE",
+		"REFRESH_MASTER",
+	} {
+		if f&1 == 1 {
+			if b.Len() > 0 {
+				b.WriteString("|")
+			}
+			b.WriteString(flag)
+		}
+		f >>= 1
+	}
+
+	return b.String()
+}
+
+type ShutdownLevel byte
+
+//nolint:revive,stylecheck
+const (
+	SHUTDOWN_DEFAULT               ShutdownLevel = 0
+	SHUTDOWN_WAIT_CONNECTIONS      ShutdownLevel = 1
+	SHUTDOWN_WAIT_TRANSACTIONS     ShutdownLevel = 2
+	SHUTDOWN_WAIT_UPDATES          ShutdownLevel = 8
+	SHUTDOWN_WAIT_ALL_BUFFERS      ShutdownLevel = 16
+	SHUTDOWN_WAIT_CRITICAL_BUFFERS ShutdownLevel = 17
+	KILL_QUERY                     ShutdownLevel = 254
+	KILL_CONNECTION                ShutdownLevel = 255
+)
+
+func (l ShutdownLevel) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.String())
+}
+
+func (l ShutdownLevel) String() string {
+	switch l {
+	case SHUTDOWN_DEFAULT:
+		return "SHUTDOWN_DEFAULT"
+	case SHUTDOWN_WAIT_CONNECTIONS:
+		return "SHUTDOWN_WAIT_CONNECTIONS"
+	case SHUTDOWN_WAIT_TRANSACTIONS:
+		return "SHUTDOWN_WAIT_TRANSACTIONS"
+	case SHUTDOWN_WAIT_UPDATES:
+		return "SHUTDOWN_WAIT_UPDATES"
+	case SHUTDOWN_WAIT_ALL_BUFFERS:
+		return "SHUTDOWN_WAIT_ALL_BUFFERS"
+	case SHUTDOWN_WAIT_CRITICAL_BUFFERS:
+		return "SHUTDOWN_WAIT_CRITICAL_BUFFERS"
+	case KILL_QUERY:
+		return "KILL_QUERY"
+	case KILL_CONNECTION:
+		return "KILL_CONNECTION"
+	}
+	return fmt.Sprintf("UNRECOGNISED: %d", byte(l))
 }
 
 type ClientCapabilities uint32
@@ -111,9 +593,9 @@ func (c ClientCapabilities) String() string {
 		"UNKNOWN",
 		"CLIENT_SESSION_TRACK",
 		"CLIENT_DEPRECATE_EOF",
-		"UNKNOWN",
+		"CLIENT_OPTIONAL_RESULTSET_METADATA",
 		"CLIENT_ZSTD_COMPRESSION_ALGORITHM",
-		"UNKNOWN",
+		"CLIENT_QUERY_ATTRIBUTES",
 		"UNKNOWN",
 		"CLIENT_CAPABILITY_EXTENSION",
 	} {
@@ -129,14 +611,32 @@ func (c ClientCapabilities) String() string {
 	return b.String()
 }
 
-// Extended capabilities?
-// MARIADB_CLIENT_PROGRESS	1 << 32	Client support progress indicator (since 10.2)
-// MARIADB_CLIENT_COM_MULTI	1 << 33	Permit COM_MULTI protocol
-// MARIADB_CLIENT_STMT_BULK_OPERATIONS	1 << 34	Permit bulk insert
-// MARIADB_CLIENT_EXTENDED_TYPE_INFO	1 << 35	add extended metadata information
+// MariaDB extended capabilities.  These are sent in the last 4 bytes of the
+// reserved area of the login packet, so are really 1 << 32 onwards.
+//
+//nolint:revive,stylecheck
+const (
+	// Client support progress indicator (since 10.2).
+	MARIADB_CLIENT_PROGRESS uint32 = 1
+	// Permit COM_MULTI protocol.
+	MARIADB_CLIENT_COM_MULTI uint32 = 1 << 1
+	// Permit bulk insert.
+	MARIADB_CLIENT_STMT_BULK_OPERATIONS uint32 = 1 << 2
+	// Add extended metadata information.
+	MARIADB_CLIENT_EXTENDED_TYPE_INFO uint32 = 1 << 3
+	// Column definitions can be skipped when they haven't changed.
+	MARIADB_CLIENT_CACHE_METADATA uint32 = 1 << 4
+)
 
 type StatusFlags uint16
 
+const (
+	SERVER_STATUS_IN_TRANS       StatusFlags = 1
+	SERVER_STATUS_AUTOCOMMIT     StatusFlags = 2
+	SERVER_MORE_RESULTS_EXISTS   StatusFlags = 8
+	SERVER_SESSION_STATE_CHANGED StatusFlags = 1 << 14
+)
+
 func (f StatusFlags) MarshalJSON() ([]byte, error) {
 	return json.Marshal(fmt.Sprintf("%x: %s", uint16(f), f.String()))
 }
@@ -177,11 +677,31 @@ type OKResponse struct {
 	LastInsertID uint64
 	ServerStatus StatusFlags
 	WarningCount uint16
-	// FIXME: deal with session tracking
-	Type string `json:"Type"`
-	Info string
+	Type         string `json:"Type"`
+	Info         string
+	// StateChanges are only sent when the client asked for session
+	// tracking.
+	StateChanges []StateChange `json:"StateChanges,omitempty"`
 }
 
+// StateChange is a change to the session state reported by the server in
+// an OK.  Name is only set for system variables.
+type StateChange struct {
+	Type  string
+	Name  string `json:"Name,omitempty"`
+	Value string
+}
+
+// The types of session state change.
+const (
+	StateSystemVariable             = "system variable"
+	StateSchema                     = "schema"
+	StateChanged                    = "state change"
+	StateGTIDs                      = "gtids"
+	StateTransactionCharacteristics = "transaction characteristics"
+	StateTransactionState           = "transaction state"
+)
+
 type PrepareOKResponse struct {
 	Type        string `json:"Type"`
 	StatementID uint32
@@ -198,6 +718,9 @@ type ErrorResponse struct {
 	Type    string
 	State   string `json:"State,omitempty"`
 	Message string
+	// Name and Category come from the catalogue of server errors.
+	Name     string `json:"Name,omitempty"`
+	Category string `json:"Category,omitempty"`
 }
 
 type ColumnInfo struct {
@@ -208,6 +731,8 @@ type ColumnInfo struct {
 	Column      string
 	ColumnAlias string
 	TypeInfo    TypeInfo
+	// Default is only sent in response to COM_FIELD_LIST.
+	Default *string `json:"Default,omitempty"`
 }
 
 type TypeInfo struct {
@@ -246,6 +771,8 @@ const (
 	MySQLOK ResponseType = 0
 	// MySQLLocalInfile type.
 	MySQLLocalInfile ResponseType = 0xfb
+	// MySQLAuthMoreData type.
+	MySQLAuthMoreData ResponseType = 0x01
 )
 
 //nolint:revive,stylecheck
@@ -299,36 +826,37 @@ const (
 	DETAIL_PART_KEY_FLAG         FieldDetail = 16384
 	DETAIL_NUM_FLAG              FieldDetail = 32768
 
-	CCAP_CLIENT_MYSQL                      ClientCapabilities = 1
-	CCAP_FOUND_ROWS                        ClientCapabilities = 2
-	CCAP_CONNECT_WITH_DB                   ClientCapabilities = 8
-	CCAP_COMPRESS                          ClientCapabilities = 32
-	CCAP_LOCAL_FILES                       ClientCapabilities = 128
-	CCAP_IGNORE_SPACE                      ClientCapabilities = 256
-	CCAP_CLIENT_PROTOCOL_41                ClientCapabilities = 1 << 9
-	CCAP_CLIENT_INTERACTIVE                ClientCapabilities = 1 << 10
-	CCAP_SSL                               ClientCapabilities = 1 << 11
-	CCAP_TRANSACTIONS                      ClientCapabilities = 1 << 12
-	CCAP_SECURE_CONNECTION                 ClientCapabilities = 1 << 13
-	CCAP_MULTI_STATEMENTS                  ClientCapabilities = 1 << 16
-	CCAP_MULTI_RESULTS                     ClientCapabilities = 1 << 17
-	CCAP_PS_MULTI_RESULTS                  ClientCapabilities = 1 << 18
-	CCAP_PLUGIN_AUTH                       ClientCapabilities = 1 << 19
-	CCAP_CONNECT_ATTRS                     ClientCapabilities = 1 << 20
-	CCAP_PLUGIN_AUTH_LENENC_CLIENT_DATA    ClientCapabilities = 1 << 21
-	CCAP_CLIENT_SESSION_TRACK              ClientCapabilities = 1 << 23
-	CCAP_CLIENT_DEPRECATE_EOF              ClientCapabilities = 1 << 24
-	CCAP_CLIENT_ZSTD_COMPRESSION_ALGORITHM ClientCapabilities = 1 << 26
-	CCAP_CLIENT_CAPABILITY_EXTENSION       ClientCapabilities = 1 << 29
+	CCAP_CLIENT_MYSQL                       ClientCapabilities = 1
+	CCAP_FOUND_ROWS                         ClientCapabilities = 2
+	CCAP_CONNECT_WITH_DB                    ClientCapabilities = 8
+	CCAP_COMPRESS                           ClientCapabilities = 32
+	CCAP_LOCAL_FILES                        ClientCapabilities = 128
+	CCAP_IGNORE_SPACE                       ClientCapabilities = 256
+	CCAP_CLIENT_PROTOCOL_41                 ClientCapabilities = 1 << 9
+	CCAP_CLIENT_INTERACTIVE                 ClientCapabilities = 1 << 10
+	CCAP_SSL                                ClientCapabilities = 1 << 11
+	CCAP_TRANSACTIONS                       ClientCapabilities = 1 << 12
+	CCAP_SECURE_CONNECTION                  ClientCapabilities = 1 << 13
+	CCAP_MULTI_STATEMENTS                   ClientCapabilities = 1 << 16
+	CCAP_MULTI_RESULTS                      ClientCapabilities = 1 << 17
+	CCAP_PS_MULTI_RESULTS                   ClientCapabilities = 1 << 18
+	CCAP_PLUGIN_AUTH                        ClientCapabilities = 1 << 19
+	CCAP_CONNECT_ATTRS                      ClientCapabilities = 1 << 20
+	CCAP_PLUGIN_AUTH_LENENC_CLIENT_DATA     ClientCapabilities = 1 << 21
+	CCAP_CLIENT_SESSION_TRACK               ClientCapabilities = 1 << 23
+	CCAP_CLIENT_DEPRECATE_EOF               ClientCapabilities = 1 << 24
+	CCAP_CLIENT_OPTIONAL_RESULTSET_METADATA ClientCapabilities = 1 << 25
+	CCAP_CLIENT_ZSTD_COMPRESSION_ALGORITHM  ClientCapabilities = 1 << 26
+	CCAP_CLIENT_QUERY_ATTRIBUTES            ClientCapabilities = 1 << 27
+	CCAP_CLIENT_CAPABILITY_EXTENSION        ClientCapabilities = 1 << 29
 )
 
 func (d FieldDetail) MarshalJSON() ([]byte, error) {
 	return json.Marshal(d.String())
 }
 
-func (d FieldDetail) String() string {
-	var b strings.Builder
-	for _, flag := range []string{
+func fieldDetailNames() []string {
+	return []string{
 		"NOT_NULL",
 		"PRIMARY_KEY",
 		"UNIQUE_KEY",
@@ -345,7 +873,12 @@ func (d FieldDetail) String() string {
 		"ON_UPDATE_NOW_FLAG",
 		"PART_KEY_FLAG",
 		"NUM_FLAG",
-	} {
+	}
+}
+
+func (d FieldDetail) String() string {
+	var b strings.Builder
+	for _, flag := range fieldDetailNames() {
 		if d&1 == 1 {
 			if b.Len() > 0 {
 				b.WriteString("|")
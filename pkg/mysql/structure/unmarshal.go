@@ -0,0 +1,98 @@
+package structure
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The json output describes the flags and types in words to make it
+// readable.  These read it back so that a transcript can be turned back
+// into the original packets.
+
+var errUnrecognised = errors.New("unrecognised value")
+
+func (f *FieldType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		var n byte
+		if err := json.Unmarshal(data, &n); err != nil {
+			return errors.Wrap(err, "unmarshal-field-type")
+		}
+		*f = FieldType(n)
+		return nil
+	}
+	for t := 0; t <= 0xff; t++ {
+		if FieldType(t).String() == name {
+			*f = FieldType(t)
+			return nil
+		}
+	}
+	return errors.Wrap(errUnrecognised, name)
+}
+
+func (d *FieldDetail) UnmarshalJSON(data []byte) error {
+	var names string
+	if err := json.Unmarshal(data, &names); err != nil {
+		var n uint16
+		if err := json.Unmarshal(data, &n); err != nil {
+			return errors.Wrap(err, "unmarshal-field-detail")
+		}
+		*d = FieldDetail(n)
+		return nil
+	}
+	*d = 0
+	if names == "" {
+		return nil
+	}
+	flags := fieldDetailNames()
+	for _, name := range strings.Split(names, "|") {
+		found := false
+		for bit, flag := range flags {
+			if flag == name {
+				*d |= 1 << bit
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.Wrap(errUnrecognised, name)
+		}
+	}
+	return nil
+}
+
+// numberPrefix reads the number at the start of the likes of
+// "2: SERVER_STATUS_AUTOCOMMIT".
+func numberPrefix(data []byte, base int, bits int) (uint64, error) {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n uint64
+		if err := json.Unmarshal(data, &n); err != nil {
+			return 0, errors.Wrap(err, "unmarshal-flags")
+		}
+		return n, nil
+	}
+	number, _, _ := strings.Cut(s, ":")
+	n, err := strconv.ParseUint(number, base, bits)
+	if err != nil {
+		return 0, errors.Wrap(err, "unmarshal-flags")
+	}
+	return n, nil
+}
+
+func (f *StatusFlags) UnmarshalJSON(data []byte) error {
+	//nolint:mnd
+	n, err := numberPrefix(data, 16, 16)
+	*f = StatusFlags(n)
+	return err
+}
+
+func (c *ClientCapabilities) UnmarshalJSON(data []byte) error {
+	//nolint:mnd
+	n, err := numberPrefix(data, 10, 32)
+	*c = ClientCapabilities(n)
+	return err
+}
@@ -0,0 +1,42 @@
+package structure_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+func TestUnmarshalRoundTrip(t *testing.T) {
+	type flags struct {
+		FieldType    structure.FieldType
+		FieldDetail  structure.FieldDetail
+		Status       structure.StatusFlags
+		Capabilities structure.ClientCapabilities
+		NoDetail     structure.FieldDetail
+	}
+	expected := flags{
+		FieldType:    structure.VAR_STRING,
+		FieldDetail:  structure.DETAIL_NOT_NULL | structure.DETAIL_UNSIGNED | structure.DETAIL_NUM_FLAG,
+		Status:       0x4022,
+		Capabilities: structure.CCAP_CLIENT_PROTOCOL_41 | structure.CCAP_CLIENT_DEPRECATE_EOF,
+	}
+	data, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got flags
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, expected); diff != "" {
+		t.Fatalf("Flags don't match (-got +expected):\n%s\n", diff)
+	}
+
+	var detail structure.FieldDetail
+	if err := json.Unmarshal([]byte(`"NOT_A_FLAG"`), &detail); err == nil {
+		t.Fatal("Expected an error for an unknown flag")
+	}
+}
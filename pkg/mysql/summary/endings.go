@@ -0,0 +1,153 @@
+package summary
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+	"github.com/pkg/errors"
+
+	"github.com/colinnewell/pcap-cli/tcp"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+const ngMagic = 0x0A0D0D0A
+
+// Endings records how the TCP connections in the captures were closed.
+// The decoder only sees the data so the flags are read from the captures
+// separately.
+type Endings struct {
+	closes map[tcp.ConnectionAddress][]closing
+}
+
+// closing is a connection on an address, a new one is started by each
+// SYN.
+type closing struct {
+	start    time.Time
+	fin, rst bool
+}
+
+// ReadEndings reads the TCP flags from the capture files.
+func ReadEndings(files []string) (*Endings, error) {
+	e := &Endings{closes: make(map[tcp.ConnectionAddress][]closing)}
+	for _, name := range files {
+		if err := e.read(name); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+func (e *Endings) read(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return errors.Wrap(err, "endings-open")
+	}
+	defer f.Close()
+	br := bufio.NewReader(f)
+	magic, err := br.Peek(4)
+	if err != nil {
+		return errors.Wrap(err, "endings-read-magic")
+	}
+	var source gopacket.PacketDataSource
+	var linkType layers.LinkType
+	if binary.LittleEndian.Uint32(magic) == ngMagic {
+		ng, err := pcapgo.NewNgReader(br, pcapgo.DefaultNgReaderOptions)
+		if err != nil {
+			return errors.Wrap(err, "endings-read-pcapng")
+		}
+		source, linkType = ng, ng.LinkType()
+	} else {
+		p, err := pcapgo.NewReader(br)
+		if err != nil {
+			return errors.Wrap(err, "endings-read-pcap")
+		}
+		source, linkType = p, p.LinkType()
+	}
+	for {
+		data, info, err := source.ReadPacketData()
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "endings-read-packet")
+		}
+		p := gopacket.NewPacket(data, linkType, gopacket.DecodeOptions{NoCopy: true, Lazy: true})
+		t, ok := p.TransportLayer().(*layers.TCP)
+		if !ok || p.NetworkLayer() == nil {
+			continue
+		}
+		e.add(address(p.NetworkLayer().NetworkFlow(), t.TransportFlow()), info.Timestamp, t)
+	}
+}
+
+// address orients the flows the way the decoder does, so they match the
+// connection's Address.
+func address(ip, port gopacket.Flow) tcp.ConnectionAddress {
+	src, dst := port.Endpoints()
+	if src.LessThan(dst) {
+		return tcp.ConnectionAddress{IP: ip.Reverse(), Port: port.Reverse()}
+	}
+	return tcp.ConnectionAddress{IP: ip, Port: port}
+}
+
+func (e *Endings) add(a tcp.ConnectionAddress, seen time.Time, t *layers.TCP) {
+	closes := e.closes[a]
+	if (t.SYN && !t.ACK) || len(closes) == 0 {
+		// the capture may have started part way through the
+		// connection.
+		start := seen
+		if !t.SYN {
+			start = time.Time{}
+		}
+		closes = append(closes, closing{start: start})
+	}
+	last := &closes[len(closes)-1]
+	last.fin = last.fin || t.FIN
+	last.rst = last.rst || t.RST
+	e.closes[a] = closes
+}
+
+// Ending gives how a connection finished.  A QUIT the decoder saw is kept,
+// otherwise an RST is reported over a FIN.
+func (e *Endings) Ending(c structure.Connection) string {
+	if c.Summary.Ending == structure.EndingQuit {
+		return c.Summary.Ending
+	}
+	var found *closing
+	for i, cl := range e.closes[c.Address] {
+		if !cl.start.After(c.Summary.Start) {
+			found = &e.closes[c.Address][i]
+		}
+	}
+	switch {
+	case found == nil:
+		return structure.EndingCaptureEnd
+	case found.rst:
+		return structure.EndingRST
+	case found.fin:
+		return structure.EndingFIN
+	}
+	return structure.EndingCaptureEnd
+}
+
+// Apply fills in how each connection ended as they pass through.
+func Apply(e *Endings, completed chan interface{}) chan interface{} {
+	ended := make(chan interface{})
+	go func() {
+		defer close(ended)
+		for c := range completed {
+			if conn, ok := c.(structure.Connection); ok {
+				conn.Summary.Ending = e.Ending(conn)
+				c = conn
+			}
+			ended <- c
+		}
+	}()
+	return ended
+}
@@ -0,0 +1,40 @@
+// Package summary deals with the connection summaries: filling in how each
+// connection ended from the TCP flags in the capture, and writing out just
+// the summaries when there are too many connections to look through.
+package summary
+
+import (
+	"github.com/colinnewell/pcap-cli/tcp"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/schema"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+// Line is the summary of a connection along with its address.
+type Line struct {
+	Address tcp.ConnectionAddress
+	structure.ConnectionSummary
+}
+
+// Only passes on just the summaries of the connections, in the version 1
+// or 2 form depending on what it's given.
+func Only(completed chan interface{}) chan interface{} {
+	summaries := make(chan interface{})
+	go func() {
+		defer close(summaries)
+		for c := range completed {
+			switch conn := c.(type) {
+			case structure.Connection:
+				c = Line{Address: conn.Address, ConnectionSummary: conn.Summary}
+			case schema.Connection:
+				c = schema.SummaryLine{
+					SchemaVersion: conn.SchemaVersion,
+					Address:       conn.Address,
+					Summary:       conn.Summary,
+				}
+			}
+			summaries <- c
+		}
+	}()
+	return summaries
+}
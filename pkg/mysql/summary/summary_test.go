@@ -0,0 +1,127 @@
+package summary_test
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"github.com/colinnewell/pcap-cli/tcp"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/pcapgen"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/schema"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/summary"
+)
+
+var start = time.Date(2021, 10, 30, 14, 2, 45, 0, time.UTC)
+
+func address(port byte) tcp.ConnectionAddress {
+	return tcp.ConnectionAddress{
+		IP:   gopacket.NewFlow(layers.EndpointIPv4, net.IP{10, 0, 0, 2}, net.IP{10, 0, 0, 1}),
+		Port: gopacket.NewFlow(layers.EndpointTCPPort, []byte{0xc3, port}, []byte{0x0c, 0xea}),
+	}
+}
+
+func connection(port byte, seen time.Time, ending string) structure.Connection {
+	return structure.Connection{
+		Address: address(port),
+		Summary: structure.ConnectionSummary{Start: seen, Ending: ending},
+	}
+}
+
+// writeCapture writes a capture with a connection closed each way, and
+// one that's reused.
+func writeCapture(t *testing.T) string {
+	c := pcapgen.New(start)
+	c.Gap = time.Millisecond
+	connect := func(port string) *pcapgen.Connection {
+		conn, err := c.Connect("10.0.0.2:"+port, "10.0.0.1:3306")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return conn
+	}
+	if err := connect("50000").Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := connect("50001").Reset(); err != nil {
+		t.Fatal(err)
+	}
+	connect("50002")
+	if err := connect("50003").Close(); err != nil {
+		t.Fatal(err)
+	}
+	c.Wait(time.Minute)
+	connect("50003")
+
+	name := filepath.Join(t.TempDir(), "endings.pcap")
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := c.Write(f); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func TestEndings(t *testing.T) {
+	endings, err := summary.ReadEndings([]string{writeCapture(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	later := start.Add(time.Hour)
+	tests := []struct {
+		name       string
+		connection structure.Connection
+		expected   string
+	}{
+		{"closed", connection(0x50, later, ""), "FIN"},
+		{"reset", connection(0x51, later, ""), "RST"},
+		{"still open", connection(0x52, later, ""), "capture end"},
+		{"quit", connection(0x50, later, structure.EndingQuit), "QUIT"},
+		{"first on reused port", connection(0x53, start.Add(time.Second), ""), "FIN"},
+		{"second on reused port", connection(0x53, later, ""), "capture end"},
+		{"not in the capture", connection(0x54, later, ""), "capture end"},
+	}
+	for _, test := range tests {
+		if diff := cmp.Diff(endings.Ending(test.connection), test.expected); diff != "" {
+			t.Errorf("%s: ending doesn't match (-got +expected):\n%s\n", test.name, diff)
+		}
+	}
+}
+
+func TestOnly(t *testing.T) {
+	completed := make(chan interface{}, 3)
+	conn := connection(0x50, start, structure.EndingQuit)
+	conn.Items = []structure.Transmission{{Data: structure.Request{Type: "QUIT"}}}
+	v2, err := schema.FromConnection(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	completed <- conn
+	completed <- v2
+	completed <- "other"
+	close(completed)
+
+	var got []interface{}
+	for s := range summary.Only(completed) {
+		got = append(got, s)
+	}
+	expected := []interface{}{
+		summary.Line{Address: address(0x50), ConnectionSummary: conn.Summary},
+		schema.SummaryLine{SchemaVersion: 2, Address: "10.0.0.2:50000 - 10.0.0.1:3306", Summary: v2.Summary},
+		"other",
+	}
+	if diff := cmp.Diff(got, expected, cmp.Comparer(func(a, b tcp.ConnectionAddress) bool {
+		return a.String() == b.String()
+	})); diff != "" {
+		t.Errorf("Summaries don't match (-got +expected):\n%s\n", diff)
+	}
+}
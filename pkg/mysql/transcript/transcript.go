@@ -0,0 +1,210 @@
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+)
+
+var errUnsupportedVersion = errors.New(
+	"only version 1 of the output can be read, write it without --output-version 2")
+
+// Connection is the part of a connection from the pcap2mysql-log json output
+// needed by the tools that work from a transcript.
+type Connection struct {
+	Address   string
+	Items     []Item
+	Exchanges []structure.Exchange
+}
+
+// Item is a request or response from the json output.
+type Item struct {
+	Data       Data
+	Seen       []time.Time
+	ResponseTo *int `json:"ResponseTo,omitempty"`
+}
+
+// Data holds the fields of interest from the items.  When the raw data has
+// been included the item is found in Transmission.
+type Data struct {
+	Type         string
+	Query        string
+	SQL          string
+	Database     string
+	Schema       string
+	StatementID  uint32
+	Params       []interface{}
+	Code         uint16
+	State        string
+	Message      string
+	Name         string
+	Category     string
+	Transmission *Data
+}
+
+// Unwrap returns the item itself, even when it has been wrapped up with its
+// raw data.
+func (d Data) Unwrap() Data {
+	if d.Transmission != nil {
+		return *d.Transmission
+	}
+	return d
+}
+
+// Read decodes the connections one at a time from the json written by
+// pcap2mysql-log, passing each to fn.  Version 1 of the output is
+// understood, either as the json array or as newline delimited json with a
+// connection per line.  The ndjson-items form, with a transmission per
+// line, is gathered back up into connections by address, which are passed
+// on once all the lines have been read.  Version 2 is rejected with an
+// error.  C is usually this package's Connection, but anything the json can
+// be decoded into will do.  Numbers are left as json.Number so that large
+// integers survive.
+func Read[C any](r io.Reader, fn func(C) error) error {
+	br := bufio.NewReader(r)
+	first, err := firstByte(br)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "transcript-start")
+	}
+	d := json.NewDecoder(br)
+	d.UseNumber()
+	if first == '[' {
+		if _, err := d.Token(); err != nil {
+			return errors.Wrap(err, "transcript-start")
+		}
+	}
+	// the array ends with a ']', the lines when the input does.
+	next := func() (json.RawMessage, error) {
+		if first == '[' && !d.More() {
+			return nil, io.EOF
+		}
+		var raw json.RawMessage
+		err := d.Decode(&raw)
+		if err != nil && err != io.EOF {
+			return nil, errors.Wrap(err, "transcript-decode")
+		}
+		return raw, err
+	}
+
+	head, err := next()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var probe struct {
+		SchemaVersion int `json:"schema_version"`
+		Index         *int
+		Items         json.RawMessage
+	}
+	if err := json.Unmarshal(head, &probe); err == nil {
+		if probe.SchemaVersion > 1 {
+			return errors.Wrapf(errUnsupportedVersion, "transcript-version %d", probe.SchemaVersion)
+		}
+		if probe.Index != nil && probe.Items == nil {
+			return readItems(head, next, fn)
+		}
+	}
+	for raw := head; ; {
+		if err := decode(raw, fn); err != nil {
+			return err
+		}
+		if raw, err = next(); err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// items is a connection gathered up from the ndjson-items lines.
+type items struct {
+	Address   string
+	Items     []json.RawMessage
+	Exchanges []json.RawMessage `json:",omitempty"`
+}
+
+// readItems gathers the transmissions into their connections.  The lines
+// of connections open at the same time are mixed together so nothing can
+// be passed on until they have all been read.
+func readItems[C any](head json.RawMessage, next func() (json.RawMessage, error), fn func(C) error) error {
+	var order []*items
+	conns := make(map[string]*items)
+	for raw := head; ; {
+		var line struct {
+			Address  string
+			Exchange json.RawMessage
+		}
+		if err := json.Unmarshal(raw, &line); err != nil {
+			return errors.Wrap(err, "transcript-item")
+		}
+		c, ok := conns[line.Address]
+		if !ok {
+			c = &items{Address: line.Address}
+			conns[line.Address] = c
+			order = append(order, c)
+		}
+		c.Items = append(c.Items, raw)
+		if line.Exchange != nil {
+			c.Exchanges = append(c.Exchanges, line.Exchange)
+		}
+		var err error
+		if raw, err = next(); err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, c := range order {
+		raw, err := json.Marshal(c)
+		if err != nil {
+			return errors.Wrap(err, "transcript-item")
+		}
+		if err := decode(raw, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decode decodes a connection and passes it to fn.
+func decode[C any](raw json.RawMessage, fn func(C) error) error {
+	var c C
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	if err := d.Decode(&c); err != nil {
+		return errors.Wrap(err, "transcript-decode")
+	}
+	return fn(c)
+}
+
+// firstByte looks at the first thing in the json, leaving it to be read.
+func firstByte(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		if b != '[' && b != '{' {
+			//nolint:err113
+			return 0, errors.New("unexpected start to the json")
+		}
+		return b, r.UnreadByte()
+	}
+}
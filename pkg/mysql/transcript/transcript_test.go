@@ -0,0 +1,94 @@
+package transcript_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/structure"
+	"github.com/colinnewell/pcap2mysql-log/pkg/mysql/transcript"
+)
+
+func addresses(t *testing.T, input string) []string {
+	t.Helper()
+
+	var got []string
+	err := transcript.Read(strings.NewReader(input), func(c transcript.Connection) error {
+		got = append(got, c.Address)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestRead(t *testing.T) {
+	for _, input := range []string{
+		`[{"Address": "a", "Items": []}, {"Address": "b"}]`,
+		"\n  {\"Address\": \"a\", \"Items\": []}\n{\"Address\": \"b\"}\n",
+	} {
+		if diff := cmp.Diff(addresses(t, input), []string{"a", "b"}); diff != "" {
+			t.Errorf("Connections don't match (-got +expected):\n%s\n", diff)
+		}
+	}
+	if got := addresses(t, " \n"); got != nil {
+		t.Errorf("expected nothing, got %#v", got)
+	}
+}
+
+func TestReadBadStart(t *testing.T) {
+	err := transcript.Read(strings.NewReader(`"a"`), func(transcript.Connection) error {
+		return nil
+	})
+	if err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestReadVersion2(t *testing.T) {
+	for _, input := range []string{
+		`[{"schema_version": 2, "address": "a", "items": []}]`,
+		`{"schema_version": 2, "address": "a", "item": {"index": 0}}` + "\n",
+	} {
+		err := transcript.Read(strings.NewReader(input), func(transcript.Connection) error {
+			t.Fatal("version 2 shouldn't be read")
+			return nil
+		})
+		if err == nil || !strings.Contains(err.Error(), "only version 1") {
+			t.Errorf("expected an error about the version, got %v", err)
+		}
+	}
+}
+
+func TestReadItems(t *testing.T) {
+	input := `{"Address": "a", "Index": 0, "Data": {"Type": "Query", "Query": "SELECT 1"},` +
+		` "Exchange": {"Request": 0, "Responses": [1], "Command": "Query"}}
+{"Address": "b", "Index": 0, "Data": {"Type": "Quit"}}
+{"Address": "a", "Index": 1, "Data": {"Type": "OK"}, "ResponseTo": 0}
+`
+	var got []transcript.Connection
+	err := transcript.Read(strings.NewReader(input), func(c transcript.Connection) error {
+		got = append(got, c)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	zero := 0
+	expected := []transcript.Connection{
+		{
+			Address: "a",
+			Items: []transcript.Item{
+				{Data: transcript.Data{Type: "Query", Query: "SELECT 1"}},
+				{Data: transcript.Data{Type: "OK"}, ResponseTo: &zero},
+			},
+			Exchanges: []structure.Exchange{{Request: 0, Responses: []int{1}, Command: "Query"}},
+		},
+		{Address: "b", Items: []transcript.Item{{Data: transcript.Data{Type: "Quit"}}}},
+	}
+	if diff := cmp.Diff(got, expected); diff != "" {
+		t.Errorf("Connections don't match (-got +expected):\n%s\n", diff)
+	}
+}